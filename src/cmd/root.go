@@ -84,15 +84,204 @@ func initEnvConfig() {
 		webhook := strings.Split(envWebhook, ",")
 		config.WhatsappWebhook = webhook
 	}
+	if envWebhookConfig := viper.GetString("WHATSAPP_WEBHOOK_CONFIG"); envWebhookConfig != "" {
+		config.WhatsappWebhookConfig = envWebhookConfig
+	}
 	if envWebhookSecret := viper.GetString("WHATSAPP_WEBHOOK_SECRET"); envWebhookSecret != "" {
 		config.WhatsappWebhookSecret = envWebhookSecret
 	}
+	if envWebhookQueueSize := viper.GetInt("WHATSAPP_WEBHOOK_QUEUE_SIZE"); envWebhookQueueSize > 0 {
+		config.WhatsappWebhookQueueSize = envWebhookQueueSize
+	}
+	if envWebhookWorkers := viper.GetInt("WHATSAPP_WEBHOOK_WORKERS"); envWebhookWorkers > 0 {
+		config.WhatsappWebhookWorkers = envWebhookWorkers
+	}
+	if envWebhookQueueOverflow := viper.GetString("WHATSAPP_WEBHOOK_QUEUE_OVERFLOW"); envWebhookQueueOverflow != "" {
+		config.WhatsappWebhookQueueOverflow = envWebhookQueueOverflow
+	}
+	if envWebhookOutboxMaxAttempts := viper.GetInt("WHATSAPP_WEBHOOK_OUTBOX_MAX_ATTEMPTS"); envWebhookOutboxMaxAttempts > 0 {
+		config.WhatsappWebhookOutboxMaxAttempts = envWebhookOutboxMaxAttempts
+	}
+	if envWebhookOutboxInterval := viper.GetDuration("WHATSAPP_WEBHOOK_OUTBOX_INTERVAL"); envWebhookOutboxInterval > 0 {
+		config.WhatsappWebhookOutboxInterval = envWebhookOutboxInterval
+	}
+	if envWebhookOutboxBaseDelay := viper.GetDuration("WHATSAPP_WEBHOOK_OUTBOX_BASE_DELAY"); envWebhookOutboxBaseDelay > 0 {
+		config.WhatsappWebhookOutboxBaseDelay = envWebhookOutboxBaseDelay
+	}
+	if envWebhookOutboxMaxDelay := viper.GetDuration("WHATSAPP_WEBHOOK_OUTBOX_MAX_DELAY"); envWebhookOutboxMaxDelay > 0 {
+		config.WhatsappWebhookOutboxMaxDelay = envWebhookOutboxMaxDelay
+	}
+	if viper.IsSet("WHATSAPP_WEBHOOK_FROM_ME") {
+		config.WhatsappWebhookFromMe = viper.GetBool("WHATSAPP_WEBHOOK_FROM_ME")
+	}
+	if envWebhookPresence := viper.GetBool("WHATSAPP_WEBHOOK_PRESENCE"); envWebhookPresence {
+		config.WhatsappWebhookPresence = envWebhookPresence
+	}
+	if envWebhookHistorySync := viper.GetBool("WHATSAPP_WEBHOOK_HISTORY_SYNC"); envWebhookHistorySync {
+		config.WhatsappWebhookHistorySync = envWebhookHistorySync
+	}
+	if envWebhookHistorySyncChunk := viper.GetInt("WHATSAPP_WEBHOOK_HISTORY_SYNC_CHUNK"); envWebhookHistorySyncChunk > 0 {
+		config.WhatsappWebhookHistorySyncChunk = envWebhookHistorySyncChunk
+	}
+	if envWebhookPictureDownload := viper.GetBool("WHATSAPP_WEBHOOK_PICTURE_DOWNLOAD"); envWebhookPictureDownload {
+		config.WhatsappWebhookPictureDownload = envWebhookPictureDownload
+	}
 	if envAccountValidation := viper.GetBool("WHATSAPP_ACCOUNT_VALIDATION"); envAccountValidation {
 		config.WhatsappAccountValidation = envAccountValidation
 	}
 	if envChatStorage := viper.GetBool("WHATSAPP_CHAT_STORAGE"); !envChatStorage {
 		config.WhatsappChatStorage = envChatStorage
 	}
+	if envRerequestFromPhone := viper.GetBool("WHATSAPP_REREQUEST_FROM_PHONE"); envRerequestFromPhone {
+		config.WhatsappRerequestFromPhone = envRerequestFromPhone
+	}
+	if envStickerConvert := viper.GetBool("WHATSAPP_STICKER_CONVERT"); envStickerConvert {
+		config.WhatsappStickerConvert = envStickerConvert
+	}
+	if envAudioTranscode := viper.GetString("WHATSAPP_AUDIO_TRANSCODE"); envAudioTranscode != "" {
+		config.WhatsappAudioTranscode = envAudioTranscode
+	}
+	if envAudioTranscodeBitrate := viper.GetString("WHATSAPP_AUDIO_TRANSCODE_BITRATE"); envAudioTranscodeBitrate != "" {
+		config.WhatsappAudioTranscodeBitrate = envAudioTranscodeBitrate
+	}
+	if envMediaStorage := viper.GetString("WHATSAPP_MEDIA_STORAGE"); envMediaStorage != "" {
+		config.WhatsappMediaStorage = envMediaStorage
+	}
+	if envMediaStoragePrefix := viper.GetString("WHATSAPP_MEDIA_STORAGE_PREFIX"); envMediaStoragePrefix != "" {
+		config.WhatsappMediaStoragePrefix = envMediaStoragePrefix
+	}
+	if envS3Endpoint := viper.GetString("WHATSAPP_S3_ENDPOINT"); envS3Endpoint != "" {
+		config.WhatsappS3Endpoint = envS3Endpoint
+	}
+	if envS3Region := viper.GetString("WHATSAPP_S3_REGION"); envS3Region != "" {
+		config.WhatsappS3Region = envS3Region
+	}
+	if envS3Bucket := viper.GetString("WHATSAPP_S3_BUCKET"); envS3Bucket != "" {
+		config.WhatsappS3Bucket = envS3Bucket
+	}
+	if envS3AccessKey := viper.GetString("WHATSAPP_S3_ACCESS_KEY"); envS3AccessKey != "" {
+		config.WhatsappS3AccessKey = envS3AccessKey
+	}
+	if envS3SecretKey := viper.GetString("WHATSAPP_S3_SECRET_KEY"); envS3SecretKey != "" {
+		config.WhatsappS3SecretKey = envS3SecretKey
+	}
+	if envMediaStoragePublicURL := viper.GetString("WHATSAPP_MEDIA_STORAGE_PUBLIC_URL"); envMediaStoragePublicURL != "" {
+		config.WhatsappMediaStoragePublicURL = envMediaStoragePublicURL
+	}
+	if envGCSBucket := viper.GetString("WHATSAPP_GCS_BUCKET"); envGCSBucket != "" {
+		config.WhatsappGCSBucket = envGCSBucket
+	}
+	if envGCSCredentialsFile := viper.GetString("WHATSAPP_GCS_CREDENTIALS_FILE"); envGCSCredentialsFile != "" {
+		config.WhatsappGCSCredentialsFile = envGCSCredentialsFile
+	}
+	if envAzureConnectionString := viper.GetString("WHATSAPP_AZURE_CONNECTION_STRING"); envAzureConnectionString != "" {
+		config.WhatsappAzureConnectionString = envAzureConnectionString
+	}
+	if envAzureAccountName := viper.GetString("WHATSAPP_AZURE_ACCOUNT_NAME"); envAzureAccountName != "" {
+		config.WhatsappAzureAccountName = envAzureAccountName
+	}
+	if envAzureAccountKey := viper.GetString("WHATSAPP_AZURE_ACCOUNT_KEY"); envAzureAccountKey != "" {
+		config.WhatsappAzureAccountKey = envAzureAccountKey
+	}
+	if envAzureContainer := viper.GetString("WHATSAPP_AZURE_CONTAINER"); envAzureContainer != "" {
+		config.WhatsappAzureContainer = envAzureContainer
+	}
+	if envMediaBaseURL := viper.GetString("WHATSAPP_MEDIA_BASE_URL"); envMediaBaseURL != "" {
+		config.WhatsappMediaBaseURL = envMediaBaseURL
+	}
+	if envMediaURLSecret := viper.GetString("WHATSAPP_MEDIA_URL_SECRET"); envMediaURLSecret != "" {
+		config.WhatsappMediaURLSecret = envMediaURLSecret
+	}
+	if envMediaEncryptionKey := viper.GetString("WHATSAPP_MEDIA_ENCRYPTION_KEY"); envMediaEncryptionKey != "" {
+		config.WhatsappMediaEncryptionKey = envMediaEncryptionKey
+	}
+	if envMediaRetentionMaxAge := viper.GetDuration("WHATSAPP_MEDIA_RETENTION_MAX_AGE"); envMediaRetentionMaxAge > 0 {
+		config.WhatsappMediaRetentionMaxAge = envMediaRetentionMaxAge
+	}
+	if envMediaRetentionMaxSize := viper.GetInt64("WHATSAPP_MEDIA_RETENTION_MAX_SIZE"); envMediaRetentionMaxSize > 0 {
+		config.WhatsappMediaRetentionMaxSize = envMediaRetentionMaxSize
+	}
+	if envMediaRetentionRules := viper.GetString("WHATSAPP_MEDIA_RETENTION_RULES"); envMediaRetentionRules != "" {
+		config.WhatsappMediaRetentionRules = strings.Split(envMediaRetentionRules, ",")
+	}
+	if envMediaRetentionInterval := viper.GetDuration("WHATSAPP_MEDIA_RETENTION_INTERVAL"); envMediaRetentionInterval > 0 {
+		config.WhatsappMediaRetentionInterval = envMediaRetentionInterval
+	}
+	if viper.IsSet("WHATSAPP_MEDIA_DEDUP") {
+		config.WhatsappMediaDedup = viper.GetBool("WHATSAPP_MEDIA_DEDUP")
+	}
+	if envMediaLazyDownload := viper.GetBool("WHATSAPP_MEDIA_LAZY_DOWNLOAD"); envMediaLazyDownload {
+		config.WhatsappMediaLazyDownload = envMediaLazyDownload
+	}
+	if envMediaScan := viper.GetString("WHATSAPP_MEDIA_SCAN"); envMediaScan != "" {
+		config.WhatsappMediaScan = envMediaScan
+	}
+	if envMediaScanAddress := viper.GetString("WHATSAPP_MEDIA_SCAN_ADDRESS"); envMediaScanAddress != "" {
+		config.WhatsappMediaScanAddress = envMediaScanAddress
+	}
+	if envMediaScanTimeout := viper.GetDuration("WHATSAPP_MEDIA_SCAN_TIMEOUT"); envMediaScanTimeout > 0 {
+		config.WhatsappMediaScanTimeout = envMediaScanTimeout
+	}
+	if envMediaDownloadMaxSize := viper.GetInt64("WHATSAPP_MEDIA_DOWNLOAD_MAX_SIZE"); envMediaDownloadMaxSize > 0 {
+		config.WhatsappMediaDownloadMaxSize = envMediaDownloadMaxSize
+	}
+	if envMediaDownloadSkipRules := viper.GetString("WHATSAPP_MEDIA_DOWNLOAD_SKIP_RULES"); envMediaDownloadSkipRules != "" {
+		config.WhatsappMediaDownloadSkipRules = strings.Split(envMediaDownloadSkipRules, ",")
+	}
+	if envMediaAsyncSize := viper.GetInt64("WHATSAPP_MEDIA_ASYNC_SIZE"); envMediaAsyncSize > 0 {
+		config.WhatsappMediaAsyncSize = envMediaAsyncSize
+	}
+	if envMediaAsyncWorkers := viper.GetInt("WHATSAPP_MEDIA_ASYNC_WORKERS"); envMediaAsyncWorkers > 0 {
+		config.WhatsappMediaAsyncWorkers = envMediaAsyncWorkers
+	}
+	if envMediaUploadExpiry := viper.GetDuration("WHATSAPP_MEDIA_UPLOAD_EXPIRY"); envMediaUploadExpiry > 0 {
+		config.WhatsappMediaUploadExpiry = envMediaUploadExpiry
+	}
+	if envGroupListCache := viper.GetDuration("WHATSAPP_GROUP_LIST_CACHE"); envGroupListCache > 0 {
+		config.WhatsappGroupListCache = envGroupListCache
+	}
+	if viper.IsSet("WHATSAPP_MESSAGE_STORE_RETENTION") {
+		config.WhatsappMessageStoreRetention = viper.GetDuration("WHATSAPP_MESSAGE_STORE_RETENTION")
+	}
+	if viper.IsSet("WHATSAPP_OUTBOUND_RATE") {
+		config.WhatsappOutboundRate = viper.GetFloat64("WHATSAPP_OUTBOUND_RATE")
+	}
+	if envOutboundBurst := viper.GetInt("WHATSAPP_OUTBOUND_BURST"); envOutboundBurst > 0 {
+		config.WhatsappOutboundBurst = envOutboundBurst
+	}
+	if viper.IsSet("WHATSAPP_OUTBOUND_CHAT_RATE") {
+		config.WhatsappOutboundChatRate = viper.GetFloat64("WHATSAPP_OUTBOUND_CHAT_RATE")
+	}
+	if envOutboundChatBurst := viper.GetInt("WHATSAPP_OUTBOUND_CHAT_BURST"); envOutboundChatBurst > 0 {
+		config.WhatsappOutboundChatBurst = envOutboundChatBurst
+	}
+	if viper.IsSet("WHATSAPP_OUTBOUND_MAX_WAIT") {
+		config.WhatsappOutboundMaxWait = viper.GetDuration("WHATSAPP_OUTBOUND_MAX_WAIT")
+	}
+	if viper.IsSet("WHATSAPP_BROADCAST_DELAY") {
+		config.WhatsappBroadcastDelay = viper.GetDuration("WHATSAPP_BROADCAST_DELAY")
+	}
+	if viper.IsSet("WHATSAPP_BROADCAST_JITTER") {
+		config.WhatsappBroadcastJitter = viper.GetDuration("WHATSAPP_BROADCAST_JITTER")
+	}
+	if viper.IsSet("WHATSAPP_SEND_RETRY_ATTEMPTS") {
+		config.WhatsappSendRetryAttempts = viper.GetInt("WHATSAPP_SEND_RETRY_ATTEMPTS")
+	}
+	if viper.IsSet("WHATSAPP_SEND_RETRY_MAX_AGE") {
+		config.WhatsappSendRetryMaxAge = viper.GetDuration("WHATSAPP_SEND_RETRY_MAX_AGE")
+	}
+	if envMediaStorageKeepLocal := viper.GetBool("WHATSAPP_MEDIA_STORAGE_KEEP_LOCAL"); envMediaStorageKeepLocal {
+		config.WhatsappMediaStorageKeepLocal = envMediaStorageKeepLocal
+	}
+	if viper.IsSet("WHATSAPP_S3_USE_SSL") {
+		config.WhatsappS3UseSSL = viper.GetBool("WHATSAPP_S3_USE_SSL")
+	}
+	if envMediaStorageURLExpiry := viper.GetDuration("WHATSAPP_MEDIA_STORAGE_URL_EXPIRY"); envMediaStorageURLExpiry > 0 {
+		config.WhatsappMediaStorageURLExpiry = envMediaStorageURLExpiry
+	}
+	if viper.IsSet("WHATSAPP_HISTORY_SYNC_STORAGE") {
+		config.WhatsappHistorySyncStorage = viper.GetBool("WHATSAPP_HISTORY_SYNC_STORAGE")
+	}
 }
 
 // initFlags sets up command line flags that override environment variables
@@ -152,12 +341,90 @@ func initFlags() {
 		config.WhatsappWebhook,
 		`forward event to webhook --webhook <string> | example: --webhook="https://yourcallback.com/callback"`,
 	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookConfig,
+		"webhook-config", "",
+		config.WhatsappWebhookConfig,
+		`structured webhook config file (yaml/json) with per-url event filters --webhook-config <string> | example: --webhook-config="webhooks.yaml"`,
+	)
 	rootCmd.PersistentFlags().StringVarP(
 		&config.WhatsappWebhookSecret,
 		"webhook-secret", "",
 		config.WhatsappWebhookSecret,
 		`secure webhook request --webhook-secret <string> | example: --webhook-secret="super-secret-key"`,
 	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookQueueSize,
+		"webhook-queue-size", "",
+		config.WhatsappWebhookQueueSize,
+		`number of webhook events buffered while waiting for delivery --webhook-queue-size <number> | example: --webhook-queue-size=1000`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookWorkers,
+		"webhook-workers", "",
+		config.WhatsappWebhookWorkers,
+		`number of goroutines delivering queued webhook events --webhook-workers <number> | example: --webhook-workers=4`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappWebhookQueueOverflow,
+		"webhook-queue-overflow", "",
+		config.WhatsappWebhookQueueOverflow,
+		`what to do when the webhook queue is full (block, drop_newest, drop_oldest) --webhook-queue-overflow <string> | example: --webhook-queue-overflow="drop_oldest"`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookOutboxMaxAttempts,
+		"webhook-outbox-max-attempts", "",
+		config.WhatsappWebhookOutboxMaxAttempts,
+		`retries of a failed webhook delivery before it is kept as a dead letter --webhook-outbox-max-attempts <number> | example: --webhook-outbox-max-attempts=10`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookOutboxInterval,
+		"webhook-outbox-interval", "",
+		config.WhatsappWebhookOutboxInterval,
+		`how often the webhook outbox is checked for due retries --webhook-outbox-interval <duration> | example: --webhook-outbox-interval=5s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookOutboxBaseDelay,
+		"webhook-outbox-base-delay", "",
+		config.WhatsappWebhookOutboxBaseDelay,
+		`delay before the first retry of a failed webhook delivery, doubled for every next one --webhook-outbox-base-delay <duration> | example: --webhook-outbox-base-delay=30s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappWebhookOutboxMaxDelay,
+		"webhook-outbox-max-delay", "",
+		config.WhatsappWebhookOutboxMaxDelay,
+		`longest delay between two retries of a failed webhook delivery --webhook-outbox-max-delay <duration> | example: --webhook-outbox-max-delay=1h`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookFromMe,
+		"webhook-from-me", "",
+		config.WhatsappWebhookFromMe,
+		`forward messages sent from this account (this device and other linked devices) to webhook --webhook-from-me <true/false> | example: --webhook-from-me=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookPresence,
+		"webhook-presence", "",
+		config.WhatsappWebhookPresence,
+		`forward presence and chat presence (typing, recording) events to webhook --webhook-presence <true/false> | example: --webhook-presence=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookHistorySync,
+		"webhook-history-sync", "",
+		config.WhatsappWebhookHistorySync,
+		`forward the chat history received after pairing to webhook --webhook-history-sync <true/false> | example: --webhook-history-sync=true`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappWebhookHistorySyncChunk,
+		"webhook-history-sync-chunk", "",
+		config.WhatsappWebhookHistorySyncChunk,
+		`number of messages per history_sync webhook --webhook-history-sync-chunk <number> | example: --webhook-history-sync-chunk=200`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappWebhookPictureDownload,
+		"webhook-picture-download", "",
+		config.WhatsappWebhookPictureDownload,
+		`download the new profile or group picture of picture webhook events --webhook-picture-download <true/false> | example: --webhook-picture-download=true`,
+	)
 	rootCmd.PersistentFlags().BoolVarP(
 		&config.WhatsappAccountValidation,
 		"account-validation", "",
@@ -170,6 +437,306 @@ func initFlags() {
 		config.WhatsappChatStorage,
 		`enable or disable chat storage --chat-storage <true/false>. If you disable this, reply feature maybe not working properly | example: --chat-storage=true`,
 	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappHistorySyncStorage,
+		"history-sync-storage", "",
+		config.WhatsappHistorySyncStorage,
+		`store the chat history received after pairing in storages --history-sync-storage <true/false> | example: --history-sync-storage=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappRerequestFromPhone,
+		"rerequest-from-phone", "",
+		config.WhatsappRerequestFromPhone,
+		`ask the phone for the messages that failed to decrypt and weren't resent --rerequest-from-phone <true/false> | example: --rerequest-from-phone=true`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappStickerConvert,
+		"sticker-convert", "",
+		config.WhatsappStickerConvert,
+		`also store received WebP stickers as PNG, or GIF when animated (needs ffmpeg) --sticker-convert <true/false> | example: --sticker-convert=true`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAudioTranscode,
+		"audio-transcode", "",
+		config.WhatsappAudioTranscode,
+		`also store received audio as mp3, ogg, m4a or wav (needs ffmpeg) --audio-transcode <string> | example: --audio-transcode=mp3`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAudioTranscodeBitrate,
+		"audio-transcode-bitrate", "",
+		config.WhatsappAudioTranscodeBitrate,
+		`bitrate of the transcoded audio --audio-transcode-bitrate <string> | example: --audio-transcode-bitrate=128k`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaStorage,
+		"media-storage", "",
+		config.WhatsappMediaStorage,
+		`where the received media are stored, local, s3, gcs or azure --media-storage <string> | example: --media-storage=s3`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaStoragePrefix,
+		"media-storage-prefix", "",
+		config.WhatsappMediaStoragePrefix,
+		`prefix of the uploaded media keys, {device} is replaced by the device number --media-storage-prefix <string> | example: --media-storage-prefix={device}/media`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappMediaStorageKeepLocal,
+		"media-storage-keep-local", "",
+		config.WhatsappMediaStorageKeepLocal,
+		`keep the local copy of the uploaded media --media-storage-keep-local <true/false> | example: --media-storage-keep-local=true`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaStoragePublicURL,
+		"media-storage-public-url", "",
+		config.WhatsappMediaStoragePublicURL,
+		`base url of a public bucket, the payload gets signed urls otherwise --media-storage-public-url <string> | example: --media-storage-public-url=https://cdn.example.com`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMediaStorageURLExpiry,
+		"media-storage-url-expiry", "",
+		config.WhatsappMediaStorageURLExpiry,
+		`lifetime of the signed media urls, at most 168h for s3 and gcs --media-storage-url-expiry <duration> | example: --media-storage-url-expiry=1h`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaBaseURL,
+		"media-base-url", "",
+		config.WhatsappMediaBaseURL,
+		`public url of this server, the local media then get signed urls --media-base-url <string> | example: --media-base-url=https://wa.example.com`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaURLSecret,
+		"media-url-secret", "",
+		config.WhatsappMediaURLSecret,
+		`secret signing the local media urls, required with --media-base-url and different from the webhook secret --media-url-secret <string> | example: --media-url-secret="$(openssl rand -hex 32)"`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaEncryptionKey,
+		"media-encryption-key", "",
+		config.WhatsappMediaEncryptionKey,
+		`encrypt the stored media with AES-256-GCM, a base64 32 bytes key --media-encryption-key <string> | example: --media-encryption-key="$(openssl rand -base64 32)"`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMediaRetentionMaxAge,
+		"media-retention-max-age", "",
+		config.WhatsappMediaRetentionMaxAge,
+		`delete the received media older than this, 0 keeps them --media-retention-max-age <duration> | example: --media-retention-max-age=720h`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappMediaRetentionMaxSize,
+		"media-retention-max-size", "",
+		config.WhatsappMediaRetentionMaxSize,
+		`delete the oldest media once the media folder is larger, in bytes --media-retention-max-size <int> | example: --media-retention-max-size=5000000000`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappMediaRetentionRules,
+		"media-retention-rules", "",
+		config.WhatsappMediaRetentionRules,
+		`max age per mime type --media-retention-rules <mime>=<duration> | example: --media-retention-rules="video/*=72h,image/*=168h"`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMediaRetentionInterval,
+		"media-retention-interval", "",
+		config.WhatsappMediaRetentionInterval,
+		`how often the media retention runs --media-retention-interval <duration> | example: --media-retention-interval=30m`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappMediaDedup,
+		"media-dedup", "",
+		config.WhatsappMediaDedup,
+		`reuse the file of media already downloaded, e.g. forwarded images --media-dedup <true/false> | example: --media-dedup=false`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappMediaLazyDownload,
+		"media-lazy-download", "",
+		config.WhatsappMediaLazyDownload,
+		`don't download the media of the webhook messages, fetch them with POST /message/:message_id/download --media-lazy-download <true/false> | example: --media-lazy-download=true`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaScan,
+		"media-scan", "",
+		config.WhatsappMediaScan,
+		`scan the received media before they're saved, infected media are quarantined --media-scan <clamav/icap> | example: --media-scan=clamav`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappMediaScanAddress,
+		"media-scan-address", "",
+		config.WhatsappMediaScanAddress,
+		`clamd address, or the icap service url --media-scan-address <string> | example: --media-scan-address=tcp://clamav:3310`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMediaScanTimeout,
+		"media-scan-timeout", "",
+		config.WhatsappMediaScanTimeout,
+		`timeout of a media scan --media-scan-timeout <duration> | example: --media-scan-timeout=1m`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappMediaDownloadMaxSize,
+		"media-download-max-size", "",
+		config.WhatsappMediaDownloadMaxSize,
+		`don't download the larger media of the webhooks, in bytes --media-download-max-size <int> | example: --media-download-max-size=50000000`,
+	)
+	rootCmd.PersistentFlags().StringSliceVarP(
+		&config.WhatsappMediaDownloadSkipRules,
+		"media-download-skip-rules", "",
+		config.WhatsappMediaDownloadSkipRules,
+		`max bytes per mime type of the downloaded media, 0 never downloads them --media-download-skip-rules <mime>=<bytes> | example: --media-download-skip-rules="video/*=50000000,application/*=0"`,
+	)
+	rootCmd.PersistentFlags().Int64VarP(
+		&config.WhatsappMediaAsyncSize,
+		"media-async-size", "",
+		config.WhatsappMediaAsyncSize,
+		`extract the larger media after the webhook, sent with media_status pending, then forward a media_ready event, in bytes --media-async-size <int> | example: --media-async-size=10000000`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappMediaAsyncWorkers,
+		"media-async-workers", "",
+		config.WhatsappMediaAsyncWorkers,
+		`number of concurrent media extractions after the webhook --media-async-workers <int> | example: --media-async-workers=4`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMediaUploadExpiry,
+		"media-upload-expiry", "",
+		config.WhatsappMediaUploadExpiry,
+		`how long the media uploaded with POST /send/media/upload can be sent --media-upload-expiry <duration> | example: --media-upload-expiry=72h`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappGroupListCache,
+		"group-list-cache", "",
+		config.WhatsappGroupListCache,
+		`how long GET /groups answers from the cache, dropped on every group change --group-list-cache <duration> | example: --group-list-cache=5m`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappMessageStoreRetention,
+		"message-store-retention", "",
+		config.WhatsappMessageStoreRetention,
+		`how long the messages are kept to be edited, revoked or forwarded, 0 keeps them forever --message-store-retention <duration> | example: --message-store-retention=720h`,
+	)
+	rootCmd.PersistentFlags().Float64VarP(
+		&config.WhatsappOutboundRate,
+		"outbound-rate", "",
+		config.WhatsappOutboundRate,
+		`sends per minute across every chat, 0 is unlimited --outbound-rate <float> | example: --outbound-rate=30`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappOutboundBurst,
+		"outbound-burst", "",
+		config.WhatsappOutboundBurst,
+		`sends allowed at once before the outbound rate applies --outbound-burst <int> | example: --outbound-burst=5`,
+	)
+	rootCmd.PersistentFlags().Float64VarP(
+		&config.WhatsappOutboundChatRate,
+		"outbound-chat-rate", "",
+		config.WhatsappOutboundChatRate,
+		`sends per minute to the same chat, 0 is unlimited --outbound-chat-rate <float> | example: --outbound-chat-rate=10`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappOutboundChatBurst,
+		"outbound-chat-burst", "",
+		config.WhatsappOutboundChatBurst,
+		`sends allowed at once to the same chat before its rate applies --outbound-chat-burst <int> | example: --outbound-chat-burst=3`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappOutboundMaxWait,
+		"outbound-max-wait", "",
+		config.WhatsappOutboundMaxWait,
+		`sends that would wait longer in the outbound queue are rejected with a 429, 0 waits as long as needed --outbound-max-wait <duration> | example: --outbound-max-wait=30s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappBroadcastDelay,
+		"broadcast-delay", "",
+		config.WhatsappBroadcastDelay,
+		`default pause between two recipients of a broadcast --broadcast-delay <duration> | example: --broadcast-delay=10s`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappBroadcastJitter,
+		"broadcast-jitter", "",
+		config.WhatsappBroadcastJitter,
+		`default random time added to the pause of a broadcast, up to this duration --broadcast-jitter <duration> | example: --broadcast-jitter=5s`,
+	)
+	rootCmd.PersistentFlags().IntVarP(
+		&config.WhatsappSendRetryAttempts,
+		"send-retry-attempts", "",
+		config.WhatsappSendRetryAttempts,
+		`sends lost with the connection are kept and sent again after the reconnection up to this many times, 0 disables it --send-retry-attempts <number> | example: --send-retry-attempts=3`,
+	)
+	rootCmd.PersistentFlags().DurationVarP(
+		&config.WhatsappSendRetryMaxAge,
+		"send-retry-max-age", "",
+		config.WhatsappSendRetryMaxAge,
+		`sends waiting longer for the connection are abandoned --send-retry-max-age <duration> | example: --send-retry-max-age=1h`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappS3Endpoint,
+		"s3-endpoint", "",
+		config.WhatsappS3Endpoint,
+		`s3 compatible endpoint, without the scheme --s3-endpoint <string> | example: --s3-endpoint=minio:9000`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappS3Region,
+		"s3-region", "",
+		config.WhatsappS3Region,
+		`s3 region --s3-region <string> | example: --s3-region=us-east-1`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappS3Bucket,
+		"s3-bucket", "",
+		config.WhatsappS3Bucket,
+		`s3 bucket of the media --s3-bucket <string> | example: --s3-bucket=whatsapp-media`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappS3AccessKey,
+		"s3-access-key", "",
+		config.WhatsappS3AccessKey,
+		`s3 access key --s3-access-key <string> | example: --s3-access-key=minioadmin`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappS3SecretKey,
+		"s3-secret-key", "",
+		config.WhatsappS3SecretKey,
+		`s3 secret key --s3-secret-key <string> | example: --s3-secret-key=minioadmin`,
+	)
+	rootCmd.PersistentFlags().BoolVarP(
+		&config.WhatsappS3UseSSL,
+		"s3-use-ssl", "",
+		config.WhatsappS3UseSSL,
+		`connect to the s3 endpoint over https --s3-use-ssl <true/false> | example: --s3-use-ssl=false`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappGCSBucket,
+		"gcs-bucket", "",
+		config.WhatsappGCSBucket,
+		`google cloud storage bucket of the media --gcs-bucket <string> | example: --gcs-bucket=whatsapp-media`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappGCSCredentialsFile,
+		"gcs-credentials-file", "",
+		config.WhatsappGCSCredentialsFile,
+		`service account key of the gcs bucket, the application default credentials otherwise --gcs-credentials-file <string> | example: --gcs-credentials-file=gcs.json`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAzureConnectionString,
+		"azure-connection-string", "",
+		config.WhatsappAzureConnectionString,
+		`azure storage account connection string --azure-connection-string <string> | example: --azure-connection-string="DefaultEndpointsProtocol=https;AccountName=..."`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAzureAccountName,
+		"azure-account-name", "",
+		config.WhatsappAzureAccountName,
+		`azure storage account name, with --azure-account-key --azure-account-name <string> | example: --azure-account-name=whatsapp`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAzureAccountKey,
+		"azure-account-key", "",
+		config.WhatsappAzureAccountKey,
+		`azure storage account key --azure-account-key <string> | example: --azure-account-key=base64key`,
+	)
+	rootCmd.PersistentFlags().StringVarP(
+		&config.WhatsappAzureContainer,
+		"azure-container", "",
+		config.WhatsappAzureContainer,
+		`azure blob container of the media --azure-container <string> | example: --azure-container=whatsapp-media`,
+	)
 }
 
 func runRest(_ *cobra.Command, _ []string) {
@@ -179,7 +746,7 @@ func runRest(_ *cobra.Command, _ []string) {
 
 	// TODO: Init Rest App
 	//preparing folder if not exist
-	err := utils.CreateFolder(config.PathQrCode, config.PathSendItems, config.PathStorages, config.PathMedia)
+	err := utils.CreateFolder(config.PathQrCode, config.PathSendItems, config.PathStorages, config.PathMedia, config.PathQuarantine)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -215,6 +782,13 @@ func runRest(_ *cobra.Command, _ []string) {
 		AllowHeaders: "Origin, Content-Type, Accept",
 	}))
 
+	if err = whatsapp.ValidateMediaURLConfig(); err != nil {
+		log.Fatalln(err)
+	}
+	if config.WhatsappMediaBaseURL != "" {
+		rest.InitRestSignedMedia(app)
+	}
+
 	if len(config.AppBasicAuthCredential) > 0 {
 		account := make(map[string]string)
 		for _, basicAuth := range config.AppBasicAuthCredential {
@@ -230,6 +804,38 @@ func runRest(_ *cobra.Command, _ []string) {
 		}))
 	}
 
+	if err = whatsapp.InitStore(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.LoadWebhookEndpoints(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.InitMediaStorage(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.InitMediaScanner(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.InitMediaEncryption(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.InitMediaSkipRules(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.ValidateAudioTranscode(config.WhatsappAudioTranscode); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.StartMediaJanitor(); err != nil {
+		log.Fatalln(err)
+	}
+	whatsapp.StartMessageJanitor()
+	if err = whatsapp.StartWebhookDispatcher(); err != nil {
+		log.Fatalln(err)
+	}
+	if err = whatsapp.StartMediaWorkers(); err != nil {
+		log.Fatalln(err)
+	}
+
 	db := whatsapp.InitWaDB()
 	cli := whatsapp.InitWaCLI(db)
 
@@ -238,16 +844,27 @@ func runRest(_ *cobra.Command, _ []string) {
 	sendService := services.NewSendService(cli, appService)
 	userService := services.NewUserService(cli)
 	messageService := services.NewMessageService(cli)
+	chatService := services.NewChatService(cli)
 	groupService := services.NewGroupService(cli)
 	newsletterService := services.NewNewsletterService(cli)
+	webhookService := services.NewWebhookService()
+	mediaService := services.NewMediaService()
 
 	// Rest
 	rest.InitRestApp(app, appService)
 	rest.InitRestSend(app, sendService)
 	rest.InitRestUser(app, userService)
 	rest.InitRestMessage(app, messageService)
+	rest.InitRestChat(app, chatService)
 	rest.InitRestGroup(app, groupService)
 	rest.InitRestNewsletter(app, newsletterService)
+	rest.InitRestWebhook(app, webhookService)
+	rest.InitRestMedia(app, mediaService)
+
+	// Resume the broadcasts interrupted by a restart
+	if err = sendService.ResumeBroadcasts(); err != nil {
+		log.Fatalln(err)
+	}
 
 	app.Get("/", func(c *fiber.Ctx) error {
 		return c.Render("views/index", fiber.Map{
@@ -266,6 +883,8 @@ func runRest(_ *cobra.Command, _ []string) {
 	go helpers.SetAutoConnectAfterBooting(appService)
 	// Set auto reconnect checking
 	go helpers.SetAutoReconnectChecking(cli)
+	// Retry failed webhook deliveries from the outbox
+	go whatsapp.StartWebhookOutboxDispatcher()
 	// Start auto flush chat csv
 	if config.WhatsappChatStorage {
 		go helpers.StartAutoFlushChatStorage()
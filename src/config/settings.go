@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	"go.mau.fi/whatsmeow/proto/waCompanionReg"
 )
 
@@ -18,6 +20,8 @@ var (
 	PathMedia       = "statics/media"
 	PathStorages    = "storages"
 	PathChatStorage = "storages/chat.csv"
+	PathStoreDB     = "storages/app.db"
+	PathQuarantine  = "storages/quarantine"
 
 	DBURI = "file:storages/whatsapp.db?_foreign_keys=on"
 
@@ -33,4 +37,76 @@ var (
 	WhatsappTypeGroup                    = "@g.us"
 	WhatsappAccountValidation            = true
 	WhatsappChatStorage                  = true
+	WhatsappHistorySyncStorage           = true  // dump the history syncs received after pairing in storages
+	WhatsappRerequestFromPhone           = false // ask the phone for the messages the sender couldn't resend
+	WhatsappStickerConvert               = false // also store received stickers as PNG, or GIF when animated
+	WhatsappAudioTranscode               = ""    // also store received audio in this format (mp3, ogg, m4a or wav)
+	WhatsappAudioTranscodeBitrate        = "64k"
+
+	WhatsappMediaStorage                  = "local" // local, s3, gcs or azure, where the received media are stored
+	WhatsappMediaStoragePrefix    string            // prefix of the uploaded object keys, {device} is the device number
+	WhatsappMediaStorageKeepLocal = false           // keep the local copy of uploaded media
+	WhatsappMediaStoragePublicURL string            // base url of a public bucket, signed urls are sent otherwise
+	WhatsappMediaStorageURLExpiry = 24 * time.Hour
+	WhatsappMediaBaseURL          string // public url of this server, signs the urls of the local media
+	WhatsappMediaURLSecret        string // signs the local media urls, required with the base url
+	WhatsappMediaEncryptionKey    string // base64 AES-256 key, encrypts the media files at rest
+	WhatsappS3Endpoint            string
+	WhatsappS3Region              string
+	WhatsappS3Bucket              string
+	WhatsappS3AccessKey           string
+	WhatsappS3SecretKey           string
+	WhatsappS3UseSSL              = true
+	WhatsappGCSBucket             string
+	WhatsappGCSCredentialsFile    string // service account key, the application default credentials otherwise
+	WhatsappAzureConnectionString string
+	WhatsappAzureAccountName      string
+	WhatsappAzureAccountKey       string
+	WhatsappAzureContainer        string
+
+	WhatsappMediaRetentionMaxAge   time.Duration // 0 keeps the media forever
+	WhatsappMediaRetentionMaxSize  int64         // bytes of the media folder, 0 is unlimited
+	WhatsappMediaRetentionRules    []string      // max age per mime type, e.g. video/*=72h
+	WhatsappMediaRetentionInterval = 1 * time.Hour
+	WhatsappMediaDedup             = true  // reuse the file of media already downloaded, found by their hash
+	WhatsappMediaLazyDownload      = false // reference the media in the payloads, downloaded on demand
+	WhatsappMediaScan              string  // clamav or icap, scan the media before they're saved
+	WhatsappMediaScanAddress       string  // tcp://host:3310 or unix:///path for clamav, icap://host:1344/service for icap
+	WhatsappMediaScanTimeout       = 30 * time.Second
+	WhatsappMediaDownloadMaxSize   int64    // bytes, larger media of the webhooks aren't downloaded, 0 is unlimited
+	WhatsappMediaDownloadSkipRules []string // max bytes per mime type, e.g. video/*=50000000, 0 never downloads them
+	WhatsappMediaAsyncSize         int64    // bytes, larger media are extracted after the webhook, 0 waits for every media
+	WhatsappMediaAsyncWorkers      = 2
+
+	WhatsappMediaUploadExpiry = 7 * 24 * time.Hour // how long the media uploaded with POST /send/media/upload are reused
+
+	WhatsappGroupListCache time.Duration // how long GET /groups answers from the cache, 0 fetches the groups every time
+
+	WhatsappMessageStoreRetention = 7 * 24 * time.Hour // how long the messages are kept to be edited, revoked or forwarded, 0 keeps them forever
+
+	WhatsappOutboundRate      = 0.0             // sends per minute across every chat, 0 is unlimited
+	WhatsappOutboundBurst     = 10              // sends allowed at once before the global rate applies
+	WhatsappOutboundChatRate  = 0.0             // sends per minute to the same chat, 0 is unlimited
+	WhatsappOutboundChatBurst = 5               // sends allowed at once to the same chat before its rate applies
+	WhatsappOutboundMaxWait   = 2 * time.Minute // longer waits in the outbound queue are rejected
+
+	WhatsappBroadcastDelay  = 5 * time.Second // default pause between two recipients of a broadcast
+	WhatsappBroadcastJitter = 3 * time.Second // default random time added to the pause, up to this duration
+
+	WhatsappSendRetryAttempts = 5              // sends lost with the connection are sent again up to this many times, 0 fails them right away
+	WhatsappSendRetryMaxAge   = 24 * time.Hour // older sends waiting for the connection are abandoned
+
+	WhatsappWebhookConfig            string             // path to structured webhook config (yaml/json)
+	WhatsappWebhookOutboxMaxAttempts = 10               // retries of a failed delivery before it becomes a dead letter
+	WhatsappWebhookOutboxInterval    = 5 * time.Second  // how often the outbox is checked for due retries
+	WhatsappWebhookOutboxBaseDelay   = 30 * time.Second // delay before the first retry, doubled for every next one
+	WhatsappWebhookOutboxMaxDelay    = 1 * time.Hour    // the doubled delay never exceeds this
+	WhatsappWebhookQueueSize         = 1000
+	WhatsappWebhookWorkers           = 4
+	WhatsappWebhookQueueOverflow     = "block" // block, drop_newest or drop_oldest
+	WhatsappWebhookFromMe            = true    // forward the messages sent from this account too
+	WhatsappWebhookPresence          = false   // forward presence and chat presence (typing, recording) events
+	WhatsappWebhookHistorySync       = false   // forward the history syncs received after pairing
+	WhatsappWebhookHistorySyncChunk  = 100     // messages per history_sync webhook
+	WhatsappWebhookPictureDownload   = false   // download the new picture of picture events
 )
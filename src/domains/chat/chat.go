@@ -0,0 +1,43 @@
+package chat
+
+import "context"
+
+type IChatService interface {
+	MarkAsRead(ctx context.Context, request MarkAsReadRequest) (response MarkAsReadResponse, err error)
+	SendPresence(ctx context.Context, request PresenceRequest) (response PresenceResponse, err error)
+	SetDisappearingTimer(ctx context.Context, request DisappearingTimerRequest) (response DisappearingTimerResponse, err error)
+}
+
+type MarkAsReadRequest struct {
+	JID        string   `json:"jid" uri:"jid"`
+	MessageIDs []string `json:"message_ids" form:"message_ids"`
+	Sender     string   `json:"sender" form:"sender"` // author of the message_ids in groups, when they aren't stored
+	Until      int64    `json:"until" form:"until"`   // unix timestamp, reads the stored messages received until then
+}
+
+type MarkAsReadResponse struct {
+	JID        string   `json:"jid"`
+	MessageIDs []string `json:"message_ids"`
+}
+
+type PresenceRequest struct {
+	JID      string `json:"jid" uri:"jid"`
+	State    string `json:"state" form:"state"`       // composing, recording or paused
+	Duration int    `json:"duration" form:"duration"` // seconds before reverting to paused, 0 keeps the state
+}
+
+type PresenceResponse struct {
+	JID      string `json:"jid"`
+	State    string `json:"state"`
+	Duration int    `json:"duration"`
+}
+
+type DisappearingTimerRequest struct {
+	JID   string `json:"jid" uri:"jid"`
+	Timer string `json:"timer" form:"timer"` // off, 24h, 7d or 90d
+}
+
+type DisappearingTimerResponse struct {
+	JID   string `json:"jid"`
+	Timer string `json:"timer"`
+}
@@ -2,6 +2,7 @@ package group
 
 import (
 	"context"
+	"mime/multipart"
 	"time"
 
 	"go.mau.fi/whatsmeow"
@@ -10,10 +11,23 @@ import (
 type IGroupService interface {
 	JoinGroupWithLink(ctx context.Context, request JoinGroupWithLinkRequest) (groupID string, err error)
 	LeaveGroup(ctx context.Context, request LeaveGroupRequest) (err error)
-	CreateGroup(ctx context.Context, request CreateGroupRequest) (groupID string, err error)
+	CreateGroup(ctx context.Context, request CreateGroupRequest) (response CreateGroupResponse, err error)
 	ManageParticipant(ctx context.Context, request ParticipantRequest) (result []ParticipantStatus, err error)
 	GetGroupRequestParticipants(ctx context.Context, request GetGroupRequestParticipantsRequest) (result []GetGroupRequestParticipantsResponse, err error)
 	ManageGroupRequestParticipants(ctx context.Context, request GroupRequestParticipantsRequest) (result []ParticipantStatus, err error)
+	SetGroupSubject(ctx context.Context, request SetGroupSubjectRequest) (err error)
+	SetGroupDescription(ctx context.Context, request SetGroupDescriptionRequest) (err error)
+	SetGroupPhoto(ctx context.Context, request SetGroupPhotoRequest) (pictureID string, err error)
+	RemoveGroupPhoto(ctx context.Context, request RemoveGroupPhotoRequest) (err error)
+	UpdateGroupSettings(ctx context.Context, request GroupSettingsRequest) (response GroupSettings, err error)
+	ListGroups(ctx context.Context, request ListGroupsRequest) (response ListGroupsResponse, err error)
+	CreateCommunity(ctx context.Context, request CreateCommunityRequest) (response CreateCommunityResponse, err error)
+	LinkCommunityGroup(ctx context.Context, request CommunityGroupRequest) (err error)
+	UnlinkCommunityGroup(ctx context.Context, request CommunityGroupRequest) (err error)
+	ListCommunityGroups(ctx context.Context, request CommunityRequest) (result []CommunityGroup, err error)
+	ListCommunityParticipants(ctx context.Context, request CommunityRequest) (result []string, err error)
+	GetGroupInviteInfo(ctx context.Context, request GroupInviteInfoRequest) (response GroupInviteInfo, err error)
+	ExportParticipants(ctx context.Context, request ExportParticipantsRequest) (result []ExportedParticipant, err error)
 }
 
 type JoinGroupWithLinkRequest struct {
@@ -25,8 +39,16 @@ type LeaveGroupRequest struct {
 }
 
 type CreateGroupRequest struct {
-	Title        string   `json:"title" form:"title"`
-	Participants []string `json:"participants" form:"participants"`
+	Title        string                `json:"title" form:"title"`
+	Participants []string              `json:"participants" form:"participants"`
+	Description  string                `json:"description" form:"description"`
+	Photo        *multipart.FileHeader `json:"photo" form:"photo"`
+}
+
+type CreateGroupResponse struct {
+	GroupID      string              `json:"group_id"`
+	Participants []ParticipantStatus `json:"participants"`
+	Warnings     []string            `json:"warnings,omitempty"` // the description or photo failed, the group is created
 }
 
 type ParticipantRequest struct {
@@ -39,6 +61,9 @@ type ParticipantStatus struct {
 	Participant string `json:"participant"`
 	Status      string `json:"status"`
 	Message     string `json:"message"`
+	Code        int    `json:"code,omitempty"` // status code of WhatsApp, e.g. 403 when the user only accepts invites
+	InviteOnly  bool   `json:"invite_only,omitempty"`
+	InviteLink  string `json:"invite_link,omitempty"` // the link to send to the users who only accept invites
 }
 
 type GetGroupRequestParticipantsRequest struct {
@@ -55,3 +80,130 @@ type GroupRequestParticipantsRequest struct {
 	Participants []string                           `json:"participants" form:"participants"`
 	Action       whatsmeow.ParticipantRequestChange `json:"action" form:"action"`
 }
+
+type SetGroupSubjectRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+	Subject string `json:"subject" form:"subject"`
+}
+
+type SetGroupDescriptionRequest struct {
+	GroupID     string `json:"group_id" form:"group_id"`
+	Description string `json:"description" form:"description"` // empty removes the description
+}
+
+type SetGroupPhotoRequest struct {
+	GroupID string                `json:"group_id" form:"group_id"`
+	Photo   *multipart.FileHeader `json:"photo" form:"photo"`
+}
+
+type RemoveGroupPhotoRequest struct {
+	GroupID string `json:"group_id" form:"group_id"`
+}
+
+// GroupSettingsRequest changes the settings given, the ones left out are kept
+type GroupSettingsRequest struct {
+	GroupID       string `json:"group_id" form:"group_id"`
+	Announce      *bool  `json:"announce" form:"announce"`               // only admins can send messages
+	Locked        *bool  `json:"locked" form:"locked"`                   // only admins can edit the group info
+	JoinApproval  *bool  `json:"join_approval" form:"join_approval"`     // admins approve who joins with the link
+	MemberAddMode string `json:"member_add_mode" form:"member_add_mode"` // admin_add or all_member_add
+}
+
+type GroupSettings struct {
+	GroupID           string `json:"group_id,omitempty"`
+	Announce          bool   `json:"announce"`
+	Locked            bool   `json:"locked"`
+	JoinApproval      bool   `json:"join_approval"`
+	MemberAddMode     string `json:"member_add_mode"`
+	DisappearingTimer string `json:"disappearing_timer"` // default expiration of the new messages: off, 24h, 7d or 90d
+}
+
+type ListGroupsRequest struct {
+	Limit   int  `json:"limit" query:"limit"`
+	Offset  int  `json:"offset" query:"offset"`
+	Refresh bool `json:"refresh" query:"refresh"` // skip the cache of --group-list-cache
+}
+
+type ListGroupsResponse struct {
+	Total  int           `json:"total"`
+	Limit  int           `json:"limit"`
+	Offset int           `json:"offset"`
+	Groups []GroupDetail `json:"groups"`
+}
+
+type GroupDetail struct {
+	GroupID      string             `json:"group_id"`
+	Subject      string             `json:"subject"`
+	Description  string             `json:"description"`
+	Owner        string             `json:"owner,omitempty"`
+	CreatedAt    time.Time          `json:"created_at"`
+	IsCommunity  bool               `json:"is_community"`
+	CommunityID  string             `json:"community_id,omitempty"` // the community the group is linked to
+	Participants []GroupParticipant `json:"participants"`
+	Settings     GroupSettings      `json:"settings"`
+}
+
+type GroupParticipant struct {
+	JID          string `json:"jid"`
+	PhoneNumber  string `json:"phone_number,omitempty"`
+	DisplayName  string `json:"display_name,omitempty"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+type CreateCommunityRequest struct {
+	Name        string `json:"name" form:"name"`
+	Description string `json:"description" form:"description"`
+	// JoinApproval makes the admins approve who joins the community
+	JoinApproval bool `json:"join_approval" form:"join_approval"`
+}
+
+type CreateCommunityResponse struct {
+	CommunityID string   `json:"community_id"`
+	Warnings    []string `json:"warnings,omitempty"` // the description failed, the community is created
+}
+
+type CommunityRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+}
+
+type CommunityGroupRequest struct {
+	CommunityID string `json:"community_id" form:"community_id"`
+	GroupID     string `json:"group_id" form:"group_id"`
+}
+
+type CommunityGroup struct {
+	GroupID        string `json:"group_id"`
+	Name           string `json:"name"`
+	IsAnnouncement bool   `json:"is_announcement"` // the announcement group every member of the community is in
+}
+
+type GroupInviteInfoRequest struct {
+	Code string `json:"code" query:"code"` // the code of the invite link, or the whole link
+}
+
+type GroupInviteInfo struct {
+	GroupID      string    `json:"group_id"`
+	Name         string    `json:"name"`
+	Description  string    `json:"description"`
+	Size         int       `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+	Owner        string    `json:"owner,omitempty"`
+	IsCommunity  bool      `json:"is_community"`
+	CommunityID  string    `json:"community_id,omitempty"`
+	JoinApproval bool      `json:"join_approval"`
+}
+
+type ExportParticipantsRequest struct {
+	GroupID string `json:"group_id" query:"group_id"`
+	Format  string `json:"format" query:"format"` // csv or json, json by default
+}
+
+type ExportedParticipant struct {
+	JID          string     `json:"jid"`
+	Phone        string     `json:"phone"`
+	PushName     string     `json:"pushname"`
+	IsAdmin      bool       `json:"is_admin"`
+	IsSuperAdmin bool       `json:"is_super_admin"`
+	JoinedAt     *time.Time `json:"joined_at"` // only the joins seen by this device are known
+}
@@ -0,0 +1,33 @@
+package media
+
+import (
+	"context"
+	"time"
+)
+
+type IMediaService interface {
+	Cleanup(ctx context.Context) (response CleanupReport, err error)
+	CleanupStatus(ctx context.Context) (response CleanupStatus, err error)
+}
+
+type CleanupReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Scanned        int       `json:"scanned"`
+	Deleted        int       `json:"deleted"`
+	FreedBytes     int64     `json:"freed_bytes"`
+	RemainingBytes int64     `json:"remaining_bytes"`
+	Error          string    `json:"error,omitempty"`
+}
+
+type RetentionPolicy struct {
+	MaxAge   string   `json:"max_age,omitempty"`
+	MaxSize  int64    `json:"max_size,omitempty"`
+	Rules    []string `json:"rules,omitempty"`
+	Interval string   `json:"interval"`
+}
+
+type CleanupStatus struct {
+	Policy      RetentionPolicy `json:"policy"`
+	LastCleanup *CleanupReport  `json:"last_cleanup"`
+}
@@ -9,6 +9,10 @@ type IMessageService interface {
 	UpdateMessage(ctx context.Context, request UpdateMessageRequest) (response GenericResponse, err error)
 	DeleteMessage(ctx context.Context, request DeleteRequest) (err error)
 	StarMessage(ctx context.Context, request StarRequest) (err error)
+	PinMessage(ctx context.Context, request PinRequest) (response GenericResponse, err error)
+	KeepMessage(ctx context.Context, request KeepRequest) (response GenericResponse, err error)
+	DownloadMedia(ctx context.Context, request DownloadMediaRequest) (response DownloadMediaResponse, err error)
+	ForwardMessage(ctx context.Context, request ForwardRequest) (response ForwardResponse, err error)
 }
 
 type GenericResponse struct {
@@ -19,6 +23,7 @@ type GenericResponse struct {
 type RevokeRequest struct {
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
+	Sender    string `json:"sender" form:"sender"` // author of the message, group admins can revoke the messages of others
 }
 
 type DeleteRequest struct {
@@ -29,7 +34,8 @@ type DeleteRequest struct {
 type ReactionRequest struct {
 	MessageID string `json:"message_id" form:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
-	Emoji     string `json:"emoji" form:"emoji"`
+	Sender    string `json:"sender" form:"sender"` // author of the message, looked up in the message store when empty
+	Emoji     string `json:"emoji" form:"emoji"`   // empty removes the reaction
 }
 
 type UpdateMessageRequest struct {
@@ -46,5 +52,55 @@ type MarkAsReadRequest struct {
 type StarRequest struct {
 	MessageID string `json:"message_id" uri:"message_id"`
 	Phone     string `json:"phone" form:"phone"`
+	Sender    string `json:"sender" form:"sender"` // author of the message, looked up in the message store when empty
 	IsStarred bool   `json:"is_starred"`
 }
+
+type PinRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone" form:"phone"`
+	Sender    string `json:"sender" form:"sender"`     // author of the message, looked up in the message store when empty
+	Duration  string `json:"duration" form:"duration"` // 24h, 7d or 30d, 7d when empty
+	IsPinned  bool   `json:"-"`
+}
+
+type KeepRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone" form:"phone"`
+	Sender    string `json:"sender" form:"sender"` // author of the message, looked up in the message store when empty
+	IsKept    bool   `json:"-"`
+}
+
+type DownloadMediaRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+type DownloadMediaResponse struct {
+	MessageID         string `json:"message_id"`
+	MediaPath         string `json:"media_path"`
+	MediaURL          string `json:"media_url,omitempty"`
+	MediaKey          string `json:"media_key,omitempty"`
+	MimeType          string `json:"mime_type"`
+	Caption           string `json:"caption"`
+	ConvertedPath     string `json:"converted_path,omitempty"`
+	ConvertedURL      string `json:"converted_url,omitempty"`
+	ConvertedKey      string `json:"converted_key,omitempty"`
+	ConvertedMimeType string `json:"converted_mime_type,omitempty"`
+}
+
+type ForwardRequest struct {
+	MessageID string   `json:"message_id" uri:"message_id"`
+	Phone     string   `json:"phone" form:"phone"` // chat of the message
+	To        []string `json:"to" form:"to"`
+}
+
+type ForwardResponse struct {
+	MessageID string          `json:"message_id"`
+	Results   []ForwardResult `json:"results"`
+}
+
+type ForwardResult struct {
+	Phone     string `json:"phone"`
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
@@ -1,11 +1,27 @@
 package newsletter
 
-import "context"
+import (
+	"context"
+	"mime/multipart"
+)
 
 type INewsletterService interface {
 	Unfollow(ctx context.Context, request UnfollowRequest) (err error)
+	Post(ctx context.Context, request PostRequest) (response PostResponse, err error)
 }
 
 type UnfollowRequest struct {
 	NewsletterID string `json:"newsletter_id" form:"newsletter_id"`
 }
+
+type PostRequest struct {
+	NewsletterID string                `json:"newsletter_id" form:"newsletter_id"`
+	Message      string                `json:"message" form:"message"` // text of the post, or the caption of the media
+	Media        *multipart.FileHeader `json:"media" form:"media"`     // image or video
+}
+
+type PostResponse struct {
+	MessageID string `json:"message_id"`
+	ServerID  int    `json:"server_id"` // ID of the post in the newsletter, used by its reactions and views
+	Status    string `json:"status"`
+}
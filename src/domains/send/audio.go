@@ -4,6 +4,13 @@ import "mime/multipart"
 
 type AudioRequest struct {
 	Phone       string                `json:"phone" form:"phone"`
+	Phones      []string              `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority    string                `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
 	Audio       *multipart.FileHeader `json:"audio" form:"audio"`
+	MediaID     string                `json:"media_id" form:"media_id"`
 	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	PTT         bool                  `json:"ptt" form:"ptt"` // send as a voice note, transcoded to Opus with its waveform
+	ViewOnce    bool                  `json:"view_once" form:"view_once"`
+	ReplyTo     *ReplyTo              `json:"reply_to" form:"reply_to"`
+	Ephemeral   string                `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
@@ -0,0 +1,39 @@
+package send
+
+type BroadcastRequest struct {
+	Recipients   []BroadcastRecipient `json:"recipients"`
+	Message      string               `json:"message"`       // template, {{name}} is replaced by the variable of the recipient
+	TemplateName string               `json:"template_name"` // stored template sent instead of the message
+	MediaID      string               `json:"media_id"`      // image, video or document of POST /send/media/upload, the message is its caption
+	Delay        *int                 `json:"delay"`         // seconds between two recipients, the --broadcast-delay otherwise
+	Jitter       *int                 `json:"jitter"`        // up to these random seconds added to the delay, the --broadcast-jitter otherwise
+}
+
+type BroadcastRecipient struct {
+	Phone     string            `json:"phone"`
+	Variables map[string]string `json:"variables"`
+}
+
+type GetBroadcastRequest struct {
+	BroadcastID string `json:"broadcast_id" uri:"broadcast_id"`
+}
+
+type BroadcastResponse struct {
+	BroadcastID string                       `json:"broadcast_id"`
+	Status      string                       `json:"status"` // running or completed
+	Total       int                          `json:"total"`
+	Pending     int                          `json:"pending"`
+	Sent        int                          `json:"sent"`
+	Failed      int                          `json:"failed"`
+	CreatedAt   string                       `json:"created_at"`
+	CompletedAt string                       `json:"completed_at,omitempty"`
+	Recipients  []BroadcastRecipientResponse `json:"recipients"`
+}
+
+type BroadcastRecipientResponse struct {
+	Phone     string `json:"phone"`
+	Status    string `json:"status"` // pending, sent or failed
+	MessageID string `json:"message_id,omitempty"`
+	Error     string `json:"error,omitempty"`
+	SentAt    string `json:"sent_at,omitempty"`
+}
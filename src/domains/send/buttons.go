@@ -0,0 +1,20 @@
+package send
+
+type ButtonsRequest struct {
+	Phone           string       `json:"phone" form:"phone"`
+	Phones          []string     `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority        string       `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Body            string       `json:"body" form:"body"`
+	Footer          string       `json:"footer" form:"footer"`
+	Header          string       `json:"header" form:"header"`                       // text header
+	HeaderMediaID   string       `json:"header_media_id" form:"header_media_id"`     // media header, uploaded with POST /send/media/upload
+	HeaderMediaType string       `json:"header_media_type" form:"header_media_type"` // image, video or document
+	Buttons         []ButtonItem `json:"buttons" form:"buttons"`
+	ReplyTo         *ReplyTo     `json:"reply_to" form:"reply_to"`
+	Ephemeral       string       `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type ButtonItem struct {
+	ID   string `json:"id" form:"id"`
+	Text string `json:"text" form:"text"`
+}
@@ -0,0 +1,28 @@
+package send
+
+type CarouselRequest struct {
+	Phone     string         `json:"phone" form:"phone"`
+	Phones    []string       `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority  string         `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Body      string         `json:"body" form:"body"`         // text above the cards
+	Footer    string         `json:"footer" form:"footer"`
+	Cards     []CarouselCard `json:"cards" form:"cards"`
+	ReplyTo   *ReplyTo       `json:"reply_to" form:"reply_to"`
+	Ephemeral string         `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type CarouselCard struct {
+	MediaID string           `json:"media_id" form:"media_id"` // image or video uploaded with POST /send/media/upload, the same type on every card
+	Title   string           `json:"title" form:"title"`
+	Body    string           `json:"body" form:"body"`
+	Footer  string           `json:"footer" form:"footer"`
+	Buttons []CarouselButton `json:"buttons" form:"buttons"`
+}
+
+type CarouselButton struct {
+	Type        string `json:"type" form:"type"` // reply, url or call
+	ID          string `json:"id" form:"id"`     // returned in the button_reply of the webhook, for reply buttons
+	Text        string `json:"text" form:"text"`
+	URL         string `json:"url" form:"url"`
+	PhoneNumber string `json:"phone_number" form:"phone_number"`
+}
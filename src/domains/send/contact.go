@@ -1,8 +1,21 @@
 package send
 
 type ContactRequest struct {
+	Phone        string        `json:"phone" form:"phone"`
+	Phones       []string      `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority     string        `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	ContactName  string        `json:"contact_name" form:"contact_name"`
+	ContactPhone string        `json:"contact_phone" form:"contact_phone"`
+	Contacts     []ContactCard `json:"contacts" form:"contacts"` // several contacts sent in one message, instead of contact_name and contact_phone
+	IsForwarded  bool          `json:"is_forwarded" form:"is_forwarded"`
+	ReplyTo      *ReplyTo      `json:"reply_to" form:"reply_to"`
+	Ephemeral    string        `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type ContactCard struct {
+	Name         string `json:"name" form:"name"`
 	Phone        string `json:"phone" form:"phone"`
-	ContactName  string `json:"contact_name" form:"contact_name"`
-	ContactPhone string `json:"contact_phone" form:"contact_phone"`
-	IsForwarded  bool   `json:"is_forwarded" form:"is_forwarded"`
+	Organization string `json:"organization" form:"organization"`
+	Email        string `json:"email" form:"email"`
+	VCard        string `json:"vcard" form:"vcard"` // raw vCard, sent as is instead of the other fields
 }
@@ -4,7 +4,14 @@ import "mime/multipart"
 
 type FileRequest struct {
 	Phone       string                `json:"phone" form:"phone"`
+	Phones      []string              `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority    string                `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
 	File        *multipart.FileHeader `json:"file" form:"file"`
+	MediaID     string                `json:"media_id" form:"media_id"`
 	Caption     string                `json:"caption" form:"caption"`
+	Thumbnail   *multipart.FileHeader `json:"thumbnail" form:"thumbnail"` // JPEG or PNG preview, the first page of a PDF otherwise
 	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	Mentions    []string              `json:"mentions" form:"mentions"`
+	ReplyTo     *ReplyTo              `json:"reply_to" form:"reply_to"`
+	Ephemeral   string                `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
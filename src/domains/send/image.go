@@ -4,10 +4,16 @@ import "mime/multipart"
 
 type ImageRequest struct {
 	Phone       string                `json:"phone" form:"phone"`
+	Phones      []string              `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority    string                `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
 	Caption     string                `json:"caption" form:"caption"`
 	Image       *multipart.FileHeader `json:"image" form:"image"`
+	MediaID     string                `json:"media_id" form:"media_id"`
 	ImageURL    *string               `json:"image_url" form:"image_url"`
 	ViewOnce    bool                  `json:"view_once" form:"view_once"`
 	Compress    bool                  `json:"compress"`
 	IsForwarded bool                  `json:"is_forwarded" form:"is_forwarded"`
+	Mentions    []string              `json:"mentions" form:"mentions"`
+	ReplyTo     *ReplyTo              `json:"reply_to" form:"reply_to"`
+	Ephemeral   string                `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
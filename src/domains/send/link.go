@@ -1,8 +1,12 @@
 package send
 
 type LinkRequest struct {
-	Phone       string `json:"phone" form:"phone"`
-	Caption     string `json:"caption"`
-	Link        string `json:"link"`
-	IsForwarded bool   `json:"is_forwarded" form:"is_forwarded"`
+	Phone       string   `json:"phone" form:"phone"`
+	Phones      []string `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority    string   `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Caption     string   `json:"caption"`
+	Link        string   `json:"link"`
+	IsForwarded bool     `json:"is_forwarded" form:"is_forwarded"`
+	ReplyTo     *ReplyTo `json:"reply_to" form:"reply_to"`
+	Ephemeral   string   `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
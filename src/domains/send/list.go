@@ -0,0 +1,25 @@
+package send
+
+type ListRequest struct {
+	Phone      string        `json:"phone" form:"phone"`
+	Phones     []string      `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority   string        `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Title      string        `json:"title" form:"title"`
+	Body       string        `json:"body" form:"body"`
+	Footer     string        `json:"footer" form:"footer"`
+	ButtonText string        `json:"button_text" form:"button_text"` // text of the button opening the menu
+	Sections   []ListSection `json:"sections" form:"sections"`
+	ReplyTo    *ReplyTo      `json:"reply_to" form:"reply_to"`
+	Ephemeral  string        `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type ListSection struct {
+	Title string    `json:"title" form:"title"`
+	Rows  []ListRow `json:"rows" form:"rows"`
+}
+
+type ListRow struct {
+	ID          string `json:"id" form:"id"`
+	Title       string `json:"title" form:"title"`
+	Description string `json:"description" form:"description"`
+}
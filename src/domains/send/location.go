@@ -1,8 +1,32 @@
 package send
 
 type LocationRequest struct {
-	Phone       string `json:"phone" form:"phone"`
-	Latitude    string `json:"latitude" form:"latitude"`
-	Longitude   string `json:"longitude" form:"longitude"`
-	IsForwarded bool   `json:"is_forwarded" form:"is_forwarded"`
+	Phone        string   `json:"phone" form:"phone"`
+	Phones       []string `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority     string   `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Latitude     string   `json:"latitude" form:"latitude"`
+	Longitude    string   `json:"longitude" form:"longitude"`
+	Name         string   `json:"name" form:"name"`
+	Address      string   `json:"address" form:"address"`
+	ThumbnailURL string   `json:"thumbnail_url" form:"thumbnail_url"` // image of the place shown in the chat
+	IsForwarded  bool     `json:"is_forwarded" form:"is_forwarded"`
+	ReplyTo      *ReplyTo `json:"reply_to" form:"reply_to"`
+	Ephemeral    string   `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type LiveLocationRequest struct {
+	MessageID string  `json:"message_id" uri:"message_id"` // live location to update, empty to start one
+	Phone     string  `json:"phone" form:"phone"`
+	Latitude  string  `json:"latitude" form:"latitude"`
+	Longitude string  `json:"longitude" form:"longitude"`
+	Accuracy  uint32  `json:"accuracy" form:"accuracy"` // meters
+	Speed     float32 `json:"speed" form:"speed"`       // meters per second
+	Heading   uint32  `json:"heading" form:"heading"`   // degrees clockwise from the magnetic north
+	Caption   string  `json:"caption" form:"caption"`
+	Duration  int     `json:"duration" form:"duration"` // seconds the location is shared for, when starting
+}
+
+type StopLiveLocationRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+	Phone     string `json:"phone" form:"phone"`
 }
@@ -0,0 +1,21 @@
+package send
+
+import "mime/multipart"
+
+type UploadMediaRequest struct {
+	Type  string                `json:"type" form:"type"` // image, video, audio or document
+	Media *multipart.FileHeader `json:"media" form:"media"`
+}
+
+type UploadMediaResponse struct {
+	MediaID    string `json:"media_id"`
+	Type       string `json:"type"`
+	MimeType   string `json:"mime_type"`
+	FileName   string `json:"file_name"`
+	FileLength uint64 `json:"file_length"`
+	FileSHA256 string `json:"file_sha256"`
+	DirectPath string `json:"direct_path"`
+	MediaKey   []byte `json:"media_key"`
+	ExpiresAt  string `json:"expires_at"`
+	Cached     bool   `json:"cached"` // the same file was already uploaded, its handle is reused
+}
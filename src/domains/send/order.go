@@ -0,0 +1,24 @@
+package send
+
+type OrderRequest struct {
+	Phone            string      `json:"phone" form:"phone"`
+	Phones           []string    `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority         string      `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	OrderID          string      `json:"order_id" form:"order_id"` // generated when empty
+	Title            string      `json:"title" form:"title"`
+	Note             string      `json:"note" form:"note"` // shown under the invoice
+	CurrencyCode     string      `json:"currency_code" form:"currency_code"`
+	Items            []OrderItem `json:"items" form:"items"`
+	Tax              float64     `json:"tax" form:"tax"`
+	Shipping         float64     `json:"shipping" form:"shipping"`
+	Discount         float64     `json:"discount" form:"discount"`
+	ThumbnailMediaID string      `json:"thumbnail_media_id" form:"thumbnail_media_id"` // image uploaded with POST /send/media/upload
+	ReplyTo          *ReplyTo    `json:"reply_to" form:"reply_to"`
+	Ephemeral        string      `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type OrderItem struct {
+	Name     string  `json:"name" form:"name"`
+	Price    float64 `json:"price" form:"price"` // unit price
+	Quantity int     `json:"quantity" form:"quantity"`
+}
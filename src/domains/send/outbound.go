@@ -0,0 +1,27 @@
+package send
+
+type OutboundStatusResponse struct {
+	Queued        int            `json:"queued"` // sends waiting for the rate limits
+	Chats         map[string]int `json:"chats"`  // sends waiting per chat
+	Lanes         map[string]int `json:"lanes"`  // sends waiting per priority
+	Sent          int64          `json:"sent"`
+	Rejected      int64          `json:"rejected"`
+	Flushed       int64          `json:"flushed"`
+	Draining      bool           `json:"draining"`
+	RatePerMinute float64        `json:"rate_per_minute"`
+	Burst         int            `json:"burst"`
+	ChatRate      float64        `json:"chat_rate_per_minute"`
+	ChatBurst     int            `json:"chat_burst"`
+}
+
+type DrainOutboundRequest struct {
+	Timeout int `json:"timeout" query:"timeout"` // seconds to wait for the queue to empty, 60 by default
+}
+
+type FlushOutboundResponse struct {
+	Cancelled int `json:"cancelled"`
+}
+
+type DrainOutboundResponse struct {
+	Drained int `json:"drained"`
+}
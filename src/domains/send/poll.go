@@ -2,7 +2,11 @@ package send
 
 type PollRequest struct {
 	Phone     string   `json:"phone" form:"phone"`
+	Phones    []string `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority  string   `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
 	Question  string   `json:"question" form:"question"`
 	Options   []string `json:"options" form:"options"`
 	MaxAnswer int      `json:"max_answer" form:"max_answer"`
+	ReplyTo   *ReplyTo `json:"reply_to" form:"reply_to"`
+	Ephemeral string   `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
@@ -0,0 +1,45 @@
+package send
+
+import "mime/multipart"
+
+type ProductRequest struct {
+	Phone            string                `json:"phone" form:"phone"`
+	Phones           []string              `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority         string                `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	ProductID        string                `json:"product_id" form:"product_id"`
+	BusinessOwnerJID string                `json:"business_owner_jid" form:"business_owner_jid"` // owner of the catalog, this account otherwise
+	Title            string                `json:"title" form:"title"`
+	Description      string                `json:"description" form:"description"`
+	CurrencyCode     string                `json:"currency_code" form:"currency_code"` // ISO 4217, e.g. IDR
+	Price            float64               `json:"price" form:"price"`
+	SalePrice        float64               `json:"sale_price" form:"sale_price"`
+	RetailerID       string                `json:"retailer_id" form:"retailer_id"` // SKU of the product in the shop
+	URL              string                `json:"url" form:"url"`
+	Image            *multipart.FileHeader `json:"image" form:"image"`
+	MediaID          string                `json:"media_id" form:"media_id"` // image uploaded with POST /send/media/upload
+	Body             string                `json:"body" form:"body"`
+	Footer           string                `json:"footer" form:"footer"`
+	ReplyTo          *ReplyTo              `json:"reply_to" form:"reply_to"`
+	Ephemeral        string                `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type ProductListRequest struct {
+	Phone            string           `json:"phone" form:"phone"`
+	Phones           []string         `json:"phones" form:"phones"`                         // sent to each of them instead of phone
+	Priority         string           `json:"priority" form:"priority"`                     // high, normal or low lane of the outbound queue
+	BusinessOwnerJID string           `json:"business_owner_jid" form:"business_owner_jid"` // owner of the catalog, this account otherwise
+	Title            string           `json:"title" form:"title"`
+	Body             string           `json:"body" form:"body"`
+	Footer           string           `json:"footer" form:"footer"`
+	ButtonText       string           `json:"button_text" form:"button_text"`             // text of the button opening the products
+	HeaderProductID  string           `json:"header_product_id" form:"header_product_id"` // product shown above the list, the first one otherwise
+	HeaderMediaID    string           `json:"header_media_id" form:"header_media_id"`     // image uploaded with POST /send/media/upload, its thumbnail heads the list
+	Sections         []ProductSection `json:"sections" form:"sections"`
+	ReplyTo          *ReplyTo         `json:"reply_to" form:"reply_to"`
+	Ephemeral        string           `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
+
+type ProductSection struct {
+	Title      string   `json:"title" form:"title"`
+	ProductIDs []string `json:"product_ids" form:"product_ids"`
+}
@@ -0,0 +1,7 @@
+package send
+
+// ReplyTo quotes a message of the chat. The participant is its sender, only needed when the message isn't stored.
+type ReplyTo struct {
+	MessageID   string `json:"message_id" form:"message_id"`
+	Participant string `json:"participant" form:"participant"`
+}
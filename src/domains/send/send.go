@@ -12,12 +12,52 @@ type ISendService interface {
 	SendContact(ctx context.Context, request ContactRequest) (response GenericResponse, err error)
 	SendLink(ctx context.Context, request LinkRequest) (response GenericResponse, err error)
 	SendLocation(ctx context.Context, request LocationRequest) (response GenericResponse, err error)
+	SendLiveLocation(ctx context.Context, request LiveLocationRequest) (response GenericResponse, err error)
+	StopLiveLocation(ctx context.Context, request StopLiveLocationRequest) (response GenericResponse, err error)
 	SendAudio(ctx context.Context, request AudioRequest) (response GenericResponse, err error)
+	SendSticker(ctx context.Context, request StickerRequest) (response GenericResponse, err error)
 	SendPoll(ctx context.Context, request PollRequest) (response GenericResponse, err error)
+	SendButtons(ctx context.Context, request ButtonsRequest) (response GenericResponse, err error)
+	SendList(ctx context.Context, request ListRequest) (response GenericResponse, err error)
+	SendCarousel(ctx context.Context, request CarouselRequest) (response GenericResponse, err error)
+	SendProduct(ctx context.Context, request ProductRequest) (response GenericResponse, err error)
+	SendProductList(ctx context.Context, request ProductListRequest) (response GenericResponse, err error)
+	SendOrder(ctx context.Context, request OrderRequest) (response GenericResponse, err error)
 	SendPresence(ctx context.Context, request PresenceRequest) (response GenericResponse, err error)
+	SendStatusText(ctx context.Context, request StatusTextRequest) (response GenericResponse, err error)
+	SendStatusMedia(ctx context.Context, request StatusMediaRequest) (response GenericResponse, err error)
+	ListStatuses(ctx context.Context) (response StatusListResponse, err error)
+	GetStatusViewers(ctx context.Context, request StatusViewersRequest) (response StatusViewersResponse, err error)
+	CreateTemplate(ctx context.Context, request TemplateRequest) (response TemplateResponse, err error)
+	UpdateTemplate(ctx context.Context, request TemplateRequest) (response TemplateResponse, err error)
+	GetTemplate(ctx context.Context, request TemplateNameRequest) (response TemplateResponse, err error)
+	ListTemplates(ctx context.Context) (response []TemplateResponse, err error)
+	DeleteTemplate(ctx context.Context, request TemplateNameRequest) (err error)
+	SendTemplate(ctx context.Context, request SendTemplateRequest) (response GenericResponse, err error)
+	SendBroadcast(ctx context.Context, request BroadcastRequest) (response BroadcastResponse, err error)
+	GetBroadcast(ctx context.Context, request GetBroadcastRequest) (response BroadcastResponse, err error)
+	ResumeBroadcasts() error
+	OutboundStatus(ctx context.Context) (response OutboundStatusResponse, err error)
+	FlushOutbound(ctx context.Context) (response FlushOutboundResponse, err error)
+	DrainOutbound(ctx context.Context, request DrainOutboundRequest) (response DrainOutboundResponse, err error)
+	UploadMedia(ctx context.Context, request UploadMediaRequest) (response UploadMediaResponse, err error)
 }
 
 type GenericResponse struct {
 	MessageID string `json:"message_id"`
 	Status    string `json:"status"`
 }
+
+// MultiSendResponse is the result of a send to several phones, keyed by phone
+type MultiSendResponse struct {
+	Sent       int                              `json:"sent"`
+	Failed     int                              `json:"failed"`
+	Queued     int                              `json:"queued"` // lost with the connection, sent again after the reconnection
+	Recipients map[string]RecipientSendResponse `json:"recipients"`
+}
+
+type RecipientSendResponse struct {
+	MessageID string `json:"message_id,omitempty"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
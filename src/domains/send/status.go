@@ -0,0 +1,43 @@
+package send
+
+import "mime/multipart"
+
+type StatusTextRequest struct {
+	Text            string `json:"text" form:"text"`
+	BackgroundColor string `json:"background_color" form:"background_color"` // #RRGGBB or #AARRGGBB
+	TextColor       string `json:"text_color" form:"text_color"`
+	Font            string `json:"font" form:"font"` // system, system_text, fb_script, system_bold, morningbreeze_regular, calistoga_regular, exo2_extrabold or courierprime_bold
+}
+
+type StatusMediaRequest struct {
+	Type    string                `json:"-" form:"-"` // image or video, set by the endpoint
+	Media   *multipart.FileHeader `json:"media" form:"media"`
+	MediaID string                `json:"media_id" form:"media_id"` // uploaded with POST /send/media/upload instead of the media file
+	Caption string                `json:"caption" form:"caption"`
+}
+
+type StatusViewersRequest struct {
+	MessageID string `json:"message_id" uri:"message_id"`
+}
+
+type StatusListResponse struct {
+	Statuses []StatusItem `json:"statuses"`
+}
+
+type StatusItem struct {
+	MessageID string `json:"message_id"`
+	Type      string `json:"type"`
+	Content   string `json:"content"`
+	PostedAt  string `json:"posted_at"`
+	Views     int    `json:"views"`
+}
+
+type StatusViewersResponse struct {
+	MessageID string         `json:"message_id"`
+	Viewers   []StatusViewer `json:"viewers"`
+}
+
+type StatusViewer struct {
+	JID      string `json:"jid"`
+	ViewedAt string `json:"viewed_at"`
+}
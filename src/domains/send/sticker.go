@@ -0,0 +1,16 @@
+package send
+
+import "mime/multipart"
+
+type StickerRequest struct {
+	Phone         string                `json:"phone" form:"phone"`
+	Phones        []string              `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority      string                `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Sticker       *multipart.FileHeader `json:"sticker" form:"sticker"`
+	PackName      string                `json:"pack_name" form:"pack_name"`
+	PackPublisher string                `json:"pack_publisher" form:"pack_publisher"`
+	Emojis        []string              `json:"emojis" form:"emojis"`
+	IsForwarded   bool                  `json:"is_forwarded" form:"is_forwarded"`
+	ReplyTo       *ReplyTo              `json:"reply_to" form:"reply_to"`
+	Ephemeral     string                `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
+}
@@ -0,0 +1,29 @@
+package send
+
+type TemplateRequest struct {
+	Name    string `json:"name" form:"name"`
+	Content string `json:"content" form:"content"` // {{name}} placeholders are replaced by the variables of the send
+}
+
+type TemplateNameRequest struct {
+	Name string `json:"name" uri:"name"`
+}
+
+type TemplateResponse struct {
+	Name         string   `json:"name"`
+	Content      string   `json:"content"`
+	Placeholders []string `json:"placeholders"`
+	CreatedAt    string   `json:"created_at"`
+	UpdatedAt    string   `json:"updated_at"`
+}
+
+type SendTemplateRequest struct {
+	Phone        string            `json:"phone" form:"phone"`
+	Phones       []string          `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority     string            `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	TemplateName string            `json:"template_name" form:"template_name"`
+	Variables    map[string]string `json:"variables" form:"variables"` // a value for every placeholder of the template
+	Mentions     []string          `json:"mentions" form:"mentions"`
+	ReplyTo      *ReplyTo          `json:"reply_to" form:"reply_to"`
+	Ephemeral    string            `json:"ephemeral" form:"ephemeral"`
+}
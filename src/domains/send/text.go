@@ -1,8 +1,13 @@
 package send
 
 type MessageRequest struct {
-	Phone          string  `json:"phone" form:"phone"`
-	Message        string  `json:"message" form:"message"`
-	IsForwarded    bool    `json:"is_forwarded" form:"is_forwarded"`
-	ReplyMessageID *string `json:"reply_message_id" form:"reply_message_id"`
+	Phone          string   `json:"phone" form:"phone"`
+	Phones         []string `json:"phones" form:"phones"`     // sent to each of them instead of phone
+	Priority       string   `json:"priority" form:"priority"` // high, normal or low lane of the outbound queue
+	Message        string   `json:"message" form:"message"`
+	IsForwarded    bool     `json:"is_forwarded" form:"is_forwarded"`
+	Mentions       []string `json:"mentions" form:"mentions"`                 // mentioned with the @-tags of the text, or silently when not tagged
+	ReplyMessageID *string  `json:"reply_message_id" form:"reply_message_id"` // deprecated, use reply_to
+	ReplyTo        *ReplyTo `json:"reply_to" form:"reply_to"`
+	Ephemeral      string   `json:"ephemeral" form:"ephemeral"` // disappears after 24h, 7d or 90d
 }
@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+type IWebhookService interface {
+	ListOutbox(ctx context.Context) (response []OutboxEntry, err error)
+	PurgeOutbox(ctx context.Context, request PurgeOutboxRequest) (response PurgeOutboxResponse, err error)
+	ListDeadLetters(ctx context.Context) (response []DeadLetter, err error)
+	ReplayDeadLetter(ctx context.Context, request ReplayDeadLetterRequest) (err error)
+	Status(ctx context.Context) (response []EndpointStatus, err error)
+	RegisterEndpoint(ctx context.Context, request RegisterEndpointRequest) (response Endpoint, err error)
+	ListEndpoints(ctx context.Context) (response []Endpoint, err error)
+	DeleteEndpoint(ctx context.Context, request DeleteEndpointRequest) (err error)
+}
+
+type OutboxEntry struct {
+	ID            int64           `json:"id"`
+	URL           string          `json:"url"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type PurgeOutboxRequest struct {
+	ID  int64  `json:"id" uri:"id"`
+	URL string `json:"url" query:"url"`
+}
+
+type PurgeOutboxResponse struct {
+	Purged int64 `json:"purged"`
+}
+
+type DeadLetter struct {
+	ID        int64           `json:"id"`
+	URL       string          `json:"url"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+type ReplayDeadLetterRequest struct {
+	ID int64 `json:"id" uri:"id"`
+}
+
+type EndpointStatus struct {
+	URL              string     `json:"url"`
+	Delivered        int64      `json:"delivered"`
+	Failed           int64      `json:"failed"`
+	Retried          int64      `json:"retried"`
+	Pending          int64      `json:"pending"`
+	LastError        string     `json:"last_error,omitempty"`
+	LastErrorAt      *time.Time `json:"last_error_at,omitempty"`
+	LastSuccessAt    *time.Time `json:"last_success_at,omitempty"`
+	AverageLatencyMS int64      `json:"average_latency_ms"`
+}
+
+type EndpointRule struct {
+	Chats        []string `json:"chats,omitempty"`
+	ChatType     string   `json:"chat_type,omitempty"`
+	MessageTypes []string `json:"message_types,omitempty"`
+	Senders      []string `json:"senders,omitempty"`
+	Keyword      string   `json:"keyword,omitempty"`
+}
+
+type RegisterEndpointRequest struct {
+	URL         string            `json:"url" form:"url"`
+	Events      []string          `json:"events" form:"events"`
+	Rules       []EndpointRule    `json:"rules" form:"rules"`
+	Secret      string            `json:"secret" form:"secret"`
+	Signature   string            `json:"signature" form:"signature"`
+	Headers     map[string]string `json:"headers" form:"headers"`
+	BearerToken string            `json:"bearer_token" form:"bearer_token"`
+	Template    string            `json:"template" form:"template"`
+}
+
+type DeleteEndpointRequest struct {
+	ID int64 `json:"id" uri:"id"`
+}
+
+type Endpoint struct {
+	ID             int64          `json:"id,omitempty"`
+	URL            string         `json:"url"`
+	Source         string         `json:"source"`
+	Events         []string       `json:"events,omitempty"`
+	Rules          []EndpointRule `json:"rules,omitempty"`
+	Signature      string         `json:"signature,omitempty"`
+	Template       string         `json:"template,omitempty"`
+	Headers        []string       `json:"headers,omitempty"`
+	HasSecret      bool           `json:"has_secret"`
+	HasBearerToken bool           `json:"has_bearer_token"`
+}
@@ -0,0 +1,93 @@
+package rest
+
+import (
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Chat struct {
+	Service domainChat.IChatService
+}
+
+func InitRestChat(app *fiber.App, service domainChat.IChatService) Chat {
+	rest := Chat{Service: service}
+	app.Post("/chat/:jid/read", rest.MarkAsRead)
+	app.Post("/chat/:jid/presence", rest.SendPresence)
+	app.Post("/chat/:jid/disappearing", rest.SetDisappearingTimer)
+	app.Delete("/chat/:jid/disappearing", rest.ClearDisappearingTimer)
+	return rest
+}
+
+func (controller *Chat) MarkAsRead(c *fiber.Ctx) error {
+	var request domainChat.MarkAsReadRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.JID = c.Params("jid")
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.MarkAsRead(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Chat marked as read",
+		Results: response,
+	})
+}
+
+func (controller *Chat) SendPresence(c *fiber.Ctx) error {
+	var request domainChat.PresenceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.JID = c.Params("jid")
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.SendPresence(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Chat presence sent",
+		Results: response,
+	})
+}
+
+func (controller *Chat) SetDisappearingTimer(c *fiber.Ctx) error {
+	var request domainChat.DisappearingTimerRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.JID = c.Params("jid")
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.SetDisappearingTimer(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Disappearing timer set",
+		Results: response,
+	})
+}
+
+func (controller *Chat) ClearDisappearingTimer(c *fiber.Ctx) error {
+	request := domainChat.DisappearingTimerRequest{JID: c.Params("jid"), Timer: "off"}
+	whatsapp.SanitizePhone(&request.JID)
+
+	response, err := controller.Service.SetDisappearingTimer(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Disappearing timer cleared",
+		Results: response,
+	})
+}
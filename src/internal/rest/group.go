@@ -1,7 +1,12 @@
 package rest
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
@@ -16,16 +21,40 @@ type Group struct {
 
 func InitRestGroup(app *fiber.App, service domainGroup.IGroupService) Group {
 	rest := Group{Service: service}
+	app.Get("/groups", rest.ListGroups)
+	app.Get("/groups/invite-info", rest.GetGroupInviteInfo)
+	app.Post("/groups", rest.CreateGroup)
 	app.Post("/group", rest.CreateGroup)
 	app.Post("/group/join-with-link", rest.JoinGroupWithLink)
 	app.Post("/group/leave", rest.LeaveGroup)
+	app.Post("/groups/:group_id/leave", rest.LeaveGroup)
 	app.Post("/group/participants", rest.AddParticipants)
 	app.Post("/group/participants/remove", rest.DeleteParticipants)
 	app.Post("/group/participants/promote", rest.PromoteParticipants)
 	app.Post("/group/participants/demote", rest.DemoteParticipants)
+	app.Post("/groups/:group_id/participants", rest.AddParticipants)
+	app.Post("/groups/:group_id/participants/remove", rest.DeleteParticipants)
+	app.Post("/groups/:group_id/participants/promote", rest.PromoteParticipants)
+	app.Post("/groups/:group_id/participants/demote", rest.DemoteParticipants)
+	app.Get("/groups/:group_id/participants/export", rest.ExportParticipants)
+	app.Put("/groups/:group_id/subject", rest.SetGroupSubject)
+	app.Put("/groups/:group_id/description", rest.SetGroupDescription)
+	app.Put("/groups/:group_id/photo", rest.SetGroupPhoto)
+	app.Delete("/groups/:group_id/photo", rest.RemoveGroupPhoto)
+	app.Patch("/groups/:group_id/settings", rest.UpdateGroupSettings)
+	app.Post("/communities", rest.CreateCommunity)
+	app.Get("/communities/:community_id/groups", rest.ListCommunityGroups)
+	app.Post("/communities/:community_id/groups", rest.LinkCommunityGroup)
+	app.Delete("/communities/:community_id/groups/:group_id", rest.UnlinkCommunityGroup)
+	app.Get("/communities/:community_id/participants", rest.ListCommunityParticipants)
 	app.Get("/group/participant-requests", rest.ListParticipantRequests)
 	app.Post("/group/participant-requests/approve", rest.ApproveParticipantRequests)
 	app.Post("/group/participant-requests/reject", rest.RejectParticipantRequests)
+	app.Get("/groups/:group_id/requests", rest.ListParticipantRequests)
+	app.Post("/groups/:group_id/requests/approve", rest.ApproveParticipantRequests)
+	app.Post("/groups/:group_id/requests/reject", rest.RejectParticipantRequests)
+	app.Post("/groups/:group_id/requests/:participant/approve", rest.ApproveParticipantRequests)
+	app.Post("/groups/:group_id/requests/:participant/reject", rest.RejectParticipantRequests)
 	return rest
 }
 
@@ -51,6 +80,9 @@ func (controller *Group) LeaveGroup(c *fiber.Ctx) error {
 	var request domainGroup.LeaveGroupRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	if groupID := c.Params("group_id"); groupID != "" {
+		request.GroupID = groupID
+	}
 
 	whatsapp.SanitizePhone(&request.GroupID)
 
@@ -69,16 +101,19 @@ func (controller *Group) CreateGroup(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	groupID, err := controller.Service.CreateGroup(c.UserContext(), request)
+	photo, err := c.FormFile("photo")
+	if err == nil {
+		request.Photo = photo
+	}
+
+	response, err := controller.Service.CreateGroup(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: fmt.Sprintf("Success created group with id %s", groupID),
-		Results: map[string]string{
-			"group_id": groupID,
-		},
+		Message: fmt.Sprintf("Success created group with id %s", response.GroupID),
+		Results: response,
 	})
 }
 func (controller *Group) AddParticipants(c *fiber.Ctx) error {
@@ -101,6 +136,9 @@ func (controller *Group) ListParticipantRequests(c *fiber.Ctx) error {
 	var request domainGroup.GetGroupRequestParticipantsRequest
 	err := c.QueryParser(&request)
 	utils.PanicIfNeeded(err)
+	if groupID := c.Params("group_id"); groupID != "" {
+		request.GroupID = groupID
+	}
 
 	if request.GroupID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(utils.ResponseData{
@@ -136,6 +174,9 @@ func (controller *Group) manageParticipants(c *fiber.Ctx, action whatsmeow.Parti
 	var request domainGroup.ParticipantRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	if groupID := c.Params("group_id"); groupID != "" {
+		request.GroupID = groupID
+	}
 	whatsapp.SanitizePhone(&request.GroupID)
 	request.Action = action
 	result, err := controller.Service.ManageParticipant(c.UserContext(), request)
@@ -153,6 +194,13 @@ func (controller *Group) handleRequestedParticipants(c *fiber.Ctx, action whatsm
 	var request domainGroup.GroupRequestParticipantsRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	if groupID := c.Params("group_id"); groupID != "" {
+		request.GroupID = groupID
+	}
+	// a single request is moderated with the participant in the path
+	if participant := c.Params("participant"); participant != "" {
+		request.Participants = []string{participant}
+	}
 	whatsapp.SanitizePhone(&request.GroupID)
 	request.Action = action
 	result, err := controller.Service.ManageGroupRequestParticipants(c.UserContext(), request)
@@ -164,3 +212,244 @@ func (controller *Group) handleRequestedParticipants(c *fiber.Ctx, action whatsm
 		Results: result,
 	})
 }
+
+func (controller *Group) SetGroupSubject(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupSubjectRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	request.GroupID = c.Params("group_id")
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupSubject(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success set group subject",
+	})
+}
+
+func (controller *Group) SetGroupDescription(c *fiber.Ctx) error {
+	var request domainGroup.SetGroupDescriptionRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	request.GroupID = c.Params("group_id")
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.SetGroupDescription(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success set group description",
+	})
+}
+
+func (controller *Group) SetGroupPhoto(c *fiber.Ctx) error {
+	request := domainGroup.SetGroupPhotoRequest{GroupID: c.Params("group_id")}
+	photo, err := c.FormFile("photo")
+	if err == nil {
+		request.Photo = photo
+	}
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	pictureID, err := controller.Service.SetGroupPhoto(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success set group photo",
+		Results: map[string]string{
+			"picture_id": pictureID,
+		},
+	})
+}
+
+func (controller *Group) RemoveGroupPhoto(c *fiber.Ctx) error {
+	request := domainGroup.RemoveGroupPhotoRequest{GroupID: c.Params("group_id")}
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err := controller.Service.RemoveGroupPhoto(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success remove group photo",
+	})
+}
+
+func (controller *Group) UpdateGroupSettings(c *fiber.Ctx) error {
+	var request domainGroup.GroupSettingsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	request.GroupID = c.Params("group_id")
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	response, err := controller.Service.UpdateGroupSettings(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success update group settings",
+		Results: response,
+	})
+}
+
+func (controller *Group) ListGroups(c *fiber.Ctx) error {
+	var request domainGroup.ListGroupsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.ListGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success get %d of %d groups", len(response.Groups), response.Total),
+		Results: response,
+	})
+}
+
+func (controller *Group) CreateCommunity(c *fiber.Ctx) error {
+	var request domainGroup.CreateCommunityRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.CreateCommunity(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created community with id %s", response.CommunityID),
+		Results: response,
+	})
+}
+
+func (controller *Group) LinkCommunityGroup(c *fiber.Ctx) error {
+	var request domainGroup.CommunityGroupRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+	request.CommunityID = c.Params("community_id")
+	whatsapp.SanitizePhone(&request.CommunityID)
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err = controller.Service.LinkCommunityGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success link group to community",
+	})
+}
+
+func (controller *Group) UnlinkCommunityGroup(c *fiber.Ctx) error {
+	request := domainGroup.CommunityGroupRequest{CommunityID: c.Params("community_id"), GroupID: c.Params("group_id")}
+	whatsapp.SanitizePhone(&request.CommunityID)
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	err := controller.Service.UnlinkCommunityGroup(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success unlink group from community",
+	})
+}
+
+func (controller *Group) ListCommunityGroups(c *fiber.Ctx) error {
+	request := domainGroup.CommunityRequest{CommunityID: c.Params("community_id")}
+	whatsapp.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.ListCommunityGroups(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community groups",
+		Results: result,
+	})
+}
+
+func (controller *Group) ListCommunityParticipants(c *fiber.Ctx) error {
+	request := domainGroup.CommunityRequest{CommunityID: c.Params("community_id")}
+	whatsapp.SanitizePhone(&request.CommunityID)
+
+	result, err := controller.Service.ListCommunityParticipants(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get community participants",
+		Results: result,
+	})
+}
+
+func (controller *Group) GetGroupInviteInfo(c *fiber.Ctx) error {
+	var request domainGroup.GroupInviteInfoRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.GetGroupInviteInfo(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get group invite info",
+		Results: response,
+	})
+}
+
+func (controller *Group) ExportParticipants(c *fiber.Ctx) error {
+	var request domainGroup.ExportParticipantsRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+	request.GroupID = c.Params("group_id")
+	whatsapp.SanitizePhone(&request.GroupID)
+
+	result, err := controller.Service.ExportParticipants(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	if request.Format != "csv" {
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: fmt.Sprintf("Success export %d participants", len(result)),
+			Results: result,
+		})
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	_ = writer.Write([]string{"jid", "phone", "pushname", "is_admin", "is_super_admin", "joined_at"})
+	for _, participant := range result {
+		joinedAt := ""
+		if participant.JoinedAt != nil {
+			joinedAt = participant.JoinedAt.UTC().Format(time.RFC3339)
+		}
+		_ = writer.Write([]string{
+			utils.CSVCell(participant.JID),
+			utils.CSVCell(participant.Phone),
+			utils.CSVCell(participant.PushName),
+			strconv.FormatBool(participant.IsAdmin),
+			strconv.FormatBool(participant.IsSuperAdmin),
+			joinedAt,
+		})
+	}
+	writer.Flush()
+	utils.PanicIfNeeded(writer.Error())
+
+	c.Attachment(fmt.Sprintf("participants-%s.csv", strings.TrimSuffix(request.GroupID, "@g.us")))
+	return c.Send(buffer.Bytes())
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"bytes"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	domainMedia "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/media"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+)
+
+type Media struct {
+	Service domainMedia.IMediaService
+}
+
+func InitRestMedia(app *fiber.App, service domainMedia.IMediaService) Media {
+	rest := Media{Service: service}
+	app.Get("/admin/media/cleanup", rest.CleanupStatus)
+	app.Post("/admin/media/cleanup", rest.Cleanup)
+	app.Get("/media/:id", rest.ServeMedia)
+	return rest
+}
+
+// InitRestSignedMedia serves the local media behind signed urls, only mounted with --media-base-url. The signature
+// authorizes the request, so it's registered before the basic auth, the requests without signature go on to the
+// authenticated /media/:id.
+func InitRestSignedMedia(app *fiber.App) {
+	app.Get("/media/:id", DownloadSignedMedia)
+}
+
+func DownloadSignedMedia(c *fiber.Ctx) error {
+	if c.Query("signature") == "" {
+		return c.Next()
+	}
+	path, err := whatsapp.VerifyMediaURL(c.Params("id"), c.Query("expires"), c.Query("signature"), time.Now())
+	if err != nil {
+		panic(pkgError.AuthError(err.Error()))
+	}
+	return sendMediaFile(c, path)
+}
+
+// ServeMedia streams a file of the media folder, with Range requests so players can seek
+func (controller *Media) ServeMedia(c *fiber.Ctx) error {
+	path, err := whatsapp.MediaFilePath(c.Params("id"))
+	if err != nil {
+		panic(pkgError.ValidationError(err.Error()))
+	}
+	return sendMediaFile(c, path)
+}
+
+func sendMediaFile(c *fiber.Ctx, path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		panic(pkgError.NotFoundError("media not found"))
+	}
+	if mimeType := mime.TypeByExtension(filepath.Ext(path)); mimeType != "" {
+		c.Set(fiber.HeaderContentType, mimeType)
+	}
+
+	encrypted, err := whatsapp.IsEncryptedMediaFile(path)
+	utils.PanicIfNeeded(err)
+	if !encrypted {
+		c.Set(fiber.HeaderAcceptRanges, "bytes")
+		return c.SendFile(path)
+	}
+
+	// encrypted media are decrypted in memory, ServeContent still answers the Range requests
+	data, err := whatsapp.ReadMediaFile(path)
+	utils.PanicIfNeeded(err)
+	return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, filepath.Base(path), info.ModTime(), bytes.NewReader(data))
+	})(c)
+}
+
+func (controller *Media) CleanupStatus(c *fiber.Ctx) error {
+	response, err := controller.Service.CleanupStatus(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get media cleanup status",
+		Results: response,
+	})
+}
+
+func (controller *Media) Cleanup(c *fiber.Ctx) error {
+	response, err := controller.Service.Cleanup(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success clean up media",
+		Results: response,
+	})
+}
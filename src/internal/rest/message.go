@@ -13,13 +13,21 @@ type Message struct {
 
 func InitRestMessage(app *fiber.App, service domainMessage.IMessageService) Message {
 	rest := Message{Service: service}
+	app.Post("/message/:message_id/react", rest.ReactMessage)
 	app.Post("/message/:message_id/reaction", rest.ReactMessage)
 	app.Post("/message/:message_id/revoke", rest.RevokeMessage)
 	app.Post("/message/:message_id/delete", rest.DeleteMessage)
+	app.Post("/message/:message_id/edit", rest.UpdateMessage)
 	app.Post("/message/:message_id/update", rest.UpdateMessage)
 	app.Post("/message/:message_id/read", rest.MarkAsRead)
 	app.Post("/message/:message_id/star", rest.StarMessage)
+	app.Post("/message/:message_id/pin", rest.PinMessage)
+	app.Post("/message/:message_id/unpin", rest.UnpinMessage)
+	app.Post("/message/:message_id/keep", rest.KeepMessage)
+	app.Post("/message/:message_id/unkeep", rest.UnkeepMessage)
 	app.Post("/message/:message_id/unstar", rest.UnstarMessage)
+	app.Post("/message/:message_id/download", rest.DownloadMedia)
+	app.Post("/message/:message_id/forward", rest.ForwardMessage)
 	return rest
 }
 
@@ -118,6 +126,62 @@ func (controller *Message) MarkAsRead(c *fiber.Ctx) error {
 	})
 }
 
+func (controller *Message) PinMessage(c *fiber.Ctx) error {
+	return controller.pinMessage(c, true)
+}
+
+func (controller *Message) UnpinMessage(c *fiber.Ctx) error {
+	return controller.pinMessage(c, false)
+}
+
+func (controller *Message) pinMessage(c *fiber.Ctx, pinned bool) error {
+	var request domainMessage.PinRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	whatsapp.SanitizePhone(&request.Phone)
+	request.IsPinned = pinned
+
+	response, err := controller.Service.PinMessage(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Message) KeepMessage(c *fiber.Ctx) error {
+	return controller.keepMessage(c, true)
+}
+
+func (controller *Message) UnkeepMessage(c *fiber.Ctx) error {
+	return controller.keepMessage(c, false)
+}
+
+func (controller *Message) keepMessage(c *fiber.Ctx, kept bool) error {
+	var request domainMessage.KeepRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	whatsapp.SanitizePhone(&request.Phone)
+	request.IsKept = kept
+
+	response, err := controller.Service.KeepMessage(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
 func (controller *Message) StarMessage(c *fiber.Ctx) error {
 	var request domainMessage.StarRequest
 	err := c.BodyParser(&request)
@@ -156,3 +220,39 @@ func (controller *Message) UnstarMessage(c *fiber.Ctx) error {
 		Results: nil,
 	})
 }
+
+func (controller *Message) DownloadMedia(c *fiber.Ctx) error {
+	request := domainMessage.DownloadMediaRequest{MessageID: c.Params("message_id")}
+
+	response, err := controller.Service.DownloadMedia(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success download media",
+		Results: response,
+	})
+}
+
+func (controller *Message) ForwardMessage(c *fiber.Ctx) error {
+	var request domainMessage.ForwardRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	whatsapp.SanitizePhone(&request.Phone)
+	for i := range request.To {
+		whatsapp.SanitizePhone(&request.To[i])
+	}
+
+	response, err := controller.Service.ForwardMessage(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Message forwarded",
+		Results: response,
+	})
+}
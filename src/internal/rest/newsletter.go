@@ -13,6 +13,7 @@ type Newsletter struct {
 func InitRestNewsletter(app *fiber.App, service domainNewsletter.INewsletterService) Newsletter {
 	rest := Newsletter{Service: service}
 	app.Post("/newsletter/unfollow", rest.Unfollow)
+	app.Post("/newsletter/post", rest.Post)
 	return rest
 }
 
@@ -30,3 +31,24 @@ func (controller *Newsletter) Unfollow(c *fiber.Ctx) error {
 		Message: "Success unfollow newsletter",
 	})
 }
+
+func (controller *Newsletter) Post(c *fiber.Ctx) error {
+	var request domainNewsletter.PostRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	media, err := c.FormFile("media")
+	if err == nil {
+		request.Media = media
+	}
+
+	response, err := controller.Service.Post(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
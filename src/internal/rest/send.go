@@ -1,7 +1,11 @@
 package rest
 
 import (
+	"errors"
+	"fmt"
+
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
 	"github.com/gofiber/fiber/v2"
@@ -20,9 +24,36 @@ func InitRestSend(app *fiber.App, service domainSend.ISendService) Send {
 	app.Post("/send/contact", rest.SendContact)
 	app.Post("/send/link", rest.SendLink)
 	app.Post("/send/location", rest.SendLocation)
+	app.Post("/send/live-location", rest.SendLiveLocation)
+	app.Post("/send/live-location/:message_id", rest.SendLiveLocation)
+	app.Post("/send/live-location/:message_id/stop", rest.StopLiveLocation)
 	app.Post("/send/audio", rest.SendAudio)
+	app.Post("/send/sticker", rest.SendSticker)
 	app.Post("/send/poll", rest.SendPoll)
+	app.Post("/send/buttons", rest.SendButtons)
+	app.Post("/send/list", rest.SendList)
+	app.Post("/send/carousel", rest.SendCarousel)
+	app.Post("/send/product", rest.SendProduct)
+	app.Post("/send/product-list", rest.SendProductList)
+	app.Post("/send/order", rest.SendOrder)
 	app.Post("/send/presence", rest.SendPresence)
+	app.Post("/send/status/text", rest.SendStatusText)
+	app.Post("/send/status/image", rest.SendStatusImage)
+	app.Post("/send/status/video", rest.SendStatusVideo)
+	app.Get("/send/status", rest.ListStatuses)
+	app.Get("/send/status/:message_id/viewers", rest.GetStatusViewers)
+	app.Get("/send/templates", rest.ListTemplates)
+	app.Post("/send/templates", rest.CreateTemplate)
+	app.Get("/send/templates/:name", rest.GetTemplate)
+	app.Put("/send/templates/:name", rest.UpdateTemplate)
+	app.Delete("/send/templates/:name", rest.DeleteTemplate)
+	app.Post("/send/template", rest.SendTemplate)
+	app.Post("/send/broadcast", rest.SendBroadcast)
+	app.Get("/send/broadcast/:broadcast_id", rest.GetBroadcast)
+	app.Get("/admin/outbound", rest.OutboundStatus)
+	app.Post("/admin/outbound/flush", rest.FlushOutbound)
+	app.Post("/admin/outbound/drain", rest.DrainOutbound)
+	app.Post("/send/media/upload", rest.UploadMedia)
 	return rest
 }
 
@@ -31,9 +62,106 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendText(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendImage(c *fiber.Ctx) error {
+	var request domainSend.ImageRequest
+	request.Compress = true
+
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	file, err := c.FormFile("image")
+	if err == nil {
+		request.Image = file
+	}
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendImage(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendFile(c *fiber.Ctx) error {
+	var request domainSend.FileRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	file, err := c.FormFile("file")
+	if err == nil {
+		request.File = file
+	}
+	thumbnail, err := c.FormFile("thumbnail")
+	if err == nil {
+		request.Thumbnail = thumbnail
+	}
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendFile(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendVideo(c *fiber.Ctx) error {
+	var request domainSend.VideoRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	video, err := c.FormFile("video")
+	if err == nil {
+		request.Video = video
+	}
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendVideo(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendContact(c *fiber.Ctx) error {
+	var request domainSend.ContactRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendContact(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendLink(c *fiber.Ctx) error {
+	var request domainSend.LinkRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendLink(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendLocation(c *fiber.Ctx) error {
+	var request domainSend.LocationRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendLocation(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendLiveLocation(c *fiber.Ctx) error {
+	var request domainSend.LiveLocationRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
 	whatsapp.SanitizePhone(&request.Phone)
 
-	response, err := controller.Service.SendText(c.UserContext(), request)
+	response, err := controller.Service.SendLiveLocation(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -44,10 +172,101 @@ func (controller *Send) SendText(c *fiber.Ctx) error {
 	})
 }
 
-func (controller *Send) SendImage(c *fiber.Ctx) error {
-	var request domainSend.ImageRequest
-	request.Compress = true
+func (controller *Send) StopLiveLocation(c *fiber.Ctx) error {
+	var request domainSend.StopLiveLocationRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.MessageID = c.Params("message_id")
+	whatsapp.SanitizePhone(&request.Phone)
+
+	response, err := controller.Service.StopLiveLocation(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Send) SendAudio(c *fiber.Ctx) error {
+	var request domainSend.AudioRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	audio, err := c.FormFile("audio")
+	if err == nil {
+		request.Audio = audio
+	}
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendAudio(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendSticker(c *fiber.Ctx) error {
+	var request domainSend.StickerRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	file, err := c.FormFile("sticker")
+	if err == nil {
+		request.Sticker = file
+	}
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendSticker(c.UserContext(), request)
+	})
+}
 
+func (controller *Send) SendPoll(c *fiber.Ctx) error {
+	var request domainSend.PollRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendPoll(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendButtons(c *fiber.Ctx) error {
+	var request domainSend.ButtonsRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendButtons(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendList(c *fiber.Ctx) error {
+	var request domainSend.ListRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendList(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendCarousel(c *fiber.Ctx) error {
+	var request domainSend.CarouselRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendCarousel(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendProduct(c *fiber.Ctx) error {
+	var request domainSend.ProductRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
@@ -56,9 +275,40 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 		request.Image = file
 	}
 
-	whatsapp.SanitizePhone(&request.Phone)
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendProduct(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendProductList(c *fiber.Ctx) error {
+	var request domainSend.ProductListRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendProductList(c.UserContext(), request)
+	})
+}
 
-	response, err := controller.Service.SendImage(c.UserContext(), request)
+func (controller *Send) SendOrder(c *fiber.Ctx) error {
+	var request domainSend.OrderRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendOrder(c.UserContext(), request)
+	})
+}
+
+func (controller *Send) SendPresence(c *fiber.Ctx) error {
+	var request domainSend.PresenceRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.SendPresence(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -69,18 +319,42 @@ func (controller *Send) SendImage(c *fiber.Ctx) error {
 	})
 }
 
-func (controller *Send) SendFile(c *fiber.Ctx) error {
-	var request domainSend.FileRequest
+func (controller *Send) SendStatusText(c *fiber.Ctx) error {
+	var request domainSend.StatusTextRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	file, err := c.FormFile("file")
+	response, err := controller.Service.SendStatusText(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
-	request.File = file
-	whatsapp.SanitizePhone(&request.Phone)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: response.Status,
+		Results: response,
+	})
+}
+
+func (controller *Send) SendStatusImage(c *fiber.Ctx) error {
+	return controller.sendStatusMedia(c, "image")
+}
+
+func (controller *Send) SendStatusVideo(c *fiber.Ctx) error {
+	return controller.sendStatusMedia(c, "video")
+}
 
-	response, err := controller.Service.SendFile(c.UserContext(), request)
+func (controller *Send) sendStatusMedia(c *fiber.Ctx, mediaType string) error {
+	var request domainSend.StatusMediaRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	request.Type = mediaType
+	media, err := c.FormFile("media")
+	if err == nil {
+		request.Media = media
+	}
+
+	response, err := controller.Service.SendStatusMedia(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
@@ -91,134 +365,303 @@ func (controller *Send) SendFile(c *fiber.Ctx) error {
 	})
 }
 
-func (controller *Send) SendVideo(c *fiber.Ctx) error {
-	var request domainSend.VideoRequest
-	err := c.BodyParser(&request)
+func (controller *Send) ListStatuses(c *fiber.Ctx) error {
+	response, err := controller.Service.ListStatuses(c.UserContext())
 	utils.PanicIfNeeded(err)
 
-	video, err := c.FormFile("video")
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("%d statuses posted in the last 24 hours", len(response.Statuses)),
+		Results: response,
+	})
+}
+
+func (controller *Send) GetStatusViewers(c *fiber.Ctx) error {
+	request := domainSend.StatusViewersRequest{MessageID: c.Params("message_id")}
+
+	response, err := controller.Service.GetStatusViewers(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
-	request.Video = video
-	whatsapp.SanitizePhone(&request.Phone)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Status %s viewed by %d contacts", response.MessageID, len(response.Viewers)),
+		Results: response,
+	})
+}
 
-	response, err := controller.Service.SendVideo(c.UserContext(), request)
+func (controller *Send) ListTemplates(c *fiber.Ctx) error {
+	response, err := controller.Service.ListTemplates(c.UserContext())
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: "Success get message templates",
 		Results: response,
 	})
 }
 
-func (controller *Send) SendContact(c *fiber.Ctx) error {
-	var request domainSend.ContactRequest
+func (controller *Send) CreateTemplate(c *fiber.Ctx) error {
+	var request domainSend.TemplateRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	whatsapp.SanitizePhone(&request.Phone)
+	response, err := controller.Service.CreateTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
 
-	response, err := controller.Service.SendContact(c.UserContext(), request)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success created template %s", response.Name),
+		Results: response,
+	})
+}
+
+func (controller *Send) GetTemplate(c *fiber.Ctx) error {
+	request := domainSend.TemplateNameRequest{Name: c.Params("name")}
+
+	response, err := controller.Service.GetTemplate(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: fmt.Sprintf("Success get template %s", response.Name),
 		Results: response,
 	})
 }
 
-func (controller *Send) SendLink(c *fiber.Ctx) error {
-	var request domainSend.LinkRequest
+func (controller *Send) UpdateTemplate(c *fiber.Ctx) error {
+	var request domainSend.TemplateRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
+	request.Name = c.Params("name")
 
-	whatsapp.SanitizePhone(&request.Phone)
-
-	response, err := controller.Service.SendLink(c.UserContext(), request)
+	response, err := controller.Service.UpdateTemplate(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: fmt.Sprintf("Success updated template %s", response.Name),
 		Results: response,
 	})
 }
 
-func (controller *Send) SendLocation(c *fiber.Ctx) error {
-	var request domainSend.LocationRequest
+func (controller *Send) DeleteTemplate(c *fiber.Ctx) error {
+	request := domainSend.TemplateNameRequest{Name: c.Params("name")}
+
+	err := controller.Service.DeleteTemplate(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success removed template %s", request.Name),
+	})
+}
+
+func (controller *Send) SendTemplate(c *fiber.Ctx) error {
+	var request domainSend.SendTemplateRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	whatsapp.SanitizePhone(&request.Phone)
+	return respondSend(c, request.Phone, request.Phones, request.Priority, func(phone string) (domainSend.GenericResponse, error) {
+		request.Phone = phone
+		return controller.Service.SendTemplate(c.UserContext(), request)
+	})
+}
 
-	response, err := controller.Service.SendLocation(c.UserContext(), request)
+func (controller *Send) UploadMedia(c *fiber.Ctx) error {
+	var request domainSend.UploadMediaRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	media, err := c.FormFile("media")
+	if err == nil {
+		request.Media = media
+	}
+
+	response, err := controller.Service.UploadMedia(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: "Media uploaded, send it with its media_id",
 		Results: response,
 	})
 }
 
-func (controller *Send) SendAudio(c *fiber.Ctx) error {
-	var request domainSend.AudioRequest
+func (controller *Send) SendBroadcast(c *fiber.Ctx) error {
+	var request domainSend.BroadcastRequest
 	err := c.BodyParser(&request)
 	utils.PanicIfNeeded(err)
 
-	audio, err := c.FormFile("audio")
+	for i := range request.Recipients {
+		whatsapp.SanitizePhone(&request.Recipients[i].Phone)
+	}
+
+	response, err := controller.Service.SendBroadcast(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
-	request.Audio = audio
-	whatsapp.SanitizePhone(&request.Phone)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Broadcast %s started to %d recipients", response.BroadcastID, response.Total),
+		Results: response,
+	})
+}
 
-	response, err := controller.Service.SendAudio(c.UserContext(), request)
+func (controller *Send) GetBroadcast(c *fiber.Ctx) error {
+	request := domainSend.GetBroadcastRequest{BroadcastID: c.Params("broadcast_id")}
+
+	response, err := controller.Service.GetBroadcast(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: fmt.Sprintf("Broadcast %s is %s", response.BroadcastID, response.Status),
 		Results: response,
 	})
 }
 
-func (controller *Send) SendPoll(c *fiber.Ctx) error {
-	var request domainSend.PollRequest
-	err := c.BodyParser(&request)
+func (controller *Send) OutboundStatus(c *fiber.Ctx) error {
+	response, err := controller.Service.OutboundStatus(c.UserContext())
 	utils.PanicIfNeeded(err)
 
-	whatsapp.SanitizePhone(&request.Phone)
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("%d sends waiting in the outbound queue", response.Queued),
+		Results: response,
+	})
+}
 
-	response, err := controller.Service.SendPoll(c.UserContext(), request)
+func (controller *Send) FlushOutbound(c *fiber.Ctx) error {
+	response, err := controller.Service.FlushOutbound(c.UserContext())
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: fmt.Sprintf("%d waiting sends cancelled", response.Cancelled),
 		Results: response,
 	})
 }
 
-func (controller *Send) SendPresence(c *fiber.Ctx) error {
-	var request domainSend.PresenceRequest
-	err := c.BodyParser(&request)
+func (controller *Send) DrainOutbound(c *fiber.Ctx) error {
+	var request domainSend.DrainOutboundRequest
+	err := c.QueryParser(&request)
 	utils.PanicIfNeeded(err)
 
-	response, err := controller.Service.SendPresence(c.UserContext(), request)
+	response, err := controller.Service.DrainOutbound(c.UserContext(), request)
 	utils.PanicIfNeeded(err)
 
 	return c.JSON(utils.ResponseData{
 		Status:  200,
 		Code:    "SUCCESS",
-		Message: response.Status,
+		Message: fmt.Sprintf("Outbound queue drained, %d waiting sends were sent", response.Drained),
 		Results: response,
 	})
 }
+
+// maxSendRecipients is the most phones a send fans out to. The phones are sent one after another while the request
+// waits, so larger lists go through POST /send/broadcast.
+const maxSendRecipients = 10
+
+// respondSend sends to the phone of the request, or to each of its phones through the outbound queue in the lane of
+// the priority, replying with the message ID and status of every recipient. The media of the request is converted and
+// uploaded once, for the first phone. It fails only when no recipient got the message.
+func respondSend(c *fiber.Ctx, phone string, phones []string, priority string, send func(phone string) (domainSend.GenericResponse, error)) error {
+	outboundPriority, err := whatsapp.ParseOutboundPriority(priority)
+	utils.PanicIfNeeded(err)
+	c.SetUserContext(whatsapp.WithOutboundPriority(c.UserContext(), outboundPriority))
+
+	if len(phones) == 0 {
+		whatsapp.SanitizePhone(&phone)
+		response, err := send(phone)
+		var queued *whatsapp.SendRetryQueued
+		if errors.As(err, &queued) {
+			return c.Status(queued.StatusCode()).JSON(utils.ResponseData{
+				Status:  queued.StatusCode(),
+				Code:    queued.ErrCode(),
+				Message: queued.Error(),
+				Results: domainSend.GenericResponse{MessageID: queued.MessageID, Status: "queued"},
+			})
+		}
+		utils.PanicIfNeeded(err)
+
+		return c.JSON(utils.ResponseData{
+			Status:  200,
+			Code:    "SUCCESS",
+			Message: response.Status,
+			Results: response,
+		})
+	}
+	if phone != "" {
+		utils.PanicIfNeeded(pkgError.ValidationError("phone and phones can't be both given"))
+	}
+	if len(phones) > maxSendRecipients {
+		utils.PanicIfNeeded(pkgError.ValidationError(fmt.Sprintf("phones: the length must be no more than %d, use POST /send/broadcast for more.", maxSendRecipients)))
+	}
+
+	c.SetUserContext(whatsapp.WithRequestMedia(c.UserContext()))
+	response := domainSend.MultiSendResponse{Recipients: make(map[string]domainSend.RecipientSendResponse, len(phones))}
+	var firstErr error
+	for _, recipient := range phones {
+		whatsapp.SanitizePhone(&recipient)
+		if _, ok := response.Recipients[recipient]; ok {
+			continue
+		}
+
+		sent, err := sendRecovered(recipient, send)
+		var queued *whatsapp.SendRetryQueued
+		if errors.As(err, &queued) {
+			response.Queued++
+			response.Recipients[recipient] = domainSend.RecipientSendResponse{MessageID: queued.MessageID, Status: "queued"}
+			continue
+		}
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			response.Failed++
+			response.Recipients[recipient] = domainSend.RecipientSendResponse{Status: "failed", Error: err.Error()}
+			continue
+		}
+		response.Sent++
+		response.Recipients[recipient] = domainSend.RecipientSendResponse{MessageID: sent.MessageID, Status: sent.Status}
+	}
+	if response.Sent == 0 && response.Queued == 0 {
+		utils.PanicIfNeeded(firstErr)
+	}
+
+	message := fmt.Sprintf("Message sent to %d of %d recipients", response.Sent, len(response.Recipients))
+	if response.Queued > 0 {
+		message += fmt.Sprintf(", %d queued until the reconnection", response.Queued)
+	}
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: message,
+		Results: response,
+	})
+}
+
+// sendRecovered returns the panics of the send as errors, so one recipient doesn't abort the others
+func sendRecovered(phone string, send func(phone string) (domainSend.GenericResponse, error)) (response domainSend.GenericResponse, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			if recoveredErr, ok := recovered.(error); ok {
+				err = recoveredErr
+			} else {
+				err = fmt.Errorf("%v", recovered)
+			}
+		}
+	}()
+	return send(phone)
+}
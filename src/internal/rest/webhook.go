@@ -0,0 +1,145 @@
+package rest
+
+import (
+	"fmt"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/gofiber/fiber/v2"
+)
+
+type Webhook struct {
+	Service domainWebhook.IWebhookService
+}
+
+func InitRestWebhook(app *fiber.App, service domainWebhook.IWebhookService) Webhook {
+	rest := Webhook{Service: service}
+	app.Get("/webhooks/status", rest.Status)
+	app.Get("/webhooks/outbox", rest.ListOutbox)
+	app.Delete("/webhooks/outbox", rest.PurgeOutbox)
+	app.Delete("/webhooks/outbox/:id", rest.PurgeOutbox)
+	app.Get("/webhooks/dead-letter", rest.ListDeadLetters)
+	app.Post("/webhooks/dead-letter/:id/replay", rest.ReplayDeadLetter)
+	app.Get("/admin/webhooks", rest.ListEndpoints)
+	app.Post("/admin/webhooks", rest.RegisterEndpoint)
+	app.Delete("/admin/webhooks/:id", rest.DeleteEndpoint)
+	return rest
+}
+
+func (controller *Webhook) Status(c *fiber.Ctx) error {
+	response, err := controller.Service.Status(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook status",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ListOutbox(c *fiber.Ctx) error {
+	response, err := controller.Service.ListOutbox(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook outbox",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) PurgeOutbox(c *fiber.Ctx) error {
+	var request domainWebhook.PurgeOutboxRequest
+	err := c.QueryParser(&request)
+	utils.PanicIfNeeded(err)
+
+	if c.Params("id") != "" {
+		id, err := c.ParamsInt("id")
+		utils.PanicIfNeeded(err)
+		request.ID = int64(id)
+	}
+
+	response, err := controller.Service.PurgeOutbox(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success purged %d webhook outbox entries", response.Purged),
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ListDeadLetters(c *fiber.Ctx) error {
+	response, err := controller.Service.ListDeadLetters(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook dead letters",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) ReplayDeadLetter(c *fiber.Ctx) error {
+	var request domainWebhook.ReplayDeadLetterRequest
+	id, err := c.ParamsInt("id")
+	utils.PanicIfNeeded(err)
+	request.ID = int64(id)
+
+	err = controller.Service.ReplayDeadLetter(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success replayed dead letter %d", request.ID),
+	})
+}
+
+func (controller *Webhook) ListEndpoints(c *fiber.Ctx) error {
+	response, err := controller.Service.ListEndpoints(c.UserContext())
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: "Success get webhook endpoints",
+		Results: response,
+	})
+}
+
+func (controller *Webhook) RegisterEndpoint(c *fiber.Ctx) error {
+	var request domainWebhook.RegisterEndpointRequest
+	err := c.BodyParser(&request)
+	utils.PanicIfNeeded(err)
+
+	response, err := controller.Service.RegisterEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success registered webhook %s", response.URL),
+		Results: response,
+	})
+}
+
+func (controller *Webhook) DeleteEndpoint(c *fiber.Ctx) error {
+	var request domainWebhook.DeleteEndpointRequest
+	id, err := c.ParamsInt("id")
+	utils.PanicIfNeeded(err)
+	request.ID = int64(id)
+
+	err = controller.Service.DeleteEndpoint(c.UserContext(), request)
+	utils.PanicIfNeeded(err)
+
+	return c.JSON(utils.ResponseData{
+		Status:  200,
+		Code:    "SUCCESS",
+		Message: fmt.Sprintf("Success removed webhook endpoint %d", request.ID),
+	})
+}
@@ -0,0 +1,44 @@
+package antivirus
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Result is the verdict of a scan, Threat names the detection of infected files
+type Result struct {
+	Infected bool
+	Threat   string
+}
+
+// Scanner checks the downloaded media before they're saved
+type Scanner interface {
+	Scan(ctx context.Context, data []byte) (Result, error)
+}
+
+// Config selects and configures the scanner
+type Config struct {
+	Engine  string // clamav or icap, empty disables the scan
+	Address string // clamav: tcp://host:3310 or unix:///path/clamd.sock, icap: icap://host:1344/service
+	Timeout time.Duration
+}
+
+// New returns the configured scanner, nil when the scan is disabled
+func New(cfg Config) (Scanner, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+
+	switch strings.ToLower(cfg.Engine) {
+	case "":
+		return nil, nil
+	case "clamav":
+		return NewClamAV(cfg.Address, cfg.Timeout)
+	case "icap":
+		return NewICAP(cfg.Address, cfg.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown media scan engine %q, use clamav or icap", cfg.Engine)
+	}
+}
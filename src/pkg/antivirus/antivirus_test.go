@@ -0,0 +1,145 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// serve answers a single connection of the test server with the handler
+func serve(t *testing.T, handler func(conn net.Conn)) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			handler(conn)
+			_ = conn.Close()
+		}
+	}()
+	return listener.Addr().String()
+}
+
+func TestNew(t *testing.T) {
+	scanner, err := New(Config{})
+	assert.NoError(t, err)
+	assert.Nil(t, scanner)
+
+	_, err = New(Config{Engine: "clamav", Address: "tcp://localhost:3310"})
+	assert.NoError(t, err)
+	_, err = New(Config{Engine: "clamav", Address: "http://localhost:3310"})
+	assert.Error(t, err)
+	_, err = New(Config{Engine: "icap", Address: "icap://localhost/avscan"})
+	assert.NoError(t, err)
+	_, err = New(Config{Engine: "icap", Address: "localhost:1344"})
+	assert.Error(t, err)
+	_, err = New(Config{Engine: "sophos"})
+	assert.Error(t, err)
+}
+
+func TestClamAVScan(t *testing.T) {
+	address := serve(t, func(conn net.Conn) {
+		reader := bufio.NewReader(conn)
+		command, _ := reader.ReadString('\x00')
+		if command != "zINSTREAM\x00" {
+			_, _ = conn.Write([]byte("UNKNOWN COMMAND\x00"))
+			return
+		}
+
+		var data []byte
+		for {
+			var size uint32
+			if binary.Read(reader, binary.BigEndian, &size) != nil || size == 0 {
+				break
+			}
+			chunk := make([]byte, size)
+			_, _ = io.ReadFull(reader, chunk)
+			data = append(data, chunk...)
+		}
+		if strings.Contains(string(data), "EICAR") {
+			_, _ = conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		} else {
+			_, _ = conn.Write([]byte("stream: OK\x00"))
+		}
+	})
+
+	scanner, err := NewClamAV("tcp://"+address, time.Second)
+	assert.NoError(t, err)
+
+	result, err := scanner.Scan(context.Background(), []byte("clean media"))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.Scan(context.Background(), []byte(eicar))
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Infected: true, Threat: "Eicar-Test-Signature"}, result)
+}
+
+func TestParseClamAVReply(t *testing.T) {
+	_, err := parseClamAVReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+}
+
+func TestICAPScan(t *testing.T) {
+	address := serve(t, func(conn net.Conn) {
+		reader := textproto.NewReader(bufio.NewReader(conn))
+		request, _ := reader.ReadLine()
+		header, _ := reader.ReadMIMEHeader()
+		if !strings.HasPrefix(request, "RESPMOD icap://") || header.Get("Encapsulated") == "" {
+			_, _ = conn.Write([]byte("ICAP/1.0 400 Bad Request\r\n\r\n"))
+			return
+		}
+
+		// the http header, then the chunked body
+		_, _ = reader.ReadLine()
+		_, _ = reader.ReadMIMEHeader()
+		_, _ = reader.ReadLine()
+		body, _ := reader.ReadLine()
+		if strings.Contains(body, "EICAR") {
+			_, _ = conn.Write([]byte("ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar-Test-Signature;\r\n\r\n"))
+		} else {
+			_, _ = conn.Write([]byte("ICAP/1.0 204 No Content\r\n\r\n"))
+		}
+	})
+
+	scanner, err := NewICAP("icap://"+address+"/avscan", time.Second)
+	assert.NoError(t, err)
+
+	result, err := scanner.Scan(context.Background(), []byte("clean media"))
+	assert.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.Scan(context.Background(), []byte(eicar))
+	assert.NoError(t, err)
+	assert.Equal(t, Result{Infected: true, Threat: "Eicar-Test-Signature"}, result)
+}
+
+func TestParseICAPReply(t *testing.T) {
+	result, err := parseICAPReply("ICAP/1.0 200 OK", textproto.MIMEHeader{"X-Virus-Id": {"Win.Test.EICAR_HDB-1"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Win.Test.EICAR_HDB-1", result.Threat)
+
+	result, err = parseICAPReply("ICAP/1.0 200 OK", textproto.MIMEHeader{})
+	assert.NoError(t, err)
+	assert.True(t, result.Infected)
+
+	_, err = parseICAPReply("ICAP/1.0 500 Server Error", textproto.MIMEHeader{})
+	assert.Error(t, err)
+	_, err = parseICAPReply("HTTP/1.1 200 OK", textproto.MIMEHeader{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,87 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// clamavChunkSize is below the default StreamMaxLength of clamd
+const clamavChunkSize = 1 << 20
+
+type clamavScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamAV returns a scanner streaming the media to clamd with the INSTREAM command
+func NewClamAV(address string, timeout time.Duration) (Scanner, error) {
+	if address == "" {
+		address = "tcp://localhost:3310"
+	}
+	parsed, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid clamav address %q: %w", address, err)
+	}
+
+	switch parsed.Scheme {
+	case "tcp":
+		return &clamavScanner{network: "tcp", address: parsed.Host, timeout: timeout}, nil
+	case "unix":
+		return &clamavScanner{network: "unix", address: parsed.Path, timeout: timeout}, nil
+	default:
+		return nil, fmt.Errorf("invalid clamav address %q, use tcp://host:port or unix:///path", address)
+	}
+}
+
+func (scanner *clamavScanner) Scan(ctx context.Context, data []byte) (Result, error) {
+	dialer := net.Dialer{Timeout: scanner.timeout}
+	conn, err := dialer.DialContext(ctx, scanner.network, scanner.address)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to clamav: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(scanner.timeout))
+
+	if _, err = conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, fmt.Errorf("failed to send to clamav: %w", err)
+	}
+	for len(data) > 0 {
+		chunk := data[:min(len(data), clamavChunkSize)]
+		data = data[len(chunk):]
+		if err = binary.Write(conn, binary.BigEndian, uint32(len(chunk))); err == nil {
+			_, err = conn.Write(chunk)
+		}
+		if err != nil {
+			return Result{}, fmt.Errorf("failed to send to clamav: %w", err)
+		}
+	}
+	if err = binary.Write(conn, binary.BigEndian, uint32(0)); err != nil {
+		return Result{}, fmt.Errorf("failed to send to clamav: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil && reply == "" {
+		return Result{}, fmt.Errorf("failed to read clamav reply: %w", err)
+	}
+	return parseClamAVReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamAVReply reads replies like "stream: OK" or "stream: Eicar-Test-Signature FOUND"
+func parseClamAVReply(reply string) (Result, error) {
+	verdict := strings.TrimSpace(strings.TrimPrefix(reply, "stream:"))
+	switch {
+	case verdict == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return Result{Infected: true, Threat: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return Result{}, fmt.Errorf("clamav scan failed: %s", reply)
+	}
+}
@@ -0,0 +1,107 @@
+package antivirus
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"net/textproto"
+	"net/url"
+	"strings"
+	"time"
+)
+
+type icapScanner struct {
+	url     *url.URL
+	timeout time.Duration
+}
+
+// NewICAP returns a scanner sending the media to an ICAP server as the body of a RESPMOD request
+func NewICAP(address string, timeout time.Duration) (Scanner, error) {
+	parsed, err := url.Parse(address)
+	if err != nil || parsed.Scheme != "icap" || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid icap address %q, use icap://host:1344/service", address)
+	}
+	if parsed.Port() == "" {
+		parsed.Host = net.JoinHostPort(parsed.Hostname(), "1344")
+	}
+	return &icapScanner{url: parsed, timeout: timeout}, nil
+}
+
+func (scanner *icapScanner) Scan(ctx context.Context, data []byte) (Result, error) {
+	dialer := net.Dialer{Timeout: scanner.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", scanner.url.Host)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to connect to icap: %w", err)
+	}
+	defer conn.Close()
+	_ = conn.SetDeadline(time.Now().Add(scanner.timeout))
+
+	if _, err = conn.Write(buildICAPRequest(scanner.url, data)); err != nil {
+		return Result{}, fmt.Errorf("failed to send to icap: %w", err)
+	}
+
+	reader := textproto.NewReader(bufio.NewReader(conn))
+	status, err := reader.ReadLine()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read icap reply: %w", err)
+	}
+	header, err := reader.ReadMIMEHeader()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to read icap reply: %w", err)
+	}
+	return parseICAPReply(status, header)
+}
+
+// buildICAPRequest wraps the media in a RESPMOD request, the body is sent at once with chunked encoding
+func buildICAPRequest(service *url.URL, data []byte) []byte {
+	httpHeader := fmt.Sprintf("HTTP/1.1 200 OK\r\nContent-Type: application/octet-stream\r\nContent-Length: %d\r\n\r\n", len(data))
+
+	var request strings.Builder
+	fmt.Fprintf(&request, "RESPMOD %s ICAP/1.0\r\n", service.String())
+	fmt.Fprintf(&request, "Host: %s\r\n", service.Host)
+	request.WriteString("Allow: 204\r\n")
+	fmt.Fprintf(&request, "Encapsulated: res-hdr=0, res-body=%d\r\n\r\n", len(httpHeader))
+	request.WriteString(httpHeader)
+	if len(data) > 0 {
+		fmt.Fprintf(&request, "%x\r\n", len(data))
+		request.Write(data)
+		request.WriteString("\r\n")
+	}
+	request.WriteString("0\r\n\r\n")
+	return []byte(request.String())
+}
+
+// parseICAPReply reads the verdict: 204 is clean, a 200 replaced the media, e.g. by a block page
+func parseICAPReply(status string, header textproto.MIMEHeader) (Result, error) {
+	fields := strings.Fields(status)
+	if len(fields) < 2 || !strings.HasPrefix(fields[0], "ICAP/") {
+		return Result{}, fmt.Errorf("invalid icap reply %q", status)
+	}
+
+	switch fields[1] {
+	case "204":
+		return Result{}, nil
+	case "200":
+		return Result{Infected: true, Threat: icapThreat(header)}, nil
+	default:
+		return Result{}, fmt.Errorf("icap scan failed: %s", status)
+	}
+}
+
+// icapThreat reads the detection from the headers set by the common ICAP servers
+func icapThreat(header textproto.MIMEHeader) string {
+	if infection := header.Get("X-Infection-Found"); infection != "" {
+		for _, part := range strings.Split(infection, ";") {
+			if name, value, found := strings.Cut(strings.TrimSpace(part), "="); found && name == "Threat" {
+				return value
+			}
+		}
+	}
+	for _, key := range []string{"X-Virus-ID", "X-Violations-Found"} {
+		if threat := header.Get(key); threat != "" {
+			return strings.TrimSpace(threat)
+		}
+	}
+	return "blocked by icap server"
+}
@@ -70,6 +70,23 @@ func (e WaUploadMediaError) StatusCode() int {
 	return http.StatusInternalServerError
 }
 
+type MediaBlockedError string
+
+// Error for complying the error interface
+func (e MediaBlockedError) Error() string {
+	return string(e)
+}
+
+// ErrCode will return the error code based on the error data type
+func (e MediaBlockedError) ErrCode() string {
+	return "MEDIA_BLOCKED"
+}
+
+// StatusCode will return the HTTP status code based on the error data type
+func (e MediaBlockedError) StatusCode() int {
+	return http.StatusUnprocessableEntity
+}
+
 const (
 	ErrInvalidJID        = InvalidJID("your JID is invalid")
 	ErrUserNotRegistered = InvalidJID("user is not registered")
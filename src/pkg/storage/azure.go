@@ -0,0 +1,86 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/sas"
+)
+
+// AzureConfig configures an Azure Blob Storage container, with either the connection string or the account key
+type AzureConfig struct {
+	ConnectionString string
+	AccountName      string
+	AccountKey       string
+	Container        string
+}
+
+type azureDriver struct {
+	client    *azblob.Client
+	container string
+	urls      urlConfig
+}
+
+// NewAzure returns a driver uploading the media to an Azure Blob Storage container
+func NewAzure(cfg AzureConfig, urls urlConfig) (Driver, error) {
+	if cfg.Container == "" {
+		return nil, fmt.Errorf("azure media storage needs a container")
+	}
+
+	var client *azblob.Client
+	var err error
+	switch {
+	case cfg.ConnectionString != "":
+		client, err = azblob.NewClientFromConnectionString(cfg.ConnectionString, nil)
+	case cfg.AccountName != "" && cfg.AccountKey != "":
+		var credential *azblob.SharedKeyCredential
+		if credential, err = azblob.NewSharedKeyCredential(cfg.AccountName, cfg.AccountKey); err == nil {
+			serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", cfg.AccountName)
+			client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+		}
+	default:
+		return nil, fmt.Errorf("azure media storage needs a connection string, or an account name and key")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create azure client: %w", err)
+	}
+	return &azureDriver{client: client, container: cfg.Container, urls: urls}, nil
+}
+
+func (driver *azureDriver) Save(ctx context.Context, key, localPath, contentType string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	_, err = driver.client.UploadFile(ctx, driver.container, key, file, &azblob.UploadFileOptions{
+		HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &contentType},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s to azure: %w", key, err)
+	}
+
+	if publicURL, ok := driver.urls.public(key); ok {
+		return publicURL, nil
+	}
+	blobClient := driver.client.ServiceClient().NewContainerClient(driver.container).NewBlobClient(key)
+	signed, err := blobClient.GetSASURL(sas.BlobPermissions{Read: true}, time.Now().Add(driver.urls.expiry), nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s: %w", key, err)
+	}
+	return signed, nil
+}
+
+func (driver *azureDriver) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	response, err := driver.client.DownloadStream(ctx, driver.container, key, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from azure: %w", key, err)
+	}
+	return response.Body, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/option"
+)
+
+// GCSConfig configures a Google Cloud Storage bucket
+type GCSConfig struct {
+	Bucket          string
+	CredentialsFile string // service account key, the application default credentials are used otherwise
+}
+
+type gcsDriver struct {
+	bucket *storage.BucketHandle
+	urls   urlConfig
+}
+
+// NewGCS returns a driver uploading the media to a Google Cloud Storage bucket
+func NewGCS(ctx context.Context, cfg GCSConfig, urls urlConfig) (Driver, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("gcs media storage needs a bucket")
+	}
+	if err := urls.validateSigV4(); err != nil {
+		return nil, err
+	}
+
+	var options []option.ClientOption
+	if cfg.CredentialsFile != "" {
+		options = append(options, option.WithCredentialsFile(cfg.CredentialsFile))
+	}
+	client, err := storage.NewClient(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gcs client: %w", err)
+	}
+	return &gcsDriver{bucket: client.Bucket(cfg.Bucket), urls: urls}, nil
+}
+
+func (driver *gcsDriver) Save(ctx context.Context, key, localPath, contentType string) (string, error) {
+	file, err := os.Open(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	writer := driver.bucket.Object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+	if _, err = io.Copy(writer, file); err != nil {
+		_ = writer.Close()
+		return "", fmt.Errorf("failed to upload %s to gcs: %w", key, err)
+	}
+	if err = writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to upload %s to gcs: %w", key, err)
+	}
+
+	if publicURL, ok := driver.urls.public(key); ok {
+		return publicURL, nil
+	}
+	signed, err := driver.bucket.SignedURL(key, &storage.SignedURLOptions{
+		Method:  "GET",
+		Expires: time.Now().Add(driver.urls.expiry),
+		Scheme:  storage.SigningSchemeV4,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to sign %s: %w", key, err)
+	}
+	return signed, nil
+}
+
+func (driver *gcsDriver) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := driver.bucket.Object(key).NewReader(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from gcs: %w", key, err)
+	}
+	return reader, nil
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config configures an S3 compatible storage, e.g. AWS S3 or MinIO
+type S3Config struct {
+	Endpoint  string // host[:port], without the scheme
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	UseSSL    bool
+}
+
+type s3Driver struct {
+	client *minio.Client
+	bucket string
+	urls   urlConfig
+}
+
+// NewS3 returns a driver uploading the media to an S3 compatible bucket
+func NewS3(cfg S3Config, urls urlConfig) (Driver, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 media storage needs a bucket")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "s3.amazonaws.com"
+	}
+	if err := urls.validateSigV4(); err != nil {
+		return nil, err
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+	return &s3Driver{client: client, bucket: cfg.Bucket, urls: urls}, nil
+}
+
+func (driver *s3Driver) Save(ctx context.Context, key, localPath, contentType string) (string, error) {
+	_, err := driver.client.FPutObject(ctx, driver.bucket, key, localPath, minio.PutObjectOptions{ContentType: contentType})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload %s to s3: %w", key, err)
+	}
+
+	if publicURL, ok := driver.urls.public(key); ok {
+		return publicURL, nil
+	}
+	presigned, err := driver.client.PresignedGetObject(ctx, driver.bucket, key, driver.urls.expiry, url.Values{})
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return presigned.String(), nil
+}
+
+func (driver *s3Driver) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	object, err := driver.client.GetObject(ctx, driver.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s from s3: %w", key, err)
+	}
+	return object, nil
+}
@@ -0,0 +1,79 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Driver uploads the media downloaded from WhatsApp out of the local media folder
+type Driver interface {
+	// Save uploads the local file under the key and returns the url it can be fetched from
+	Save(ctx context.Context, key, localPath, contentType string) (string, error)
+	// Open reads back an uploaded file, e.g. to inline it once the local copy is removed
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// Config selects and configures the media storage driver
+type Config struct {
+	Driver    string        // local, s3, gcs or azure
+	PublicURL string        // base url of a public bucket or CDN, the payload gets signed urls otherwise
+	URLExpiry time.Duration // lifetime of the signed urls
+	S3        S3Config
+	GCS       GCSConfig
+	Azure     AzureConfig
+}
+
+// New returns the configured driver, nil for local, where the media stays in the media folder
+func New(ctx context.Context, cfg Config) (Driver, error) {
+	if cfg.URLExpiry <= 0 {
+		cfg.URLExpiry = 24 * time.Hour
+	}
+	urls := urlConfig{publicURL: cfg.PublicURL, expiry: cfg.URLExpiry}
+
+	switch strings.ToLower(cfg.Driver) {
+	case "", "local":
+		return nil, nil
+	case "s3":
+		return NewS3(cfg.S3, urls)
+	case "gcs":
+		return NewGCS(ctx, cfg.GCS, urls)
+	case "azure":
+		return NewAzure(cfg.Azure, urls)
+	default:
+		return nil, fmt.Errorf("unknown media storage driver %q, use local, s3, gcs or azure", cfg.Driver)
+	}
+}
+
+// ObjectKey is the name of the uploaded file, the local file name under the prefix
+func ObjectKey(prefix, localPath string) string {
+	return path.Join(strings.Trim(prefix, "/"), filepath.Base(localPath))
+}
+
+// urlConfig is how the drivers build the url of an uploaded file
+type urlConfig struct {
+	publicURL string
+	expiry    time.Duration
+}
+
+// public returns the url of the key under the public base url, if there's one
+func (urls urlConfig) public(key string) (string, bool) {
+	if urls.publicURL == "" {
+		return "", false
+	}
+	return strings.TrimSuffix(urls.publicURL, "/") + "/" + key, true
+}
+
+// maxSignedURLExpiry is the longest lifetime of the SigV4 urls of S3 and GCS
+const maxSignedURLExpiry = 7 * 24 * time.Hour
+
+func (urls urlConfig) validateSigV4() error {
+	if urls.publicURL == "" && urls.expiry > maxSignedURLExpiry {
+		return fmt.Errorf("signed urls can't expire after more than 7 days")
+	}
+	return nil
+}
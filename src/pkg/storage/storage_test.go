@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		cfg        Config
+		wantDriver bool
+		wantErr    bool
+	}{
+		{name: "should keep the media local by default", cfg: Config{}},
+		{name: "should keep the media local", cfg: Config{Driver: "local"}},
+		{
+			name:       "should create the s3 driver",
+			cfg:        Config{Driver: "S3", S3: S3Config{Bucket: "media", Endpoint: "localhost:9000"}},
+			wantDriver: true,
+		},
+		{name: "should reject s3 without a bucket", cfg: Config{Driver: "s3"}, wantErr: true},
+		{
+			name:    "should reject a signed url expiry above 7 days",
+			cfg:     Config{Driver: "s3", URLExpiry: 8 * 24 * time.Hour, S3: S3Config{Bucket: "media"}},
+			wantErr: true,
+		},
+		{
+			name:       "should ignore the expiry of public urls",
+			cfg:        Config{Driver: "s3", URLExpiry: 8 * 24 * time.Hour, PublicURL: "https://cdn.example.com", S3: S3Config{Bucket: "media"}},
+			wantDriver: true,
+		},
+		{name: "should reject gcs without a bucket", cfg: Config{Driver: "gcs"}, wantErr: true},
+		{
+			name: "should create the azure driver",
+			cfg: Config{Driver: "azure", Azure: AzureConfig{
+				AccountName: "account", AccountKey: "a2V5", Container: "media",
+			}},
+			wantDriver: true,
+		},
+		{name: "should reject azure without a container", cfg: Config{Driver: "azure"}, wantErr: true},
+		{
+			name:    "should reject azure without credentials",
+			cfg:     Config{Driver: "azure", Azure: AzureConfig{Container: "media"}},
+			wantErr: true,
+		},
+		{name: "should reject an unknown driver", cfg: Config{Driver: "ftp"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			driver, err := New(context.Background(), tt.cfg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantDriver, driver != nil)
+		})
+	}
+}
+
+func TestObjectKey(t *testing.T) {
+	assert.Equal(t, "1700000000-abc.jpg", ObjectKey("", "statics/media/1700000000-abc.jpg"))
+	assert.Equal(t, "bot/media/1700000000-abc.jpg", ObjectKey("/bot/media/", "statics/media/1700000000-abc.jpg"))
+}
+
+func TestPublicURL(t *testing.T) {
+	publicURL, ok := urlConfig{publicURL: "https://cdn.example.com/"}.public("bot/a.jpg")
+	assert.True(t, ok)
+	assert.Equal(t, "https://cdn.example.com/bot/a.jpg", publicURL)
+
+	_, ok = urlConfig{}.public("bot/a.jpg")
+	assert.False(t, ok)
+}
@@ -0,0 +1,12 @@
+package utils
+
+import "strings"
+
+// CSVCell escapes a value written to a CSV export. Spreadsheets run the cells starting with =, +, -, @, a tab or
+// a carriage return as formulas, those are prefixed with a quote so a push name opens as plain text.
+func CSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
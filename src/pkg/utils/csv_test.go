@@ -0,0 +1,29 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"Jane", "Jane"},
+		{"", ""},
+		{"628123456789@s.whatsapp.net", "628123456789@s.whatsapp.net"},
+		{"=HYPERLINK(\"http://evil.test\")", "'=HYPERLINK(\"http://evil.test\")"},
+		{"+1+2", "'+1+2"},
+		{"-2+3", "'-2+3"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"Jane = Joe", "Jane = Joe"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, utils.CSVCell(tt.value), tt.value)
+	}
+}
@@ -0,0 +1,76 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+)
+
+// VCard holds the fields of a contact card
+type VCard struct {
+	Name         string
+	Phone        string
+	Organization string
+	Email        string
+}
+
+// String returns the vCard 3.0 of the contact, the phone is linked to its WhatsApp account
+func (card VCard) String() string {
+	name := escapeVCardValue(card.Name)
+	phone := onlyDigits(card.Phone)
+
+	var builder strings.Builder
+	builder.WriteString("BEGIN:VCARD\nVERSION:3.0\n")
+	fmt.Fprintf(&builder, "N:;%s;;;\nFN:%s\n", name, name)
+	if card.Organization != "" {
+		fmt.Fprintf(&builder, "ORG:%s\n", escapeVCardValue(card.Organization))
+	}
+	fmt.Fprintf(&builder, "TEL;type=CELL;waid=%s:+%s\n", phone, phone)
+	if card.Email != "" {
+		fmt.Fprintf(&builder, "EMAIL:%s\n", escapeVCardValue(card.Email))
+	}
+	builder.WriteString("END:VCARD")
+	return builder.String()
+}
+
+// IsVCard tells if the text looks like a vCard
+func IsVCard(text string) bool {
+	text = strings.TrimSpace(text)
+	return strings.HasPrefix(strings.ToUpper(text), "BEGIN:VCARD") && strings.HasSuffix(strings.ToUpper(text), "END:VCARD")
+}
+
+// VCardDisplayName returns the formatted name (FN) of a vCard, empty when it has none
+func VCardDisplayName(vcard string) string {
+	for _, line := range strings.Split(strings.ReplaceAll(vcard, "\r\n", "\n"), "\n") {
+		property, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		// the property can have parameters, like FN;CHARSET=UTF-8
+		if name, _, _ := strings.Cut(property, ";"); strings.EqualFold(name, "FN") {
+			return unescapeVCardValue(strings.TrimSpace(value))
+		}
+	}
+	return ""
+}
+
+var (
+	vCardEscaper   = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`)
+	vCardUnescaper = strings.NewReplacer(`\\`, `\`, `\;`, ";", `\,`, ",", `\n`, "\n", `\N`, "\n")
+)
+
+func escapeVCardValue(value string) string {
+	return vCardEscaper.Replace(value)
+}
+
+func unescapeVCardValue(value string) string {
+	return vCardUnescaper.Replace(value)
+}
+
+func onlyDigits(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+}
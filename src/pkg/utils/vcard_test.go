@@ -0,0 +1,29 @@
+package utils_test
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVCardString(t *testing.T) {
+	card := utils.VCard{Name: "Aldino Kemal", Phone: "+62 812-3456-789"}
+	assert.Equal(t, "BEGIN:VCARD\nVERSION:3.0\nN:;Aldino Kemal;;;\nFN:Aldino Kemal\nTEL;type=CELL;waid=628123456789:+628123456789\nEND:VCARD", card.String())
+
+	card = utils.VCard{Name: "Doe; John", Phone: "628123456789", Organization: "Acme, Inc", Email: "john@acme.test"}
+	assert.Equal(t, "BEGIN:VCARD\nVERSION:3.0\nN:;Doe\\; John;;;\nFN:Doe\\; John\nORG:Acme\\, Inc\n"+
+		"TEL;type=CELL;waid=628123456789:+628123456789\nEMAIL:john@acme.test\nEND:VCARD", card.String())
+	assert.Equal(t, "Doe; John", utils.VCardDisplayName(card.String()))
+}
+
+func TestVCardDisplayName(t *testing.T) {
+	assert.Equal(t, "Jane", utils.VCardDisplayName("BEGIN:VCARD\r\nVERSION:3.0\r\nFN;CHARSET=UTF-8:Jane\r\nEND:VCARD"))
+	assert.Equal(t, "", utils.VCardDisplayName("BEGIN:VCARD\nVERSION:3.0\nEND:VCARD"))
+}
+
+func TestIsVCard(t *testing.T) {
+	assert.True(t, utils.IsVCard("BEGIN:VCARD\nVERSION:3.0\nFN:Jane\nEND:VCARD\n"))
+	assert.True(t, utils.IsVCard("begin:vcard\nend:vcard"))
+	assert.False(t, utils.IsVCard("FN:Jane"))
+}
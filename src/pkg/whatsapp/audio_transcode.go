@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+type audioTranscodeFormat struct {
+	Codec    string
+	MimeType string
+}
+
+// audioTranscodeFormats are the targets of --audio-transcode, the received voice notes are Opus in an OGG container
+var audioTranscodeFormats = map[string]audioTranscodeFormat{
+	"mp3": {Codec: "libmp3lame", MimeType: "audio/mpeg"},
+	"ogg": {Codec: "libvorbis", MimeType: "audio/ogg"},
+	"m4a": {Codec: "aac", MimeType: "audio/mp4"},
+	"wav": {Codec: "pcm_s16le", MimeType: "audio/wav"},
+}
+
+// ValidateAudioTranscode checks the configured target format, an empty format disables the transcoding.
+func ValidateAudioTranscode(format string) error {
+	if format == "" {
+		return nil
+	}
+	if _, ok := audioTranscodeFormats[strings.ToLower(format)]; !ok {
+		return fmt.Errorf("unsupported audio transcode format %q, use mp3, ogg, m4a or wav", format)
+	}
+	return nil
+}
+
+// transcodeAudio stores a copy of the received audio in the given format next to the original, using ffmpeg.
+func transcodeAudio(media ExtractedMedia, format, bitrate string) (ExtractedMedia, error) {
+	format = strings.ToLower(format)
+	target, ok := audioTranscodeFormats[format]
+	if !ok {
+		return media, fmt.Errorf("unsupported audio transcode format %q", format)
+	}
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return media, fmt.Errorf("ffmpeg not installed, can't transcode audio")
+	}
+
+	destination := strings.TrimSuffix(media.MediaPath, filepath.Ext(media.MediaPath)) + "." + format
+	if destination == media.MediaPath {
+		destination = strings.TrimSuffix(media.MediaPath, filepath.Ext(media.MediaPath)) + "-transcoded." + format
+	}
+	err := convertMediaFile(media.MediaPath, destination, func(source, destination string) error {
+		args := audioTranscodeArgs(source, destination, target.Codec, bitrate)
+		if output, err := exec.Command("ffmpeg", args...).CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, output)
+		}
+		return nil
+	})
+	if err != nil {
+		return media, fmt.Errorf("failed to transcode audio: %w", err)
+	}
+	media.ConvertedPath, media.ConvertedMimeType = destination, target.MimeType
+	return media, nil
+}
+
+func audioTranscodeArgs(source, destination, codec, bitrate string) []string {
+	args := []string{"-y", "-i", source, "-vn", "-c:a", codec}
+	// the bitrate doesn't apply to uncompressed output
+	if bitrate != "" && codec != "pcm_s16le" {
+		args = append(args, "-b:a", bitrate)
+	}
+	return append(args, destination)
+}
@@ -0,0 +1,71 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAudioTranscode(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		wantErr bool
+	}{
+		{name: "should accept an empty format", format: ""},
+		{name: "should accept mp3", format: "mp3"},
+		{name: "should accept an upper case format", format: "OGG"},
+		{name: "should reject an unknown format", format: "flac", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateAudioTranscode(tt.format)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestAudioTranscodeArgs(t *testing.T) {
+	tests := []struct {
+		name     string
+		codec    string
+		bitrate  string
+		expected []string
+	}{
+		{
+			name:     "should set the bitrate",
+			codec:    "libmp3lame",
+			bitrate:  "64k",
+			expected: []string{"-y", "-i", "in.oga", "-vn", "-c:a", "libmp3lame", "-b:a", "64k", "out"},
+		},
+		{
+			name:     "should leave the bitrate to ffmpeg when empty",
+			codec:    "libvorbis",
+			expected: []string{"-y", "-i", "in.oga", "-vn", "-c:a", "libvorbis", "out"},
+		},
+		{
+			name:     "should skip the bitrate of wav",
+			codec:    "pcm_s16le",
+			bitrate:  "64k",
+			expected: []string{"-y", "-i", "in.oga", "-vn", "-c:a", "pcm_s16le", "out"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, audioTranscodeArgs("in.oga", "out", tt.codec, tt.bitrate))
+		})
+	}
+}
+
+func TestTranscodeAudioUnsupportedFormat(t *testing.T) {
+	media := ExtractedMedia{MediaPath: "voice.oga", MimeType: "audio/ogg; codecs=opus"}
+	result, err := transcodeAudio(media, "flac", "64k")
+	assert.Error(t, err)
+	assert.Equal(t, media, result)
+}
@@ -0,0 +1,248 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+var broadcastMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS broadcasts (
+		id           TEXT    PRIMARY KEY,
+		message      TEXT    NOT NULL,
+		media_id     TEXT    NOT NULL DEFAULT '',
+		delay        INTEGER NOT NULL,
+		jitter       INTEGER NOT NULL,
+		status       TEXT    NOT NULL,
+		created_at   INTEGER NOT NULL,
+		completed_at INTEGER
+	)`,
+	`CREATE TABLE IF NOT EXISTS broadcast_recipients (
+		broadcast_id TEXT    NOT NULL,
+		position     INTEGER NOT NULL,
+		phone        TEXT    NOT NULL,
+		variables    TEXT    NOT NULL,
+		status       TEXT    NOT NULL,
+		message_id   TEXT    NOT NULL DEFAULT '',
+		error        TEXT    NOT NULL DEFAULT '',
+		sent_at      INTEGER,
+		PRIMARY KEY (broadcast_id, position)
+	)`,
+}
+
+const (
+	BroadcastStatusRunning   = "running"
+	BroadcastStatusCompleted = "completed"
+
+	BroadcastRecipientPending = "pending"
+	BroadcastRecipientSent    = "sent"
+	BroadcastRecipientFailed  = "failed"
+)
+
+// Broadcast is a message sent to many recipients, one at a time by a paced worker
+type Broadcast struct {
+	ID          string
+	Message     string // template, {{name}} is replaced by the variable of the recipient
+	MediaID     string
+	Delay       time.Duration
+	Jitter      time.Duration
+	Status      string
+	CreatedAt   time.Time
+	CompletedAt time.Time
+	Recipients  []BroadcastRecipient
+}
+
+type BroadcastRecipient struct {
+	Position  int
+	Phone     string
+	Variables map[string]string
+	Status    string
+	MessageID string
+	Error     string
+	SentAt    time.Time
+}
+
+var broadcastVariable = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// RenderBroadcastMessage replaces the {{name}} placeholders of the template, unknown ones are left as is
+func RenderBroadcastMessage(template string, variables map[string]string) string {
+	return broadcastVariable.ReplaceAllStringFunc(template, func(placeholder string) string {
+		if value, ok := variables[broadcastVariable.FindStringSubmatch(placeholder)[1]]; ok {
+			return value
+		}
+		return placeholder
+	})
+}
+
+// CreateBroadcast stores a broadcast and its recipients, all pending
+func CreateBroadcast(broadcast Broadcast) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	tx, err := appStore.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err = tx.Exec(
+		`INSERT INTO broadcasts (id, message, media_id, delay, jitter, status, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		broadcast.ID, broadcast.Message, broadcast.MediaID, broadcast.Delay.Milliseconds(), broadcast.Jitter.Milliseconds(),
+		BroadcastStatusRunning, broadcast.CreatedAt.Unix(),
+	); err != nil {
+		return err
+	}
+	for position, recipient := range broadcast.Recipients {
+		variables, err := json.Marshal(recipient.Variables)
+		if err != nil {
+			return err
+		}
+		if _, err = tx.Exec(
+			`INSERT INTO broadcast_recipients (broadcast_id, position, phone, variables, status) VALUES (?, ?, ?, ?, ?)`,
+			broadcast.ID, position, recipient.Phone, string(variables), BroadcastRecipientPending,
+		); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetBroadcast returns a broadcast with the status of its recipients
+func GetBroadcast(id string) (Broadcast, error) {
+	broadcast := Broadcast{ID: id}
+	if appStore == nil {
+		return broadcast, errStoreNotInitialized
+	}
+
+	var (
+		delay, jitter, createdAt int64
+		completedAt              sql.NullInt64
+	)
+	err := appStore.QueryRow(
+		`SELECT message, media_id, delay, jitter, status, created_at, completed_at FROM broadcasts WHERE id = ?`, id,
+	).Scan(&broadcast.Message, &broadcast.MediaID, &delay, &jitter, &broadcast.Status, &createdAt, &completedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return broadcast, pkgError.NotFoundError(fmt.Sprintf("broadcast %s not found", id))
+	}
+	if err != nil {
+		return broadcast, err
+	}
+	broadcast.Delay, broadcast.Jitter = time.Duration(delay)*time.Millisecond, time.Duration(jitter)*time.Millisecond
+	broadcast.CreatedAt = time.Unix(createdAt, 0)
+	if completedAt.Valid {
+		broadcast.CompletedAt = time.Unix(completedAt.Int64, 0)
+	}
+
+	rows, err := appStore.Query(
+		`SELECT position, phone, variables, status, message_id, error, sent_at FROM broadcast_recipients
+		WHERE broadcast_id = ? ORDER BY position`, id,
+	)
+	if err != nil {
+		return broadcast, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			recipient BroadcastRecipient
+			variables string
+			sentAt    sql.NullInt64
+		)
+		if err = rows.Scan(&recipient.Position, &recipient.Phone, &variables, &recipient.Status, &recipient.MessageID,
+			&recipient.Error, &sentAt); err != nil {
+			return broadcast, err
+		}
+		if err = json.Unmarshal([]byte(variables), &recipient.Variables); err != nil {
+			return broadcast, err
+		}
+		if sentAt.Valid {
+			recipient.SentAt = time.Unix(sentAt.Int64, 0)
+		}
+		broadcast.Recipients = append(broadcast.Recipients, recipient)
+	}
+	return broadcast, rows.Err()
+}
+
+// UpdateBroadcastRecipient records the outcome of the send to a recipient
+func UpdateBroadcastRecipient(id string, recipient BroadcastRecipient) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+	_, err := appStore.Exec(
+		`UPDATE broadcast_recipients SET status = ?, message_id = ?, error = ?, sent_at = ? WHERE broadcast_id = ? AND position = ?`,
+		recipient.Status, recipient.MessageID, recipient.Error, recipient.SentAt.Unix(), id, recipient.Position,
+	)
+	return err
+}
+
+// CompleteBroadcast marks a broadcast as done once every recipient was tried
+func CompleteBroadcast(id string, completedAt time.Time) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+	_, err := appStore.Exec(
+		`UPDATE broadcasts SET status = ?, completed_at = ? WHERE id = ?`, BroadcastStatusCompleted, completedAt.Unix(), id,
+	)
+	return err
+}
+
+// RunningBroadcasts returns the ID of the broadcasts still sending, resumed after a restart
+func RunningBroadcasts() ([]string, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+	rows, err := appStore.Query(`SELECT id FROM broadcasts WHERE status = ? ORDER BY created_at`, BroadcastStatusRunning)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err = rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// ForwardBroadcastToWebhook sends the broadcast.completed event with the status of every recipient
+func ForwardBroadcastToWebhook(broadcast Broadcast) {
+	sent, failed := 0, 0
+	recipients := make([]map[string]interface{}, 0, len(broadcast.Recipients))
+	for _, recipient := range broadcast.Recipients {
+		switch recipient.Status {
+		case BroadcastRecipientSent:
+			sent++
+		case BroadcastRecipientFailed:
+			failed++
+		}
+		recipients = append(recipients, map[string]interface{}{
+			"phone":      recipient.Phone,
+			"status":     recipient.Status,
+			"message_id": recipient.MessageID,
+			"error":      recipient.Error,
+		})
+	}
+
+	body := map[string]interface{}{
+		"event_type":   "broadcast.completed",
+		"broadcast_id": broadcast.ID,
+		"total":        len(broadcast.Recipients),
+		"sent":         sent,
+		"failed":       failed,
+		"recipients":   recipients,
+		"completed_at": broadcast.CompletedAt.UTC().Format(time.RFC3339),
+	}
+	enqueueWebhook("broadcast.completed", func() error {
+		return forwardEventToWebhook("broadcast.completed", body)
+	})
+}
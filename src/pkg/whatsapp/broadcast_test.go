@@ -0,0 +1,75 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderBroadcastMessage(t *testing.T) {
+	variables := map[string]string{"name": "Budi", "code": "X1"}
+	assert.Equal(t, "Hi Budi, your code is X1", RenderBroadcastMessage("Hi {{name}}, your code is {{ code }}", variables))
+	assert.Equal(t, "Hi {{nickname}}", RenderBroadcastMessage("Hi {{nickname}}", variables))
+	assert.Equal(t, "Hi {{name}}", RenderBroadcastMessage("Hi {{name}}", nil))
+}
+
+func TestBroadcast(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	now := time.Now().Truncate(time.Second)
+	assert.NoError(t, CreateBroadcast(Broadcast{
+		ID: "B1", Message: "Hi {{name}}", Delay: 5 * time.Second, Jitter: 1500 * time.Millisecond, CreatedAt: now,
+		Recipients: []BroadcastRecipient{
+			{Phone: "628111", Variables: map[string]string{"name": "Budi"}},
+			{Phone: "628222"},
+		},
+	}))
+
+	running, err := RunningBroadcasts()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"B1"}, running)
+
+	broadcast, err := GetBroadcast("B1")
+	assert.NoError(t, err)
+	assert.Equal(t, BroadcastStatusRunning, broadcast.Status)
+	assert.Equal(t, 1500*time.Millisecond, broadcast.Jitter)
+	assert.True(t, now.Equal(broadcast.CreatedAt))
+	assert.True(t, broadcast.CompletedAt.IsZero())
+	assert.Len(t, broadcast.Recipients, 2)
+	assert.Equal(t, "Budi", broadcast.Recipients[0].Variables["name"])
+	assert.Equal(t, BroadcastRecipientPending, broadcast.Recipients[1].Status)
+
+	assert.NoError(t, UpdateBroadcastRecipient("B1", BroadcastRecipient{
+		Position: 0, Status: BroadcastRecipientSent, MessageID: "MSG", SentAt: now,
+	}))
+	assert.NoError(t, UpdateBroadcastRecipient("B1", BroadcastRecipient{
+		Position: 1, Status: BroadcastRecipientFailed, Error: "not on whatsapp", SentAt: now,
+	}))
+	assert.NoError(t, CompleteBroadcast("B1", now))
+
+	broadcast, err = GetBroadcast("B1")
+	assert.NoError(t, err)
+	assert.Equal(t, BroadcastStatusCompleted, broadcast.Status)
+	assert.True(t, now.Equal(broadcast.CompletedAt))
+	assert.Equal(t, "MSG", broadcast.Recipients[0].MessageID)
+	assert.Equal(t, "not on whatsapp", broadcast.Recipients[1].Error)
+
+	running, err = RunningBroadcasts()
+	assert.NoError(t, err)
+	assert.Empty(t, running)
+
+	_, err = GetBroadcast("B2")
+	assert.Equal(t, pkgError.NotFoundError("broadcast B2 not found"), err)
+}
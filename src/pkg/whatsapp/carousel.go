@@ -0,0 +1,83 @@
+package whatsapp
+
+import (
+	"encoding/json"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// CarouselButton is a native flow button of a carousel card: reply, url or call
+type CarouselButton struct {
+	Type        string
+	ID          string
+	Text        string
+	URL         string
+	PhoneNumber string
+}
+
+// NativeFlowButton returns the native flow button the clients render for the carousel button
+func NativeFlowButton(button CarouselButton) *waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton {
+	var name string
+	params := map[string]string{"display_text": button.Text}
+	switch button.Type {
+	case "url":
+		name = "cta_url"
+		params["url"] = button.URL
+		params["merchant_url"] = button.URL
+	case "call":
+		name = "cta_call"
+		params["phone_number"] = button.PhoneNumber
+	default:
+		name = "quick_reply"
+		params["id"] = button.ID
+	}
+
+	paramsJSON, _ := json.Marshal(params)
+	return &waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{
+		Name:             proto.String(name),
+		ButtonParamsJSON: proto.String(string(paramsJSON)),
+	}
+}
+
+// CarouselCard returns a card of a carousel, its header is the image or video already uploaded to WhatsApp
+func CarouselCard(header *waE2E.Message, title, body, footer string, buttons []CarouselButton) *waE2E.InteractiveMessage {
+	card := &waE2E.InteractiveMessage{
+		Header: &waE2E.InteractiveMessage_Header{HasMediaAttachment: proto.Bool(true)},
+		Body:   &waE2E.InteractiveMessage_Body{Text: proto.String(body)},
+	}
+	if title != "" {
+		card.Header.Title = proto.String(title)
+	}
+	switch {
+	case header.GetImageMessage() != nil:
+		card.Header.Media = &waE2E.InteractiveMessage_Header_ImageMessage{ImageMessage: header.GetImageMessage()}
+	case header.GetVideoMessage() != nil:
+		card.Header.Media = &waE2E.InteractiveMessage_Header_VideoMessage{VideoMessage: header.GetVideoMessage()}
+	}
+	if footer != "" {
+		card.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(footer)}
+	}
+
+	nativeFlow := &waE2E.InteractiveMessage_NativeFlowMessage{MessageVersion: proto.Int32(1)}
+	for _, button := range buttons {
+		nativeFlow.Buttons = append(nativeFlow.Buttons, NativeFlowButton(button))
+	}
+	card.InteractiveMessage = &waE2E.InteractiveMessage_NativeFlowMessage_{NativeFlowMessage: nativeFlow}
+	return card
+}
+
+// NewCarouselMessage returns the interactive message showing the cards side by side under the body
+func NewCarouselMessage(body, footer string, cards []*waE2E.InteractiveMessage) *waE2E.InteractiveMessage {
+	msg := &waE2E.InteractiveMessage{
+		Body: &waE2E.InteractiveMessage_Body{Text: proto.String(body)},
+		InteractiveMessage: &waE2E.InteractiveMessage_CarouselMessage_{CarouselMessage: &waE2E.InteractiveMessage_CarouselMessage{
+			Cards:          cards,
+			MessageVersion: proto.Int32(1),
+		}},
+	}
+	if footer != "" {
+		msg.Footer = &waE2E.InteractiveMessage_Footer{Text: proto.String(footer)}
+	}
+	return msg
+}
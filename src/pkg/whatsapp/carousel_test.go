@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNativeFlowButton(t *testing.T) {
+	button := NativeFlowButton(CarouselButton{Type: "reply", ID: "buy_1", Text: "Buy"})
+	assert.Equal(t, "quick_reply", button.GetName())
+	assert.JSONEq(t, `{"display_text":"Buy","id":"buy_1"}`, button.GetButtonParamsJSON())
+
+	button = NativeFlowButton(CarouselButton{Type: "url", Text: "Open", URL: "https://shop.example.com"})
+	assert.Equal(t, "cta_url", button.GetName())
+	assert.JSONEq(t, `{"display_text":"Open","url":"https://shop.example.com","merchant_url":"https://shop.example.com"}`, button.GetButtonParamsJSON())
+
+	button = NativeFlowButton(CarouselButton{Type: "call", Text: "Call us", PhoneNumber: "+6289685028129"})
+	assert.Equal(t, "cta_call", button.GetName())
+	assert.JSONEq(t, `{"display_text":"Call us","phone_number":"+6289685028129"}`, button.GetButtonParamsJSON())
+}
+
+func TestNewCarouselMessage(t *testing.T) {
+	image := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{URL: proto.String("https://mmg.whatsapp.net/image")}}
+	video := &waE2E.Message{VideoMessage: &waE2E.VideoMessage{URL: proto.String("https://mmg.whatsapp.net/video")}}
+
+	cards := []*waE2E.InteractiveMessage{
+		CarouselCard(image, "Batik shirt", "Hand drawn", "IDR 150000", []CarouselButton{{Type: "reply", ID: "buy_1", Text: "Buy"}}),
+		CarouselCard(video, "", "Sarong", "", []CarouselButton{{Type: "reply", ID: "buy_2", Text: "Buy"}, {Type: "url", Text: "Open", URL: "https://shop.example.com"}}),
+	}
+	msg := NewCarouselMessage("New arrivals", "", cards)
+
+	assert.Equal(t, "New arrivals", msg.GetBody().GetText())
+	assert.Nil(t, msg.Footer)
+	assert.Len(t, msg.GetCarouselMessage().GetCards(), 2)
+
+	first := msg.GetCarouselMessage().GetCards()[0]
+	assert.Equal(t, "Batik shirt", first.GetHeader().GetTitle())
+	assert.True(t, first.GetHeader().GetHasMediaAttachment())
+	assert.Equal(t, "https://mmg.whatsapp.net/image", first.GetHeader().GetImageMessage().GetURL())
+	assert.Equal(t, "IDR 150000", first.GetFooter().GetText())
+	assert.Len(t, first.GetNativeFlowMessage().GetButtons(), 1)
+
+	second := msg.GetCarouselMessage().GetCards()[1]
+	assert.Nil(t, second.GetHeader().Title)
+	assert.Equal(t, "https://mmg.whatsapp.net/video", second.GetHeader().GetVideoMessage().GetURL())
+	assert.Nil(t, second.Footer)
+	assert.Len(t, second.GetNativeFlowMessage().GetButtons(), 2)
+}
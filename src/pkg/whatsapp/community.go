@@ -0,0 +1,114 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"golang.org/x/sync/singleflight"
+)
+
+// communityFailureBackoff is how long a failed lookup is kept, the messages of the group meanwhile go without
+// their community instead of each trying again
+const communityFailureBackoff = time.Minute
+
+type communityEntry struct {
+	community map[string]interface{}
+	expires   time.Time // set for the failed lookups only
+}
+
+// communityCache keeps the community of the groups the messages come from, nil for the groups outside of a
+// community. A change of a group drops its entry, it's how groups are linked or unlinked.
+var communityCache struct {
+	sync.Mutex
+	groups map[types.JID]communityEntry
+	// generation is bumped by each invalidation, a lookup started before isn't cached
+	generation uint64
+	fetches    singleflight.Group
+}
+
+// communityContext returns the `community` of the payloads of a group linked to a community, `announcement` is set
+// for the announcement group of the community
+func communityContext(info *types.GroupInfo) map[string]interface{} {
+	if info == nil || info.LinkedParentJID.IsEmpty() {
+		return nil
+	}
+	return map[string]interface{}{
+		"jid":          info.LinkedParentJID.String(),
+		"announcement": info.IsDefaultSubGroup,
+	}
+}
+
+// lookupCommunity returns the community context of the group, its info is fetched once until the group changes.
+// The concurrent lookups of a group share the same fetch, made without holding the cache.
+func lookupCommunity(group types.JID, fetch func(types.JID) (*types.GroupInfo, error)) map[string]interface{} {
+	if group.Server != types.GroupServer || fetch == nil {
+		return nil
+	}
+
+	communityCache.Lock()
+	entry, ok := communityCache.groups[group]
+	generation := communityCache.generation
+	communityCache.Unlock()
+	if ok && (entry.expires.IsZero() || time.Now().Before(entry.expires)) {
+		return entry.community
+	}
+
+	community, _, _ := communityCache.fetches.Do(group.String(), func() (interface{}, error) {
+		entry := communityEntry{}
+		info, err := fetch(group)
+		if err != nil {
+			logrus.Warnf("Failed to get the community of group %s: %v", group, err)
+			entry.expires = time.Now().Add(communityFailureBackoff)
+		} else {
+			entry.community = communityContext(info)
+		}
+
+		communityCache.Lock()
+		defer communityCache.Unlock()
+		if communityCache.generation == generation {
+			if communityCache.groups == nil {
+				communityCache.groups = make(map[types.JID]communityEntry)
+			}
+			communityCache.groups[group] = entry
+		}
+		return entry.community, nil
+	})
+	return community.(map[string]interface{})
+}
+
+// invalidateCommunities drops the groups of a group event, a community event drops the group it links or unlinks too
+func invalidateCommunities(evt interface{}) {
+	var groups []types.JID
+	switch evt := evt.(type) {
+	case *events.GroupInfo:
+		groups = append(groups, evt.JID)
+		if evt.Link != nil {
+			groups = append(groups, evt.Link.Group.JID)
+		}
+		if evt.Unlink != nil {
+			groups = append(groups, evt.Unlink.Group.JID)
+		}
+	case *events.JoinedGroup:
+		groups = append(groups, evt.JID)
+	case *events.Picture:
+		groups = append(groups, evt.JID)
+	}
+
+	communityCache.Lock()
+	defer communityCache.Unlock()
+	communityCache.generation++
+	for _, group := range groups {
+		delete(communityCache.groups, group)
+	}
+}
+
+// groupInfoFetcher returns how the community of a group is looked up, nil before the client is connected
+func groupInfoFetcher() func(types.JID) (*types.GroupInfo, error) {
+	if cli == nil {
+		return nil
+	}
+	return cli.GetGroupInfo
+}
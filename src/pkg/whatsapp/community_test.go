@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func resetCommunities() {
+	communityCache.Lock()
+	communityCache.groups = nil
+	communityCache.Unlock()
+}
+
+func TestLookupCommunity(t *testing.T) {
+	t.Cleanup(resetCommunities)
+	resetCommunities()
+
+	community := types.NewJID("120363000000000001", types.GroupServer)
+	announcement := types.NewJID("120363000000000002", types.GroupServer)
+	standalone := types.NewJID("120363000000000003", types.GroupServer)
+
+	var mu sync.Mutex
+	fetches := 0
+	fetch := func(group types.JID) (*types.GroupInfo, error) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		info := &types.GroupInfo{JID: group}
+		if group == announcement {
+			info.LinkedParentJID = community
+			info.IsDefaultSubGroup = true
+		}
+		return info, nil
+	}
+
+	assert.Equal(t, map[string]interface{}{"jid": community.String(), "announcement": true}, lookupCommunity(announcement, fetch))
+	assert.Nil(t, lookupCommunity(standalone, fetch))
+	lookupCommunity(announcement, fetch)
+	lookupCommunity(standalone, fetch)
+	assert.Equal(t, 2, fetches, "the groups are fetched once, with or without a community")
+
+	assert.Nil(t, lookupCommunity(types.NewJID("628123456789", types.DefaultUserServer), fetch), "private chats are skipped")
+	assert.Equal(t, 2, fetches)
+
+	t.Run("should only drop the changed groups", func(t *testing.T) {
+		invalidateCommunities(&events.GroupInfo{JID: standalone})
+		lookupCommunity(announcement, fetch)
+		lookupCommunity(standalone, fetch)
+		assert.Equal(t, 3, fetches)
+
+		invalidateCommunities(&events.GroupInfo{JID: community, Unlink: &types.GroupLinkChange{
+			Group: types.GroupLinkTarget{JID: announcement},
+		}})
+		lookupCommunity(announcement, fetch)
+		lookupCommunity(standalone, fetch)
+		assert.Equal(t, 4, fetches, "the unlinked group is fetched again")
+	})
+
+	t.Run("should keep a failed lookup for a while", func(t *testing.T) {
+		resetCommunities()
+		failing := func(types.JID) (*types.GroupInfo, error) { return nil, errors.New("not connected") }
+		assert.Nil(t, lookupCommunity(announcement, failing))
+		assert.Nil(t, lookupCommunity(announcement, fetch), "the failure is kept")
+		assert.Equal(t, 4, fetches)
+
+		communityCache.Lock()
+		communityCache.groups[announcement] = communityEntry{expires: time.Now().Add(-time.Second)}
+		communityCache.Unlock()
+		assert.NotNil(t, lookupCommunity(announcement, fetch), "the lookup is tried again after the backoff")
+		assert.Equal(t, 5, fetches)
+	})
+
+	t.Run("should share the fetch of concurrent lookups", func(t *testing.T) {
+		resetCommunities()
+		release := make(chan struct{})
+		slowFetches := 0
+		slow := func(group types.JID) (*types.GroupInfo, error) {
+			mu.Lock()
+			slowFetches++
+			mu.Unlock()
+			<-release
+			return &types.GroupInfo{JID: group}, nil
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				lookupCommunity(standalone, slow)
+			}()
+		}
+		// another group isn't held back by the pending fetch
+		assert.NotNil(t, lookupCommunity(announcement, fetch))
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		wg.Wait()
+		assert.Equal(t, 1, slowFetches)
+	})
+}
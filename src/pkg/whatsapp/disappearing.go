@@ -0,0 +1,25 @@
+package whatsapp
+
+import (
+	"fmt"
+	"time"
+
+	"go.mau.fi/whatsmeow"
+)
+
+// DisappearingTimerName returns the timer of a chat as it's set: off, 24h, 7d or 90d, the timers set by other
+// clients outside of these are in seconds
+func DisappearingTimerName(seconds uint32) string {
+	switch time.Duration(seconds) * time.Second {
+	case whatsmeow.DisappearingTimerOff:
+		return "off"
+	case whatsmeow.DisappearingTimer24Hours:
+		return "24h"
+	case whatsmeow.DisappearingTimer7Days:
+		return "7d"
+	case whatsmeow.DisappearingTimer90Days:
+		return "90d"
+	default:
+		return fmt.Sprintf("%ds", seconds)
+	}
+}
@@ -0,0 +1,15 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDisappearingTimerName(t *testing.T) {
+	assert.Equal(t, "off", DisappearingTimerName(0))
+	assert.Equal(t, "24h", DisappearingTimerName(86400))
+	assert.Equal(t, "7d", DisappearingTimerName(604800))
+	assert.Equal(t, "90d", DisappearingTimerName(7776000))
+	assert.Equal(t, "3600s", DisappearingTimerName(3600))
+}
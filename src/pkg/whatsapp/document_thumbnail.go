@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/disintegration/imaging"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+)
+
+// documentThumbnailSize is the largest side of the preview shown above a document
+const documentThumbnailSize = 480
+
+type DocumentThumbnail struct {
+	JPEG   []byte
+	Width  uint32
+	Height uint32
+}
+
+// EncodeDocumentThumbnail scales the image down to the preview size and encodes it as JPEG
+func EncodeDocumentThumbnail(image []byte) (DocumentThumbnail, error) {
+	src, err := imaging.Decode(bytes.NewReader(image), imaging.AutoOrientation(true))
+	if err != nil {
+		return DocumentThumbnail{}, fmt.Errorf("failed to decode the thumbnail: %v", err)
+	}
+	if bounds := src.Bounds(); bounds.Dx() > documentThumbnailSize || bounds.Dy() > documentThumbnailSize {
+		src = imaging.Fit(src, documentThumbnailSize, documentThumbnailSize, imaging.Lanczos)
+	}
+
+	var thumbnail bytes.Buffer
+	if err = imaging.Encode(&thumbnail, src, imaging.JPEG, imaging.JPEGQuality(75)); err != nil {
+		return DocumentThumbnail{}, fmt.Errorf("failed to encode the thumbnail: %v", err)
+	}
+	return DocumentThumbnail{
+		JPEG:   thumbnail.Bytes(),
+		Width:  uint32(src.Bounds().Dx()),
+		Height: uint32(src.Bounds().Dy()),
+	}, nil
+}
+
+// PDFThumbnail renders the first page of the PDF with pdftoppm, from poppler-utils
+func PDFThumbnail(pdf []byte) (DocumentThumbnail, error) {
+	if _, err := exec.LookPath("pdftoppm"); err != nil {
+		return DocumentThumbnail{}, fmt.Errorf("pdftoppm not installed, can't render the PDF thumbnail")
+	}
+
+	generateUUID := fiberUtils.UUIDv4()
+	sourcePath := filepath.Join(config.PathSendItems, generateUUID+".pdf")
+	pagePath := filepath.Join(config.PathSendItems, generateUUID)
+	defer func() { _ = utils.RemoveFile(0, sourcePath, pagePath+".jpg") }()
+
+	if err := os.WriteFile(sourcePath, pdf, 0600); err != nil {
+		return DocumentThumbnail{}, err
+	}
+	if output, err := exec.Command("pdftoppm", "-jpeg", "-f", "1", "-l", "1", "-singlefile",
+		"-scale-to", fmt.Sprint(documentThumbnailSize), sourcePath, pagePath).CombinedOutput(); err != nil {
+		return DocumentThumbnail{}, fmt.Errorf("failed to render the PDF thumbnail: %v: %s", err, output)
+	}
+
+	page, err := os.ReadFile(pagePath + ".jpg")
+	if err != nil {
+		return DocumentThumbnail{}, err
+	}
+	return EncodeDocumentThumbnail(page)
+}
@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeDocumentThumbnail(t *testing.T) {
+	encode := func(width, height int) []byte {
+		img := image.NewRGBA(image.Rect(0, 0, width, height))
+		for x := 0; x < width; x++ {
+			img.Set(x, 0, color.RGBA{R: 255, A: 255})
+		}
+		var buf bytes.Buffer
+		assert.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	thumbnail, err := EncodeDocumentThumbnail(encode(1000, 500))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(480), thumbnail.Width)
+	assert.Equal(t, uint32(240), thumbnail.Height)
+	decoded, err := jpeg.Decode(bytes.NewReader(thumbnail.JPEG))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 480, 240), decoded.Bounds())
+
+	thumbnail, err = EncodeDocumentThumbnail(encode(200, 300))
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(200), thumbnail.Width, "small images aren't scaled up")
+	assert.Equal(t, uint32(300), thumbnail.Height)
+
+	_, err = EncodeDocumentThumbnail([]byte("not an image"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var groupJoinMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS group_joins (
+		group_jid   TEXT    NOT NULL,
+		participant TEXT    NOT NULL,
+		joined_at   INTEGER NOT NULL,
+		PRIMARY KEY (group_jid, participant)
+	)`,
+}
+
+// recordGroupJoins keeps when the participants joined the group, WhatsApp only tells it with the join. A participant
+// joining again keeps the last join and one leaving is dropped.
+func recordGroupJoins(group types.JID, joined, left []types.JID, at time.Time) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	tx, err := appStore.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, participant := range joined {
+		if _, err = tx.Exec(
+			`INSERT OR REPLACE INTO group_joins (group_jid, participant, joined_at) VALUES (?, ?, ?)`,
+			group.String(), participant.ToNonAD().String(), at.Unix(),
+		); err != nil {
+			return err
+		}
+	}
+	for _, participant := range left {
+		if _, err = tx.Exec(`DELETE FROM group_joins WHERE group_jid = ? AND participant = ?`, group.String(), participant.ToNonAD().String()); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GroupJoinDates returns when the participants of the group joined by their JID, for the joins seen by this device
+func GroupJoinDates(group types.JID) (map[string]time.Time, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	rows, err := appStore.Query(`SELECT participant, joined_at FROM group_joins WHERE group_jid = ?`, group.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	joins := make(map[string]time.Time)
+	for rows.Next() {
+		var (
+			participant string
+			joinedAt    int64
+		)
+		if err = rows.Scan(&participant, &joinedAt); err != nil {
+			return nil, err
+		}
+		joins[participant] = time.Unix(joinedAt, 0)
+	}
+	return joins, rows.Err()
+}
+
+func handleGroupJoins(evt *events.GroupInfo) {
+	if appStore == nil || len(evt.Join) == 0 && len(evt.Leave) == 0 {
+		return
+	}
+	at := evt.Timestamp
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if err := recordGroupJoins(evt.JID, evt.Join, evt.Leave, at); err != nil {
+		logrus.Errorf("Failed to record the joins of group %s: %v", evt.JID, err)
+	}
+}
@@ -0,0 +1,42 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestGroupJoinDates(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	other := types.NewJID("120363025246125999", types.GroupServer)
+	first := types.NewADJID("628111111111", 0, 3)
+	second := types.NewJID("628222222222", types.DefaultUserServer)
+
+	joinedAt := time.Unix(1718700000, 0)
+	assert.NoError(t, recordGroupJoins(group, []types.JID{first, second}, nil, joinedAt))
+	assert.NoError(t, recordGroupJoins(other, []types.JID{second}, nil, joinedAt))
+
+	rejoinedAt := joinedAt.Add(time.Hour)
+	assert.NoError(t, recordGroupJoins(group, []types.JID{first}, []types.JID{second}, rejoinedAt))
+
+	joins, err := GroupJoinDates(group)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"628111111111@s.whatsapp.net": rejoinedAt}, joins)
+
+	joins, err = GroupJoinDates(other)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]time.Time{"628222222222@s.whatsapp.net": joinedAt}, joins)
+}
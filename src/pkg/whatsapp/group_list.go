@@ -0,0 +1,61 @@
+package whatsapp
+
+import (
+	"sync"
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"golang.org/x/sync/singleflight"
+)
+
+// joinedGroupsCache keeps the joined groups for --group-list-cache, a group change drops it
+var joinedGroupsCache struct {
+	sync.Mutex
+	groups    []*types.GroupInfo
+	fetchedAt time.Time
+	// generation is bumped by each invalidation, a fetch started before isn't cached
+	generation uint64
+	fetches    singleflight.Group
+}
+
+// JoinedGroups returns the groups the account is in from the cache younger than ttl, or fetches them. A ttl of 0 or
+// a refresh always fetches them. The concurrent lists share the same fetch, made without holding the cache.
+func JoinedGroups(fetch func() ([]*types.GroupInfo, error), ttl time.Duration, refresh bool) ([]*types.GroupInfo, error) {
+	joinedGroupsCache.Lock()
+	if ttl > 0 && !refresh && joinedGroupsCache.groups != nil && time.Since(joinedGroupsCache.fetchedAt) < ttl {
+		groups := joinedGroupsCache.groups
+		joinedGroupsCache.Unlock()
+		return groups, nil
+	}
+	generation := joinedGroupsCache.generation
+	joinedGroupsCache.Unlock()
+
+	groups, err, _ := joinedGroupsCache.fetches.Do("joined", func() (interface{}, error) {
+		groups, err := fetch()
+		if err != nil {
+			return nil, err
+		}
+		if groups == nil {
+			groups = []*types.GroupInfo{}
+		}
+
+		joinedGroupsCache.Lock()
+		defer joinedGroupsCache.Unlock()
+		if ttl > 0 && joinedGroupsCache.generation == generation {
+			joinedGroupsCache.groups, joinedGroupsCache.fetchedAt = groups, time.Now()
+		}
+		return groups, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return groups.([]*types.GroupInfo), nil
+}
+
+// invalidateJoinedGroups drops the cached groups, the next list fetches them again
+func invalidateJoinedGroups() {
+	joinedGroupsCache.Lock()
+	joinedGroupsCache.groups = nil
+	joinedGroupsCache.generation++
+	joinedGroupsCache.Unlock()
+}
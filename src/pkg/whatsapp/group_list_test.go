@@ -0,0 +1,85 @@
+package whatsapp
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestJoinedGroups(t *testing.T) {
+	t.Cleanup(invalidateJoinedGroups)
+	invalidateJoinedGroups()
+
+	fetches := 0
+	fetch := func() ([]*types.GroupInfo, error) {
+		fetches++
+		return []*types.GroupInfo{{GroupName: types.GroupName{Name: "Support"}}}, nil
+	}
+
+	groups, err := JoinedGroups(fetch, time.Minute, false)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 1)
+	_, _ = JoinedGroups(fetch, time.Minute, false)
+	assert.Equal(t, 1, fetches, "the second list comes from the cache")
+
+	_, _ = JoinedGroups(fetch, time.Minute, true)
+	assert.Equal(t, 2, fetches, "a refresh fetches them again")
+
+	invalidateJoinedGroups()
+	_, _ = JoinedGroups(fetch, time.Minute, false)
+	assert.Equal(t, 3, fetches, "a group change drops the cache")
+
+	_, _ = JoinedGroups(fetch, 0, false)
+	_, _ = JoinedGroups(fetch, 0, false)
+	assert.Equal(t, 5, fetches, "without a ttl nothing is cached")
+
+	_, err = JoinedGroups(func() ([]*types.GroupInfo, error) { return nil, errors.New("not connected") }, time.Minute, true)
+	assert.EqualError(t, err, "not connected")
+	groups, _ = JoinedGroups(fetch, time.Minute, false)
+	assert.Len(t, groups, 1)
+}
+
+func TestJoinedGroupsConcurrentFetch(t *testing.T) {
+	t.Cleanup(invalidateJoinedGroups)
+	invalidateJoinedGroups()
+
+	var mu sync.Mutex
+	started, release := make(chan struct{}), make(chan struct{})
+	fetches := 0
+	slow := func() ([]*types.GroupInfo, error) {
+		mu.Lock()
+		fetches++
+		if fetches == 1 {
+			close(started)
+		}
+		mu.Unlock()
+		<-release
+		return []*types.GroupInfo{{GroupName: types.GroupName{Name: "Support"}}}, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			groups, err := JoinedGroups(slow, time.Minute, false)
+			assert.NoError(t, err)
+			assert.Len(t, groups, 1)
+		}()
+	}
+	<-started
+	// a group change isn't held back by the pending fetch, whose result is then left out of the cache
+	invalidateJoinedGroups()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, 1, fetches)
+
+	joinedGroupsCache.Lock()
+	assert.Nil(t, joinedGroupsCache.groups)
+	joinedGroupsCache.Unlock()
+}
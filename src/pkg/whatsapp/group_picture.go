@@ -0,0 +1,33 @@
+package whatsapp
+
+import (
+	"bytes"
+	"fmt"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/disintegration/imaging"
+)
+
+// groupPictureSize is the largest side of a group picture WhatsApp accepts
+const groupPictureSize = 640
+
+// GroupPicture crops the image to a centered square, scales it down to 640x640 and encodes it as the JPEG
+// WhatsApp expects for group pictures
+func GroupPicture(data []byte) ([]byte, error) {
+	src, err := imaging.Decode(bytes.NewReader(data), imaging.AutoOrientation(true))
+	if err != nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("photo: failed to decode image: %v", err))
+	}
+
+	size := min(src.Bounds().Dx(), src.Bounds().Dy())
+	picture := imaging.CropCenter(src, size, size)
+	if size > groupPictureSize {
+		picture = imaging.Resize(picture, groupPictureSize, groupPictureSize, imaging.Lanczos)
+	}
+
+	var buf bytes.Buffer
+	if err = imaging.Encode(&buf, picture, imaging.JPEG, imaging.JPEGQuality(80)); err != nil {
+		return nil, fmt.Errorf("failed to encode group picture: %w", err)
+	}
+	return buf.Bytes(), nil
+}
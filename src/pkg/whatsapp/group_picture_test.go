@@ -0,0 +1,41 @@
+package whatsapp
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupPicture(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 1280, 800))
+	for x := 0; x < 1280; x++ {
+		for y := 0; y < 800; y++ {
+			src.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, src))
+
+	picture, err := GroupPicture(encoded.Bytes())
+	assert.NoError(t, err)
+	decoded, format, err := image.Decode(bytes.NewReader(picture))
+	assert.NoError(t, err)
+	assert.Equal(t, "jpeg", format)
+	assert.Equal(t, image.Rect(0, 0, 640, 640), decoded.Bounds())
+
+	small := image.NewRGBA(image.Rect(0, 0, 300, 200))
+	encoded.Reset()
+	assert.NoError(t, png.Encode(&encoded, small))
+	picture, err = GroupPicture(encoded.Bytes())
+	assert.NoError(t, err)
+	decoded, _, err = image.Decode(bytes.NewReader(picture))
+	assert.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 200, 200), decoded.Bounds())
+
+	_, err = GroupPicture([]byte("not an image"))
+	assert.Error(t, err)
+}
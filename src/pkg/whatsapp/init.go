@@ -13,7 +13,6 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/internal/websocket"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
-	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
 	"go.mau.fi/whatsmeow/proto/waE2E"
@@ -28,8 +27,30 @@ import (
 // Type definitions
 type ExtractedMedia struct {
 	MediaPath string `json:"media_path"`
+	MediaURL  string `json:"media_url,omitempty"` // set when the media storage uploads the media
+	MediaKey  string `json:"media_key,omitempty"` // object key of the uploaded media, it doesn't expire like the url
 	MimeType  string `json:"mime_type"`
 	Caption   string `json:"caption"`
+
+	// copy of the media in a more common format, e.g. a PNG of a WebP sticker
+	ConvertedPath     string `json:"converted_path,omitempty"`
+	ConvertedURL      string `json:"converted_url,omitempty"`
+	ConvertedKey      string `json:"converted_key,omitempty"`
+	ConvertedMimeType string `json:"converted_mime_type,omitempty"`
+
+	// audio only, the waveform is base64 encoded by the json marshaller
+	PTT      bool   `json:"ptt,omitempty"`
+	Duration uint32 `json:"duration,omitempty"` // seconds
+	Waveform []byte `json:"waveform,omitempty"`
+
+	// document only
+	FileName  string `json:"file_name,omitempty"`
+	FileSize  uint64 `json:"file_size,omitempty"`
+	PageCount uint32 `json:"page_count,omitempty"`
+
+	// set when the antivirus scan blocked the media, it's quarantined instead of saved
+	MediaBlocked bool   `json:"media_blocked,omitempty"`
+	Detection    string `json:"detection,omitempty"`
 }
 
 type evtReaction struct {
@@ -99,6 +120,7 @@ func InitWaCLI(storeContainer *sqlstore.Container) *whatsmeow.Client {
 	cli = whatsmeow.NewClient(device, waLog.Stdout("Client", config.WhatsappLogLevel, true))
 	cli.EnableAutoReconnect = true
 	cli.AutoTrustIdentity = true
+	cli.AutomaticMessageRerequestFromPhone = config.WhatsappRerequestFromPhone
 	cli.AddEventHandler(handler)
 
 	return cli
@@ -115,7 +137,10 @@ func handler(rawEvt interface{}) {
 		handlePairSuccess(evt)
 	case *events.LoggedOut:
 		handleLoggedOut()
-	case *events.Connected, *events.PushNameSetting:
+	case *events.Connected:
+		handleConnectionEvents()
+		go RetryFailedSends()
+	case *events.PushNameSetting:
 		handleConnectionEvents()
 	case *events.StreamReplaced:
 		handleStreamReplaced()
@@ -125,10 +150,28 @@ func handler(rawEvt interface{}) {
 		handleReceipt(evt)
 	case *events.Presence:
 		handlePresence(evt)
+	case *events.ChatPresence:
+		handleChatPresence(evt)
 	case *events.HistorySync:
 		handleHistorySync(evt)
 	case *events.AppState:
 		handleAppState(evt)
+	case *events.CallOffer, *events.CallOfferNotice, *events.CallAccept, *events.CallReject, *events.CallTerminate:
+		handleCall(evt)
+	case *events.GroupInfo, *events.JoinedGroup:
+		handleGroupChange(evt)
+	case *events.Picture:
+		handlePicture(evt)
+	case *events.Contact, *events.PushName:
+		handleContactChange(evt)
+	case *events.Blocklist:
+		handleBlocklist(evt)
+	case *events.Archive, *events.Pin, *events.Mute, *events.Star:
+		handleChatState(evt)
+	case *events.IdentityChange:
+		handleIdentityChange(evt)
+	case *events.UndecryptableMessage:
+		handleUndecryptableMessage(evt)
 	}
 }
 
@@ -193,6 +236,7 @@ func handleMessage(evt *events.Message) {
 	// Record the message
 	message := ExtractMessageText(evt)
 	utils.RecordMessage(evt.Info.ID, evt.Info.Sender.String(), message)
+	storeReceivedMessage(evt)
 
 	// Handle image message if present
 	handleImageMessage(evt)
@@ -235,42 +279,141 @@ func handleAutoReply(evt *events.Message) {
 	if config.WhatsappAutoReplyMessage != "" &&
 		!isGroupJid(evt.Info.Chat.String()) &&
 		!evt.Info.IsIncomingBroadcast() &&
+		!isNewsletterMessage(evt) &&
 		evt.Message.GetExtendedTextMessage().GetText() != "" {
-		_, _ = cli.SendMessage(
-			context.Background(),
-			FormatJID(evt.Info.Sender.String()),
-			&waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)},
-		)
+		// queued in its own goroutine, the rate limits mustn't hold back the event handler
+		go func() {
+			recipient := FormatJID(evt.Info.Sender.String())
+			msg := &waE2E.Message{Conversation: proto.String(config.WhatsappAutoReplyMessage)}
+			resp, err := SendOutbound(context.Background(), cli, recipient, msg)
+			if err != nil {
+				log.Errorf("Failed to send auto reply to %s: %v", recipient, err)
+				return
+			}
+			RecordSentMessage(recipient, resp, msg, config.WhatsappAutoReplyMessage)
+		}()
 	}
 }
 
 func handleWebhookForward(evt *events.Message) {
-	if len(config.WhatsappWebhook) > 0 &&
-		!strings.Contains(evt.Info.SourceString(), "broadcast") {
-		go func(evt *events.Message) {
-			if err := forwardToWebhook(evt); err != nil {
-				logrus.Error("Failed forward to webhook: ", err)
-			}
-		}(evt)
+	if hasWebhookEndpoints() &&
+		!strings.Contains(evt.Info.SourceString(), "broadcast") &&
+		(!evt.Info.IsFromMe || config.WhatsappWebhookFromMe) {
+		enqueueWebhook("message", func() error {
+			return forwardToWebhook(evt)
+		})
 	}
 }
 
 func handleReceipt(evt *events.Receipt) {
 	if evt.Type == types.ReceiptTypeRead || evt.Type == types.ReceiptTypeReadSelf {
 		log.Infof("%v was read by %s at %s", evt.MessageIDs, evt.SourceString(), evt.Timestamp)
+	} else if evt.Type == types.ReceiptTypePlayed || evt.Type == types.ReceiptTypePlayedSelf {
+		log.Infof("%v was played by %s at %s", evt.MessageIDs, evt.SourceString(), evt.Timestamp)
 	} else if evt.Type == types.ReceiptTypeDelivered {
 		log.Infof("%s was delivered to %s at %s", evt.MessageIDs[0], evt.SourceString(), evt.Timestamp)
 	}
+	handleStatusReceipt(evt)
 
 	// Forward receipt to webhook if configured
-	if len(config.WhatsappWebhook) > 0 &&
+	if hasWebhookEndpoints() &&
 		!strings.Contains(evt.SourceString(), "broadcast") &&
 		!evt.IsFromMe {
-		go func(evt *events.Receipt) {
-			if err := forwardReceiptToWebhook(evt); err != nil {
-				logrus.Error("Failed forward receipt to webhook: ", err)
-			}
-		}(evt)
+		enqueueWebhook("receipt", func() error {
+			return forwardReceiptToWebhook(evt)
+		})
+	}
+}
+
+func handleCall(evt interface{}) {
+	log.Infof("Received call event %T", evt)
+
+	// Forward call to webhook if configured
+	if hasWebhookEndpoints() {
+		enqueueWebhook("call", func() error {
+			return forwardCallToWebhook(evt)
+		})
+	}
+}
+
+func handleGroupChange(evt interface{}) {
+	log.Infof("Received group event %T", evt)
+	invalidateJoinedGroups()
+	invalidateCommunities(evt)
+	if info, ok := evt.(*events.GroupInfo); ok {
+		handleGroupJoins(info)
+	}
+
+	// Forward group change to webhook if configured
+	if hasWebhookEndpoints() {
+		enqueueWebhook("group", func() error {
+			return forwardGroupToWebhook(evt)
+		})
+	}
+}
+
+func handlePicture(evt *events.Picture) {
+	// a group photo is a group change too, contact photos aren't
+	if evt.JID.Server == types.GroupServer {
+		handleGroupChange(evt)
+	}
+
+	if hasWebhookEndpoints() {
+		enqueueWebhook("picture", func() error {
+			return forwardPictureToWebhook(evt)
+		})
+	}
+}
+
+func handleChatState(evt interface{}) {
+	log.Debugf("Received chat state event %T", evt)
+
+	// Forward chat state to webhook if configured
+	if hasWebhookEndpoints() {
+		enqueueWebhook("chat state", func() error {
+			return forwardChatStateToWebhook(evt)
+		})
+	}
+}
+
+func handleUndecryptableMessage(evt *events.UndecryptableMessage) {
+	log.Warnf("Failed to decrypt message %s from %s (unavailable: %t)", evt.Info.ID, evt.Info.SourceString(), evt.IsUnavailable)
+
+	if hasWebhookEndpoints() && !strings.Contains(evt.Info.SourceString(), "broadcast") {
+		enqueueWebhook("undecryptable message", func() error {
+			return forwardUndecryptableToWebhook(evt)
+		})
+	}
+}
+
+func handleIdentityChange(evt *events.IdentityChange) {
+	log.Infof("Security code of %s changed (implicit: %t)", evt.JID, evt.Implicit)
+
+	if hasWebhookEndpoints() {
+		enqueueWebhook("identity change", func() error {
+			return forwardIdentityChangeToWebhook(evt)
+		})
+	}
+}
+
+func handleBlocklist(evt *events.Blocklist) {
+	log.Infof("Blocklist changed: %s %+v", evt.Action, evt.Changes)
+
+	if hasWebhookEndpoints() {
+		enqueueWebhook("blocklist", func() error {
+			return forwardBlocklistToWebhook(evt)
+		})
+	}
+}
+
+func handleContactChange(evt interface{}) {
+	log.Debugf("Received contact event %T", evt)
+
+	// Forward contact change to webhook if configured
+	if hasWebhookEndpoints() {
+		enqueueWebhook("contact", func() error {
+			return forwardContactToWebhook(evt)
+		})
 	}
 }
 
@@ -284,9 +427,38 @@ func handlePresence(evt *events.Presence) {
 	} else {
 		log.Infof("%s is now online", evt.From)
 	}
+
+	// Forward presence to webhook if enabled, it's disabled by default because of the volume
+	if config.WhatsappWebhookPresence && hasWebhookEndpoints() {
+		enqueueWebhook("presence", func() error {
+			return forwardPresenceToWebhook(evt)
+		})
+	}
+}
+
+func handleChatPresence(evt *events.ChatPresence) {
+	log.Debugf("%s is %s in %s", evt.Sender, evt.State, evt.Chat)
+
+	if config.WhatsappWebhookPresence && hasWebhookEndpoints() {
+		enqueueWebhook("chat presence", func() error {
+			return forwardChatPresenceToWebhook(evt)
+		})
+	}
 }
 
 func handleHistorySync(evt *events.HistorySync) {
+	if config.WhatsappWebhookHistorySync && hasWebhookEndpoints() {
+		enqueueWebhook("history sync", func() error {
+			return forwardHistorySyncToWebhook(evt.Data)
+		})
+	}
+
+	if config.WhatsappHistorySyncStorage {
+		storeHistorySync(evt)
+	}
+}
+
+func storeHistorySync(evt *events.HistorySync) {
 	id := atomic.AddInt32(&historySyncID, 1)
 	fileName := fmt.Sprintf("%s/history-%d-%s-%d-%s.json",
 		config.PathStorages,
@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// NewKeepInChatMessage keeps a disappearing message in the chat for everyone, or undoes the keep
+func NewKeepInChatMessage(key *waCommon.MessageKey, keep bool, now time.Time) *waE2E.Message {
+	keepType := waE2E.KeepType_UNDO_KEEP_FOR_ALL
+	if keep {
+		keepType = waE2E.KeepType_KEEP_FOR_ALL
+	}
+	return &waE2E.Message{KeepInChatMessage: &waE2E.KeepInChatMessage{
+		Key:         key,
+		KeepType:    keepType.Enum(),
+		TimestampMS: proto.Int64(now.UnixMilli()),
+	}}
+}
+
+// createKeepInChatPayload builds the payload of a disappearing message kept or unkept in a chat, nil when the
+// event isn't a keep
+func createKeepInChatPayload(evt *events.Message) map[string]interface{} {
+	keep := evt.Message.GetKeepInChatMessage()
+	if keep == nil {
+		return nil
+	}
+
+	body := make(map[string]interface{})
+	body["event_type"] = "keep_in_chat"
+	body["action"] = "unkeep"
+	if keep.GetKeepType() == waE2E.KeepType_KEEP_FOR_ALL {
+		body["action"] = "keep"
+	}
+	body["chat"] = evt.Info.Chat.String()
+	body["message_id"] = keep.GetKey().GetID()
+	body["by"] = evt.Info.Sender.ToNonAD().String()
+	body["from_me"] = evt.Info.IsFromMe
+	body["timestamp"] = evt.Info.Timestamp.Format(time.RFC3339)
+	if participant := keep.GetKey().GetParticipant(); participant != "" {
+		body["message_sender"] = participant
+	}
+	return body
+}
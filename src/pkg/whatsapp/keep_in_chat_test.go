@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewKeepInChatMessage(t *testing.T) {
+	key := &waCommon.MessageKey{ID: proto.String("ORIGINAL1"), FromMe: proto.Bool(false)}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	msg := NewKeepInChatMessage(key, true, now)
+	assert.Equal(t, waE2E.KeepType_KEEP_FOR_ALL, msg.GetKeepInChatMessage().GetKeepType())
+	assert.Equal(t, "ORIGINAL1", msg.GetKeepInChatMessage().GetKey().GetID())
+	assert.Equal(t, now.UnixMilli(), msg.GetKeepInChatMessage().GetTimestampMS())
+
+	msg = NewKeepInChatMessage(key, false, now)
+	assert.Equal(t, waE2E.KeepType_UNDO_KEEP_FOR_ALL, msg.GetKeepInChatMessage().GetKeepType())
+}
+
+func TestCreateKeepInChatPayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: group, Sender: types.NewADJID("628123456789", 0, 1), IsGroup: true},
+		ID:            "KEEP1",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	key := &waCommon.MessageKey{ID: proto.String("ORIGINAL1"), Participant: proto.String("628987654321@s.whatsapp.net")}
+
+	payload := createKeepInChatPayload(&events.Message{Info: info, Message: &waE2E.Message{
+		KeepInChatMessage: &waE2E.KeepInChatMessage{Key: key, KeepType: waE2E.KeepType_KEEP_FOR_ALL.Enum()},
+	}})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":     "keep_in_chat",
+		"action":         "keep",
+		"chat":           group.String(),
+		"message_id":     "ORIGINAL1",
+		"message_sender": "628987654321@s.whatsapp.net",
+		"by":             "628123456789@s.whatsapp.net",
+		"from_me":        false,
+		"timestamp":      "2025-01-02T03:04:05Z",
+	}, payload)
+
+	payload = createKeepInChatPayload(&events.Message{Info: info, Message: &waE2E.Message{
+		KeepInChatMessage: &waE2E.KeepInChatMessage{Key: key, KeepType: waE2E.KeepType_UNDO_KEEP_FOR_ALL.Enum()},
+	}})
+	assert.Equal(t, "unkeep", payload["action"])
+
+	assert.Nil(t, createKeepInChatPayload(&events.Message{Info: info, Message: &waE2E.Message{Conversation: proto.String("hello")}}))
+}
@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow/types"
+)
+
+var liveLocationMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS live_locations (
+		chat       TEXT    NOT NULL,
+		message_id TEXT    NOT NULL,
+		latitude   REAL    NOT NULL,
+		longitude  REAL    NOT NULL,
+		sequence   INTEGER NOT NULL,
+		started_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL,
+		PRIMARY KEY (chat, message_id)
+	)`,
+}
+
+// LiveLocation is a live location shared from this account, kept under its first message. Each update is a new
+// live location message with the next sequence number.
+type LiveLocation struct {
+	Chat      types.JID
+	MessageID types.MessageID
+	Latitude  float64 // last position sent
+	Longitude float64
+	Sequence  int64
+	StartedAt time.Time
+	ExpiresAt time.Time
+}
+
+// SaveLiveLocation stores a live location when it starts or is updated
+func SaveLiveLocation(location LiveLocation) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+	_, err := appStore.Exec(
+		`INSERT OR REPLACE INTO live_locations (chat, message_id, latitude, longitude, sequence, started_at, expires_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		location.Chat.String(), location.MessageID, location.Latitude, location.Longitude, location.Sequence,
+		location.StartedAt.Unix(), location.ExpiresAt.Unix(),
+	)
+	return err
+}
+
+// GetLiveLocation returns a live location still shared in the chat
+func GetLiveLocation(chat types.JID, messageID types.MessageID) (LiveLocation, error) {
+	location := LiveLocation{Chat: chat, MessageID: messageID}
+	if appStore == nil {
+		return location, errStoreNotInitialized
+	}
+
+	var startedAt, expiresAt int64
+	err := appStore.QueryRow(
+		`SELECT latitude, longitude, sequence, started_at, expires_at FROM live_locations WHERE chat = ? AND message_id = ?`,
+		chat.String(), messageID,
+	).Scan(&location.Latitude, &location.Longitude, &location.Sequence, &startedAt, &expiresAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return location, pkgError.ValidationError(fmt.Sprintf("no live location %s in %s", messageID, chat))
+	}
+	if err != nil {
+		return location, err
+	}
+
+	location.StartedAt, location.ExpiresAt = time.Unix(startedAt, 0), time.Unix(expiresAt, 0)
+	if time.Now().After(location.ExpiresAt) {
+		_ = DeleteLiveLocation(chat, messageID)
+		return location, pkgError.ValidationError(fmt.Sprintf("live location %s has ended", messageID))
+	}
+	return location, nil
+}
+
+// DeleteLiveLocation forgets a live location once it's stopped
+func DeleteLiveLocation(chat types.JID, messageID types.MessageID) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+	_, err := appStore.Exec(`DELETE FROM live_locations WHERE chat = ? AND message_id = ?`, chat.String(), messageID)
+	return err
+}
@@ -0,0 +1,49 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestLiveLocation(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	now := time.Now().Truncate(time.Second)
+	assert.NoError(t, SaveLiveLocation(LiveLocation{
+		Chat: chat, MessageID: "LIVE", Latitude: -6.2, Longitude: 106.8, Sequence: 3,
+		StartedAt: now, ExpiresAt: now.Add(time.Hour),
+	}))
+	assert.NoError(t, SaveLiveLocation(LiveLocation{
+		Chat: chat, MessageID: "ENDED", Sequence: 1,
+		StartedAt: now.Add(-2 * time.Hour), ExpiresAt: now.Add(-time.Hour),
+	}))
+
+	location, err := GetLiveLocation(chat, "LIVE")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), location.Sequence)
+	assert.Equal(t, -6.2, location.Latitude)
+	assert.Equal(t, 106.8, location.Longitude)
+	assert.True(t, now.Equal(location.StartedAt))
+
+	_, err = GetLiveLocation(chat, "ENDED")
+	assert.Equal(t, pkgError.ValidationError("live location ENDED has ended"), err)
+
+	assert.NoError(t, DeleteLiveLocation(chat, "LIVE"))
+	_, err = GetLiveLocation(chat, "LIVE")
+	assert.Equal(t, pkgError.ValidationError("no live location LIVE in 628123456789@s.whatsapp.net"), err)
+}
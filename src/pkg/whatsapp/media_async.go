@@ -0,0 +1,106 @@
+package whatsapp
+
+import (
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Status of the media extracted after the message webhook, see deferMediaExtraction
+const (
+	MediaStatusPending = "pending"
+	MediaStatusReady   = "ready"
+	MediaStatusFailed  = "failed"
+)
+
+// mediaQueue runs the deferred media extractions, apart from the webhook workers so a large download
+// doesn't hold back the other events
+var mediaQueue chan func()
+
+// StartMediaWorkers starts the workers of the deferred media extractions, if the extraction is deferred
+func StartMediaWorkers() error {
+	if config.WhatsappMediaAsyncSize <= 0 {
+		return nil
+	}
+	if config.WhatsappMediaAsyncWorkers < 1 {
+		return fmt.Errorf("media async workers must be at least 1")
+	}
+
+	mediaQueue = make(chan func(), config.WhatsappWebhookQueueSize)
+	for i := 0; i < config.WhatsappMediaAsyncWorkers; i++ {
+		go func() {
+			for extract := range mediaQueue {
+				extract()
+			}
+		}()
+	}
+	return nil
+}
+
+// deferMediaExtraction sets the media field of the payload to the media metadata with a pending media_status
+// when the media is larger than the async size. The media is then extracted by forwardPendingMedia.
+func deferMediaExtraction(evt *events.Message, body map[string]interface{}) bool {
+	if config.WhatsappMediaAsyncSize <= 0 {
+		return false
+	}
+	field, media := getDownloadableMedia(evt.Message)
+	if media == nil {
+		return false
+	}
+
+	reference := buildMediaReference(evt.Info.ID, media)
+	if reference.FileLength <= uint64(config.WhatsappMediaAsyncSize) {
+		return false
+	}
+	reference.DownloadURL = ""
+	body[field] = reference
+	body["media_status"] = MediaStatusPending
+	return true
+}
+
+// forwardPendingMedia extracts the media of a payload sent with a pending media_status, then forwards a
+// media_ready event with the extracted media, or the error when the extraction failed.
+func forwardPendingMedia(evt *events.Message, source *webhookEventSource, payload map[string]interface{}) {
+	if payload["media_status"] != MediaStatusPending {
+		return
+	}
+
+	extract := func() {
+		if err := dispatchWebhookEvent(webhookEvent{
+			Type:    "media_ready",
+			Payload: createMediaReadyPayload(evt),
+			Source:  source,
+		}); err != nil {
+			logrus.Errorf("Failed forward media_ready of %s to webhook: %v", evt.Info.ID, err)
+		}
+	}
+	if mediaQueue == nil {
+		go extract()
+		return
+	}
+	mediaQueue <- extract
+}
+
+func createMediaReadyPayload(evt *events.Message) map[string]interface{} {
+	field, media := getDownloadableMedia(evt.Message)
+	body := map[string]interface{}{
+		"event_type": "media_ready",
+		"message_id": evt.Info.ID,
+		"chat":       evt.Info.Chat.String(),
+		"from":       evt.Info.SourceString(),
+		"field":      field,
+	}
+
+	extracted, err := ExtractMedia(config.PathMedia, media)
+	if err != nil {
+		logrus.Errorf("Failed to download %s from %s: %v", field, evt.Info.SourceString(), err)
+		body["media_status"] = MediaStatusFailed
+		body["error"] = err.Error()
+		return body
+	}
+	body["media_status"] = MediaStatusReady
+	body[field] = storeMedia(convertMedia(evt.Info.SourceString(), extracted, media))
+	return body
+}
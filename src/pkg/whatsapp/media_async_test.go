@@ -0,0 +1,68 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestDeferMediaExtraction(t *testing.T) {
+	originalSize := config.WhatsappMediaAsyncSize
+	defer func() { config.WhatsappMediaAsyncSize = originalSize }()
+
+	video := func(size uint64) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{ID: "3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+			Message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+				Mimetype:   proto.String("video/mp4"),
+				Caption:    proto.String("holiday"),
+				FileLength: proto.Uint64(size),
+			}},
+		}
+	}
+
+	t.Run("should wait for the media by default", func(t *testing.T) {
+		config.WhatsappMediaAsyncSize = 0
+		assert.False(t, deferMediaExtraction(video(60000000), map[string]interface{}{}))
+	})
+
+	t.Run("should wait for the small media", func(t *testing.T) {
+		config.WhatsappMediaAsyncSize = 10000000
+		body := map[string]interface{}{}
+		assert.False(t, deferMediaExtraction(video(1000), body))
+		assert.Empty(t, body)
+	})
+
+	t.Run("should defer the large media", func(t *testing.T) {
+		config.WhatsappMediaAsyncSize = 10000000
+		body := map[string]interface{}{}
+		assert.True(t, deferMediaExtraction(video(60000000), body))
+		assert.Equal(t, MediaStatusPending, body["media_status"])
+		assert.Equal(t, evtMediaReference{
+			MessageID:  "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+			MimeType:   "video/mp4",
+			Caption:    "holiday",
+			FileLength: 60000000,
+		}, body["video"])
+	})
+
+	t.Run("should ignore the messages without media", func(t *testing.T) {
+		config.WhatsappMediaAsyncSize = 10000000
+		text := &events.Message{Message: &waE2E.Message{Conversation: proto.String("hi")}}
+		assert.False(t, deferMediaExtraction(text, map[string]interface{}{}))
+	})
+}
+
+func TestForwardPendingMediaIgnoresExtractedMedia(t *testing.T) {
+	originalQueue := mediaQueue
+	defer func() { mediaQueue = originalQueue }()
+
+	mediaQueue = make(chan func(), 1)
+	forwardPendingMedia(&events.Message{}, nil, map[string]interface{}{"event_type": "message"})
+	assert.Empty(t, mediaQueue, "nothing is pending")
+}
@@ -0,0 +1,171 @@
+package whatsapp
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// mediaEncryptionMagic starts the encrypted media files, followed by the nonce and the AES-GCM sealed data
+var mediaEncryptionMagic = []byte("WAMEDIA1")
+
+// mediaEncryption encrypts the media files at rest, nil keeps them in clear
+var mediaEncryption cipher.AEAD
+
+// InitMediaEncryption reads the media encryption key, a base64 encoded 32 bytes AES-256 key
+func InitMediaEncryption() (err error) {
+	mediaEncryption, err = newMediaEncryption(config.WhatsappMediaEncryptionKey)
+	return err
+}
+
+func newMediaEncryption(encodedKey string) (cipher.AEAD, error) {
+	if encodedKey == "" {
+		return nil, nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(key) != 32 {
+		return nil, fmt.Errorf("media encryption key must be 32 bytes encoded in base64, e.g. openssl rand -base64 32")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func encryptMediaData(aead cipher.AEAD, data []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := append(append([]byte{}, mediaEncryptionMagic...), nonce...)
+	return aead.Seal(sealed, nonce, data, mediaEncryptionMagic), nil
+}
+
+func decryptMediaData(aead cipher.AEAD, data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, mediaEncryptionMagic)
+	if len(data) < aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted media is truncated")
+	}
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], mediaEncryptionMagic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt media, wrong key or corrupted file")
+	}
+	return plain, nil
+}
+
+// IsEncryptedMediaFile tells whether the media file was encrypted by writeMediaFile
+func IsEncryptedMediaFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	header := make([]byte, len(mediaEncryptionMagic))
+	if _, err = io.ReadFull(file, header); err != nil {
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(header, mediaEncryptionMagic), nil
+}
+
+// ReadMediaFile reads a media file, decrypting it when it's encrypted
+func ReadMediaFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !bytes.HasPrefix(data, mediaEncryptionMagic) {
+		return data, err
+	}
+	if mediaEncryption == nil {
+		return nil, fmt.Errorf("media %s is encrypted, set the media encryption key", path)
+	}
+	return decryptMediaData(mediaEncryption, data)
+}
+
+// writeMediaFile writes a media file, encrypted when the media encryption is enabled. Every media kept in the
+// local folders is written through it, a clear copy only lives in the temporary files of the conversions.
+func writeMediaFile(path string, data []byte, flag int) error {
+	if mediaEncryption != nil {
+		sealed, err := encryptMediaData(mediaEncryption, data)
+		if err != nil {
+			return err
+		}
+		data = sealed
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|flag, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Write(data); err != nil {
+		_ = file.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return file.Close()
+}
+
+// convertMediaFile runs convert from a clear copy of the source to a temporary file, which is then written to
+// the destination through writeMediaFile
+func convertMediaFile(source, destination string, convert func(source, destination string) error) error {
+	clearSource, cleanup, err := clearMediaFile(source)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	temp, err := os.CreateTemp(filepath.Dir(destination), ".convert-*"+filepath.Ext(destination))
+	if err != nil {
+		return err
+	}
+	_ = temp.Close()
+	defer os.Remove(temp.Name())
+
+	if err = convert(clearSource, temp.Name()); err != nil {
+		return err
+	}
+	data, err := os.ReadFile(temp.Name())
+	if err != nil {
+		return err
+	}
+	return writeMediaFile(destination, data, os.O_TRUNC)
+}
+
+// clearMediaFile returns a path to the media in clear, a temporary copy removed by cleanup when it's encrypted
+func clearMediaFile(path string) (clearPath string, cleanup func(), err error) {
+	encrypted, err := IsEncryptedMediaFile(path)
+	if err != nil || !encrypted {
+		return path, func() {}, err
+	}
+	data, err := ReadMediaFile(path)
+	if err != nil {
+		return "", nil, err
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), ".clear-*"+filepath.Ext(path))
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup = func() { _ = os.Remove(temp.Name()) }
+	if _, err = temp.Write(data); err == nil {
+		err = temp.Close()
+	} else {
+		_ = temp.Close()
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return temp.Name(), cleanup, nil
+}
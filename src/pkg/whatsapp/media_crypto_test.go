@@ -0,0 +1,98 @@
+package whatsapp
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMediaEncryption(t *testing.T) {
+	aead, err := newMediaEncryption("")
+	assert.NoError(t, err)
+	assert.Nil(t, aead, "the media are kept in clear without a key")
+
+	_, err = newMediaEncryption(base64.StdEncoding.EncodeToString([]byte("too short")))
+	assert.Error(t, err)
+	_, err = newMediaEncryption("not base64!")
+	assert.Error(t, err)
+}
+
+func TestWriteMediaFile(t *testing.T) {
+	original := mediaEncryption
+	defer func() { mediaEncryption = original }()
+
+	var err error
+	mediaEncryption, err = newMediaEncryption(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("k", 32))))
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "a.jpg")
+	assert.NoError(t, writeMediaFile(path, []byte("media"), os.O_TRUNC))
+
+	sealed, _ := os.ReadFile(path)
+	assert.NotContains(t, string(sealed), "media", "no clear copy is written")
+	encrypted, err := IsEncryptedMediaFile(path)
+	assert.NoError(t, err)
+	assert.True(t, encrypted)
+	assert.ErrorIs(t, writeMediaFile(path, []byte("other"), os.O_EXCL), os.ErrExist)
+
+	data, err := ReadMediaFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "media", string(data))
+
+	clearPath, cleanup, err := clearMediaFile(path)
+	assert.NoError(t, err)
+	data, _ = os.ReadFile(clearPath)
+	assert.Equal(t, "media", string(data))
+	cleanup()
+	assert.NoFileExists(t, clearPath)
+
+	t.Run("should convert from and to encrypted files", func(t *testing.T) {
+		destination := filepath.Join(filepath.Dir(path), "a.png")
+		err := convertMediaFile(path, destination, func(source, destination string) error {
+			data, err := os.ReadFile(source)
+			if err != nil {
+				return err
+			}
+			return os.WriteFile(destination, []byte(strings.ToUpper(string(data))), 0600)
+		})
+		assert.NoError(t, err)
+
+		encrypted, err := IsEncryptedMediaFile(destination)
+		assert.NoError(t, err)
+		assert.True(t, encrypted)
+		data, err := ReadMediaFile(destination)
+		assert.NoError(t, err)
+		assert.Equal(t, "MEDIA", string(data))
+
+		leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(path), ".*"))
+		assert.Empty(t, leftovers, "the clear temporary files are removed")
+	})
+
+	t.Run("should fail with another key", func(t *testing.T) {
+		mediaEncryption, _ = newMediaEncryption(base64.StdEncoding.EncodeToString([]byte(strings.Repeat("x", 32))))
+		_, err := ReadMediaFile(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("should fail without the key", func(t *testing.T) {
+		mediaEncryption = nil
+		_, err := ReadMediaFile(path)
+		assert.Error(t, err)
+	})
+}
+
+func TestReadMediaFileInClear(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "a.txt")
+	assert.NoError(t, os.WriteFile(path, []byte("hi"), 0600))
+
+	encrypted, err := IsEncryptedMediaFile(path)
+	assert.NoError(t, err)
+	assert.False(t, encrypted)
+	data, err := ReadMediaFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", string(data))
+}
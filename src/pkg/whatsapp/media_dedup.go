@@ -0,0 +1,78 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+var mediaDedupMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS media_files (
+		sha256       TEXT    PRIMARY KEY,
+		path         TEXT    NOT NULL,
+		created_at   INTEGER NOT NULL,
+		last_used_at INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS media_files_path ON media_files (path)`,
+}
+
+// findDedupMedia returns the file already downloaded for the hash in the storage location. The file is touched, so
+// the retention ages it from its latest use and keeps it as long as the newest message sharing it.
+func findDedupMedia(storageLocation string, fileSHA256 []byte) (string, bool) {
+	if !config.WhatsappMediaDedup || appStore == nil || len(fileSHA256) == 0 {
+		return "", false
+	}
+
+	var path string
+	err := appStore.QueryRow(`SELECT path FROM media_files WHERE sha256 = ?`, hex.EncodeToString(fileSHA256)).Scan(&path)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			logrus.Errorf("Failed to look up media %x: %v", fileSHA256, err)
+		}
+		return "", false
+	}
+	if filepath.Dir(path) != filepath.Clean(storageLocation) {
+		return "", false
+	}
+
+	now := time.Now()
+	if err = os.Chtimes(path, now, now); err != nil {
+		// the file was removed, e.g. after an upload, it's downloaded again
+		forgetDedupMedia(path)
+		return "", false
+	}
+	if _, err = appStore.Exec(`UPDATE media_files SET last_used_at = ? WHERE path = ?`, now.Unix(), path); err != nil {
+		logrus.Errorf("Failed to renew media %s: %v", path, err)
+	}
+	return path, true
+}
+
+// storeDedupMedia records the hash of a downloaded file, so the next messages with the same media reuse it
+func storeDedupMedia(path string, fileSHA256 []byte) {
+	if !config.WhatsappMediaDedup || appStore == nil || len(fileSHA256) == 0 {
+		return
+	}
+	now := time.Now().Unix()
+	if _, err := appStore.Exec(
+		`INSERT OR REPLACE INTO media_files (sha256, path, created_at, last_used_at) VALUES (?, ?, ?, ?)`,
+		hex.EncodeToString(fileSHA256), path, now, now,
+	); err != nil {
+		logrus.Errorf("Failed to store hash of media %s: %v", path, err)
+	}
+}
+
+// forgetDedupMedia drops the hash of a deleted file, the next message with the same media downloads it again
+func forgetDedupMedia(path string) {
+	if appStore == nil {
+		return
+	}
+	if _, err := appStore.Exec(`DELETE FROM media_files WHERE path = ?`, path); err != nil {
+		logrus.Errorf("Failed to forget media %s: %v", path, err)
+	}
+}
@@ -0,0 +1,89 @@
+package whatsapp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupMedia(t *testing.T) {
+	originalPath, originalStore, originalDedup := config.PathStoreDB, appStore, config.WhatsappMediaDedup
+	defer func() {
+		config.PathStoreDB, appStore, config.WhatsappMediaDedup = originalPath, originalStore, originalDedup
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	config.WhatsappMediaDedup = true
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	stored := func(path string) (count int) {
+		_ = appStore.QueryRow(`SELECT COUNT(*) FROM media_files WHERE path = ?`, path).Scan(&count)
+		return count
+	}
+
+	folder := t.TempDir()
+	hash := []byte{0xde, 0xad, 0xbe, 0xef}
+	path := filepath.Join(folder, "1700000000-abc.jpg")
+	assert.NoError(t, os.WriteFile(path, []byte("image"), 0600))
+	old := time.Now().Add(-48 * time.Hour)
+	assert.NoError(t, os.Chtimes(path, old, old))
+
+	_, found := findDedupMedia(folder, hash)
+	assert.False(t, found, "nothing is stored yet")
+
+	storeDedupMedia(path, hash)
+	assert.Equal(t, 1, stored(path))
+
+	reused, found := findDedupMedia(folder, hash)
+	assert.True(t, found)
+	assert.Equal(t, path, reused)
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), info.ModTime(), time.Minute, "the reuse renews the retention")
+
+	_, found = findDedupMedia(t.TempDir(), hash)
+	assert.False(t, found, "a file of another storage location isn't reused")
+
+	config.WhatsappMediaDedup = false
+	_, found = findDedupMedia(folder, hash)
+	assert.False(t, found, "deduplication is disabled")
+	config.WhatsappMediaDedup = true
+
+	assert.NoError(t, os.Remove(path))
+	_, found = findDedupMedia(folder, hash)
+	assert.False(t, found, "a removed file is downloaded again")
+	assert.Equal(t, 0, stored(path))
+}
+
+func TestCleanupForgetsDedupMedia(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	originalMaxAge, originalRules := config.WhatsappMediaRetentionMaxAge, mediaRetentionRules
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+		config.WhatsappMediaRetentionMaxAge, mediaRetentionRules = originalMaxAge, originalRules
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+	config.WhatsappMediaRetentionMaxAge, mediaRetentionRules = time.Hour, nil
+
+	folder := t.TempDir()
+	path := filepath.Join(folder, "1700000000-abc.jpg")
+	assert.NoError(t, os.WriteFile(path, []byte("image"), 0600))
+	old := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(path, old, old))
+	storeDedupMedia(path, []byte{0x01})
+
+	report := cleanupMediaFolder(folder, time.Now())
+	assert.Equal(t, 1, report.Deleted)
+
+	var count int
+	assert.NoError(t, appStore.QueryRow(`SELECT COUNT(*) FROM media_files`).Scan(&count))
+	assert.Equal(t, 0, count)
+}
@@ -0,0 +1,74 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+// maxMediaFileName keeps the names of the stored documents below the file system limits
+const maxMediaFileName = 120
+
+// addDocumentDetails copies the metadata of a document, its file name is sanitized to be stored as is
+func addDocumentDetails(extractedMedia *ExtractedMedia, document *waE2E.DocumentMessage) {
+	extractedMedia.FileName = sanitizeFileName(document.GetFileName())
+	extractedMedia.FileSize = document.GetFileLength()
+	extractedMedia.PageCount = document.GetPageCount()
+}
+
+// sanitizeFileName keeps the ASCII letters, digits, dots, dashes and underscores of the base name, the name is
+// served as is by /media/:id. Leading dots are dropped so it's never a hidden file.
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '_'
+		}
+		return -1
+	}, name)
+	if strings.Trim(strings.TrimSuffix(name, filepath.Ext(name)), ".") == "" {
+		return ""
+	}
+	name = strings.TrimLeft(name, ".")
+
+	if len(name) > maxMediaFileName {
+		extension := filepath.Ext(name)
+		if len(extension) > maxMediaFileName/2 {
+			extension = ""
+		}
+		name = name[:maxMediaFileName-len(extension)] + extension
+	}
+	return name
+}
+
+// writeNamedMediaFile writes the data under the file name, adding -1, -2... before the extension when a file
+// already has that name
+func writeNamedMediaFile(storageLocation, fileName string, data []byte) (string, error) {
+	extension := filepath.Ext(fileName)
+	base := strings.TrimSuffix(fileName, extension)
+
+	for attempt := 0; attempt < 1000; attempt++ {
+		name := fileName
+		if attempt > 0 {
+			name = fmt.Sprintf("%s-%d%s", base, attempt, extension)
+		}
+		mediaPath := fmt.Sprintf("%s/%s", storageLocation, name)
+
+		err := writeMediaFile(mediaPath, data, os.O_EXCL)
+		if errors.Is(err, os.ErrExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		return mediaPath, nil
+	}
+	return "", fmt.Errorf("too many files named %s", fileName)
+}
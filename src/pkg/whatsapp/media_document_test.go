@@ -0,0 +1,65 @@
+package whatsapp
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSanitizeFileName(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		expected string
+	}{
+		{name: "should keep a plain name", fileName: "invoice-2024_01.pdf", expected: "invoice-2024_01.pdf"},
+		{name: "should replace the spaces", fileName: "annual report.pdf", expected: "annual_report.pdf"},
+		{name: "should drop the directories", fileName: "../../etc/passwd", expected: "passwd"},
+		{name: "should drop windows directories", fileName: `C:\Users\me\cv.docx`, expected: "cv.docx"},
+		{name: "should drop the leading dots", fileName: "..hidden.txt", expected: "hidden.txt"},
+		{name: "should drop the other characters", fileName: "résumé<1>.pdf", expected: "rsum1.pdf"},
+		{name: "should drop a name without letters", fileName: "文件.pdf", expected: ""},
+		{name: "should be empty without a name", fileName: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, sanitizeFileName(tt.fileName))
+		})
+	}
+
+	long := sanitizeFileName(strings.Repeat("a", 300) + ".pdf")
+	assert.Len(t, long, maxMediaFileName)
+	assert.True(t, strings.HasSuffix(long, ".pdf"))
+}
+
+func TestAddDocumentDetails(t *testing.T) {
+	var media ExtractedMedia
+	addDocumentDetails(&media, &waE2E.DocumentMessage{
+		FileName:   proto.String("annual report.pdf"),
+		FileLength: proto.Uint64(48213),
+		PageCount:  proto.Uint32(12),
+	})
+	assert.Equal(t, ExtractedMedia{FileName: "annual_report.pdf", FileSize: 48213, PageCount: 12}, media)
+}
+
+func TestWriteNamedMediaFile(t *testing.T) {
+	folder := t.TempDir()
+
+	first, err := writeNamedMediaFile(folder, "invoice.pdf", []byte("first"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(folder, "invoice.pdf"), filepath.Clean(first))
+
+	second, err := writeNamedMediaFile(folder, "invoice.pdf", []byte("second"))
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(folder, "invoice-1.pdf"), filepath.Clean(second))
+
+	data, err := os.ReadFile(first)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data), "the first file isn't overwritten")
+}
@@ -0,0 +1,167 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+var mediaLazyMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS media_references (
+		message_id TEXT    PRIMARY KEY,
+		chat       TEXT    NOT NULL,
+		field      TEXT    NOT NULL,
+		media      BLOB    NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+}
+
+// evtMediaReference replaces the downloaded media in the payload when the media are downloaded on demand
+type evtMediaReference struct {
+	MessageID   string `json:"message_id"`
+	MimeType    string `json:"mime_type"`
+	Caption     string `json:"caption,omitempty"`
+	FileName    string `json:"file_name,omitempty"`
+	FileLength  uint64 `json:"file_length,omitempty"`
+	PageCount   uint32 `json:"page_count,omitempty"`
+	FileSHA256  string `json:"file_sha256,omitempty"`
+	DownloadURL string `json:"download_url,omitempty"` // POST it to download the media
+
+	// why the media wasn't downloaded, set when a skip rule matched
+	DownloadSkipped string `json:"download_skipped,omitempty"`
+}
+
+// getDownloadableMedia returns the payload field and the media of the message, nil for the messages without media
+func getDownloadableMedia(msg *waE2E.Message) (string, whatsmeow.DownloadableMessage) {
+	switch {
+	case msg.GetAudioMessage() != nil:
+		return "audio", msg.GetAudioMessage()
+	case msg.GetDocumentMessage() != nil:
+		return "document", msg.GetDocumentMessage()
+	case msg.GetImageMessage() != nil:
+		return "image", msg.GetImageMessage()
+	case msg.GetStickerMessage() != nil:
+		return "sticker", msg.GetStickerMessage()
+	case msg.GetVideoMessage() != nil:
+		return "video", msg.GetVideoMessage()
+	}
+	return "", nil
+}
+
+// addMediaReference sets the media field of the payload to a reference of the media, stored so it can be
+// downloaded later. It reports false when there's nothing to reference, the media is then downloaded.
+func addMediaReference(evt *events.Message, body map[string]interface{}) bool {
+	field, media := getDownloadableMedia(evt.Message)
+	if media == nil || storeMediaReference(evt, field, media) != nil {
+		return false
+	}
+
+	body[field] = buildMediaReference(evt.Info.ID, media)
+	return true
+}
+
+// storeMediaReference keeps the media of the message for DownloadMediaReference
+func storeMediaReference(evt *events.Message, field string, media whatsmeow.DownloadableMessage) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	data, err := proto.Marshal(media.(proto.Message))
+	if err != nil {
+		logrus.Errorf("Failed to encode media of %s: %v", evt.Info.ID, err)
+		return err
+	}
+	if _, err = appStore.Exec(
+		`INSERT OR REPLACE INTO media_references (message_id, chat, field, media, created_at) VALUES (?, ?, ?, ?, ?)`,
+		evt.Info.ID, evt.Info.Chat.String(), field, data, time.Now().Unix(),
+	); err != nil {
+		logrus.Errorf("Failed to store media reference of %s: %v", evt.Info.ID, err)
+		return err
+	}
+	return nil
+}
+
+func buildMediaReference(messageID string, media whatsmeow.DownloadableMessage) evtMediaReference {
+	reference := evtMediaReference{
+		MessageID:   messageID,
+		FileSHA256:  hex.EncodeToString(media.GetFileSHA256()),
+		DownloadURL: fmt.Sprintf("/message/%s/download", messageID),
+	}
+	switch media := media.(type) {
+	case *waE2E.AudioMessage:
+		reference.MimeType, reference.FileLength = media.GetMimetype(), media.GetFileLength()
+	case *waE2E.DocumentMessage:
+		reference.MimeType, reference.FileLength = media.GetMimetype(), media.GetFileLength()
+		reference.Caption, reference.FileName = media.GetCaption(), sanitizeFileName(media.GetFileName())
+		reference.PageCount = media.GetPageCount()
+	case *waE2E.ImageMessage:
+		reference.MimeType, reference.FileLength = media.GetMimetype(), media.GetFileLength()
+		reference.Caption = media.GetCaption()
+	case *waE2E.StickerMessage:
+		reference.MimeType, reference.FileLength = media.GetMimetype(), media.GetFileLength()
+	case *waE2E.VideoMessage:
+		reference.MimeType, reference.FileLength = media.GetMimetype(), media.GetFileLength()
+		reference.Caption = media.GetCaption()
+	}
+	return reference
+}
+
+// loadMediaReference reads the media stored by addMediaReference
+func loadMediaReference(messageID string) (field string, media whatsmeow.DownloadableMessage, err error) {
+	if appStore == nil {
+		return "", nil, errStoreNotInitialized
+	}
+
+	var data []byte
+	err = appStore.QueryRow(`SELECT field, media FROM media_references WHERE message_id = ?`, messageID).Scan(&field, &data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil, pkgError.ValidationError(fmt.Sprintf("no media of message %s to download", messageID))
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	var message proto.Message
+	switch field {
+	case "audio":
+		message = &waE2E.AudioMessage{}
+	case "document":
+		message = &waE2E.DocumentMessage{}
+	case "image":
+		message = &waE2E.ImageMessage{}
+	case "sticker":
+		message = &waE2E.StickerMessage{}
+	case "video":
+		message = &waE2E.VideoMessage{}
+	default:
+		return "", nil, fmt.Errorf("unknown media field %q of message %s", field, messageID)
+	}
+	if err = proto.Unmarshal(data, message); err != nil {
+		return "", nil, fmt.Errorf("failed to decode media of message %s: %w", messageID, err)
+	}
+	return field, message.(whatsmeow.DownloadableMessage), nil
+}
+
+// DownloadMediaReference downloads the media of a message forwarded with a media reference
+func DownloadMediaReference(messageID string) (ExtractedMedia, error) {
+	field, media, err := loadMediaReference(messageID)
+	if err != nil {
+		return ExtractedMedia{}, err
+	}
+
+	extracted, err := ExtractMedia(config.PathMedia, media)
+	if err != nil {
+		return extracted, pkgError.WebhookError(fmt.Sprintf("Failed to download %s: %v", field, err))
+	}
+	return storeMedia(convertMedia(messageID, extracted, media)), nil
+}
@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAddMediaReference(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	image := &waE2E.ImageMessage{
+		Mimetype:   proto.String("image/jpeg"),
+		Caption:    proto.String("look"),
+		FileLength: proto.Uint64(2048),
+		FileSHA256: []byte{0xca, 0xfe},
+		DirectPath: proto.String("/v/t62.7118-24/abc"),
+		MediaKey:   []byte{0x01, 0x02},
+	}
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: types.NewJID("628123456789", types.DefaultUserServer)},
+			ID:            "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+			Timestamp:     time.Unix(1700000000, 0),
+		},
+		Message: &waE2E.Message{ImageMessage: image},
+	}
+
+	body := map[string]interface{}{}
+	assert.True(t, addMediaReference(evt, body))
+	assert.Equal(t, evtMediaReference{
+		MessageID:   "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+		MimeType:    "image/jpeg",
+		Caption:     "look",
+		FileLength:  2048,
+		FileSHA256:  "cafe",
+		DownloadURL: "/message/3EB0B430B6F8F1D0E053AC120E0A9E5C/download",
+	}, body["image"])
+
+	field, media, err := loadMediaReference("3EB0B430B6F8F1D0E053AC120E0A9E5C")
+	assert.NoError(t, err)
+	assert.Equal(t, "image", field)
+	assert.True(t, proto.Equal(image, media.(proto.Message)))
+
+	_, _, err = loadMediaReference("unknown")
+	assert.Error(t, err)
+
+	text := &events.Message{Info: evt.Info, Message: &waE2E.Message{Conversation: proto.String("hi")}}
+	assert.False(t, addMediaReference(text, map[string]interface{}{}), "a text has no media to reference")
+}
+
+func TestGetDownloadableMedia(t *testing.T) {
+	tests := []struct {
+		name     string
+		message  *waE2E.Message
+		expected string
+	}{
+		{name: "should find the audio", message: &waE2E.Message{AudioMessage: &waE2E.AudioMessage{}}, expected: "audio"},
+		{name: "should find the document", message: &waE2E.Message{DocumentMessage: &waE2E.DocumentMessage{}}, expected: "document"},
+		{name: "should find the sticker", message: &waE2E.Message{StickerMessage: &waE2E.StickerMessage{}}, expected: "sticker"},
+		{name: "should find the video", message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{}}, expected: "video"},
+		{name: "should find nothing in a text", message: &waE2E.Message{Conversation: proto.String("hi")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			field, media := getDownloadableMedia(tt.message)
+			assert.Equal(t, tt.expected, field)
+			assert.Equal(t, tt.expected != "", media != nil)
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package whatsapp
+
+import (
+	"fmt"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// mediaRetentionRule keeps the media of a mime type, e.g. video/*, for a different age than the default
+type mediaRetentionRule struct {
+	MimeType string
+	MaxAge   time.Duration
+}
+
+// MediaCleanupReport is the outcome of a cleanup of the media folder
+type MediaCleanupReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	Scanned        int       `json:"scanned"`
+	Deleted        int       `json:"deleted"`
+	FreedBytes     int64     `json:"freed_bytes"`
+	RemainingBytes int64     `json:"remaining_bytes"`
+	Error          string    `json:"error,omitempty"`
+}
+
+type mediaFile struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+var (
+	mediaRetentionRules []mediaRetentionRule
+	mediaCleanupMu      sync.Mutex
+	lastMediaCleanup    *MediaCleanupReport
+)
+
+// parseMediaRetentionRules reads the mime=age rules, e.g. video/*=72h
+func parseMediaRetentionRules(values []string) (rules []mediaRetentionRule, err error) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		mimeType, age, found := strings.Cut(value, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid media retention rule %q, use <mime type>=<max age>", value)
+		}
+		maxAge, err := time.ParseDuration(strings.TrimSpace(age))
+		if err != nil || maxAge <= 0 {
+			return nil, fmt.Errorf("invalid max age of media retention rule %q", value)
+		}
+		rules = append(rules, mediaRetentionRule{MimeType: strings.TrimSpace(mimeType), MaxAge: maxAge})
+	}
+	return rules, nil
+}
+
+// StartMediaJanitor periodically deletes the media past their retention, if a policy is set
+func StartMediaJanitor() error {
+	rules, err := parseMediaRetentionRules(config.WhatsappMediaRetentionRules)
+	if err != nil {
+		return err
+	}
+	mediaRetentionRules = rules
+	if !mediaRetentionEnabled() {
+		return nil
+	}
+	if config.WhatsappMediaRetentionInterval <= 0 {
+		return fmt.Errorf("media retention interval must be positive")
+	}
+
+	go func() {
+		ticker := time.NewTicker(config.WhatsappMediaRetentionInterval)
+		defer ticker.Stop()
+
+		logrus.Infof("Media janitor started, cleaning every %s", config.WhatsappMediaRetentionInterval)
+		for range ticker.C {
+			if report := CleanupMedia(); report.Error != "" {
+				logrus.Errorf("Error cleaning media: %s", report.Error)
+			}
+		}
+	}()
+	return nil
+}
+
+func mediaRetentionEnabled() bool {
+	return config.WhatsappMediaRetentionMaxAge > 0 || config.WhatsappMediaRetentionMaxSize > 0 || len(mediaRetentionRules) > 0
+}
+
+// CleanupMedia applies the retention policy to the media folder now, a cleanup already running is waited for
+func CleanupMedia() MediaCleanupReport {
+	mediaCleanupMu.Lock()
+	defer mediaCleanupMu.Unlock()
+
+	report := cleanupMediaFolder(config.PathMedia, time.Now())
+	if report.Deleted > 0 {
+		logrus.Infof("Media cleanup deleted %d files, freeing %d bytes", report.Deleted, report.FreedBytes)
+	}
+	lastMediaCleanup = &report
+	return report
+}
+
+// LastMediaCleanup returns the report of the latest cleanup, nil before the first one
+func LastMediaCleanup() *MediaCleanupReport {
+	mediaCleanupMu.Lock()
+	defer mediaCleanupMu.Unlock()
+	return lastMediaCleanup
+}
+
+// cleanupMediaFolder deletes the files older than their max age, then the oldest ones until the folder
+// fits in the max size
+func cleanupMediaFolder(folder string, now time.Time) (report MediaCleanupReport) {
+	report.StartedAt = now
+	defer func() { report.FinishedAt = time.Now() }()
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		report.Error = err.Error()
+		return report
+	}
+
+	var kept []mediaFile
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() {
+			continue
+		}
+		report.Scanned++
+		file := mediaFile{path: filepath.Join(folder, entry.Name()), size: info.Size(), modTime: info.ModTime()}
+
+		if maxAge := mediaMaxAge(file.path); maxAge > 0 && now.Sub(file.modTime) > maxAge {
+			if removeMediaFile(file, &report) {
+				continue
+			}
+		}
+		kept = append(kept, file)
+		report.RemainingBytes += file.size
+	}
+
+	if config.WhatsappMediaRetentionMaxSize > 0 && report.RemainingBytes > config.WhatsappMediaRetentionMaxSize {
+		sort.Slice(kept, func(i, j int) bool { return kept[i].modTime.Before(kept[j].modTime) })
+		for _, file := range kept {
+			if report.RemainingBytes <= config.WhatsappMediaRetentionMaxSize {
+				break
+			}
+			if removeMediaFile(file, &report) {
+				report.RemainingBytes -= file.size
+			}
+		}
+	}
+	return report
+}
+
+// mediaMaxAge is the age of the first rule matching the mime type of the file, the default max age otherwise
+func mediaMaxAge(path string) time.Duration {
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	for _, rule := range mediaRetentionRules {
+		if mimeType != "" && matchMimeType(rule.MimeType, mimeType) {
+			return rule.MaxAge
+		}
+	}
+	return config.WhatsappMediaRetentionMaxAge
+}
+
+func removeMediaFile(file mediaFile, report *MediaCleanupReport) bool {
+	if err := os.Remove(file.path); err != nil {
+		logrus.Warnf("Failed to remove media %s: %v", file.path, err)
+		return false
+	}
+	forgetDedupMedia(file.path)
+	report.Deleted++
+	report.FreedBytes += file.size
+	return true
+}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMediaRetentionRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		expected []mediaRetentionRule
+		wantErr  bool
+	}{
+		{name: "should accept no rules", values: nil},
+		{
+			name:   "should parse the rules",
+			values: []string{"video/*=72h", " image/png = 30m ", ""},
+			expected: []mediaRetentionRule{
+				{MimeType: "video/*", MaxAge: 72 * time.Hour},
+				{MimeType: "image/png", MaxAge: 30 * time.Minute},
+			},
+		},
+		{name: "should reject a rule without an age", values: []string{"video/*"}, wantErr: true},
+		{name: "should reject an invalid age", values: []string{"video/*=3 days"}, wantErr: true},
+		{name: "should reject a negative age", values: []string{"video/*=-1h"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules, err := parseMediaRetentionRules(tt.values)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, rules)
+		})
+	}
+}
+
+func TestCleanupMediaFolder(t *testing.T) {
+	originalRules, originalMaxAge, originalMaxSize := mediaRetentionRules, config.WhatsappMediaRetentionMaxAge, config.WhatsappMediaRetentionMaxSize
+	defer func() {
+		mediaRetentionRules, config.WhatsappMediaRetentionMaxAge = originalRules, originalMaxAge
+		config.WhatsappMediaRetentionMaxSize = originalMaxSize
+	}()
+
+	now := time.Now()
+	// writeMedia creates a file of the size, last modified age ago
+	writeMedia := func(t *testing.T, folder, name string, size int, age time.Duration) {
+		path := filepath.Join(folder, name)
+		assert.NoError(t, os.WriteFile(path, make([]byte, size), 0600))
+		assert.NoError(t, os.Chtimes(path, now.Add(-age), now.Add(-age)))
+	}
+	remaining := func(t *testing.T, folder string) (names []string) {
+		entries, err := os.ReadDir(folder)
+		assert.NoError(t, err)
+		for _, entry := range entries {
+			names = append(names, entry.Name())
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	t.Run("should delete the media past their max age", func(t *testing.T) {
+		folder := t.TempDir()
+		mediaRetentionRules = []mediaRetentionRule{{MimeType: "video/*", MaxAge: time.Hour}}
+		config.WhatsappMediaRetentionMaxAge, config.WhatsappMediaRetentionMaxSize = 24*time.Hour, 0
+
+		writeMedia(t, folder, "old.jpg", 10, 48*time.Hour)
+		writeMedia(t, folder, "new.jpg", 10, time.Minute)
+		writeMedia(t, folder, "old.mp4", 20, 2*time.Hour)
+		writeMedia(t, folder, "new.mp4", 20, time.Minute)
+
+		report := cleanupMediaFolder(folder, now)
+		assert.Equal(t, 4, report.Scanned)
+		assert.Equal(t, 2, report.Deleted)
+		assert.Equal(t, int64(30), report.FreedBytes)
+		assert.Equal(t, int64(30), report.RemainingBytes)
+		assert.Equal(t, []string{"new.jpg", "new.mp4"}, remaining(t, folder))
+	})
+
+	t.Run("should delete the oldest media above the max size", func(t *testing.T) {
+		folder := t.TempDir()
+		mediaRetentionRules = nil
+		config.WhatsappMediaRetentionMaxAge, config.WhatsappMediaRetentionMaxSize = 0, 25
+
+		writeMedia(t, folder, "a.jpg", 10, 3*time.Hour)
+		writeMedia(t, folder, "b.jpg", 10, 2*time.Hour)
+		writeMedia(t, folder, "c.jpg", 10, time.Hour)
+
+		report := cleanupMediaFolder(folder, now)
+		assert.Equal(t, 1, report.Deleted)
+		assert.Equal(t, int64(20), report.RemainingBytes)
+		assert.Equal(t, []string{"b.jpg", "c.jpg"}, remaining(t, folder))
+	})
+
+	t.Run("should report a missing folder", func(t *testing.T) {
+		report := cleanupMediaFolder(filepath.Join(t.TempDir(), "missing"), now)
+		assert.NotEmpty(t, report.Error)
+	})
+}
@@ -0,0 +1,58 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/antivirus"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// mediaScanner checks the downloaded media before they're saved, nil saves them unchecked
+var mediaScanner antivirus.Scanner
+
+// InitMediaScanner creates the configured antivirus scanner
+func InitMediaScanner() (err error) {
+	mediaScanner, err = antivirus.New(antivirus.Config{
+		Engine:  config.WhatsappMediaScan,
+		Address: config.WhatsappMediaScanAddress,
+		Timeout: config.WhatsappMediaScanTimeout,
+	})
+	return err
+}
+
+// quarantineInfectedMedia scans the downloaded data and moves it to the quarantine when it's infected, the media
+// is then flagged as blocked with the detection name. The media is blocked too when the scan fails.
+func quarantineInfectedMedia(media *ExtractedMedia, data []byte, extension string) bool {
+	if mediaScanner == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), config.WhatsappMediaScanTimeout)
+	defer cancel()
+	result, err := mediaScanner.Scan(ctx, data)
+	if err != nil {
+		logrus.Errorf("Failed to scan media, blocking it: %v", err)
+		result = antivirus.Result{Infected: true, Threat: "scan failed"}
+	}
+	if !result.Infected {
+		return false
+	}
+
+	quarantinePath := filepath.Join(config.PathQuarantine, fmt.Sprintf("%d-%s%s", time.Now().Unix(), uuid.NewString(), extension))
+	if err = writeMediaFile(quarantinePath, data, os.O_TRUNC); err != nil {
+		logrus.Errorf("Failed to quarantine media: %v", err)
+	} else {
+		logrus.Warnf("Media blocked (%s), quarantined to %s", result.Threat, quarantinePath)
+	}
+
+	media.MediaPath = ""
+	media.MediaBlocked = true
+	media.Detection = result.Threat
+	return true
+}
@@ -0,0 +1,59 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/antivirus"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMediaScanner struct {
+	result antivirus.Result
+	err    error
+}
+
+func (fake fakeMediaScanner) Scan(context.Context, []byte) (antivirus.Result, error) {
+	return fake.result, fake.err
+}
+
+func TestQuarantineInfectedMedia(t *testing.T) {
+	originalScanner, originalQuarantine := mediaScanner, config.PathQuarantine
+	defer func() { mediaScanner, config.PathQuarantine = originalScanner, originalQuarantine }()
+
+	t.Run("should save the media without a scanner", func(t *testing.T) {
+		mediaScanner = nil
+		media := ExtractedMedia{MimeType: "image/jpeg"}
+		assert.False(t, quarantineInfectedMedia(&media, []byte("media"), ".jpg"))
+	})
+
+	t.Run("should save clean media", func(t *testing.T) {
+		mediaScanner = fakeMediaScanner{}
+		media := ExtractedMedia{MimeType: "image/jpeg"}
+		assert.False(t, quarantineInfectedMedia(&media, []byte("media"), ".jpg"))
+		assert.False(t, media.MediaBlocked)
+	})
+
+	t.Run("should quarantine infected media", func(t *testing.T) {
+		mediaScanner = fakeMediaScanner{result: antivirus.Result{Infected: true, Threat: "Eicar-Test-Signature"}}
+		config.PathQuarantine = t.TempDir()
+		media := ExtractedMedia{MimeType: "application/pdf"}
+
+		assert.True(t, quarantineInfectedMedia(&media, []byte("media"), ".pdf"))
+		assert.Equal(t, ExtractedMedia{MimeType: "application/pdf", MediaBlocked: true, Detection: "Eicar-Test-Signature"}, media)
+		files, _ := os.ReadDir(config.PathQuarantine)
+		assert.Len(t, files, 1)
+	})
+
+	t.Run("should block the media when the scan fails", func(t *testing.T) {
+		mediaScanner = fakeMediaScanner{err: errors.New("connection refused")}
+		config.PathQuarantine = t.TempDir()
+		media := ExtractedMedia{MimeType: "image/jpeg"}
+
+		assert.True(t, quarantineInfectedMedia(&media, []byte("media"), ".jpg"))
+		assert.Equal(t, "scan failed", media.Detection)
+	})
+}
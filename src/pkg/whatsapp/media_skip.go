@@ -0,0 +1,89 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// mediaSkipRule caps the size of the media of a mime type downloaded with the webhooks, 0 never downloads them
+type mediaSkipRule struct {
+	MimeType string
+	MaxSize  uint64
+}
+
+var mediaSkipRules []mediaSkipRule
+
+// parseMediaSkipRules reads the mime=bytes rules, e.g. video/*=50000000
+func parseMediaSkipRules(values []string) (rules []mediaSkipRule, err error) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		mimeType, size, found := strings.Cut(value, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid media download skip rule %q, use <mime type>=<max bytes>", value)
+		}
+		maxSize, err := strconv.ParseUint(strings.TrimSpace(size), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max size of media download skip rule %q", value)
+		}
+		rules = append(rules, mediaSkipRule{MimeType: strings.TrimSpace(mimeType), MaxSize: maxSize})
+	}
+	return rules, nil
+}
+
+// InitMediaSkipRules validates the configured skip rules
+func InitMediaSkipRules() (err error) {
+	if config.WhatsappMediaDownloadMaxSize < 0 {
+		return fmt.Errorf("media download max size can't be negative")
+	}
+	mediaSkipRules, err = parseMediaSkipRules(config.WhatsappMediaDownloadSkipRules)
+	return err
+}
+
+// mediaDownloadSkipReason tells why the media shouldn't be downloaded, empty when it should. The first rule
+// matching the mime type applies, the global max size otherwise.
+func mediaDownloadSkipReason(mimeType string, size uint64) string {
+	for _, rule := range mediaSkipRules {
+		if !matchMimeType(rule.MimeType, mimeType) {
+			continue
+		}
+		if rule.MaxSize == 0 {
+			return fmt.Sprintf("%s media aren't downloaded", rule.MimeType)
+		}
+		if size > rule.MaxSize {
+			return fmt.Sprintf("size %d over the %d bytes limit of %s media", size, rule.MaxSize, rule.MimeType)
+		}
+		return ""
+	}
+
+	if maxSize := config.WhatsappMediaDownloadMaxSize; maxSize > 0 && size > uint64(maxSize) {
+		return fmt.Sprintf("size %d over the %d bytes limit", size, maxSize)
+	}
+	return ""
+}
+
+// skipMediaDownload sets the media field of the payload to a reference of the media when a skip rule matches,
+// so the consumer can still download it with POST /message/:message_id/download.
+func skipMediaDownload(evt *events.Message, body map[string]interface{}) bool {
+	field, media := getDownloadableMedia(evt.Message)
+	if media == nil {
+		return false
+	}
+
+	reference := buildMediaReference(evt.Info.ID, media)
+	reference.DownloadSkipped = mediaDownloadSkipReason(reference.MimeType, reference.FileLength)
+	if reference.DownloadSkipped == "" {
+		return false
+	}
+	if storeMediaReference(evt, field, media) != nil {
+		reference.DownloadURL = ""
+	}
+	body[field] = reference
+	return true
+}
@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestParseMediaSkipRules(t *testing.T) {
+	rules, err := parseMediaSkipRules([]string{"video/*=50000000", " application/pdf = 0 ", ""})
+	assert.NoError(t, err)
+	assert.Equal(t, []mediaSkipRule{{MimeType: "video/*", MaxSize: 50000000}, {MimeType: "application/pdf"}}, rules)
+
+	_, err = parseMediaSkipRules([]string{"video/*"})
+	assert.Error(t, err)
+	_, err = parseMediaSkipRules([]string{"video/*=50MB"})
+	assert.Error(t, err)
+}
+
+func TestMediaDownloadSkipReason(t *testing.T) {
+	originalRules, originalMaxSize := mediaSkipRules, config.WhatsappMediaDownloadMaxSize
+	defer func() { mediaSkipRules, config.WhatsappMediaDownloadMaxSize = originalRules, originalMaxSize }()
+
+	mediaSkipRules = []mediaSkipRule{{MimeType: "video/*", MaxSize: 50000000}, {MimeType: "application/zip"}}
+	config.WhatsappMediaDownloadMaxSize = 10000000
+
+	tests := []struct {
+		name     string
+		mimeType string
+		size     uint64
+		skipped  bool
+	}{
+		{name: "should download a video within its rule", mimeType: "video/mp4", size: 40000000},
+		{name: "should skip a video over its rule", mimeType: "video/mp4", size: 60000000, skipped: true},
+		{name: "should never download a zip", mimeType: "application/zip", size: 1, skipped: true},
+		{name: "should download an image within the max size", mimeType: "image/jpeg", size: 1000},
+		{name: "should skip an image over the max size", mimeType: "image/jpeg", size: 20000000, skipped: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.skipped, mediaDownloadSkipReason(tt.mimeType, tt.size) != "")
+		})
+	}
+}
+
+func TestSkipMediaDownload(t *testing.T) {
+	originalPath, originalStore, originalRules := config.PathStoreDB, appStore, mediaSkipRules
+	defer func() {
+		config.PathStoreDB, appStore, mediaSkipRules = originalPath, originalStore, originalRules
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+	mediaSkipRules = []mediaSkipRule{{MimeType: "video/*", MaxSize: 50000000}}
+
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: types.NewJID("628123456789", types.DefaultUserServer)},
+		ID:            "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+	}
+	video := &events.Message{Info: info, Message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+		Mimetype:   proto.String("video/mp4"),
+		FileLength: proto.Uint64(60000000),
+	}}}
+
+	body := map[string]interface{}{}
+	assert.True(t, skipMediaDownload(video, body))
+	reference := body["video"].(evtMediaReference)
+	assert.Equal(t, "size 60000000 over the 50000000 bytes limit of video/* media", reference.DownloadSkipped)
+	assert.Equal(t, "/message/3EB0B430B6F8F1D0E053AC120E0A9E5C/download", reference.DownloadURL)
+
+	field, _, err := loadMediaReference(info.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "video", field)
+
+	small := &events.Message{Info: info, Message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+		Mimetype:   proto.String("video/mp4"),
+		FileLength: proto.Uint64(1000),
+	}}}
+	assert.False(t, skipMediaDownload(small, map[string]interface{}{}), "the small video is downloaded")
+}
@@ -0,0 +1,117 @@
+package whatsapp
+
+import (
+	"context"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/storage"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+)
+
+// mediaStorage uploads the received media, nil keeps them in config.PathMedia
+var mediaStorage storage.Driver
+
+// InitMediaStorage creates the configured media storage driver
+func InitMediaStorage() (err error) {
+	mediaStorage, err = storage.New(context.Background(), storage.Config{
+		Driver:    config.WhatsappMediaStorage,
+		PublicURL: config.WhatsappMediaStoragePublicURL,
+		URLExpiry: config.WhatsappMediaStorageURLExpiry,
+		S3: storage.S3Config{
+			Endpoint:  config.WhatsappS3Endpoint,
+			Region:    config.WhatsappS3Region,
+			Bucket:    config.WhatsappS3Bucket,
+			AccessKey: config.WhatsappS3AccessKey,
+			SecretKey: config.WhatsappS3SecretKey,
+			UseSSL:    config.WhatsappS3UseSSL,
+		},
+		GCS: storage.GCSConfig{
+			Bucket:          config.WhatsappGCSBucket,
+			CredentialsFile: config.WhatsappGCSCredentialsFile,
+		},
+		Azure: storage.AzureConfig{
+			ConnectionString: config.WhatsappAzureConnectionString,
+			AccountName:      config.WhatsappAzureAccountName,
+			AccountKey:       config.WhatsappAzureAccountKey,
+			Container:        config.WhatsappAzureContainer,
+		},
+	})
+	return err
+}
+
+// mediaStorageKey is the object key of a local file, under the prefix of the logged in device
+func mediaStorageKey(localPath string) string {
+	device := "unknown"
+	if cli != nil && cli.Store.ID != nil {
+		device = cli.Store.ID.User
+	}
+	return storage.ObjectKey(strings.ReplaceAll(config.WhatsappMediaStoragePrefix, "{device}", device), localPath)
+}
+
+// storeMedia uploads the downloaded media and its converted copy. Unless the local copies are kept, the payload
+// then carries the urls and the object keys, which the inline media read back. A failed upload keeps the local
+// path, so the webhook is still delivered.
+// Media kept in the local media folder get signed urls of this server instead, when its base url is set.
+func storeMedia(media ExtractedMedia) ExtractedMedia {
+	if media.MediaPath == "" {
+		return media
+	}
+	if mediaStorage == nil {
+		media.MediaURL = signLocalMediaURL(media.MediaPath, time.Now())
+		media.ConvertedURL = signLocalMediaURL(media.ConvertedPath, time.Now())
+		return media
+	}
+
+	// the local name of a document is reused once its file is removed, a unique folder keeps it in the bucket
+	key := mediaStorageKey(media.MediaPath)
+	if media.FileName != "" {
+		key = path.Join(path.Dir(key), uuid.NewString(), path.Base(key))
+	}
+	mediaURL, err := saveMediaFile(key, media.MediaPath, media.MimeType)
+	if err != nil {
+		logrus.Warnf("Failed to upload media %s: %v", media.MediaPath, err)
+		return media
+	}
+	media.MediaURL, media.MediaKey = mediaURL, key
+	media.MediaPath = removeStoredMedia(media.MediaPath)
+
+	if media.ConvertedPath != "" {
+		convertedKey := mediaStorageKey(media.ConvertedPath)
+		convertedURL, err := saveMediaFile(convertedKey, media.ConvertedPath, media.ConvertedMimeType)
+		if err != nil {
+			logrus.Warnf("Failed to upload media %s: %v", media.ConvertedPath, err)
+			return media
+		}
+		media.ConvertedURL, media.ConvertedKey = convertedURL, convertedKey
+		media.ConvertedPath = removeStoredMedia(media.ConvertedPath)
+	}
+	return media
+}
+
+// saveMediaFile uploads the file in clear, the local files are encrypted when the media encryption is enabled
+func saveMediaFile(key, localPath, contentType string) (string, error) {
+	clearPath, cleanup, err := clearMediaFile(localPath)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	return mediaStorage.Save(context.Background(), key, clearPath, contentType)
+}
+
+// removeStoredMedia deletes an uploaded file unless the local copies are kept, returning the path left in the payload
+func removeStoredMedia(path string) string {
+	if config.WhatsappMediaStorageKeepLocal {
+		return path
+	}
+	if err := os.Remove(path); err != nil {
+		logrus.Warnf("Failed to remove uploaded media %s: %v", path, err)
+		return path
+	}
+	forgetDedupMedia(path)
+	return ""
+}
@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeMediaStorage struct {
+	err   error
+	saved []string
+	files map[string][]byte
+}
+
+func (fake *fakeMediaStorage) Save(_ context.Context, key, localPath, _ string) (string, error) {
+	if fake.err != nil {
+		return "", fake.err
+	}
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return "", err
+	}
+	if fake.files == nil {
+		fake.files = make(map[string][]byte)
+	}
+	fake.saved = append(fake.saved, key)
+	fake.files[key] = data
+	return "https://cdn.example.com/" + key, nil
+}
+
+func (fake *fakeMediaStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := fake.files[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func TestStoreMedia(t *testing.T) {
+	originalStorage, originalKeepLocal := mediaStorage, config.WhatsappMediaStorageKeepLocal
+	originalPrefix := config.WhatsappMediaStoragePrefix
+	defer func() {
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = originalStorage, originalKeepLocal
+		config.WhatsappMediaStoragePrefix = originalPrefix
+	}()
+
+	writeMedia := func(t *testing.T, name string) string {
+		path := filepath.Join(t.TempDir(), name)
+		assert.NoError(t, os.WriteFile(path, []byte("media"), 0600))
+		return path
+	}
+
+	t.Run("should keep the local path without a storage", func(t *testing.T) {
+		mediaStorage, config.WhatsappMediaBaseURL = nil, ""
+		media := ExtractedMedia{MediaPath: writeMedia(t, "a.jpg"), MimeType: "image/jpeg"}
+		assert.Equal(t, media, storeMedia(media))
+	})
+
+	t.Run("should replace the local paths with the urls and keys", func(t *testing.T) {
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = &fakeMediaStorage{}, false
+		mediaPath, convertedPath := writeMedia(t, "a.webp"), writeMedia(t, "a.png")
+
+		media := storeMedia(ExtractedMedia{MediaPath: mediaPath, ConvertedPath: convertedPath})
+		assert.Equal(t, ExtractedMedia{
+			MediaURL:     "https://cdn.example.com/a.webp",
+			MediaKey:     "a.webp",
+			ConvertedURL: "https://cdn.example.com/a.png",
+			ConvertedKey: "a.png",
+		}, media)
+		assert.NoFileExists(t, mediaPath)
+		assert.NoFileExists(t, convertedPath)
+	})
+
+	t.Run("should keep the local copy when configured", func(t *testing.T) {
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = &fakeMediaStorage{}, true
+		mediaPath := writeMedia(t, "a.jpg")
+
+		media := storeMedia(ExtractedMedia{MediaPath: mediaPath})
+		assert.Equal(t, mediaPath, media.MediaPath)
+		assert.Equal(t, "https://cdn.example.com/a.jpg", media.MediaURL)
+		assert.FileExists(t, mediaPath)
+	})
+
+	t.Run("should upload under the device prefix", func(t *testing.T) {
+		fake := &fakeMediaStorage{}
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = fake, false
+		config.WhatsappMediaStoragePrefix = "{device}/media"
+		defer func() { config.WhatsappMediaStoragePrefix = "" }()
+
+		media := storeMedia(ExtractedMedia{MediaPath: writeMedia(t, "a.jpg")})
+		assert.Equal(t, []string{"unknown/media/a.jpg"}, fake.saved)
+		assert.Equal(t, "https://cdn.example.com/unknown/media/a.jpg", media.MediaURL)
+	})
+
+	t.Run("should keep the local path when the upload fails", func(t *testing.T) {
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = &fakeMediaStorage{err: errors.New("offline")}, false
+		media := ExtractedMedia{MediaPath: writeMedia(t, "a.jpg")}
+
+		assert.Equal(t, media, storeMedia(media))
+		assert.FileExists(t, media.MediaPath)
+	})
+}
@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/google/uuid"
+	"go.mau.fi/whatsmeow"
+)
+
+var uploadedMediaMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS uploaded_media (
+		id              TEXT    PRIMARY KEY,
+		type            TEXT    NOT NULL,
+		mime_type       TEXT    NOT NULL,
+		file_name       TEXT    NOT NULL,
+		url             TEXT    NOT NULL,
+		direct_path     TEXT    NOT NULL,
+		media_key       BLOB    NOT NULL,
+		file_sha256     BLOB    NOT NULL,
+		file_enc_sha256 BLOB    NOT NULL,
+		file_length     INTEGER NOT NULL,
+		thumbnail       BLOB,
+		created_at      INTEGER NOT NULL
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS uploaded_media_file ON uploaded_media (type, file_sha256)`,
+}
+
+// UploadedMedia is a media uploaded once to WhatsApp, attached to any number of messages by its ID
+type UploadedMedia struct {
+	ID        string
+	Type      string // image, video, audio or document
+	MimeType  string
+	FileName  string
+	Upload    whatsmeow.UploadResponse
+	Thumbnail []byte // JPEG, images and videos only
+	CreatedAt time.Time
+}
+
+// ExpiresAt is when WhatsApp may have deleted the uploaded file, the media must be uploaded again then
+func (media UploadedMedia) ExpiresAt() time.Time {
+	return media.CreatedAt.Add(config.WhatsappMediaUploadExpiry)
+}
+
+// UploadedMediaType maps the upload types to the whatsmeow media types
+func UploadedMediaType(mediaType string) (whatsmeow.MediaType, bool) {
+	switch mediaType {
+	case "image":
+		return whatsmeow.MediaImage, true
+	case "video":
+		return whatsmeow.MediaVideo, true
+	case "audio":
+		return whatsmeow.MediaAudio, true
+	case "document":
+		return whatsmeow.MediaDocument, true
+	}
+	return "", false
+}
+
+const uploadedMediaColumns = `id, type, mime_type, file_name, url, direct_path, media_key, file_sha256, file_enc_sha256, file_length, thumbnail, created_at`
+
+// FindCachedUpload returns the unexpired upload of the same file, so it isn't uploaded again
+func FindCachedUpload(mediaType string, fileSHA256 []byte) (UploadedMedia, bool) {
+	if appStore == nil {
+		return UploadedMedia{}, false
+	}
+	media, err := scanUploadedMedia(appStore.QueryRow(
+		`SELECT `+uploadedMediaColumns+` FROM uploaded_media WHERE type = ? AND file_sha256 = ?`, mediaType, fileSHA256,
+	))
+	if err != nil || time.Now().After(media.ExpiresAt()) {
+		return UploadedMedia{}, false
+	}
+	return media, true
+}
+
+// SaveUploadedMedia stores the upload with a new ID, replacing an expired upload of the same file
+func SaveUploadedMedia(media UploadedMedia) (UploadedMedia, error) {
+	if appStore == nil {
+		return media, errStoreNotInitialized
+	}
+
+	media.ID, media.CreatedAt = uuid.NewString(), time.Now()
+	_, err := appStore.Exec(
+		`INSERT OR REPLACE INTO uploaded_media (`+uploadedMediaColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		media.ID, media.Type, media.MimeType, media.FileName, media.Upload.URL, media.Upload.DirectPath, media.Upload.MediaKey,
+		media.Upload.FileSHA256, media.Upload.FileEncSHA256, media.Upload.FileLength, media.Thumbnail, media.CreatedAt.Unix(),
+	)
+	return media, err
+}
+
+// GetUploadedMedia returns the upload to attach to a message of the media type
+func GetUploadedMedia(id, mediaType string) (UploadedMedia, error) {
+	media, err := FindUploadedMedia(id)
+	if err != nil {
+		return media, err
+	}
+	if media.Type != mediaType {
+		return media, pkgError.ValidationError(fmt.Sprintf("uploaded media %s is a %s, not a %s", id, media.Type, mediaType))
+	}
+	return media, nil
+}
+
+// FindUploadedMedia returns the upload of any media type, as long as it hasn't expired
+func FindUploadedMedia(id string) (UploadedMedia, error) {
+	if appStore == nil {
+		return UploadedMedia{}, errStoreNotInitialized
+	}
+
+	media, err := scanUploadedMedia(appStore.QueryRow(`SELECT `+uploadedMediaColumns+` FROM uploaded_media WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return media, pkgError.ValidationError(fmt.Sprintf("no uploaded media %s", id))
+	}
+	if err != nil {
+		return media, err
+	}
+	if time.Now().After(media.ExpiresAt()) {
+		return media, pkgError.ValidationError(fmt.Sprintf("uploaded media %s expired, upload it again", id))
+	}
+	return media, nil
+}
+
+func scanUploadedMedia(row *sql.Row) (media UploadedMedia, err error) {
+	var createdAt int64
+	err = row.Scan(
+		&media.ID, &media.Type, &media.MimeType, &media.FileName, &media.Upload.URL, &media.Upload.DirectPath, &media.Upload.MediaKey,
+		&media.Upload.FileSHA256, &media.Upload.FileEncSHA256, &media.Upload.FileLength, &media.Thumbnail, &createdAt,
+	)
+	media.CreatedAt = time.Unix(createdAt, 0)
+	return media, err
+}
@@ -0,0 +1,77 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+)
+
+func TestUploadedMedia(t *testing.T) {
+	originalPath, originalStore, originalExpiry := config.PathStoreDB, appStore, config.WhatsappMediaUploadExpiry
+	defer func() {
+		config.PathStoreDB, appStore, config.WhatsappMediaUploadExpiry = originalPath, originalStore, originalExpiry
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+	config.WhatsappMediaUploadExpiry = time.Hour
+
+	upload := whatsmeow.UploadResponse{
+		URL:           "https://mmg.whatsapp.net/v/t62.7118-24/abc",
+		DirectPath:    "/v/t62.7118-24/abc",
+		MediaKey:      []byte{0x01},
+		FileEncSHA256: []byte{0x02},
+		FileSHA256:    []byte{0xca, 0xfe},
+		FileLength:    2048,
+	}
+	saved, err := SaveUploadedMedia(UploadedMedia{Type: "image", MimeType: "image/jpeg", FileName: "a.jpg", Upload: upload})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, saved.ID)
+
+	t.Run("should find the upload of the same file", func(t *testing.T) {
+		cached, ok := FindCachedUpload("image", []byte{0xca, 0xfe})
+		assert.True(t, ok)
+		assert.Equal(t, saved.ID, cached.ID)
+		assert.Equal(t, upload, cached.Upload)
+
+		_, ok = FindCachedUpload("document", []byte{0xca, 0xfe})
+		assert.False(t, ok, "the same file uploaded as a document is another upload")
+	})
+
+	t.Run("should get the upload by its id", func(t *testing.T) {
+		media, err := GetUploadedMedia(saved.ID, "image")
+		assert.NoError(t, err)
+		assert.Equal(t, "a.jpg", media.FileName)
+
+		_, err = GetUploadedMedia(saved.ID, "video")
+		assert.Error(t, err)
+		_, err = GetUploadedMedia("unknown", "image")
+		assert.Error(t, err)
+	})
+
+	t.Run("should expire the upload", func(t *testing.T) {
+		config.WhatsappMediaUploadExpiry = -time.Second
+		defer func() { config.WhatsappMediaUploadExpiry = time.Hour }()
+
+		_, ok := FindCachedUpload("image", []byte{0xca, 0xfe})
+		assert.False(t, ok)
+		_, err := GetUploadedMedia(saved.ID, "image")
+		assert.Error(t, err)
+	})
+
+	t.Run("should replace the expired upload of the same file", func(t *testing.T) {
+		again, err := SaveUploadedMedia(UploadedMedia{Type: "image", MimeType: "image/jpeg", FileName: "b.jpg", Upload: upload})
+		assert.NoError(t, err)
+
+		cached, ok := FindCachedUpload("image", []byte{0xca, 0xfe})
+		assert.True(t, ok)
+		assert.Equal(t, again.ID, cached.ID)
+		_, err = GetUploadedMedia(saved.ID, "image")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+package whatsapp
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// ValidateMediaURLConfig checks the signed media urls have their own secret, /media/:id serves them without basic
+// auth so a known or shared secret would let anyone sign urls for any media
+func ValidateMediaURLConfig() error {
+	if config.WhatsappMediaBaseURL == "" {
+		return nil
+	}
+	if config.WhatsappMediaURLSecret == "" {
+		return fmt.Errorf("--media-url-secret is required with --media-base-url")
+	}
+	if config.WhatsappMediaURLSecret == config.WhatsappWebhookSecret {
+		return fmt.Errorf("--media-url-secret must differ from the webhook secret")
+	}
+	return nil
+}
+
+func mediaURLSignature(file string, expires int64) string {
+	mac := hmac.New(sha256.New, []byte(config.WhatsappMediaURLSecret))
+	mac.Write([]byte(fmt.Sprintf("%s:%d", file, expires)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// signLocalMediaURL returns a time limited url of a file of the media folder, served by /media/:id.
+// Without --media-base-url the server doesn't know its public address and no url is returned.
+func signLocalMediaURL(localPath string, now time.Time) string {
+	if config.WhatsappMediaBaseURL == "" || localPath == "" {
+		return ""
+	}
+	file := filepath.Base(localPath)
+	expires := now.Add(config.WhatsappMediaStorageURLExpiry).Unix()
+	query := url.Values{
+		"expires":   {strconv.FormatInt(expires, 10)},
+		"signature": {mediaURLSignature(file, expires)},
+	}
+	return fmt.Sprintf("%s/media/%s?%s", strings.TrimSuffix(config.WhatsappMediaBaseURL, "/"), url.PathEscape(file), query.Encode())
+}
+
+// VerifyMediaURL checks the signature and the expiry of a local media url, returning the path of the file
+func VerifyMediaURL(file, expires, signature string, now time.Time) (string, error) {
+	path, err := MediaFilePath(file)
+	if err != nil {
+		return "", err
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid media url expiry")
+	}
+	if !hmac.Equal([]byte(signature), []byte(mediaURLSignature(file, expiresAt))) {
+		return "", fmt.Errorf("invalid media url signature")
+	}
+	if now.Unix() > expiresAt {
+		return "", fmt.Errorf("media url expired")
+	}
+	return path, nil
+}
+
+// MediaFilePath returns the path of a file of the media folder, its name being the id of the media
+func MediaFilePath(id string) (string, error) {
+	if id == "" || id != filepath.Base(id) || strings.HasPrefix(id, ".") {
+		return "", fmt.Errorf("invalid media file")
+	}
+	return filepath.Join(config.PathMedia, id), nil
+}
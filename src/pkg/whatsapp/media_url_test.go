@@ -0,0 +1,87 @@
+package whatsapp
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignLocalMediaURL(t *testing.T) {
+	originalBaseURL, originalSecret := config.WhatsappMediaBaseURL, config.WhatsappMediaURLSecret
+	defer func() {
+		config.WhatsappMediaBaseURL, config.WhatsappMediaURLSecret = originalBaseURL, originalSecret
+	}()
+	config.WhatsappMediaURLSecret = "media-secret"
+	now := time.Unix(1700000000, 0)
+
+	t.Run("should not sign without a base url", func(t *testing.T) {
+		config.WhatsappMediaBaseURL = ""
+		assert.Empty(t, signLocalMediaURL("statics/media/a.jpg", now))
+	})
+
+	t.Run("should sign a url the server accepts until it expires", func(t *testing.T) {
+		config.WhatsappMediaBaseURL = "https://bot.example.com/"
+		signed := signLocalMediaURL("statics/media/a.jpg", now)
+		assert.True(t, strings.HasPrefix(signed, "https://bot.example.com/media/a.jpg?"))
+
+		parsed, err := url.Parse(signed)
+		assert.NoError(t, err)
+		expires, signature := parsed.Query().Get("expires"), parsed.Query().Get("signature")
+
+		path, err := VerifyMediaURL("a.jpg", expires, signature, now)
+		assert.NoError(t, err)
+		assert.Equal(t, filepath.Join(config.PathMedia, "a.jpg"), path)
+
+		_, err = VerifyMediaURL("a.jpg", expires, signature, now.Add(config.WhatsappMediaStorageURLExpiry+time.Second))
+		assert.EqualError(t, err, "media url expired")
+
+		_, err = VerifyMediaURL("b.jpg", expires, signature, now)
+		assert.EqualError(t, err, "invalid media url signature")
+
+		_, err = VerifyMediaURL("a.jpg", "1800000000", signature, now)
+		assert.EqualError(t, err, "invalid media url signature")
+	})
+
+	t.Run("should reject paths out of the media folder", func(t *testing.T) {
+		for _, file := range []string{"", "../webhook.db", ".env"} {
+			_, err := VerifyMediaURL(file, "1800000000", "", now)
+			assert.Error(t, err, file)
+		}
+	})
+}
+
+func TestMediaFilePath(t *testing.T) {
+	path, err := MediaFilePath("1700000000-abc.mp4")
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(config.PathMedia, "1700000000-abc.mp4"), path)
+
+	for _, id := range []string{"", "../webhook.db", "a/b.jpg", ".env"} {
+		_, err = MediaFilePath(id)
+		assert.Error(t, err, id)
+	}
+}
+
+func TestValidateMediaURLConfig(t *testing.T) {
+	originalBaseURL, originalSecret, originalWebhookSecret := config.WhatsappMediaBaseURL, config.WhatsappMediaURLSecret, config.WhatsappWebhookSecret
+	defer func() {
+		config.WhatsappMediaBaseURL, config.WhatsappMediaURLSecret, config.WhatsappWebhookSecret = originalBaseURL, originalSecret, originalWebhookSecret
+	}()
+	config.WhatsappWebhookSecret = "secret"
+
+	config.WhatsappMediaBaseURL, config.WhatsappMediaURLSecret = "", ""
+	assert.NoError(t, ValidateMediaURLConfig(), "signed urls are off without a base url")
+
+	config.WhatsappMediaBaseURL = "https://bot.example.com"
+	assert.EqualError(t, ValidateMediaURLConfig(), "--media-url-secret is required with --media-base-url")
+
+	config.WhatsappMediaURLSecret = "secret"
+	assert.EqualError(t, ValidateMediaURLConfig(), "--media-url-secret must differ from the webhook secret")
+
+	config.WhatsappMediaURLSecret = "media-secret"
+	assert.NoError(t, ValidateMediaURLConfig())
+}
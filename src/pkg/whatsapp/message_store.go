@@ -0,0 +1,324 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+var messageStoreMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS messages (
+		chat       TEXT    NOT NULL,
+		id         TEXT    NOT NULL,
+		sender     TEXT    NOT NULL,
+		from_me    INTEGER NOT NULL,
+		message    BLOB    NOT NULL,
+		timestamp  INTEGER NOT NULL,
+		PRIMARY KEY (chat, id)
+	)`,
+}
+
+// StoredMessage is a message kept in the store to act on it later, like editing or forwarding it
+type StoredMessage struct {
+	Chat      types.JID
+	ID        types.MessageID
+	Sender    types.JID
+	FromMe    bool
+	Message   *waE2E.Message
+	Timestamp time.Time
+}
+
+// StoreSentMessage keeps a message sent from this device
+func StoreSentMessage(recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message) {
+	if appStore == nil || cli == nil || cli.Store.ID == nil || msg.GetViewOnceMessageV2() != nil {
+		return
+	}
+	storeMessage(StoredMessage{
+		Chat:      recipient,
+		ID:        resp.ID,
+		Sender:    cli.Store.ID.ToNonAD(),
+		FromMe:    true,
+		Message:   msg,
+		Timestamp: resp.Timestamp,
+	})
+}
+
+// storeReceivedMessage keeps a message of a chat, view once messages can't be forwarded and aren't kept
+func storeReceivedMessage(evt *events.Message) {
+	if appStore == nil || evt.IsViewOnce || evt.Message.GetProtocolMessage() != nil ||
+		evt.Message.GetReactionMessage() != nil || evt.Message.GetPollUpdateMessage() != nil {
+		return
+	}
+	storeMessage(StoredMessage{
+		Chat:      evt.Info.Chat,
+		ID:        evt.Info.ID,
+		Sender:    evt.Info.Sender.ToNonAD(),
+		FromMe:    evt.Info.IsFromMe,
+		Message:   evt.Message,
+		Timestamp: evt.Info.Timestamp,
+	})
+}
+
+func storeMessage(stored StoredMessage) {
+	data, err := proto.Marshal(stored.Message)
+	if err != nil {
+		logrus.Errorf("Failed to encode message %s: %v", stored.ID, err)
+		return
+	}
+	if _, err = appStore.Exec(
+		`INSERT OR REPLACE INTO messages (chat, id, sender, from_me, message, timestamp) VALUES (?, ?, ?, ?, ?, ?)`,
+		stored.Chat.String(), stored.ID, stored.Sender.String(), stored.FromMe, data, stored.Timestamp.Unix(),
+	); err != nil {
+		logrus.Errorf("Failed to store message %s: %v", stored.ID, err)
+	}
+}
+
+// FindStoredMessage returns a message of the chat, nil when it isn't stored
+func FindStoredMessage(chat types.JID, id types.MessageID) (*StoredMessage, error) {
+	if appStore == nil {
+		return nil, nil
+	}
+
+	var (
+		sender    string
+		data      []byte
+		timestamp int64
+	)
+	stored := &StoredMessage{Chat: chat, ID: id, Message: &waE2E.Message{}}
+	err := appStore.QueryRow(
+		`SELECT sender, from_me, message, timestamp FROM messages WHERE chat = ? AND id = ?`, chat.String(), id,
+	).Scan(&sender, &stored.FromMe, &data, &timestamp)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if stored.Sender, err = types.ParseJID(sender); err != nil {
+		return nil, err
+	}
+	if err = proto.Unmarshal(data, stored.Message); err != nil {
+		return nil, err
+	}
+	stored.Timestamp = time.Unix(timestamp, 0)
+	return stored, nil
+}
+
+// FindStoredMessagesUntil returns the ID and sender of the messages received in the chat until a time
+func FindStoredMessagesUntil(chat types.JID, until time.Time) ([]StoredMessage, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	rows, err := appStore.Query(
+		`SELECT id, sender, timestamp FROM messages WHERE chat = ? AND from_me = 0 AND timestamp <= ? ORDER BY timestamp`,
+		chat.String(), until.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []StoredMessage
+	for rows.Next() {
+		var (
+			sender    string
+			timestamp int64
+		)
+		stored := StoredMessage{Chat: chat}
+		if err = rows.Scan(&stored.ID, &sender, &timestamp); err != nil {
+			return nil, err
+		}
+		if stored.Sender, err = types.ParseJID(sender); err != nil {
+			return nil, err
+		}
+		stored.Timestamp = time.Unix(timestamp, 0)
+		messages = append(messages, stored)
+	}
+	return messages, rows.Err()
+}
+
+// StartMessageJanitor periodically deletes the stored messages past their retention
+func StartMessageJanitor() {
+	if config.WhatsappMessageStoreRetention <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := pruneStoredMessages(time.Now().Add(-config.WhatsappMessageStoreRetention)); err != nil {
+				logrus.Errorf("Error pruning stored messages: %v", err)
+			}
+		}
+	}()
+}
+
+func pruneStoredMessages(before time.Time) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+	_, err := appStore.Exec(`DELETE FROM messages WHERE timestamp < ?`, before.Unix())
+	return err
+}
+
+// BuildEditedContent returns the new content of an edited message, the caption is replaced for media
+func BuildEditedContent(original *waE2E.Message, text string) *waE2E.Message {
+	switch {
+	case original.GetImageMessage() != nil:
+		image := proto.Clone(original.GetImageMessage()).(*waE2E.ImageMessage)
+		image.Caption = proto.String(text)
+		return &waE2E.Message{ImageMessage: image}
+	case original.GetVideoMessage() != nil:
+		video := proto.Clone(original.GetVideoMessage()).(*waE2E.VideoMessage)
+		video.Caption = proto.String(text)
+		return &waE2E.Message{VideoMessage: video}
+	case original.GetDocumentMessage() != nil:
+		document := proto.Clone(original.GetDocumentMessage()).(*waE2E.DocumentMessage)
+		document.Caption = proto.String(text)
+		return &waE2E.Message{DocumentMessage: document}
+	case original.GetExtendedTextMessage() != nil:
+		return &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text:        proto.String(text),
+			ContextInfo: original.GetExtendedTextMessage().GetContextInfo(),
+		}}
+	default:
+		return &waE2E.Message{Conversation: proto.String(text)}
+	}
+}
+
+// BuildForwardedMessage copies a message to forward it, the media are sent again from their WhatsApp server copy
+func BuildForwardedMessage(original *waE2E.Message) (*waE2E.Message, error) {
+	msg := &waE2E.Message{}
+	switch {
+	case original.GetConversation() != "":
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
+			Text:        proto.String(original.GetConversation()),
+			ContextInfo: forwardedContextInfo(nil),
+		}
+	case original.GetExtendedTextMessage() != nil:
+		msg.ExtendedTextMessage = proto.Clone(original.GetExtendedTextMessage()).(*waE2E.ExtendedTextMessage)
+		msg.ExtendedTextMessage.ContextInfo = forwardedContextInfo(msg.ExtendedTextMessage.ContextInfo)
+	case original.GetImageMessage() != nil:
+		msg.ImageMessage = proto.Clone(original.GetImageMessage()).(*waE2E.ImageMessage)
+		msg.ImageMessage.ContextInfo = forwardedContextInfo(msg.ImageMessage.ContextInfo)
+	case original.GetVideoMessage() != nil:
+		msg.VideoMessage = proto.Clone(original.GetVideoMessage()).(*waE2E.VideoMessage)
+		msg.VideoMessage.ContextInfo = forwardedContextInfo(msg.VideoMessage.ContextInfo)
+	case original.GetAudioMessage() != nil:
+		msg.AudioMessage = proto.Clone(original.GetAudioMessage()).(*waE2E.AudioMessage)
+		msg.AudioMessage.ContextInfo = forwardedContextInfo(msg.AudioMessage.ContextInfo)
+	case original.GetDocumentMessage() != nil:
+		msg.DocumentMessage = proto.Clone(original.GetDocumentMessage()).(*waE2E.DocumentMessage)
+		msg.DocumentMessage.ContextInfo = forwardedContextInfo(msg.DocumentMessage.ContextInfo)
+	case original.GetStickerMessage() != nil:
+		msg.StickerMessage = proto.Clone(original.GetStickerMessage()).(*waE2E.StickerMessage)
+		msg.StickerMessage.ContextInfo = forwardedContextInfo(msg.StickerMessage.ContextInfo)
+	case original.GetLocationMessage() != nil:
+		msg.LocationMessage = proto.Clone(original.GetLocationMessage()).(*waE2E.LocationMessage)
+		msg.LocationMessage.ContextInfo = forwardedContextInfo(msg.LocationMessage.ContextInfo)
+	case original.GetContactMessage() != nil:
+		msg.ContactMessage = proto.Clone(original.GetContactMessage()).(*waE2E.ContactMessage)
+		msg.ContactMessage.ContextInfo = forwardedContextInfo(msg.ContactMessage.ContextInfo)
+	case original.GetContactsArrayMessage() != nil:
+		msg.ContactsArrayMessage = proto.Clone(original.GetContactsArrayMessage()).(*waE2E.ContactsArrayMessage)
+		msg.ContactsArrayMessage.ContextInfo = forwardedContextInfo(msg.ContactsArrayMessage.ContextInfo)
+	default:
+		return nil, pkgError.ValidationError("this type of message can't be forwarded")
+	}
+	return msg, nil
+}
+
+// SetQuotedContext makes msg a reply to the quoted message of the context, a plain text becomes an extended text
+// to carry it. Messages without a context, e.g. reactions, are left as is.
+func SetQuotedContext(msg *waE2E.Message, quote *waE2E.ContextInfo) {
+	if quote == nil {
+		return
+	}
+	if contextInfo := editableContextInfo(msg); contextInfo != nil {
+		contextInfo.StanzaID = quote.StanzaID
+		contextInfo.Participant = quote.Participant
+		contextInfo.QuotedMessage = quote.QuotedMessage
+	}
+}
+
+// SetEphemeralContext makes msg disappear after the expiration, whatever the disappearing timer of the chat
+func SetEphemeralContext(msg *waE2E.Message, expiration time.Duration, now time.Time) {
+	if expiration <= 0 {
+		return
+	}
+	if contextInfo := editableContextInfo(msg); contextInfo != nil {
+		contextInfo.Expiration = proto.Uint32(uint32(expiration.Seconds()))
+		contextInfo.EphemeralSettingTimestamp = proto.Int64(now.Unix())
+	}
+}
+
+// editableContextInfo returns the context info of the message to fill it, created when missing. A plain text
+// becomes an extended text to carry it, nil is returned for messages without a context.
+func editableContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	if msg.Conversation != nil {
+		msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{Text: msg.Conversation}
+		msg.Conversation = nil
+	}
+
+	var contextInfo **waE2E.ContextInfo
+	switch {
+	case msg.ExtendedTextMessage != nil:
+		contextInfo = &msg.ExtendedTextMessage.ContextInfo
+	case msg.ImageMessage != nil:
+		contextInfo = &msg.ImageMessage.ContextInfo
+	case msg.VideoMessage != nil:
+		contextInfo = &msg.VideoMessage.ContextInfo
+	case msg.AudioMessage != nil:
+		contextInfo = &msg.AudioMessage.ContextInfo
+	case msg.DocumentMessage != nil:
+		contextInfo = &msg.DocumentMessage.ContextInfo
+	case msg.StickerMessage != nil:
+		contextInfo = &msg.StickerMessage.ContextInfo
+	case msg.LocationMessage != nil:
+		contextInfo = &msg.LocationMessage.ContextInfo
+	case msg.ContactMessage != nil:
+		contextInfo = &msg.ContactMessage.ContextInfo
+	case msg.ContactsArrayMessage != nil:
+		contextInfo = &msg.ContactsArrayMessage.ContextInfo
+	case msg.PollCreationMessage != nil:
+		contextInfo = &msg.PollCreationMessage.ContextInfo
+	case msg.ButtonsMessage != nil:
+		contextInfo = &msg.ButtonsMessage.ContextInfo
+	case msg.ListMessage != nil:
+		contextInfo = &msg.ListMessage.ContextInfo
+	case msg.InteractiveMessage != nil:
+		contextInfo = &msg.InteractiveMessage.ContextInfo
+	case msg.ProductMessage != nil:
+		contextInfo = &msg.ProductMessage.ContextInfo
+	case msg.OrderMessage != nil:
+		contextInfo = &msg.OrderMessage.ContextInfo
+	default:
+		return nil
+	}
+
+	if *contextInfo == nil {
+		*contextInfo = &waE2E.ContextInfo{}
+	}
+	return *contextInfo
+}
+
+// forwardedContextInfo drops the quote and mentions of the original message, the score counts the forwards
+func forwardedContextInfo(original *waE2E.ContextInfo) *waE2E.ContextInfo {
+	return &waE2E.ContextInfo{
+		IsForwarded:     proto.Bool(true),
+		ForwardingScore: proto.Uint32(original.GetForwardingScore() + 1),
+	}
+}
@@ -0,0 +1,217 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestFindStoredMessage(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	sentAt := time.Unix(1700000000, 0)
+	storeMessage(StoredMessage{
+		Chat:      chat,
+		ID:        "3EB0ABCDEF",
+		Sender:    types.NewJID("628987654321", types.DefaultUserServer),
+		FromMe:    true,
+		Message:   &waE2E.Message{Conversation: proto.String("Helo")},
+		Timestamp: sentAt,
+	})
+
+	stored, err := FindStoredMessage(chat, "3EB0ABCDEF")
+	assert.NoError(t, err)
+	if assert.NotNil(t, stored) {
+		assert.True(t, stored.FromMe)
+		assert.Equal(t, "628987654321@s.whatsapp.net", stored.Sender.String())
+		assert.Equal(t, "Helo", stored.Message.GetConversation())
+		assert.True(t, sentAt.Equal(stored.Timestamp))
+	}
+
+	stored, err = FindStoredMessage(types.NewJID("628111111111", types.DefaultUserServer), "3EB0ABCDEF")
+	assert.NoError(t, err)
+	assert.Nil(t, stored, "messages are looked up in their chat")
+}
+
+func TestFindStoredMessagesUntil(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	chat := types.NewJID("120363000000000000", types.GroupServer)
+	alice := types.NewJID("628111111111", types.DefaultUserServer)
+	bob := types.NewJID("628222222222", types.DefaultUserServer)
+	for _, stored := range []StoredMessage{
+		{ID: "A1", Sender: alice, Timestamp: time.Unix(1700000100, 0)},
+		{ID: "B1", Sender: bob, Timestamp: time.Unix(1700000000, 0)},
+		{ID: "ME", Sender: alice, FromMe: true, Timestamp: time.Unix(1700000050, 0)},
+		{ID: "A2", Sender: alice, Timestamp: time.Unix(1700000300, 0)},
+	} {
+		stored.Chat = chat
+		stored.Message = &waE2E.Message{Conversation: proto.String("Helo")}
+		storeMessage(stored)
+	}
+
+	messages, err := FindStoredMessagesUntil(chat, time.Unix(1700000200, 0))
+	assert.NoError(t, err)
+	if assert.Len(t, messages, 2, "our own and the later messages are left out") {
+		assert.Equal(t, "B1", messages[0].ID)
+		assert.Equal(t, bob, messages[0].Sender)
+		assert.Equal(t, "A1", messages[1].ID)
+		assert.Equal(t, alice, messages[1].Sender)
+	}
+}
+
+func TestBuildEditedContent(t *testing.T) {
+	image := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		Caption:    proto.String("old caption"),
+		DirectPath: proto.String("/v/t62/abc"),
+	}}
+	edited := BuildEditedContent(image, "new caption")
+	assert.Equal(t, "new caption", edited.GetImageMessage().GetCaption())
+	assert.Equal(t, "/v/t62/abc", edited.GetImageMessage().GetDirectPath())
+	assert.Equal(t, "old caption", image.GetImageMessage().GetCaption(), "the original message is left as is")
+
+	text := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String("helo")}}
+	assert.Equal(t, "hello", BuildEditedContent(text, "hello").GetExtendedTextMessage().GetText())
+
+	assert.Equal(t, "hello", BuildEditedContent(&waE2E.Message{Conversation: proto.String("helo")}, "hello").GetConversation())
+}
+
+func TestStoreReceivedMessage(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	chat := types.NewJID("120363025246125486", types.GroupServer)
+	received := func(id types.MessageID, msg *waE2E.Message, viewOnce bool, sentAt time.Time) *events.Message {
+		return &events.Message{
+			Info: types.MessageInfo{
+				MessageSource: types.MessageSource{Chat: chat, Sender: types.NewADJID("628123456789", 0, 3), IsGroup: true},
+				ID:            id,
+				Timestamp:     sentAt,
+			},
+			Message:    msg,
+			IsViewOnce: viewOnce,
+		}
+	}
+
+	now := time.Now()
+	storeReceivedMessage(received("TEXT", &waE2E.Message{Conversation: proto.String("hi")}, false, now))
+	storeReceivedMessage(received("OLD", &waE2E.Message{Conversation: proto.String("hi")}, false, now.Add(-48*time.Hour)))
+	storeReceivedMessage(received("VIEWONCE", &waE2E.Message{ImageMessage: &waE2E.ImageMessage{}}, true, now))
+
+	stored, err := FindStoredMessage(chat, "TEXT")
+	assert.NoError(t, err)
+	if assert.NotNil(t, stored) {
+		assert.False(t, stored.FromMe)
+		assert.Equal(t, "628123456789@s.whatsapp.net", stored.Sender.String(), "the device of the sender is dropped")
+	}
+
+	stored, err = FindStoredMessage(chat, "VIEWONCE")
+	assert.NoError(t, err)
+	assert.Nil(t, stored, "view once messages aren't kept")
+
+	assert.NoError(t, pruneStoredMessages(now.Add(-24*time.Hour)))
+	stored, err = FindStoredMessage(chat, "OLD")
+	assert.NoError(t, err)
+	assert.Nil(t, stored, "messages past the retention are pruned")
+	stored, err = FindStoredMessage(chat, "TEXT")
+	assert.NoError(t, err)
+	assert.NotNil(t, stored)
+}
+
+func TestBuildForwardedMessage(t *testing.T) {
+	msg, err := BuildForwardedMessage(&waE2E.Message{Conversation: proto.String("hello")})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", msg.GetExtendedTextMessage().GetText())
+	assert.True(t, msg.GetExtendedTextMessage().GetContextInfo().GetIsForwarded())
+	assert.Equal(t, uint32(1), msg.GetExtendedTextMessage().GetContextInfo().GetForwardingScore())
+
+	image := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		DirectPath: proto.String("/v/t62/abc"),
+		MediaKey:   []byte("key"),
+		ContextInfo: &waE2E.ContextInfo{
+			StanzaID:        proto.String("QUOTED"),
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(2),
+		},
+	}}
+	msg, err = BuildForwardedMessage(image)
+	assert.NoError(t, err)
+	assert.Equal(t, "/v/t62/abc", msg.GetImageMessage().GetDirectPath(), "the media aren't uploaded again")
+	assert.Equal(t, []byte("key"), msg.GetImageMessage().GetMediaKey())
+	assert.Empty(t, msg.GetImageMessage().GetContextInfo().GetStanzaID(), "the quote isn't forwarded")
+	assert.Equal(t, uint32(3), msg.GetImageMessage().GetContextInfo().GetForwardingScore())
+	assert.Equal(t, uint32(2), image.GetImageMessage().GetContextInfo().GetForwardingScore(), "the original message is left as is")
+
+	_, err = BuildForwardedMessage(&waE2E.Message{PollCreationMessage: &waE2E.PollCreationMessage{Name: proto.String("?")}})
+	assert.Error(t, err)
+}
+
+func TestSetQuotedContext(t *testing.T) {
+	quote := &waE2E.ContextInfo{
+		StanzaID:      proto.String("3EB0ABCDEF"),
+		Participant:   proto.String("628987654321@s.whatsapp.net"),
+		QuotedMessage: &waE2E.Message{Conversation: proto.String("Helo")},
+	}
+
+	text := &waE2E.Message{Conversation: proto.String("Hi")}
+	SetQuotedContext(text, quote)
+	assert.Nil(t, text.Conversation)
+	assert.Equal(t, "Hi", text.GetExtendedTextMessage().GetText(), "a plain text becomes an extended text")
+	assert.Equal(t, "3EB0ABCDEF", text.GetExtendedTextMessage().GetContextInfo().GetStanzaID())
+
+	image := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{ContextInfo: &waE2E.ContextInfo{
+		MentionedJID: []string{"628111111111@s.whatsapp.net"},
+	}}}
+	SetQuotedContext(image, quote)
+	contextInfo := image.GetImageMessage().GetContextInfo()
+	assert.Equal(t, "628987654321@s.whatsapp.net", contextInfo.GetParticipant())
+	assert.Equal(t, "Helo", contextInfo.GetQuotedMessage().GetConversation())
+	assert.Equal(t, []string{"628111111111@s.whatsapp.net"}, contextInfo.GetMentionedJID(), "the mentions are kept")
+
+	reaction := &waE2E.Message{ReactionMessage: &waE2E.ReactionMessage{Text: proto.String("👍")}}
+	SetQuotedContext(reaction, quote)
+	assert.Equal(t, "👍", reaction.GetReactionMessage().GetText())
+}
+
+func TestSetEphemeralContext(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+
+	text := &waE2E.Message{Conversation: proto.String("Hi")}
+	SetEphemeralContext(text, 7*24*time.Hour, now)
+	contextInfo := text.GetExtendedTextMessage().GetContextInfo()
+	assert.Equal(t, uint32(604800), contextInfo.GetExpiration())
+	assert.Equal(t, int64(1700000000), contextInfo.GetEphemeralSettingTimestamp())
+
+	untouched := &waE2E.Message{Conversation: proto.String("Hi")}
+	SetEphemeralContext(untouched, 0, now)
+	assert.Equal(t, "Hi", untouched.GetConversation(), "messages without expiration are left as is")
+}
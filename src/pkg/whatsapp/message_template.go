@@ -0,0 +1,163 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+var messageTemplateMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS message_templates (
+		name       TEXT    PRIMARY KEY,
+		content    TEXT    NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+}
+
+// MessageTemplate is a named message with {{name}} placeholders, rendered with the variables of each send
+type MessageTemplate struct {
+	Name      string
+	Content   string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TemplatePlaceholders returns the placeholder names of the content in their order, each once
+func TemplatePlaceholders(content string) []string {
+	placeholders := []string{}
+	seen := map[string]bool{}
+	for _, match := range broadcastVariable.FindAllStringSubmatch(content, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			placeholders = append(placeholders, match[1])
+		}
+	}
+	return placeholders
+}
+
+// MissingTemplateVariables returns the placeholders of the content without a variable
+func MissingTemplateVariables(content string, variables map[string]string) []string {
+	var missing []string
+	for _, placeholder := range TemplatePlaceholders(content) {
+		if _, ok := variables[placeholder]; !ok {
+			missing = append(missing, placeholder)
+		}
+	}
+	return missing
+}
+
+// RenderMessageTemplate replaces the placeholders of the content, every placeholder needs a variable
+func RenderMessageTemplate(content string, variables map[string]string) (string, error) {
+	if missing := MissingTemplateVariables(content, variables); len(missing) > 0 {
+		return "", pkgError.ValidationError(fmt.Sprintf("variables: %v are missing.", missing))
+	}
+	return RenderBroadcastMessage(content, variables), nil
+}
+
+// CreateMessageTemplate stores a new template, the name is unique
+func CreateMessageTemplate(template MessageTemplate) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	result, err := appStore.Exec(
+		`INSERT OR IGNORE INTO message_templates (name, content, created_at, updated_at) VALUES (?, ?, ?, ?)`,
+		template.Name, template.Content, template.CreatedAt.Unix(), template.UpdatedAt.Unix(),
+	)
+	if err != nil {
+		return err
+	}
+	if created, _ := result.RowsAffected(); created == 0 {
+		return pkgError.ValidationError(fmt.Sprintf("template %s already exists", template.Name))
+	}
+	return nil
+}
+
+// UpdateMessageTemplate replaces the content of a template, it keeps its creation time
+func UpdateMessageTemplate(template MessageTemplate) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	result, err := appStore.Exec(
+		`UPDATE message_templates SET content = ?, updated_at = ? WHERE name = ?`,
+		template.Content, template.UpdatedAt.Unix(), template.Name,
+	)
+	if err != nil {
+		return err
+	}
+	if updated, _ := result.RowsAffected(); updated == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("template %s not found", template.Name))
+	}
+	return nil
+}
+
+func GetMessageTemplate(name string) (MessageTemplate, error) {
+	if appStore == nil {
+		return MessageTemplate{}, errStoreNotInitialized
+	}
+
+	var (
+		template             MessageTemplate
+		createdAt, updatedAt int64
+	)
+	err := appStore.QueryRow(
+		`SELECT name, content, created_at, updated_at FROM message_templates WHERE name = ?`, name,
+	).Scan(&template.Name, &template.Content, &createdAt, &updatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return template, pkgError.NotFoundError(fmt.Sprintf("template %s not found", name))
+	}
+	if err != nil {
+		return template, err
+	}
+	template.CreatedAt = time.Unix(createdAt, 0)
+	template.UpdatedAt = time.Unix(updatedAt, 0)
+	return template, nil
+}
+
+// ListMessageTemplates returns every template by name
+func ListMessageTemplates() ([]MessageTemplate, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	rows, err := appStore.Query(`SELECT name, content, created_at, updated_at FROM message_templates ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	templates := []MessageTemplate{}
+	for rows.Next() {
+		var (
+			template             MessageTemplate
+			createdAt, updatedAt int64
+		)
+		if err = rows.Scan(&template.Name, &template.Content, &createdAt, &updatedAt); err != nil {
+			return nil, err
+		}
+		template.CreatedAt = time.Unix(createdAt, 0)
+		template.UpdatedAt = time.Unix(updatedAt, 0)
+		templates = append(templates, template)
+	}
+	return templates, rows.Err()
+}
+
+func DeleteMessageTemplate(name string) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	result, err := appStore.Exec(`DELETE FROM message_templates WHERE name = ?`, name)
+	if err != nil {
+		return err
+	}
+	if deleted, _ := result.RowsAffected(); deleted == 0 {
+		return pkgError.NotFoundError(fmt.Sprintf("template %s not found", name))
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplatePlaceholders(t *testing.T) {
+	assert.Equal(t, []string{"name", "order_id"}, TemplatePlaceholders("Hi {{name}}, order {{ order_id }} is shipped. Thanks {{name}}!"))
+	assert.Empty(t, TemplatePlaceholders("No placeholders {here}"))
+}
+
+func TestRenderMessageTemplate(t *testing.T) {
+	message, err := RenderMessageTemplate("Hi {{name}}, order {{ order_id }} is shipped", map[string]string{"name": "Budi", "order_id": "INV-001", "unused": "x"})
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi Budi, order INV-001 is shipped", message)
+
+	_, err = RenderMessageTemplate("Hi {{name}}, order {{order_id}} is shipped", map[string]string{"name": "Budi"})
+	assert.Equal(t, pkgError.ValidationError("variables: [order_id] are missing."), err)
+}
+
+func TestMissingTemplateVariables(t *testing.T) {
+	content := "Hi {{name}}, order {{order_id}} is shipped"
+	assert.Empty(t, MissingTemplateVariables(content, map[string]string{"name": "Budi", "order_id": "INV-001"}))
+	assert.Equal(t, []string{"order_id"}, MissingTemplateVariables(content, map[string]string{"name": "Budi"}))
+	assert.Equal(t, []string{"name", "order_id"}, MissingTemplateVariables(content, nil))
+}
+
+func TestMessageTemplateStore(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	now := time.Now().Truncate(time.Second)
+	shipped := MessageTemplate{Name: "order_shipped", Content: "Order {{order_id}} is shipped", CreatedAt: now, UpdatedAt: now}
+	assert.NoError(t, CreateMessageTemplate(shipped))
+	assert.NoError(t, CreateMessageTemplate(MessageTemplate{Name: "greeting", Content: "Hi {{name}}", CreatedAt: now, UpdatedAt: now}))
+	assert.Equal(t, pkgError.ValidationError("template order_shipped already exists"), CreateMessageTemplate(shipped))
+
+	later := now.Add(time.Hour)
+	assert.NoError(t, UpdateMessageTemplate(MessageTemplate{Name: "order_shipped", Content: "Hi {{name}}, order {{order_id}} is on its way", UpdatedAt: later}))
+	assert.Equal(t, pkgError.NotFoundError("template missing not found"), UpdateMessageTemplate(MessageTemplate{Name: "missing", Content: "x", UpdatedAt: later}))
+
+	template, err := GetMessageTemplate("order_shipped")
+	assert.NoError(t, err)
+	assert.Equal(t, "Hi {{name}}, order {{order_id}} is on its way", template.Content)
+	assert.True(t, now.Equal(template.CreatedAt))
+	assert.True(t, later.Equal(template.UpdatedAt))
+
+	templates, err := ListMessageTemplates()
+	assert.NoError(t, err)
+	assert.Len(t, templates, 2)
+	assert.Equal(t, "greeting", templates[0].Name)
+	assert.Equal(t, "order_shipped", templates[1].Name)
+
+	assert.NoError(t, DeleteMessageTemplate("greeting"))
+	assert.Equal(t, pkgError.NotFoundError("template greeting not found"), DeleteMessageTemplate("greeting"))
+	_, err = GetMessageTemplate("greeting")
+	assert.Equal(t, pkgError.NotFoundError("template greeting not found"), err)
+}
@@ -0,0 +1,41 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+// IsNewsletterAdmin tells whether the account can post to the newsletter, only its owner and admins can
+func IsNewsletterAdmin(metadata *types.NewsletterMetadata) bool {
+	if metadata == nil || metadata.ViewerMeta == nil {
+		return false
+	}
+	return metadata.ViewerMeta.Role == types.NewsletterRoleOwner || metadata.ViewerMeta.Role == types.NewsletterRoleAdmin
+}
+
+// NewsletterMediaMessage returns the image or video post of media uploaded with UploadNewsletter. Newsletter media
+// isn't encrypted, the message has no media key and is sent with the media handle of the upload.
+func NewsletterMediaMessage(mediaType string, uploaded whatsmeow.UploadResponse, mimeType, caption string, thumbnail []byte) *waE2E.Message {
+	if mediaType == "video" {
+		return &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
+			URL:           proto.String(uploaded.URL),
+			DirectPath:    proto.String(uploaded.DirectPath),
+			FileSHA256:    uploaded.FileSHA256,
+			FileLength:    proto.Uint64(uploaded.FileLength),
+			Mimetype:      proto.String(mimeType),
+			Caption:       proto.String(caption),
+			JPEGThumbnail: thumbnail,
+		}}
+	}
+	return &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		URL:           proto.String(uploaded.URL),
+		DirectPath:    proto.String(uploaded.DirectPath),
+		FileSHA256:    uploaded.FileSHA256,
+		FileLength:    proto.Uint64(uploaded.FileLength),
+		Mimetype:      proto.String(mimeType),
+		Caption:       proto.String(caption),
+		JPEGThumbnail: thumbnail,
+	}}
+}
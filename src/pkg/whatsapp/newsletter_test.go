@@ -0,0 +1,40 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestIsNewsletterAdmin(t *testing.T) {
+	assert.True(t, IsNewsletterAdmin(&types.NewsletterMetadata{ViewerMeta: &types.NewsletterViewerMetadata{Role: types.NewsletterRoleOwner}}))
+	assert.True(t, IsNewsletterAdmin(&types.NewsletterMetadata{ViewerMeta: &types.NewsletterViewerMetadata{Role: types.NewsletterRoleAdmin}}))
+	assert.False(t, IsNewsletterAdmin(&types.NewsletterMetadata{ViewerMeta: &types.NewsletterViewerMetadata{Role: types.NewsletterRoleSubscriber}}))
+	assert.False(t, IsNewsletterAdmin(&types.NewsletterMetadata{}))
+}
+
+func TestNewsletterMediaMessage(t *testing.T) {
+	uploaded := whatsmeow.UploadResponse{
+		URL:        "https://mmg.whatsapp.net/newsletter/image",
+		DirectPath: "/newsletter/image",
+		Handle:     "handle",
+		FileSHA256: []byte{1, 2, 3},
+		FileLength: 2048,
+	}
+
+	msg := NewsletterMediaMessage("image", uploaded, "image/jpeg", "New arrivals", []byte{0xff})
+	image := msg.GetImageMessage()
+	assert.NotNil(t, image)
+	assert.Equal(t, "/newsletter/image", image.GetDirectPath())
+	assert.Equal(t, uint64(2048), image.GetFileLength())
+	assert.Equal(t, "New arrivals", image.GetCaption())
+	assert.Nil(t, image.MediaKey)
+	assert.Nil(t, image.FileEncSHA256)
+
+	video := NewsletterMediaMessage("video", uploaded, "video/mp4", "", nil).GetVideoMessage()
+	assert.NotNil(t, video)
+	assert.Equal(t, "video/mp4", video.GetMimetype())
+	assert.Nil(t, video.MediaKey)
+}
@@ -0,0 +1,150 @@
+package whatsapp
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+// Order is an order composed by the seller, the items are listed in the text of the message since only orders
+// placed from a catalog have items WhatsApp can fetch
+type Order struct {
+	ID        string
+	Title     string
+	Note      string
+	Seller    string
+	Currency  string
+	Items     []OrderItem
+	Tax       float64
+	Shipping  float64
+	Discount  float64
+	Thumbnail []byte
+}
+
+type OrderItem struct {
+	Name     string
+	Price    float64
+	Quantity int
+}
+
+// zeroDecimalCurrencies are the currencies whose amounts are shown without minor units
+var zeroDecimalCurrencies = map[string]bool{
+	"BIF": true, "CLP": true, "DJF": true, "GNF": true, "IDR": true, "ISK": true, "JPY": true, "KMF": true,
+	"KRW": true, "PYG": true, "RWF": true, "UGX": true, "VND": true, "VUV": true, "XAF": true, "XOF": true,
+	"XPF": true,
+}
+
+// threeDecimalCurrencies are the currencies whose minor unit is a thousandth
+var threeDecimalCurrencies = map[string]bool{
+	"BHD": true, "IQD": true, "JOD": true, "KWD": true, "LYD": true, "OMR": true, "TND": true,
+}
+
+func (item OrderItem) amount1000() int64 {
+	return amount1000(item.Price) * int64(item.Quantity)
+}
+
+// Subtotal1000 is the sum of the items in thousandths, before tax, shipping and discount
+func (order Order) Subtotal1000() int64 {
+	var subtotal int64
+	for _, item := range order.Items {
+		subtotal += item.amount1000()
+	}
+	return subtotal
+}
+
+func (order Order) Total1000() int64 {
+	return order.Subtotal1000() + amount1000(order.Tax) + amount1000(order.Shipping) - amount1000(order.Discount)
+}
+
+// NewOrderMessage builds the order message with its invoice, WhatsApp amounts are in thousandths of the currency unit
+func NewOrderMessage(order Order) *waE2E.OrderMessage {
+	var itemCount int32
+	for _, item := range order.Items {
+		itemCount += int32(item.Quantity)
+	}
+
+	msg := &waE2E.OrderMessage{
+		OrderID:           proto.String(order.ID),
+		ItemCount:         proto.Int32(itemCount),
+		Status:            waE2E.OrderMessage_INQUIRY.Enum(),
+		Surface:           waE2E.OrderMessage_CATALOG.Enum(),
+		Message:           proto.String(OrderInvoice(order)),
+		OrderTitle:        proto.String(order.Title),
+		SellerJID:         proto.String(order.Seller),
+		TotalAmount1000:   proto.Int64(order.Total1000()),
+		TotalCurrencyCode: proto.String(order.Currency),
+		MessageVersion:    proto.Int32(1),
+	}
+	if len(order.Thumbnail) > 0 {
+		msg.Thumbnail = order.Thumbnail
+	}
+	return msg
+}
+
+// OrderInvoice renders the items and totals of the order, followed by the note of the seller
+func OrderInvoice(order Order) string {
+	var invoice strings.Builder
+	for _, item := range order.Items {
+		fmt.Fprintf(&invoice, "%d x %s  %s\n", item.Quantity, item.Name, formatAmount(order.Currency, item.amount1000()))
+	}
+	if order.Tax != 0 || order.Shipping != 0 || order.Discount != 0 {
+		fmt.Fprintf(&invoice, "Subtotal  %s\n", formatAmount(order.Currency, order.Subtotal1000()))
+	}
+	if order.Tax != 0 {
+		fmt.Fprintf(&invoice, "Tax  %s\n", formatAmount(order.Currency, amount1000(order.Tax)))
+	}
+	if order.Shipping != 0 {
+		fmt.Fprintf(&invoice, "Shipping  %s\n", formatAmount(order.Currency, amount1000(order.Shipping)))
+	}
+	if order.Discount != 0 {
+		fmt.Fprintf(&invoice, "Discount  -%s\n", formatAmount(order.Currency, amount1000(order.Discount)))
+	}
+	fmt.Fprintf(&invoice, "*Total  %s*", formatAmount(order.Currency, order.Total1000()))
+	if order.Note != "" {
+		invoice.WriteString("\n\n" + order.Note)
+	}
+	return invoice.String()
+}
+
+// amount1000 converts an amount of the request to thousandths of the currency unit, so the totals are summed
+// without float drift
+func amount1000(amount float64) int64 {
+	return int64(math.Round(amount * 1000))
+}
+
+func currencyDecimals(currency string) int {
+	currency = strings.ToUpper(currency)
+	switch {
+	case zeroDecimalCurrencies[currency]:
+		return 0
+	case threeDecimalCurrencies[currency]:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// formatAmount renders an amount in thousandths with the minor units of the currency, rounding half away from zero
+func formatAmount(currency string, amount1000 int64) string {
+	sign := ""
+	if amount1000 < 0 {
+		sign = "-"
+		amount1000 = -amount1000
+	}
+
+	decimals := currencyDecimals(currency)
+	scale := int64(1)
+	for i := decimals; i < 3; i++ {
+		scale *= 10
+	}
+	minor := (amount1000 + scale/2) / scale
+	if decimals == 0 {
+		return fmt.Sprintf("%s %s%d", currency, sign, minor)
+	}
+
+	unit := 1000 / scale
+	return fmt.Sprintf("%s %s%d.%0*d", currency, sign, minor/unit, decimals, minor%unit)
+}
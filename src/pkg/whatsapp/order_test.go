@@ -0,0 +1,76 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+func TestNewOrderMessage(t *testing.T) {
+	order := Order{
+		ID:       "INV-001",
+		Title:    "Batik order",
+		Note:     "Thank you for shopping with us",
+		Seller:   "6289685028129@s.whatsapp.net",
+		Currency: "IDR",
+		Items: []OrderItem{
+			{Name: "Batik shirt", Price: 150000, Quantity: 2},
+			{Name: "Sarong", Price: 75000, Quantity: 1},
+		},
+		Shipping: 20000,
+		Discount: 25000,
+	}
+
+	msg := NewOrderMessage(order)
+	assert.Equal(t, "INV-001", msg.GetOrderID())
+	assert.Equal(t, "Batik order", msg.GetOrderTitle())
+	assert.Equal(t, int32(3), msg.GetItemCount())
+	assert.Equal(t, int64(370000000), msg.GetTotalAmount1000())
+	assert.Equal(t, "IDR", msg.GetTotalCurrencyCode())
+	assert.Equal(t, waE2E.OrderMessage_INQUIRY, msg.GetStatus())
+	assert.Equal(t, waE2E.OrderMessage_CATALOG, msg.GetSurface())
+	assert.Nil(t, msg.Thumbnail)
+	assert.Equal(t, "2 x Batik shirt  IDR 300000\n"+
+		"1 x Sarong  IDR 75000\n"+
+		"Subtotal  IDR 375000\n"+
+		"Shipping  IDR 20000\n"+
+		"Discount  -IDR 25000\n"+
+		"*Total  IDR 370000*\n\n"+
+		"Thank you for shopping with us", msg.GetMessage())
+}
+
+func TestOrderInvoiceWithoutAdjustments(t *testing.T) {
+	order := Order{Currency: "USD", Items: []OrderItem{{Name: "Mug", Price: 12.5, Quantity: 2}}}
+	assert.Equal(t, "2 x Mug  USD 25.00\n*Total  USD 25.00*", OrderInvoice(order))
+}
+
+func TestOrderTotalInThousandths(t *testing.T) {
+	order := Order{Currency: "USD", Items: []OrderItem{{Name: "Pen", Price: 0.1, Quantity: 3}}, Tax: 0.2}
+	assert.Equal(t, int64(300), order.Subtotal1000())
+	assert.Equal(t, int64(500), order.Total1000())
+	assert.Equal(t, "3 x Pen  USD 0.30\nSubtotal  USD 0.30\nTax  USD 0.20\n*Total  USD 0.50*", OrderInvoice(order))
+}
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		currency   string
+		amount1000 int64
+		want       string
+	}{
+		{"USD", 12345, "USD 12.35"},
+		{"USD", 12344, "USD 12.34"},
+		{"EUR", 5, "EUR 0.01"},
+		{"JPY", 1500500, "JPY 1501"},
+		{"IDR", 75000499, "IDR 75000"},
+		{"idr", 1000, "idr 1"},
+		{"KWD", 1234, "KWD 1.234"},
+		{"USD", -2500, "USD -2.50"},
+		{"USD", 0, "USD 0.00"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatAmount(tt.currency, tt.amount1000))
+		})
+	}
+}
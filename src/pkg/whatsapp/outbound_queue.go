@@ -0,0 +1,408 @@
+package whatsapp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// outboundIdleChats is how many chat buckets are kept before the idle ones are dropped
+const outboundIdleChats = 1024
+
+var (
+	errOutboundFlushed  = pkgError.TooManyRequestsError("the outbound queue was flushed, send the message again")
+	errOutboundDraining = pkgError.TooManyRequestsError("the outbound queue is draining, send the message again later")
+)
+
+// tokenBucket refills rate tokens per second up to burst, a send takes one token. The tokens go negative when
+// sends are queued ahead, so every waiting send has its own slot.
+type tokenBucket struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(perMinute float64, burst int) *tokenBucket {
+	if perMinute <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: perMinute / 60, burst: float64(burst), tokens: float64(burst)}
+}
+
+// delay is how long until the next token, a nil bucket is unlimited
+func (bucket *tokenBucket) delay(now time.Time) time.Duration {
+	return bucket.delayAfter(now, 0)
+}
+
+// delayAfter is how long until there is a token left once ahead sends took theirs
+func (bucket *tokenBucket) delayAfter(now time.Time, ahead int) time.Duration {
+	if bucket == nil {
+		return 0
+	}
+	if !bucket.last.IsZero() {
+		bucket.tokens = min(bucket.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*bucket.rate)
+	}
+	bucket.last = now
+	needed := float64(ahead + 1)
+	if bucket.tokens >= needed {
+		return 0
+	}
+	return time.Duration((needed - bucket.tokens) / bucket.rate * float64(time.Second))
+}
+
+func (bucket *tokenBucket) take() {
+	if bucket != nil {
+		bucket.tokens--
+	}
+}
+
+func (bucket *tokenBucket) idle() bool {
+	return bucket == nil || bucket.tokens >= bucket.burst
+}
+
+// OutboundPriority orders the waiting sends, the higher lanes are sent first within the rate limits
+type OutboundPriority int
+
+const (
+	OutboundPriorityHigh OutboundPriority = iota
+	OutboundPriorityNormal
+	OutboundPriorityLow
+)
+
+var outboundPriorityNames = [...]string{"high", "normal", "low"}
+
+func (priority OutboundPriority) String() string {
+	return outboundPriorityNames[priority]
+}
+
+// ParseOutboundPriority returns the priority named high, normal or low, normal when empty
+func ParseOutboundPriority(name string) (OutboundPriority, error) {
+	if name == "" {
+		return OutboundPriorityNormal, nil
+	}
+	for priority, priorityName := range outboundPriorityNames {
+		if name == priorityName {
+			return OutboundPriority(priority), nil
+		}
+	}
+	return OutboundPriorityNormal, pkgError.ValidationError(fmt.Sprintf("priority: must be high, normal or low, not %s", name))
+}
+
+type outboundPriorityKey struct{}
+
+// WithOutboundPriority sets the priority of the sends made with the context
+func WithOutboundPriority(ctx context.Context, priority OutboundPriority) context.Context {
+	return context.WithValue(ctx, outboundPriorityKey{}, priority)
+}
+
+func outboundPriority(ctx context.Context) OutboundPriority {
+	if priority, ok := ctx.Value(outboundPriorityKey{}).(OutboundPriority); ok {
+		return priority
+	}
+	return OutboundPriorityNormal
+}
+
+// OutboundStats are the metrics of the outbound queue
+type OutboundStats struct {
+	Queued   int            // sends waiting for their turn
+	Chats    map[string]int // sends waiting per chat
+	Lanes    map[string]int // sends waiting per priority
+	Sent     int64
+	Rejected int64 // sends refused because the wait exceeded the max wait, or the queue was draining
+	Flushed  int64
+	Draining bool
+}
+
+// outboundWaiter is a send waiting in its lane, ready gets nil once it may be sent, or the reason it won't be
+type outboundWaiter struct {
+	chat  string
+	ready chan error
+}
+
+// outboundQueue paces every send with a global and a per chat token bucket. The sends that can't go right away
+// wait in a lane per priority, and a dispatcher hands out the tokens to the highest lane first.
+type outboundQueue struct {
+	mu          sync.Mutex
+	global      *tokenBucket
+	chats       map[string]*tokenBucket
+	queued      map[string]int
+	lanes       [len(outboundPriorityNames)][]*outboundWaiter
+	depth       int
+	sent        int64
+	rejected    int64
+	flushed     int64
+	wake        chan struct{} // wakes the dispatcher up when a send joins a lane
+	dispatching bool
+	idle        chan struct{} // closed when the last waiting send leaves the queue
+	draining    bool
+}
+
+func newOutboundQueue() *outboundQueue {
+	return &outboundQueue{
+		global: newTokenBucket(config.WhatsappOutboundRate, config.WhatsappOutboundBurst),
+		chats:  map[string]*tokenBucket{},
+		queued: map[string]int{},
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+var (
+	outboundOnce sync.Once
+	outbound     *outboundQueue
+)
+
+func getOutboundQueue() *outboundQueue {
+	outboundOnce.Do(func() { outbound = newOutboundQueue() })
+	return outbound
+}
+
+// SendOutbound sends the message once the rate limits allow it, every send to WhatsApp goes through it.
+// The priority is the one set on the context with WithOutboundPriority, normal otherwise.
+func SendOutbound(ctx context.Context, waCli *whatsmeow.Client, to types.JID, msg *waE2E.Message, extra ...whatsmeow.SendRequestExtra) (whatsmeow.SendResponse, error) {
+	if err := getOutboundQueue().wait(ctx, to.ToNonAD().String(), outboundPriority(ctx), time.Now()); err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+	return waCli.SendMessage(ctx, to, msg, extra...)
+}
+
+// wait blocks the send until the dispatcher hands it a token of both buckets
+func (queue *outboundQueue) wait(ctx context.Context, chat string, priority OutboundPriority, now time.Time) error {
+	queue.mu.Lock()
+	if queue.draining {
+		queue.rejected++
+		queue.mu.Unlock()
+		return errOutboundDraining
+	}
+
+	bucket, ok := queue.chats[chat]
+	if !ok {
+		queue.pruneChats()
+		bucket = newTokenBucket(config.WhatsappOutboundChatRate, config.WhatsappOutboundChatBurst)
+		queue.chats[chat] = bucket
+	}
+	ahead := 0
+	for lane := OutboundPriorityHigh; lane <= priority; lane++ {
+		ahead += len(queue.lanes[lane])
+	}
+	delay := max(queue.global.delayAfter(now, ahead), bucket.delayAfter(now, queue.queued[chat]))
+	if delay <= 0 {
+		queue.global.take()
+		bucket.take()
+		queue.sent++
+		queue.mu.Unlock()
+		return nil
+	}
+	if config.WhatsappOutboundMaxWait > 0 && delay > config.WhatsappOutboundMaxWait {
+		queue.rejected++
+		queue.mu.Unlock()
+		return pkgError.TooManyRequestsError(fmt.Sprintf("the outbound queue is full, the message would wait %s", delay.Round(time.Second)))
+	}
+
+	waiter := &outboundWaiter{chat: chat, ready: make(chan error, 1)}
+	queue.lanes[priority] = append(queue.lanes[priority], waiter)
+	queue.depth++
+	queue.queued[chat]++
+	if !queue.dispatching {
+		queue.dispatching = true
+		go queue.dispatch()
+	}
+	select {
+	case queue.wake <- struct{}{}:
+	default:
+	}
+	queue.mu.Unlock()
+
+	var err error
+	answered := false
+	select {
+	case err = <-waiter.ready:
+		answered = true
+	case <-ctx.Done():
+		err = pkgError.ContextError(ctx.Err().Error())
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if !answered && !queue.removeWaiter(priority, waiter) {
+		// the dispatcher or a flush answered while the context was done, the send is given up anyway
+		<-waiter.ready
+	}
+	queue.depth--
+	if queue.queued[chat]--; queue.queued[chat] == 0 {
+		delete(queue.queued, chat)
+	}
+	switch {
+	case err == nil:
+		queue.sent++
+	case err == errOutboundFlushed:
+		queue.flushed++
+	}
+	if queue.depth == 0 && queue.idle != nil {
+		close(queue.idle)
+		queue.idle = nil
+	}
+	return err
+}
+
+// removeWaiter takes the waiter out of its lane, false when it already left it
+func (queue *outboundQueue) removeWaiter(priority OutboundPriority, waiter *outboundWaiter) bool {
+	lane := queue.lanes[priority]
+	for i, queued := range lane {
+		if queued == waiter {
+			queue.lanes[priority] = append(lane[:i], lane[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// dispatch hands out the tokens to the waiting sends until the lanes are empty
+func (queue *outboundQueue) dispatch() {
+	for {
+		queue.mu.Lock()
+		priority, index, delay := queue.next(time.Now())
+		if index >= 0 {
+			lane := queue.lanes[priority]
+			waiter := lane[index]
+			queue.lanes[priority] = append(lane[:index], lane[index+1:]...)
+			queue.global.take()
+			queue.chats[waiter.chat].take()
+			waiter.ready <- nil
+			queue.mu.Unlock()
+			continue
+		}
+		if delay < 0 {
+			queue.dispatching = false
+			queue.mu.Unlock()
+			return
+		}
+		queue.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-queue.wake:
+		}
+		timer.Stop()
+	}
+}
+
+// next finds the first send of the highest lane whose chat has a token, or how long until one may have it.
+// The delay is negative when no send is waiting.
+func (queue *outboundQueue) next(now time.Time) (OutboundPriority, int, time.Duration) {
+	globalDelay := queue.global.delay(now)
+	delay := time.Duration(-1)
+	for priority, lane := range queue.lanes {
+		for index, waiter := range lane {
+			wait := max(globalDelay, queue.chats[waiter.chat].delay(now))
+			if wait <= 0 {
+				return OutboundPriority(priority), index, 0
+			}
+			if delay < 0 || wait < delay {
+				delay = wait
+			}
+		}
+	}
+	return 0, -1, delay
+}
+
+// pruneChats drops the buckets of the chats without recent sends, they would start full again anyway
+func (queue *outboundQueue) pruneChats() {
+	if len(queue.chats) < outboundIdleChats {
+		return
+	}
+	now := time.Now()
+	for chat, bucket := range queue.chats {
+		bucket.delay(now)
+		if bucket.idle() && queue.queued[chat] == 0 {
+			delete(queue.chats, chat)
+		}
+	}
+}
+
+func (queue *outboundQueue) stats() OutboundStats {
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	chats := make(map[string]int, len(queue.queued))
+	for chat, queued := range queue.queued {
+		chats[chat] = queued
+	}
+	lanes := make(map[string]int, len(queue.lanes))
+	for priority, lane := range queue.lanes {
+		lanes[outboundPriorityNames[priority]] = len(lane)
+	}
+	return OutboundStats{
+		Queued:   queue.depth,
+		Chats:    chats,
+		Lanes:    lanes,
+		Sent:     queue.sent,
+		Rejected: queue.rejected,
+		Flushed:  queue.flushed,
+		Draining: queue.draining,
+	}
+}
+
+// GetOutboundStats returns the depth and counters of the outbound queue
+func GetOutboundStats() OutboundStats {
+	return getOutboundQueue().stats()
+}
+
+// FlushOutbound cancels the waiting sends, they fail with a 429 and the buckets keep their state.
+// It returns how many sends were cancelled.
+func FlushOutbound() int {
+	queue := getOutboundQueue()
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+
+	flushed := 0
+	for priority, lane := range queue.lanes {
+		for _, waiter := range lane {
+			waiter.ready <- errOutboundFlushed
+		}
+		flushed += len(lane)
+		queue.lanes[priority] = nil
+	}
+	return flushed
+}
+
+// DrainOutbound refuses the new sends until the waiting ones are sent, or the context is done.
+// It returns how many sends were waiting when the drain started.
+func DrainOutbound(ctx context.Context) (int, error) {
+	queue := getOutboundQueue()
+	queue.mu.Lock()
+	waiting := queue.depth
+	if waiting == 0 {
+		queue.mu.Unlock()
+		return 0, nil
+	}
+	queue.draining = true
+	if queue.idle == nil {
+		queue.idle = make(chan struct{})
+	}
+	idle := queue.idle
+	queue.mu.Unlock()
+
+	var err error
+	select {
+	case <-idle:
+	case <-ctx.Done():
+		err = pkgError.ContextError(fmt.Sprintf("the outbound queue didn't drain: %v", ctx.Err()))
+	}
+
+	queue.mu.Lock()
+	queue.draining = false
+	queue.mu.Unlock()
+	return waiting, err
+}
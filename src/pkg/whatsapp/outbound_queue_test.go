@@ -0,0 +1,190 @@
+package whatsapp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenBucket(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(60, 2) // one token per second
+
+	assert.Zero(t, bucket.delay(now))
+	bucket.take()
+	assert.Zero(t, bucket.delay(now))
+	bucket.take()
+	assert.Equal(t, time.Second, bucket.delay(now))
+	bucket.take()
+	assert.Equal(t, 2*time.Second, bucket.delay(now))
+
+	assert.Equal(t, time.Second, bucket.delay(now.Add(time.Second)))
+	assert.Zero(t, bucket.delay(now.Add(10*time.Second)))
+	assert.True(t, bucket.idle())
+
+	var unlimited *tokenBucket
+	assert.Nil(t, newTokenBucket(0, 5))
+	assert.Zero(t, unlimited.delay(now))
+	unlimited.take()
+}
+
+func withOutboundConfig(t *testing.T, rate, chatRate float64, maxWait time.Duration) *outboundQueue {
+	originalRate, originalBurst := config.WhatsappOutboundRate, config.WhatsappOutboundBurst
+	originalChatRate, originalChatBurst := config.WhatsappOutboundChatRate, config.WhatsappOutboundChatBurst
+	originalMaxWait := config.WhatsappOutboundMaxWait
+	t.Cleanup(func() {
+		config.WhatsappOutboundRate, config.WhatsappOutboundBurst = originalRate, originalBurst
+		config.WhatsappOutboundChatRate, config.WhatsappOutboundChatBurst = originalChatRate, originalChatBurst
+		config.WhatsappOutboundMaxWait = originalMaxWait
+	})
+
+	config.WhatsappOutboundRate, config.WhatsappOutboundBurst = rate, 1
+	config.WhatsappOutboundChatRate, config.WhatsappOutboundChatBurst = chatRate, 1
+	config.WhatsappOutboundMaxWait = maxWait
+	return newOutboundQueue()
+}
+
+func TestOutboundQueueChatRate(t *testing.T) {
+	queue := withOutboundConfig(t, 0, 60, time.Minute)
+	ctx := context.Background()
+
+	start := time.Now()
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+	assert.NoError(t, queue.wait(ctx, "b", OutboundPriorityNormal, time.Now()), "other chats have their own bucket")
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+
+	done := make(chan error)
+	go func() { done <- queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()) }()
+	assert.Eventually(t, func() bool { return queue.stats().Queued == 1 }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, map[string]int{"a": 1}, queue.stats().Chats)
+
+	assert.NoError(t, <-done)
+	assert.GreaterOrEqual(t, time.Since(start), 900*time.Millisecond)
+
+	stats := queue.stats()
+	assert.Zero(t, stats.Queued)
+	assert.Empty(t, stats.Chats)
+	assert.Equal(t, int64(3), stats.Sent)
+}
+
+func TestOutboundQueueMaxWait(t *testing.T) {
+	queue := withOutboundConfig(t, 1, 0, time.Second)
+	ctx := context.Background()
+
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+	err := queue.wait(ctx, "b", OutboundPriorityNormal, time.Now())
+	assert.Equal(t, pkgError.TooManyRequestsError("the outbound queue is full, the message would wait 1m0s"), err)
+	assert.Equal(t, int64(1), queue.stats().Rejected)
+}
+
+func TestOutboundQueuePriority(t *testing.T) {
+	queue := withOutboundConfig(t, 120, 0, time.Minute)
+	ctx := context.Background()
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+
+	order := make(chan string, 3)
+	send := func(chat string, priority OutboundPriority) {
+		assert.NoError(t, queue.wait(WithOutboundPriority(ctx, priority), chat, priority, time.Now()))
+		order <- chat
+	}
+	go send("bulk", OutboundPriorityLow)
+	assert.Eventually(t, func() bool { return queue.stats().Lanes["low"] == 1 }, time.Second, 10*time.Millisecond)
+	go send("campaign", OutboundPriorityNormal)
+	assert.Eventually(t, func() bool { return queue.stats().Lanes["normal"] == 1 }, time.Second, 10*time.Millisecond)
+	go send("otp", OutboundPriorityHigh)
+	assert.Eventually(t, func() bool { return queue.stats().Lanes["high"] == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, "otp", <-order, "the high lane is sent first")
+	assert.Equal(t, "campaign", <-order)
+	assert.Equal(t, "bulk", <-order)
+	assert.Equal(t, map[string]int{"high": 0, "normal": 0, "low": 0}, queue.stats().Lanes)
+}
+
+func TestOutboundQueuePriorityMaxWait(t *testing.T) {
+	queue := withOutboundConfig(t, 1, 0, 90*time.Second)
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+
+	done := make(chan error)
+	go func() { done <- queue.wait(ctx, "b", OutboundPriorityLow, time.Now()) }()
+	assert.Eventually(t, func() bool { return queue.stats().Queued == 1 }, time.Second, 10*time.Millisecond)
+
+	go func() { done <- queue.wait(ctx, "c", OutboundPriorityHigh, time.Now()) }()
+	assert.Eventually(t, func() bool { return queue.stats().Queued == 2 }, time.Second, 10*time.Millisecond)
+	assert.IsType(t, pkgError.TooManyRequestsError(""), queue.wait(ctx, "d", OutboundPriorityLow, time.Now()),
+		"the low send waits behind the other two")
+
+	cancel()
+	assert.Error(t, <-done)
+	assert.Error(t, <-done)
+	assert.Zero(t, queue.stats().Queued)
+}
+
+func TestParseOutboundPriority(t *testing.T) {
+	priority, err := ParseOutboundPriority("")
+	assert.NoError(t, err)
+	assert.Equal(t, OutboundPriorityNormal, priority)
+
+	priority, err = ParseOutboundPriority("high")
+	assert.NoError(t, err)
+	assert.Equal(t, OutboundPriorityHigh, priority)
+	assert.Equal(t, "high", priority.String())
+
+	_, err = ParseOutboundPriority("urgent")
+	assert.Equal(t, pkgError.ValidationError("priority: must be high, normal or low, not urgent"), err)
+	assert.Equal(t, OutboundPriorityNormal, outboundPriority(context.Background()))
+}
+
+func TestOutboundQueueFlush(t *testing.T) {
+	queue := withOutboundConfig(t, 1, 0, time.Hour)
+	outboundOnce.Do(func() {})
+	original := outbound
+	outbound = queue
+	defer func() { outbound = original }()
+
+	ctx := context.Background()
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+
+	done := make(chan error)
+	go func() { done <- queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()) }()
+	assert.Eventually(t, func() bool { return GetOutboundStats().Queued == 1 }, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1, FlushOutbound())
+	assert.Equal(t, errOutboundFlushed, <-done)
+	assert.Equal(t, int64(1), GetOutboundStats().Flushed)
+}
+
+func TestOutboundQueueDrain(t *testing.T) {
+	queue := withOutboundConfig(t, 0, 120, time.Minute)
+	outboundOnce.Do(func() {})
+	original := outbound
+	outbound = queue
+	defer func() { outbound = original }()
+
+	ctx := context.Background()
+	waiting, err := DrainOutbound(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, waiting)
+
+	assert.NoError(t, queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()))
+	done := make(chan error)
+	go func() { done <- queue.wait(ctx, "a", OutboundPriorityNormal, time.Now()) }()
+	assert.Eventually(t, func() bool { return GetOutboundStats().Queued == 1 }, time.Second, 10*time.Millisecond)
+
+	drained := make(chan int)
+	go func() {
+		waiting, err := DrainOutbound(ctx)
+		assert.NoError(t, err)
+		drained <- waiting
+	}()
+	assert.Eventually(t, func() bool { return GetOutboundStats().Draining }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, errOutboundDraining, queue.wait(ctx, "b", OutboundPriorityNormal, time.Now()))
+
+	assert.NoError(t, <-done)
+	assert.Equal(t, 1, <-drained)
+	assert.False(t, GetOutboundStats().Draining)
+}
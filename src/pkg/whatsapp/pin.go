@@ -0,0 +1,64 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// PinDurations are the pin durations the clients offer, the message is unpinned for everyone once it's over
+var PinDurations = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// NewPinMessage pins the message for everyone in the chat during the duration, or unpins it
+func NewPinMessage(key *waCommon.MessageKey, pin bool, duration time.Duration, now time.Time) *waE2E.Message {
+	pinType := waE2E.PinInChatMessage_UNPIN_FOR_ALL
+	if pin {
+		pinType = waE2E.PinInChatMessage_PIN_FOR_ALL
+	}
+	msg := &waE2E.Message{PinInChatMessage: &waE2E.PinInChatMessage{
+		Key:               key,
+		Type:              pinType.Enum(),
+		SenderTimestampMS: proto.Int64(now.UnixMilli()),
+	}}
+	if pin {
+		msg.MessageContextInfo = &waE2E.MessageContextInfo{MessageAddOnDurationInSecs: proto.Uint32(uint32(duration.Seconds()))}
+	}
+	return msg
+}
+
+// createPinPayload builds the payload of a message pinned or unpinned in a chat, nil when the event isn't a pin
+func createPinPayload(evt *events.Message) map[string]interface{} {
+	pin := evt.Message.GetPinInChatMessage()
+	if pin == nil {
+		return nil
+	}
+
+	body := make(map[string]interface{})
+	body["event_type"] = "pin"
+	body["chat"] = evt.Info.Chat.String()
+	body["message_id"] = pin.GetKey().GetID()
+	body["by"] = evt.Info.Sender.ToNonAD().String()
+	body["from_me"] = evt.Info.IsFromMe
+	body["timestamp"] = evt.Info.Timestamp.Format(time.RFC3339)
+	if participant := pin.GetKey().GetParticipant(); participant != "" {
+		body["message_sender"] = participant
+	}
+
+	if pin.GetType() != waE2E.PinInChatMessage_PIN_FOR_ALL {
+		body["action"] = "unpin"
+		return body
+	}
+	body["action"] = "pin"
+	if seconds := evt.Message.GetMessageContextInfo().GetMessageAddOnDurationInSecs(); seconds > 0 {
+		body["duration"] = seconds
+		body["expires_at"] = evt.Info.Timestamp.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339)
+	}
+	return body
+}
@@ -0,0 +1,63 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestNewPinMessage(t *testing.T) {
+	key := &waCommon.MessageKey{ID: proto.String("ORIGINAL1"), FromMe: proto.Bool(true)}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	msg := NewPinMessage(key, true, PinDurations["7d"], now)
+	assert.Equal(t, waE2E.PinInChatMessage_PIN_FOR_ALL, msg.GetPinInChatMessage().GetType())
+	assert.Equal(t, "ORIGINAL1", msg.GetPinInChatMessage().GetKey().GetID())
+	assert.Equal(t, now.UnixMilli(), msg.GetPinInChatMessage().GetSenderTimestampMS())
+	assert.Equal(t, uint32(604800), msg.GetMessageContextInfo().GetMessageAddOnDurationInSecs())
+
+	msg = NewPinMessage(key, false, 0, now)
+	assert.Equal(t, waE2E.PinInChatMessage_UNPIN_FOR_ALL, msg.GetPinInChatMessage().GetType())
+	assert.Nil(t, msg.MessageContextInfo)
+}
+
+func TestCreatePinPayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: group, Sender: types.NewADJID("628123456789", 0, 1), IsGroup: true},
+		ID:            "PIN1",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	key := &waCommon.MessageKey{ID: proto.String("ORIGINAL1"), Participant: proto.String("628987654321@s.whatsapp.net")}
+
+	payload := createPinPayload(&events.Message{Info: info, Message: &waE2E.Message{
+		PinInChatMessage:   &waE2E.PinInChatMessage{Key: key, Type: waE2E.PinInChatMessage_PIN_FOR_ALL.Enum()},
+		MessageContextInfo: &waE2E.MessageContextInfo{MessageAddOnDurationInSecs: proto.Uint32(86400)},
+	}})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":     "pin",
+		"action":         "pin",
+		"chat":           group.String(),
+		"message_id":     "ORIGINAL1",
+		"message_sender": "628987654321@s.whatsapp.net",
+		"by":             "628123456789@s.whatsapp.net",
+		"from_me":        false,
+		"duration":       uint32(86400),
+		"expires_at":     "2025-01-03T03:04:05Z",
+		"timestamp":      "2025-01-02T03:04:05Z",
+	}, payload)
+
+	payload = createPinPayload(&events.Message{Info: info, Message: &waE2E.Message{
+		PinInChatMessage: &waE2E.PinInChatMessage{Key: key, Type: waE2E.PinInChatMessage_UNPIN_FOR_ALL.Enum()},
+	}})
+	assert.Equal(t, "unpin", payload["action"])
+	assert.NotContains(t, payload, "expires_at")
+
+	assert.Nil(t, createPinPayload(&events.Message{Info: info, Message: &waE2E.Message{Conversation: proto.String("hello")}}))
+}
@@ -0,0 +1,41 @@
+package whatsapp
+
+import (
+	"context"
+	"sync"
+)
+
+type requestMediaKey struct{}
+
+// requestMedia keeps what the sends of a request prepare and upload, shared by all its recipients
+type requestMedia struct {
+	sync.Mutex
+	media map[string]interface{}
+}
+
+// WithRequestMedia lets the sends made with the context share the media they prepare and upload, so a request sent
+// to several recipients converts and uploads its media once
+func WithRequestMedia(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestMediaKey{}, &requestMedia{media: make(map[string]interface{})})
+}
+
+// RequestMedia returns what an earlier send of the request prepared under the key, or prepares it. Without
+// WithRequestMedia it's prepared on every call, and a failure is never kept. The prepare mustn't use RequestMedia
+// itself, the sends of the request wait for it.
+func RequestMedia[T any](ctx context.Context, key string, prepare func() (T, error)) (T, error) {
+	shared, ok := ctx.Value(requestMediaKey{}).(*requestMedia)
+	if !ok {
+		return prepare()
+	}
+
+	shared.Lock()
+	defer shared.Unlock()
+	if media, ok := shared.media[key]; ok {
+		return media.(T), nil
+	}
+	media, err := prepare()
+	if err == nil {
+		shared.media[key] = media
+	}
+	return media, err
+}
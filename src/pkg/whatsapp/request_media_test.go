@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestMedia(t *testing.T) {
+	prepares := 0
+	prepare := func() (string, error) {
+		prepares++
+		return "uploaded", nil
+	}
+
+	t.Run("should prepare on every send without a request", func(t *testing.T) {
+		prepares = 0
+		_, _ = RequestMedia(context.Background(), "image", prepare)
+		_, _ = RequestMedia(context.Background(), "image", prepare)
+		assert.Equal(t, 2, prepares)
+	})
+
+	t.Run("should prepare once per request", func(t *testing.T) {
+		prepares = 0
+		ctx := WithRequestMedia(context.Background())
+		for i := 0; i < 3; i++ {
+			media, err := RequestMedia(ctx, "image", prepare)
+			assert.NoError(t, err)
+			assert.Equal(t, "uploaded", media)
+		}
+		assert.Equal(t, 1, prepares)
+
+		_, _ = RequestMedia(WithRequestMedia(context.Background()), "image", prepare)
+		assert.Equal(t, 2, prepares, "another request prepares its own")
+	})
+
+	t.Run("should not keep a failure", func(t *testing.T) {
+		ctx := WithRequestMedia(context.Background())
+		_, err := RequestMedia(ctx, "video", func() (string, error) { return "", errors.New("ffmpeg not installed") })
+		assert.EqualError(t, err, "ffmpeg not installed")
+
+		media, err := RequestMedia(ctx, "video", func() (string, error) { return "converted", nil })
+		assert.NoError(t, err)
+		assert.Equal(t, "converted", media)
+	})
+}
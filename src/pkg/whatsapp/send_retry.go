@@ -0,0 +1,252 @@
+package whatsapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/socket"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+var sendRetryMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS send_retries (
+		id         TEXT    PRIMARY KEY,
+		chat       TEXT    NOT NULL,
+		message    BLOB    NOT NULL,
+		content    TEXT    NOT NULL,
+		priority   INTEGER NOT NULL,
+		attempts   INTEGER NOT NULL,
+		last_error TEXT    NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+}
+
+// SendRetryQueued is returned instead of the error of a send lost with the connection, the message is kept and
+// sent again with the same ID once connected
+type SendRetryQueued struct {
+	MessageID string
+	Cause     error
+}
+
+func (e *SendRetryQueued) Error() string {
+	return fmt.Sprintf("message %s is queued and sent again after the reconnection: %v", e.MessageID, e.Cause)
+}
+
+func (e *SendRetryQueued) Unwrap() error {
+	return e.Cause
+}
+
+// ErrCode and StatusCode make the queued send a 202 for the handlers that don't check for it
+func (e *SendRetryQueued) ErrCode() string {
+	return "QUEUED"
+}
+
+func (e *SendRetryQueued) StatusCode() int {
+	return http.StatusAccepted
+}
+
+// SendRetry is a send waiting for the connection
+type SendRetry struct {
+	ID        string
+	Chat      string
+	Message   *waE2E.Message
+	Content   string // text recorded for the message once it's sent
+	Priority  OutboundPriority
+	Attempts  int
+	LastError string
+	CreatedAt time.Time
+}
+
+// isTransientSendError tells the errors of a lost connection from the ones a new attempt wouldn't fix
+func isTransientSendError(err error) bool {
+	var disconnected *whatsmeow.DisconnectedError
+	return errors.Is(err, whatsmeow.ErrNotConnected) ||
+		errors.Is(err, whatsmeow.ErrIQTimedOut) ||
+		errors.Is(err, whatsmeow.ErrMessageTimedOut) ||
+		errors.Is(err, socket.ErrSocketClosed) ||
+		errors.As(err, &disconnected)
+}
+
+// SendWithRetry sends the message through the outbound queue. When the connection is lost during the send, the
+// message is kept to be sent again after the reconnection and a SendRetryQueued error is returned.
+func SendWithRetry(ctx context.Context, waCli *whatsmeow.Client, to types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
+	id := waCli.GenerateMessageID()
+	resp, err := SendOutbound(ctx, waCli, to, msg, whatsmeow.SendRequestExtra{ID: id})
+	if err == nil || config.WhatsappSendRetryAttempts <= 0 || !isTransientSendError(err) {
+		return resp, err
+	}
+
+	retry := SendRetry{
+		ID:        id,
+		Chat:      to.String(),
+		Message:   msg,
+		Content:   content,
+		Priority:  outboundPriority(ctx),
+		Attempts:  1,
+		LastError: err.Error(),
+		CreatedAt: time.Now(),
+	}
+	if storeErr := queueSendRetry(retry); storeErr != nil {
+		logrus.Errorf("Failed to keep message %s to send it again: %v", id, storeErr)
+		return resp, err
+	}
+	logrus.Warnf("Message %s to %s is sent again after the reconnection: %v", id, to, err)
+	return resp, &SendRetryQueued{MessageID: id, Cause: err}
+}
+
+func queueSendRetry(retry SendRetry) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	message, err := proto.Marshal(retry.Message)
+	if err != nil {
+		return err
+	}
+	_, err = appStore.Exec(
+		`INSERT INTO send_retries (id, chat, message, content, priority, attempts, last_error, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		retry.ID, retry.Chat, message, retry.Content, int(retry.Priority), retry.Attempts, retry.LastError, retry.CreatedAt.Unix(),
+	)
+	return err
+}
+
+// PendingSendRetries returns the sends waiting for the connection, the oldest first
+func PendingSendRetries() ([]SendRetry, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	rows, err := appStore.Query(`SELECT id, chat, message, content, priority, attempts, last_error, created_at FROM send_retries ORDER BY created_at, rowid`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var retries []SendRetry
+	for rows.Next() {
+		var (
+			retry     SendRetry
+			message   []byte
+			priority  int
+			createdAt int64
+		)
+		if err = rows.Scan(&retry.ID, &retry.Chat, &message, &retry.Content, &priority, &retry.Attempts, &retry.LastError, &createdAt); err != nil {
+			return nil, err
+		}
+		retry.Message = &waE2E.Message{}
+		if err = proto.Unmarshal(message, retry.Message); err != nil {
+			return nil, fmt.Errorf("failed to decode message %s: %w", retry.ID, err)
+		}
+		retry.Priority = OutboundPriority(priority)
+		retry.CreatedAt = time.Unix(createdAt, 0)
+		retries = append(retries, retry)
+	}
+	return retries, rows.Err()
+}
+
+func updateSendRetry(retry SendRetry) error {
+	_, err := appStore.Exec(`UPDATE send_retries SET attempts = ?, last_error = ? WHERE id = ?`, retry.Attempts, retry.LastError, retry.ID)
+	return err
+}
+
+func deleteSendRetry(id string) error {
+	_, err := appStore.Exec(`DELETE FROM send_retries WHERE id = ?`, id)
+	return err
+}
+
+var sendRetrying atomic.Bool
+
+// RetryFailedSends sends again the messages lost with the connection, it runs after every connection. A send
+// failing again is kept for the next connection until it runs out of attempts or gets too old.
+func RetryFailedSends() {
+	if appStore == nil || !sendRetrying.CompareAndSwap(false, true) {
+		return
+	}
+	defer sendRetrying.Store(false)
+
+	retries, err := PendingSendRetries()
+	if err != nil {
+		logrus.Errorf("Failed to load the sends to retry: %v", err)
+		return
+	}
+	if len(retries) > 0 {
+		logrus.Infof("Sending again %d messages lost with the connection", len(retries))
+	}
+
+	for _, retry := range retries {
+		if time.Since(retry.CreatedAt) > config.WhatsappSendRetryMaxAge {
+			retry.LastError = fmt.Sprintf("not sent within %s: %s", config.WhatsappSendRetryMaxAge, retry.LastError)
+			abandonSendRetry(retry)
+			continue
+		}
+
+		to, err := types.ParseJID(retry.Chat)
+		if err != nil {
+			retry.LastError = err.Error()
+			abandonSendRetry(retry)
+			continue
+		}
+		// the same ID is sent again, the recipient drops the copy when the first attempt got through after all
+		retry.Attempts++
+		ctx := WithOutboundPriority(context.Background(), retry.Priority)
+		resp, err := SendOutbound(ctx, cli, to, retry.Message, whatsmeow.SendRequestExtra{ID: retry.ID})
+		if err == nil {
+			if err = deleteSendRetry(retry.ID); err != nil {
+				logrus.Errorf("Failed to delete the retry of message %s: %v", retry.ID, err)
+			}
+			RecordSentMessage(to, resp, retry.Message, retry.Content)
+			forwardSendRetryToWebhook("message.retry_sent", retry, resp.Timestamp)
+			continue
+		}
+
+		retry.LastError = err.Error()
+		// a full outbound queue is waited out too, the retries of a long disconnection come all at once
+		var tooMany pkgError.TooManyRequestsError
+		if !isTransientSendError(err) && !errors.As(err, &tooMany) || retry.Attempts >= config.WhatsappSendRetryAttempts {
+			abandonSendRetry(retry)
+			continue
+		}
+		if err = updateSendRetry(retry); err != nil {
+			logrus.Errorf("Failed to update the retry of message %s: %v", retry.ID, err)
+		}
+		if !cli.IsConnected() {
+			// the next connection picks up from here
+			return
+		}
+	}
+}
+
+func abandonSendRetry(retry SendRetry) {
+	logrus.Warnf("Abandoned message %s to %s after %d attempts: %s", retry.ID, retry.Chat, retry.Attempts, retry.LastError)
+	if err := deleteSendRetry(retry.ID); err != nil {
+		logrus.Errorf("Failed to delete the retry of message %s: %v", retry.ID, err)
+	}
+	forwardSendRetryToWebhook("message.retry_abandoned", retry, time.Now())
+}
+
+func forwardSendRetryToWebhook(eventType string, retry SendRetry, at time.Time) {
+	body := map[string]interface{}{
+		"event_type": eventType,
+		"message_id": retry.ID,
+		"chat":       retry.Chat,
+		"attempts":   retry.Attempts,
+		"queued_at":  retry.CreatedAt.UTC().Format(time.RFC3339),
+		"timestamp":  at.UTC().Format(time.RFC3339),
+	}
+	if eventType == "message.retry_abandoned" {
+		body["error"] = retry.LastError
+	}
+	enqueueWebhook(eventType, func() error {
+		return forwardEventToWebhook(eventType, body)
+	})
+}
@@ -0,0 +1,80 @@
+package whatsapp
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/socket"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestIsTransientSendError(t *testing.T) {
+	assert.True(t, isTransientSendError(whatsmeow.ErrNotConnected))
+	assert.True(t, isTransientSendError(fmt.Errorf("failed to send: %w", whatsmeow.ErrMessageTimedOut)))
+	assert.True(t, isTransientSendError(fmt.Errorf("failed to send: %w", socket.ErrSocketClosed)))
+	assert.True(t, isTransientSendError(&whatsmeow.DisconnectedError{Action: "message send"}))
+	assert.False(t, isTransientSendError(whatsmeow.ErrRecipientADJID))
+	assert.False(t, isTransientSendError(pkgError.TooManyRequestsError("the outbound queue is full")))
+}
+
+func TestSendRetryQueued(t *testing.T) {
+	var err error = &SendRetryQueued{MessageID: "3EB0A1", Cause: whatsmeow.ErrNotConnected}
+
+	generic, ok := err.(pkgError.GenericError)
+	assert.True(t, ok, "the recovery middleware renders it as a 202")
+	assert.Equal(t, 202, generic.StatusCode())
+	assert.Equal(t, "QUEUED", generic.ErrCode())
+	assert.ErrorIs(t, err, whatsmeow.ErrNotConnected)
+
+	var queued *SendRetryQueued
+	assert.True(t, errors.As(fmt.Errorf("send text: %w", err), &queued))
+	assert.Equal(t, "3EB0A1", queued.MessageID)
+}
+
+func TestSendRetryStore(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	now := time.Now().Truncate(time.Second)
+	for _, retry := range []SendRetry{
+		{ID: "MSG2", Chat: "628222@s.whatsapp.net", Message: &waE2E.Message{Conversation: proto.String("second")}, CreatedAt: now, Attempts: 1, LastError: "websocket not connected"},
+		{ID: "MSG1", Chat: "628111@s.whatsapp.net", Message: &waE2E.Message{Conversation: proto.String("first")}, Content: "first", Priority: OutboundPriorityHigh, CreatedAt: now.Add(-time.Minute), Attempts: 1},
+	} {
+		assert.NoError(t, queueSendRetry(retry))
+	}
+
+	retries, err := PendingSendRetries()
+	assert.NoError(t, err)
+	assert.Len(t, retries, 2)
+	assert.Equal(t, "MSG1", retries[0].ID, "the oldest send goes first")
+	assert.Equal(t, "first", retries[0].Message.GetConversation())
+	assert.Equal(t, "first", retries[0].Content)
+	assert.Equal(t, OutboundPriorityHigh, retries[0].Priority)
+	assert.True(t, now.Add(-time.Minute).Equal(retries[0].CreatedAt))
+	assert.Equal(t, "websocket not connected", retries[1].LastError)
+
+	retries[1].Attempts, retries[1].LastError = 2, "info query timed out"
+	assert.NoError(t, updateSendRetry(retries[1]))
+	assert.NoError(t, deleteSendRetry("MSG1"))
+
+	retries, err = PendingSendRetries()
+	assert.NoError(t, err)
+	assert.Len(t, retries, 1)
+	assert.Equal(t, 2, retries[0].Attempts)
+	assert.Equal(t, "info query timed out", retries[0].LastError)
+}
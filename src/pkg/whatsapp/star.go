@@ -0,0 +1,17 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow/appstate"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// BuildStarPatch returns the app state patch starring or unstarring the message on every device. Only the
+// messages of others in groups carry their participant, the index has "0" for the other messages.
+func BuildStarPatch(chat, sender types.JID, id types.MessageID, fromMe, starred bool) appstate.PatchInfo {
+	chat = chat.ToNonAD()
+	participant := chat
+	if !fromMe && chat.Server == types.GroupServer {
+		participant = sender.ToNonAD()
+	}
+	return appstate.BuildStar(chat, participant, id, fromMe, starred)
+}
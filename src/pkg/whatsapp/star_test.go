@@ -0,0 +1,25 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestBuildStarPatch(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	contact := types.NewJID("628123456789", types.DefaultUserServer)
+	participant := types.NewADJID("628987654321", 0, 2)
+
+	patch := BuildStarPatch(group, participant, "MSG1", false, true)
+	assert.Equal(t, []string{"star", group.String(), "MSG1", "0", "628987654321@s.whatsapp.net"}, patch.Mutations[0].Index)
+	assert.True(t, patch.Mutations[0].Value.GetStarAction().GetStarred())
+
+	patch = BuildStarPatch(group, types.EmptyJID, "MSG2", true, false)
+	assert.Equal(t, []string{"star", group.String(), "MSG2", "1", "0"}, patch.Mutations[0].Index)
+	assert.False(t, patch.Mutations[0].Value.GetStarAction().GetStarred())
+
+	patch = BuildStarPatch(contact, contact, "MSG3", false, true)
+	assert.Equal(t, []string{"star", contact.String(), "MSG3", "0", "0"}, patch.Mutations[0].Index)
+}
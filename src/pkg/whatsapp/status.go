@@ -0,0 +1,191 @@
+package whatsapp
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var statusMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS statuses (
+		id         TEXT    PRIMARY KEY,
+		type       TEXT    NOT NULL,
+		content    TEXT    NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS status_views (
+		message_id TEXT    NOT NULL,
+		viewer     TEXT    NOT NULL,
+		viewed_at  INTEGER NOT NULL,
+		PRIMARY KEY (message_id, viewer)
+	)`,
+}
+
+// statusLifetime is how long a status is shown to the contacts
+const statusLifetime = 24 * time.Hour
+
+// Status is a status posted by this account
+type Status struct {
+	ID        string
+	Type      string // text, image or video
+	Content   string // text or caption
+	CreatedAt time.Time
+	Views     int
+}
+
+type StatusViewer struct {
+	JID      string
+	ViewedAt time.Time
+}
+
+// ParseARGB reads a #RRGGBB or #AARRGGBB color, the colors without alpha are opaque
+func ParseARGB(color string) (uint32, error) {
+	hex := strings.TrimPrefix(color, "#")
+	if len(hex) != 6 && len(hex) != 8 {
+		return 0, pkgError.ValidationError(fmt.Sprintf("color %s is not #RRGGBB or #AARRGGBB", color))
+	}
+	argb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return 0, pkgError.ValidationError(fmt.Sprintf("color %s is not #RRGGBB or #AARRGGBB", color))
+	}
+	if len(hex) == 6 {
+		argb |= 0xff000000
+	}
+	return uint32(argb), nil
+}
+
+// StatusFont returns the font of a text status by its lowercase name, e.g. system_bold or courierprime_bold
+func StatusFont(name string) (waE2E.ExtendedTextMessage_FontType, bool) {
+	font, ok := waE2E.ExtendedTextMessage_FontType_value[strings.ToUpper(name)]
+	return waE2E.ExtendedTextMessage_FontType(font), ok
+}
+
+// SaveStatus keeps the status to count its views, the statuses past their lifetime are dropped with their views
+func SaveStatus(status Status) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	tx, err := appStore.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	expired := status.CreatedAt.Add(-statusLifetime).Unix()
+	if _, err = tx.Exec(`DELETE FROM status_views WHERE message_id IN (SELECT id FROM statuses WHERE created_at < ?)`, expired); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(`DELETE FROM statuses WHERE created_at < ?`, expired); err != nil {
+		return err
+	}
+	if _, err = tx.Exec(
+		`INSERT OR REPLACE INTO statuses (id, type, content, created_at) VALUES (?, ?, ?, ?)`,
+		status.ID, status.Type, status.Content, status.CreatedAt.Unix(),
+	); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ListStatuses returns the statuses still shown, the newest first, with their view count
+func ListStatuses(now time.Time) ([]Status, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	rows, err := appStore.Query(
+		`SELECT s.id, s.type, s.content, s.created_at, COUNT(v.viewer) FROM statuses s
+		LEFT JOIN status_views v ON v.message_id = s.id
+		WHERE s.created_at >= ? GROUP BY s.id ORDER BY s.created_at DESC, s.rowid DESC`,
+		now.Add(-statusLifetime).Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statuses := []Status{}
+	for rows.Next() {
+		var (
+			status    Status
+			createdAt int64
+		)
+		if err = rows.Scan(&status.ID, &status.Type, &status.Content, &createdAt, &status.Views); err != nil {
+			return nil, err
+		}
+		status.CreatedAt = time.Unix(createdAt, 0)
+		statuses = append(statuses, status)
+	}
+	return statuses, rows.Err()
+}
+
+// GetStatusViewers returns who viewed the status, the first viewer first
+func GetStatusViewers(id string) ([]StatusViewer, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+
+	var exists int
+	if err := appStore.QueryRow(`SELECT COUNT(*) FROM statuses WHERE id = ?`, id).Scan(&exists); err != nil {
+		return nil, err
+	}
+	if exists == 0 {
+		return nil, pkgError.NotFoundError(fmt.Sprintf("status %s not found", id))
+	}
+
+	rows, err := appStore.Query(`SELECT viewer, viewed_at FROM status_views WHERE message_id = ? ORDER BY viewed_at, viewer`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	viewers := []StatusViewer{}
+	for rows.Next() {
+		var (
+			viewer   StatusViewer
+			viewedAt int64
+		)
+		if err = rows.Scan(&viewer.JID, &viewedAt); err != nil {
+			return nil, err
+		}
+		viewer.ViewedAt = time.Unix(viewedAt, 0)
+		viewers = append(viewers, viewer)
+	}
+	return viewers, rows.Err()
+}
+
+// recordStatusViews keeps the read receipts of the statuses posted by this account, a second view keeps the first time
+func recordStatusViews(ids []types.MessageID, viewer types.JID, viewedAt time.Time) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	for _, id := range ids {
+		_, err := appStore.Exec(
+			`INSERT OR IGNORE INTO status_views (message_id, viewer, viewed_at) SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM statuses WHERE id = ?)`,
+			id, viewer.ToNonAD().String(), viewedAt.Unix(), id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func handleStatusReceipt(evt *events.Receipt) {
+	if evt.Chat != types.StatusBroadcastJID || evt.IsFromMe ||
+		(evt.Type != types.ReceiptTypeRead && evt.Type != types.ReceiptTypePlayed) {
+		return
+	}
+	if err := recordStatusViews(evt.MessageIDs, evt.Sender, evt.Timestamp); err != nil {
+		logrus.Errorf("Failed to record the status views of %s: %v", evt.Sender, err)
+	}
+}
@@ -0,0 +1,80 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParseARGB(t *testing.T) {
+	argb, err := ParseARGB("#1E88E5")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0xff1e88e5), argb)
+
+	argb, err = ParseARGB("#80000000")
+	assert.NoError(t, err)
+	assert.Equal(t, uint32(0x80000000), argb)
+
+	_, err = ParseARGB("#12345")
+	assert.Error(t, err)
+	_, err = ParseARGB("#GGGGGG")
+	assert.Error(t, err)
+}
+
+func TestStatusFont(t *testing.T) {
+	font, ok := StatusFont("courierprime_bold")
+	assert.True(t, ok)
+	assert.Equal(t, waE2E.ExtendedTextMessage_COURIERPRIME_BOLD, font)
+
+	_, ok = StatusFont("comic_sans")
+	assert.False(t, ok)
+}
+
+func TestStatusStore(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	now := time.Now().Truncate(time.Second)
+	assert.NoError(t, SaveStatus(Status{ID: "OLD", Type: "text", Content: "yesterday", CreatedAt: now.Add(-25 * time.Hour)}))
+	assert.NoError(t, SaveStatus(Status{ID: "S1", Type: "text", Content: "Open today", CreatedAt: now.Add(-time.Hour)}))
+	assert.NoError(t, SaveStatus(Status{ID: "S2", Type: "image", Content: "New arrivals", CreatedAt: now}))
+
+	budi := types.NewJID("628111", types.DefaultUserServer)
+	siti := types.NewJID("628222", types.DefaultUserServer)
+	assert.NoError(t, recordStatusViews([]types.MessageID{"S1", "S2", "UNKNOWN"}, budi, now.Add(time.Minute)))
+	assert.NoError(t, recordStatusViews([]types.MessageID{"S1"}, siti, now.Add(2*time.Minute)))
+	// the second view keeps the time of the first
+	assert.NoError(t, recordStatusViews([]types.MessageID{"S1"}, budi, now.Add(3*time.Minute)))
+
+	statuses, err := ListStatuses(now)
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 2)
+	assert.Equal(t, "S2", statuses[0].ID)
+	assert.Equal(t, 1, statuses[0].Views)
+	assert.Equal(t, "S1", statuses[1].ID)
+	assert.Equal(t, "Open today", statuses[1].Content)
+	assert.Equal(t, 2, statuses[1].Views)
+
+	viewers, err := GetStatusViewers("S1")
+	assert.NoError(t, err)
+	assert.Equal(t, []StatusViewer{
+		{JID: "628111@s.whatsapp.net", ViewedAt: now.Add(time.Minute)},
+		{JID: "628222@s.whatsapp.net", ViewedAt: now.Add(2 * time.Minute)},
+	}, viewers)
+
+	// the expired status was dropped when the newer ones were saved
+	_, err = GetStatusViewers("OLD")
+	assert.Equal(t, pkgError.NotFoundError("status OLD not found"), err)
+}
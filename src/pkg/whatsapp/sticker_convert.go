@@ -0,0 +1,65 @@
+package whatsapp
+
+import (
+	"fmt"
+	"image/png"
+	"os"
+	"os/exec"
+	"strings"
+
+	"golang.org/x/image/webp"
+)
+
+// convertSticker stores a PNG copy of a static WebP sticker, or a GIF copy of an animated one, next to the
+// original. Decoding animated WebP isn't supported by x/image, those are converted with ffmpeg when it's installed.
+func convertSticker(media ExtractedMedia, animated bool) (ExtractedMedia, error) {
+	basePath := strings.TrimSuffix(media.MediaPath, ".webp")
+
+	if !animated {
+		pngPath := basePath + ".png"
+		if err := convertMediaFile(media.MediaPath, pngPath, convertWebpToPNG); err != nil {
+			return media, fmt.Errorf("failed to convert sticker: %w", err)
+		}
+		media.ConvertedPath, media.ConvertedMimeType = pngPath, "image/png"
+		return media, nil
+	}
+
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return media, fmt.Errorf("ffmpeg not installed, can't convert animated sticker")
+	}
+	gifPath := basePath + ".gif"
+	err := convertMediaFile(media.MediaPath, gifPath, func(source, destination string) error {
+		if output, err := exec.Command("ffmpeg", "-y", "-i", source, destination).CombinedOutput(); err != nil {
+			return fmt.Errorf("%v: %s", err, output)
+		}
+		return nil
+	})
+	if err != nil {
+		return media, fmt.Errorf("failed to convert animated sticker: %w", err)
+	}
+	media.ConvertedPath, media.ConvertedMimeType = gifPath, "image/gif"
+	return media, nil
+}
+
+func convertWebpToPNG(source, destination string) error {
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+
+	img, err := webp.Decode(input)
+	if err != nil {
+		return err
+	}
+
+	output, err := os.OpenFile(destination, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	if err = png.Encode(output, img); err != nil {
+		_ = output.Close()
+		return err
+	}
+	return output.Close()
+}
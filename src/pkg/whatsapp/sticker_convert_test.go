@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	"encoding/base64"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// a 1x1 transparent lossless WebP
+const testWebpSticker = "UklGRhoAAABXRUJQVlA4TA0AAAAvAAAAEAcQERGIiP4HAA=="
+
+func TestConvertSticker(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(testWebpSticker)
+	assert.NoError(t, err)
+
+	stickerPath := filepath.Join(t.TempDir(), "sticker.webp")
+	assert.NoError(t, os.WriteFile(stickerPath, data, 0600))
+
+	media, err := convertSticker(ExtractedMedia{MediaPath: stickerPath, MimeType: "image/webp"}, false)
+	assert.NoError(t, err)
+	assert.Equal(t, stickerPath, media.MediaPath, "the original sticker is kept")
+	assert.Equal(t, "image/png", media.ConvertedMimeType)
+
+	converted, err := os.Open(media.ConvertedPath)
+	assert.NoError(t, err)
+	defer converted.Close()
+	img, err := png.Decode(converted)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, img.Bounds().Dx())
+
+	invalidPath := filepath.Join(t.TempDir(), "invalid.webp")
+	assert.NoError(t, os.WriteFile(invalidPath, []byte("not a webp"), 0600))
+	_, err = convertSticker(ExtractedMedia{MediaPath: invalidPath}, false)
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image/gif"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"golang.org/x/image/webp"
+)
+
+// StickerSize is the width and height of the stickers, smaller images are padded with transparency
+const StickerSize = 512
+
+type StickerMetadata struct {
+	PackName      string
+	PackPublisher string
+	Emojis        []string
+}
+
+type Sticker struct {
+	WebP       []byte
+	IsAnimated bool
+}
+
+// EncodeSticker converts a PNG, JPEG, GIF or WebP image to a 512x512 WebP with ffmpeg, animated for GIFs
+// with several frames, and sets the sticker pack metadata in its EXIF
+func EncodeSticker(image []byte, mimeType string, metadata StickerMetadata) (Sticker, error) {
+	sticker := Sticker{WebP: image}
+	if mimeType == "image/gif" {
+		if decoded, err := gif.DecodeAll(bytes.NewReader(image)); err == nil {
+			sticker.IsAnimated = len(decoded.Image) > 1
+		}
+	}
+
+	// a static WebP of the right size is sent as is
+	webpConfig, err := webp.DecodeConfig(bytes.NewReader(image))
+	if mimeType != "image/webp" || err != nil || webpConfig.Width != StickerSize || webpConfig.Height != StickerSize {
+		if sticker.WebP, err = convertToWebP(image, sticker.IsAnimated); err != nil {
+			return sticker, err
+		}
+	}
+
+	if sticker.WebP, err = setWebPExif(sticker.WebP, stickerExif(metadata)); err != nil {
+		return sticker, fmt.Errorf("failed to set sticker metadata: %w", err)
+	}
+	return sticker, nil
+}
+
+func convertToWebP(image []byte, animated bool) ([]byte, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("ffmpeg not installed, can't convert sticker")
+	}
+
+	generateUUID := fiberUtils.UUIDv4()
+	sourcePath := filepath.Join(config.PathSendItems, generateUUID)
+	webpPath := filepath.Join(config.PathSendItems, generateUUID+".webp")
+	defer func() { _ = utils.RemoveFile(0, sourcePath, webpPath) }()
+
+	if err := os.WriteFile(sourcePath, image, 0600); err != nil {
+		return nil, err
+	}
+	if output, err := exec.Command("ffmpeg", stickerArgs(sourcePath, webpPath, animated)...).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to convert sticker: %v: %s", err, output)
+	}
+	return os.ReadFile(webpPath)
+}
+
+func stickerArgs(source, destination string, animated bool) []string {
+	filter := fmt.Sprintf("scale=%[1]d:%[1]d:force_original_aspect_ratio=decrease,format=rgba,"+
+		"pad=%[1]d:%[1]d:(ow-iw)/2:(oh-ih)/2:color=0x00000000", StickerSize)
+	args := []string{"-y", "-i", source, "-an", "-vf", filter}
+	if animated {
+		// WhatsApp plays animated stickers up to 10 seconds
+		args = append(args, "-c:v", "libwebp_anim", "-loop", "0", "-t", "10", "-r", "15", "-q:v", "50")
+	} else {
+		args = append(args, "-c:v", "libwebp", "-frames:v", "1", "-q:v", "75")
+	}
+	return append(args, destination)
+}
+
+// stickerExif is the TIFF block with the sticker pack JSON that WhatsApp reads from the WebP EXIF
+func stickerExif(metadata StickerMetadata) []byte {
+	emojis := metadata.Emojis
+	if emojis == nil {
+		emojis = []string{}
+	}
+	data, _ := json.Marshal(map[string]any{
+		"sticker-pack-id":        fiberUtils.UUIDv4(),
+		"sticker-pack-name":      metadata.PackName,
+		"sticker-pack-publisher": metadata.PackPublisher,
+		"emojis":                 emojis,
+	})
+
+	exif := []byte{0x49, 0x49, 0x2A, 0x00, 0x08, 0x00, 0x00, 0x00, 0x01, 0x00, 0x41, 0x57, 0x07, 0x00, 0, 0, 0, 0, 0x16, 0x00, 0x00, 0x00}
+	binary.LittleEndian.PutUint32(exif[14:18], uint32(len(data)))
+	return append(exif, data...)
+}
+
+// setWebPExif replaces the EXIF chunk of a WebP, a simple WebP is turned into an extended one to hold it
+func setWebPExif(image, exif []byte) ([]byte, error) {
+	if len(image) < 12 || string(image[0:4]) != "RIFF" || string(image[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("not a WebP image")
+	}
+
+	type chunk struct {
+		fourCC string
+		data   []byte
+	}
+	var chunks []chunk
+	for offset := 12; offset+8 <= len(image); {
+		size := int(binary.LittleEndian.Uint32(image[offset+4 : offset+8]))
+		end := offset + 8 + size
+		if end > len(image) {
+			return nil, fmt.Errorf("truncated WebP chunk %s", image[offset:offset+4])
+		}
+		if fourCC := string(image[offset : offset+4]); fourCC != "EXIF" {
+			chunks = append(chunks, chunk{fourCC: fourCC, data: image[offset+8 : end]})
+		}
+		offset = end + size%2
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("WebP without image data")
+	}
+
+	if chunks[0].fourCC != "VP8X" {
+		imageConfig, err := webp.DecodeConfig(bytes.NewReader(image))
+		if err != nil {
+			return nil, err
+		}
+		// the alpha flag is only a hint, lossless images carry their alpha in the VP8L chunk
+		header := make([]byte, 10)
+		putUint24(header[4:7], uint32(imageConfig.Width-1))
+		putUint24(header[7:10], uint32(imageConfig.Height-1))
+		chunks = append([]chunk{{fourCC: "VP8X", data: header}}, chunks...)
+	}
+	if len(chunks[0].data) < 10 {
+		return nil, fmt.Errorf("invalid WebP VP8X chunk")
+	}
+	header := append([]byte(nil), chunks[0].data...)
+	header[0] |= 0x08 // EXIF flag
+	chunks[0].data = header
+	chunks = append(chunks, chunk{fourCC: "EXIF", data: exif})
+
+	var body bytes.Buffer
+	body.WriteString("WEBP")
+	for _, c := range chunks {
+		body.WriteString(c.fourCC)
+		_ = binary.Write(&body, binary.LittleEndian, uint32(len(c.data)))
+		body.Write(c.data)
+		if len(c.data)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+
+	output := make([]byte, 8, 8+body.Len())
+	copy(output, "RIFF")
+	binary.LittleEndian.PutUint32(output[4:8], uint32(body.Len()))
+	return append(output, body.Bytes()...), nil
+}
+
+func putUint24(b []byte, v uint32) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
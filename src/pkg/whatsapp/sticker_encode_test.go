@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/image/webp"
+)
+
+func TestStickerExif(t *testing.T) {
+	exif := stickerExif(StickerMetadata{PackName: "Pack", PackPublisher: "Me", Emojis: []string{"😀"}})
+
+	assert.Equal(t, []byte{0x49, 0x49, 0x2A, 0x00}, exif[0:4], "little endian TIFF header")
+	assert.Equal(t, uint32(len(exif)-22), binary.LittleEndian.Uint32(exif[14:18]))
+
+	var pack map[string]any
+	assert.NoError(t, json.Unmarshal(exif[22:], &pack))
+	assert.Equal(t, "Pack", pack["sticker-pack-name"])
+	assert.Equal(t, "Me", pack["sticker-pack-publisher"])
+	assert.Equal(t, []any{"😀"}, pack["emojis"])
+	assert.NotEmpty(t, pack["sticker-pack-id"])
+}
+
+func TestSetWebPExif(t *testing.T) {
+	data, err := base64.StdEncoding.DecodeString(testWebpSticker)
+	assert.NoError(t, err)
+
+	output, err := setWebPExif(data, []byte("exif"))
+	assert.NoError(t, err)
+	assert.Equal(t, "VP8X", string(output[12:16]), "the simple WebP is extended")
+	assert.Equal(t, byte(0x08), output[20], "exif flag")
+	assert.True(t, bytes.HasSuffix(output, []byte("EXIF\x04\x00\x00\x00exif")))
+	assert.Equal(t, uint32(len(output)-8), binary.LittleEndian.Uint32(output[4:8]))
+
+	img, err := webp.Decode(bytes.NewReader(output))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, img.Bounds().Dx())
+
+	// the EXIF is replaced rather than added twice
+	again, err := setWebPExif(output, []byte("new"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, bytes.Count(again, []byte("EXIF")))
+	assert.True(t, bytes.HasSuffix(again, []byte("EXIF\x03\x00\x00\x00new\x00")))
+
+	_, err = setWebPExif([]byte("not a webp"), []byte("exif"))
+	assert.Error(t, err)
+}
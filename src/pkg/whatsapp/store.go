@@ -0,0 +1,56 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"fmt"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+// appStore keeps what this service persists besides the whatsmeow session: webhook deliveries and endpoints,
+// messages, media, broadcasts, statuses, templates...
+var (
+	appStore               *sql.DB
+	errStoreNotInitialized = pkgError.InternalServerError("store is not initialized")
+)
+
+// storeMigrations are the tables of every feature, declared next to it, created in this order on every start
+var storeMigrations = [][]string{
+	webhookOutboxMigrations,
+	webhookDeadLetterMigrations,
+	webhookEndpointMigrations,
+	webhookPollMigrations,
+	webhookContactMigrations,
+	mediaDedupMigrations,
+	mediaLazyMigrations,
+	uploadedMediaMigrations,
+	messageStoreMigrations,
+	liveLocationMigrations,
+	broadcastMigrations,
+	sendRetryMigrations,
+	statusMigrations,
+	messageTemplateMigrations,
+	groupJoinMigrations,
+}
+
+// InitStore opens the store and creates the missing tables
+func InitStore() error {
+	db, err := sql.Open("sqlite3", fmt.Sprintf("file:%s?_busy_timeout=5000", config.PathStoreDB))
+	if err != nil {
+		return fmt.Errorf("failed to open store: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+
+	for _, migrations := range storeMigrations {
+		for _, migration := range migrations {
+			if _, err = db.Exec(migration); err != nil {
+				_ = db.Close()
+				return fmt.Errorf("failed to migrate store: %w", err)
+			}
+		}
+	}
+
+	appStore = db
+	return nil
+}
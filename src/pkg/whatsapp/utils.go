@@ -60,17 +60,6 @@ func ExtractMedia(storageLocation string, mediaFile whatsmeow.DownloadableMessag
 		return extractedMedia, nil
 	}
 
-	data, err := cli.Download(mediaFile)
-	if err != nil {
-		return extractedMedia, err
-	}
-
-	// Validate file size before writing to disk
-	maxFileSize := config.WhatsappSettingMaxDownloadSize
-	if int64(len(data)) > maxFileSize {
-		return extractedMedia, fmt.Errorf("file size exceeds the maximum limit of %d bytes", maxFileSize)
-	}
-
 	var originalFileName string
 	switch media := mediaFile.(type) {
 	case *waE2E.ImageMessage:
@@ -78,6 +67,7 @@ func ExtractMedia(storageLocation string, mediaFile whatsmeow.DownloadableMessag
 		extractedMedia.Caption = media.GetCaption()
 	case *waE2E.AudioMessage:
 		extractedMedia.MimeType = media.GetMimetype()
+		addAudioDetails(&extractedMedia, media)
 	case *waE2E.VideoMessage:
 		extractedMedia.MimeType = media.GetMimetype()
 		extractedMedia.Caption = media.GetCaption()
@@ -87,19 +77,81 @@ func ExtractMedia(storageLocation string, mediaFile whatsmeow.DownloadableMessag
 		extractedMedia.MimeType = media.GetMimetype()
 		extractedMedia.Caption = media.GetCaption()
 		originalFileName = media.GetFileName()
+		addDocumentDetails(&extractedMedia, media)
+	}
+
+	// The same media is often forwarded, its hash tells whether it's already downloaded
+	if path, ok := findDedupMedia(storageLocation, mediaFile.GetFileSHA256()); ok {
+		extractedMedia.MediaPath = path
+		return extractedMedia, nil
+	}
+
+	data, err := cli.Download(mediaFile)
+	if err != nil {
+		return extractedMedia, err
+	}
+
+	// Validate file size before writing to disk
+	maxFileSize := config.WhatsappSettingMaxDownloadSize
+	if int64(len(data)) > maxFileSize {
+		return extractedMedia, fmt.Errorf("file size exceeds the maximum limit of %d bytes", maxFileSize)
 	}
 
 	// Use enhanced extension detection with priority-based logic
 	extension := extractFileExtension(originalFileName, extractedMedia.MimeType)
 
-	extractedMedia.MediaPath = fmt.Sprintf("%s/%d-%s%s", storageLocation, time.Now().Unix(), uuid.NewString(), extension)
-	err = os.WriteFile(extractedMedia.MediaPath, data, 0600)
+	if quarantineInfectedMedia(&extractedMedia, data, extension) {
+		return extractedMedia, nil
+	}
+
+	// documents keep their file name, the other media get a unique one
+	if extractedMedia.FileName != "" {
+		if filepath.Ext(extractedMedia.FileName) == "" {
+			extractedMedia.FileName += extension
+		}
+		extractedMedia.MediaPath, err = writeNamedMediaFile(storageLocation, extractedMedia.FileName, data)
+	} else {
+		extractedMedia.MediaPath = fmt.Sprintf("%s/%d-%s%s", storageLocation, time.Now().Unix(), uuid.NewString(), extension)
+		err = writeMediaFile(extractedMedia.MediaPath, data, os.O_TRUNC)
+	}
 	if err != nil {
 		return extractedMedia, err
 	}
+	storeDedupMedia(extractedMedia.MediaPath, mediaFile.GetFileSHA256())
 	return extractedMedia, nil
 }
 
+// convertMedia stores the configured copies of a downloaded sticker or audio, the media is kept as is on failure
+func convertMedia(source string, media ExtractedMedia, message whatsmeow.DownloadableMessage) ExtractedMedia {
+	if media.MediaPath == "" {
+		return media
+	}
+
+	var err error
+	switch message := message.(type) {
+	case *waE2E.StickerMessage:
+		if config.WhatsappStickerConvert {
+			if media, err = convertSticker(media, message.GetIsAnimated()); err != nil {
+				logrus.Warnf("Failed to convert sticker from %s: %v", source, err)
+			}
+		}
+	case *waE2E.AudioMessage:
+		if config.WhatsappAudioTranscode != "" {
+			if media, err = transcodeAudio(media, config.WhatsappAudioTranscode, config.WhatsappAudioTranscodeBitrate); err != nil {
+				logrus.Warnf("Failed to transcode audio from %s: %v", source, err)
+			}
+		}
+	}
+	return media
+}
+
+// addAudioDetails copies what a voice message bubble needs: whether it's a voice note, its length and waveform
+func addAudioDetails(extractedMedia *ExtractedMedia, audio *waE2E.AudioMessage) {
+	extractedMedia.PTT = audio.GetPTT()
+	extractedMedia.Duration = audio.GetSeconds()
+	extractedMedia.Waveform = audio.GetWaveform()
+}
+
 func SanitizePhone(phone *string) {
 	if phone != nil && len(*phone) > 0 && !strings.Contains(*phone, "@") {
 		if len(*phone) <= 15 {
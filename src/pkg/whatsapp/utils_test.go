@@ -0,0 +1,44 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAddAudioDetails(t *testing.T) {
+	tests := []struct {
+		name     string
+		audio    *waE2E.AudioMessage
+		expected string
+	}{
+		{
+			name: "should add the voice note details",
+			audio: &waE2E.AudioMessage{
+				PTT:      proto.Bool(true),
+				Seconds:  proto.Uint32(12),
+				Waveform: []byte{0, 10, 20},
+			},
+			expected: `{"media_path":"voice.ogg","mime_type":"audio/ogg","caption":"","ptt":true,"duration":12,"waveform":"AAoU"}`,
+		},
+		{
+			name:     "should leave out the details of an audio file",
+			audio:    &waE2E.AudioMessage{},
+			expected: `{"media_path":"voice.ogg","mime_type":"audio/ogg","caption":""}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			media := ExtractedMedia{MediaPath: "voice.ogg", MimeType: "audio/ogg"}
+			addAudioDetails(&media, tt.audio)
+
+			result, err := json.Marshal(media)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tt.expected, string(result))
+		})
+	}
+}
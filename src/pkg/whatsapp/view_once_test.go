@@ -0,0 +1,23 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWrapViewOnce(t *testing.T) {
+	msg := WrapViewOnce(&waE2E.Message{AudioMessage: &waE2E.AudioMessage{PTT: proto.Bool(true)}})
+	assert.True(t, msg.GetViewOnceMessageV2().GetMessage().GetAudioMessage().GetViewOnce())
+	assert.True(t, msg.GetViewOnceMessageV2().GetMessage().GetAudioMessage().GetPTT())
+
+	unwrapped := unwrapViewOnce(&events.Message{Message: msg})
+	assert.True(t, unwrapped.IsViewOnce)
+	assert.NotNil(t, unwrapped.Message.GetAudioMessage())
+
+	text := &waE2E.Message{Conversation: proto.String("hello")}
+	assert.Same(t, text, WrapViewOnce(text))
+}
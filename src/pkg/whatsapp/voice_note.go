@@ -0,0 +1,100 @@
+package whatsapp
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	fiberUtils "github.com/gofiber/fiber/v2/utils"
+)
+
+const (
+	VoiceNoteMimeType = "audio/ogg; codecs=opus"
+
+	// voiceNoteWaveformBars is the number of bars drawn by WhatsApp, each one from 0 to 100
+	voiceNoteWaveformBars = 64
+	// voiceNoteSampleRate is the rate the audio is decoded at to measure it, enough for the waveform
+	voiceNoteSampleRate = 8000
+)
+
+type VoiceNote struct {
+	Audio    []byte // Opus in an OGG container
+	Seconds  uint32
+	Waveform []byte
+}
+
+// ConvertVoiceNote transcodes the audio to Opus with ffmpeg and measures its duration and waveform
+func ConvertVoiceNote(audio []byte) (VoiceNote, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return VoiceNote{}, fmt.Errorf("ffmpeg not installed, can't send a voice note")
+	}
+
+	generateUUID := fiberUtils.UUIDv4()
+	sourcePath := filepath.Join(config.PathSendItems, generateUUID)
+	oggPath := filepath.Join(config.PathSendItems, generateUUID+".ogg")
+	defer func() { _ = utils.RemoveFile(0, sourcePath, oggPath) }()
+
+	if err := os.WriteFile(sourcePath, audio, 0600); err != nil {
+		return VoiceNote{}, err
+	}
+	if output, err := exec.Command("ffmpeg", "-y", "-i", sourcePath, "-vn", "-ac", "1", "-ar", "48000",
+		"-c:a", "libopus", "-b:a", "32k", "-application", "voip", oggPath).CombinedOutput(); err != nil {
+		return VoiceNote{}, fmt.Errorf("failed to transcode voice note: %v: %s", err, output)
+	}
+	pcm, err := exec.Command("ffmpeg", "-i", oggPath, "-ac", "1", "-ar", fmt.Sprint(voiceNoteSampleRate),
+		"-f", "s16le", "-").Output()
+	if err != nil {
+		return VoiceNote{}, fmt.Errorf("failed to decode voice note: %v", err)
+	}
+
+	samples := make([]int16, len(pcm)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(pcm[i*2:]))
+	}
+
+	ogg, err := os.ReadFile(oggPath)
+	if err != nil {
+		return VoiceNote{}, err
+	}
+	return VoiceNote{
+		Audio:    ogg,
+		Seconds:  uint32(math.Ceil(float64(len(samples)) / voiceNoteSampleRate)),
+		Waveform: voiceNoteWaveform(samples, voiceNoteWaveformBars),
+	}, nil
+}
+
+// voiceNoteWaveform averages the loudness of the samples in each bar, scaled so the loudest bar is 100
+func voiceNoteWaveform(samples []int16, bars int) []byte {
+	levels := make([]float64, bars)
+	if len(samples) == 0 {
+		return make([]byte, bars)
+	}
+
+	var loudest float64
+	for bar := range levels {
+		start, end := bar*len(samples)/bars, (bar+1)*len(samples)/bars
+		if end == start {
+			end = min(start+1, len(samples))
+		}
+		var sum float64
+		for _, sample := range samples[start:end] {
+			sum += math.Abs(float64(sample))
+		}
+		levels[bar] = sum / float64(end-start)
+		loudest = math.Max(loudest, levels[bar])
+	}
+
+	waveform := make([]byte, bars)
+	if loudest == 0 {
+		return waveform
+	}
+	for bar, level := range levels {
+		waveform[bar] = byte(math.Round(level / loudest * 100))
+	}
+	return waveform
+}
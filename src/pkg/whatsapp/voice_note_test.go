@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVoiceNoteWaveform(t *testing.T) {
+	tests := []struct {
+		name     string
+		samples  []int16
+		bars     int
+		expected []byte
+	}{
+		{
+			name:     "should scale the loudest bar to 100",
+			samples:  []int16{100, -100, 50, -50, 0, 0, 200, -200},
+			bars:     4,
+			expected: []byte{50, 25, 0, 100},
+		},
+		{
+			name:     "should be flat when silent",
+			samples:  []int16{0, 0, 0, 0},
+			bars:     2,
+			expected: []byte{0, 0},
+		},
+		{
+			name:     "should be flat without samples",
+			bars:     3,
+			expected: []byte{0, 0, 0},
+		},
+		{
+			name:     "should repeat samples when shorter than the bars",
+			samples:  []int16{10, 20},
+			bars:     4,
+			expected: []byte{50, 50, 100, 100},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, voiceNoteWaveform(tt.samples, tt.bars))
+		})
+	}
+}
@@ -2,8 +2,10 @@ package whatsapp
 
 import (
 	"bytes"
-	"encoding/json"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -15,27 +17,125 @@ import (
 	"go.mau.fi/whatsmeow/types/events"
 )
 
-// forwardEventToWebhook is a generic helper function to forward any event payload to webhook URLs
+// webhookEvent is an event ready to be forwarded to the webhook endpoints
+type webhookEvent struct {
+	Type    string
+	Payload map[string]interface{}
+	// Source is what the endpoint rules are evaluated on, nil for events without chat context
+	Source *webhookEventSource
+	// OnResponse receives the response body of every direct delivery when it's set
+	OnResponse func(endpoint WebhookEndpoint, response []byte)
+}
+
+// forwardEventToWebhook is a generic helper function to forward any event payload to the webhook URLs subscribed to its event type.
+// Every endpoint is delivered by its own worker so a slow or broken endpoint can't delay the others.
 func forwardEventToWebhook(eventType string, payload map[string]interface{}) error {
-	logrus.Infof("Forwarding %s to webhook: %v", eventType, config.WhatsappWebhook)
+	return dispatchWebhookEvent(webhookEvent{Type: eventType, Payload: payload})
+}
 
-	for _, url := range config.WhatsappWebhook {
-		if err := submitWebhook(payload, url); err != nil {
-			return err
+// dispatchWebhookEvent renders the event for every endpoint subscribed to its type whose rules accept its source
+// and queues it on the worker of the endpoint
+func dispatchWebhookEvent(event webhookEvent) error {
+	eventType := event.Type
+	endpoints := webhookEndpointsForEvent(eventType, event.Source)
+	if len(endpoints) == 0 {
+		logrus.Debugf("No webhook subscribed to %s event", eventType)
+		return nil
+	}
+
+	logrus.Infof("Forwarding %s event to %d webhook(s)", eventType, len(endpoints))
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		postBody, err := renderWebhookBody(endpoint, event.Payload)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", endpoint.URL, err))
+			continue
 		}
+		enqueueEndpointDelivery(webhookDelivery{event: event, endpoint: endpoint, body: postBody})
+	}
+	if err := errors.Join(errs...); err != nil {
+		return err
 	}
 
-	logrus.Infof("%s forwarded to webhook", strings.Title(eventType))
+	logrus.Infof("%s event queued for webhook", strings.Title(eventType))
+	return nil
+}
+
+// deliverToEndpoint submits the body to a single endpoint, queueing it in the outbox when the delivery fails
+func deliverToEndpoint(event webhookEvent, endpoint WebhookEndpoint, postBody []byte) error {
+	if endpoint.Batch != nil {
+		enqueueWebhookBatch(endpoint, postBody)
+		return nil
+	}
+
+	response, err := submitWebhook(context.Background(), endpoint, postBody)
+	if err == nil {
+		if event.OnResponse != nil {
+			event.OnResponse(endpoint, response)
+		}
+		return nil
+	}
+
+	if errQueue := queueWebhookRetry(event.Type, endpoint.URL, postBody, err); errQueue != nil {
+		logrus.Errorf("Failed to queue webhook retry for %s: %v", endpoint.URL, errQueue)
+		return fmt.Errorf("%s: %w", endpoint.URL, err)
+	}
+
+	logrus.Warnf("Webhook %s failed, queued in outbox for retry: %v", endpoint.URL, err)
 	return nil
 }
 
 // forwardToWebhook is a helper function to forward event to webhook url
 func forwardToWebhook(evt *events.Message) error {
+	evt = unwrapViewOnce(evt)
+	source := newMessageEventSource(evt)
+	storeWebhookPoll(evt)
+
+	if isNewsletterMessage(evt) {
+		return forwardNewsletterToWebhook(evt, source)
+	}
+	if payload := createRevokePayload(evt); payload != nil {
+		return dispatchWebhookEvent(webhookEvent{Type: "revoke", Payload: payload, Source: source})
+	}
+	if payload := createEditPayload(evt); payload != nil {
+		return dispatchWebhookEvent(webhookEvent{Type: "edit", Payload: payload, Source: source})
+	}
+	if payload := createPinPayload(evt); payload != nil {
+		return dispatchWebhookEvent(webhookEvent{Type: "pin", Payload: payload, Source: source})
+	}
+	if payload := createKeepInChatPayload(evt); payload != nil {
+		return dispatchWebhookEvent(webhookEvent{Type: "keep_in_chat", Payload: payload, Source: source})
+	}
+	if evt.Message.GetPollUpdateMessage() != nil {
+		if len(webhookEndpointsForEvent("poll_vote", source)) == 0 {
+			return nil
+		}
+		payload, err := createPollVotePayload(evt)
+		if err != nil {
+			return err
+		}
+		return dispatchWebhookEvent(webhookEvent{Type: "poll_vote", Payload: payload, Source: source})
+	}
+
+	// skip downloading the media when no endpoint wants the message
+	if len(webhookEndpointsForEvent("message", source)) == 0 {
+		return nil
+	}
+
 	payload, err := createPayload(evt)
 	if err != nil {
 		return err
 	}
-	return forwardEventToWebhook("event", payload)
+	defer forwardPendingMedia(evt, source, payload)
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "message",
+		Payload: payload,
+		Source:  source,
+		OnResponse: func(endpoint WebhookEndpoint, response []byte) {
+			handleWebhookReply(evt, endpoint, response)
+		},
+	})
 }
 
 func createPayload(evt *events.Message) (map[string]interface{}, error) {
@@ -67,39 +167,59 @@ func createPayload(evt *events.Message) (map[string]interface{}, error) {
 	if forwarded {
 		body["forwarded"] = forwarded
 	}
+	if quoted := buildEventQuoted(evt.Message); quoted != nil {
+		body["quoted"] = quoted
+	}
+	addEventMentions(body, evt.Message)
+	if ephemeral := buildEventEphemeral(evt); ephemeral != nil {
+		body["ephemeral"] = ephemeral
+	}
+	if poll := buildEventPoll(evt); poll != nil {
+		body["poll"] = poll
+	}
+	if community := lookupCommunity(evt.Info.Chat, groupInfoFetcher()); community != nil {
+		body["community"] = community
+	}
 	if timestamp := evt.Info.Timestamp.Format(time.RFC3339); timestamp != "" {
 		body["timestamp"] = timestamp
 	}
 
-	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil {
+	// with lazy downloads the media fields only reference the media, see POST /message/:message_id/download,
+	// as do the media skipped by the download rules. Large media are extracted after the webhook.
+	lazyMedia := config.WhatsappMediaLazyDownload && addMediaReference(evt, body)
+	if !lazyMedia {
+		lazyMedia = skipMediaDownload(evt, body) || deferMediaExtraction(evt, body)
+	}
+
+	if audioMedia := evt.Message.GetAudioMessage(); audioMedia != nil && !lazyMedia {
 		path, err := ExtractMedia(config.PathMedia, audioMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download audio from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download audio: %v", err))
 		}
-		body["audio"] = path
+		body["audio"] = storeMedia(convertMedia(evt.Info.SourceString(), path, audioMedia))
 	}
 
 	if contactMessage := evt.Message.GetContactMessage(); contactMessage != nil {
 		body["contact"] = contactMessage
 	}
 
-	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil {
+	if documentMedia := evt.Message.GetDocumentMessage(); documentMedia != nil && !lazyMedia {
 		path, err := ExtractMedia(config.PathMedia, documentMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download document from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download document: %v", err))
 		}
-		body["document"] = path
+		body["document"] = storeMedia(path)
 	}
 
-	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil {
+	if imageMedia := evt.Message.GetImageMessage(); imageMedia != nil && !lazyMedia {
 		path, err := ExtractMedia(config.PathMedia, imageMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download image from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download image: %v", err))
 		}
-		body["image"] = path
+		body["image"] = storeMedia(path)
 	}
 
 	if listMessage := evt.Message.GetListMessage(); listMessage != nil {
@@ -111,71 +231,110 @@ func createPayload(evt *events.Message) (map[string]interface{}, error) {
 	}
 
 	if locationMessage := evt.Message.GetLocationMessage(); locationMessage != nil {
-		body["location"] = locationMessage
+		body["location"] = buildEventLocation(locationMessage)
+	}
+
+	if interactive := buildEventInteractive(evt.Message); interactive != nil {
+		body["interactive"] = interactive
+	}
+
+	if buttonReply := buildEventButtonReply(evt.Message); buttonReply != nil {
+		body["button_reply"] = buttonReply
 	}
 
 	if orderMessage := evt.Message.GetOrderMessage(); orderMessage != nil {
-		body["order"] = orderMessage
+		body["order"] = buildEventOrder(orderMessage)
 	}
 
-	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil {
+	if stickerMedia := evt.Message.GetStickerMessage(); stickerMedia != nil && !lazyMedia {
 		path, err := ExtractMedia(config.PathMedia, stickerMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download sticker from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download sticker: %v", err))
 		}
-		body["sticker"] = path
+		body["sticker"] = storeMedia(convertMedia(evt.Info.SourceString(), path, stickerMedia))
 	}
 
-	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil {
+	if videoMedia := evt.Message.GetVideoMessage(); videoMedia != nil && !lazyMedia {
 		path, err := ExtractMedia(config.PathMedia, videoMedia)
 		if err != nil {
 			logrus.Errorf("Failed to download video from %s: %v", evt.Info.SourceString(), err)
 			return nil, pkgError.WebhookError(fmt.Sprintf("Failed to download video: %v", err))
 		}
-		body["video"] = path
+		body["video"] = storeMedia(path)
 	}
 
 	return body, nil
 }
 
-func submitWebhook(payload map[string]interface{}, url string) error {
-	client := &http.Client{Timeout: 10 * time.Second}
+// submitWebhook delivers the body to the endpoint, retrying failed attempts with the endpoint backoff.
+// Every attempt builds a fresh request, and the context cancels the remaining attempts.
+func submitWebhook(ctx context.Context, endpoint WebhookEndpoint, postBody []byte) (response []byte, err error) {
+	maxAttempts := endpoint.maxAttempts()
 
-	postBody, err := json.Marshal(payload)
-	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	var attempt int
+	for attempt = 1; attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			recordWebhookRetry(endpoint.URL)
+		}
+		if response, err = postWebhook(ctx, endpoint, postBody); err == nil {
+			logrus.Infof("Successfully submitted webhook on attempt %d", attempt)
+			return response, nil
+		}
+		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt, err)
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, pkgError.WebhookError(fmt.Sprintf("webhook submit cancelled after %d attempts: %v", attempt, err))
+		case <-time.After(endpoint.backoff(attempt)):
+		}
 	}
 
-	req, err := http.NewRequest(http.MethodPost, url, bytes.NewBuffer(postBody))
+	return nil, pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", maxAttempts, err))
+}
+
+// postWebhook performs a single signed delivery attempt of the body to the endpoint and returns the response body
+func postWebhook(ctx context.Context, endpoint WebhookEndpoint, postBody []byte) ([]byte, error) {
+	requestBody, contentEncoding, err := encodeWebhookBody(endpoint, postBody)
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
+		return nil, pkgError.WebhookError(fmt.Sprintf("error when compress body %v", err))
 	}
 
-	secretKey := []byte(config.WhatsappWebhookSecret)
-	signature, err := getMessageDigestOrSignature(postBody, secretKey)
+	ctx, cancel := context.WithTimeout(ctx, endpoint.timeout())
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(requestBody))
 	if err != nil {
-		return pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+		return nil, pkgError.WebhookError(fmt.Sprintf("error when create http object %v", err))
 	}
 
+	endpoint.setHeaders(req)
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", signature))
-
-	var attempt int
-	var maxAttempts = 5
-	var sleepDuration = 1 * time.Second
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	// signatures always cover the uncompressed JSON body
+	if err = signWebhookRequest(req, endpoint, postBody); err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("error when create signature %v", err))
+	}
 
-	for attempt = 0; attempt < maxAttempts; attempt++ {
-		if _, err = client.Do(req); err == nil {
-			logrus.Infof("Successfully submitted webhook on attempt %d", attempt+1)
-			return nil
+	var response []byte
+	startedAt := time.Now()
+	resp, err := endpoint.httpClient().Do(req)
+	if err == nil {
+		defer resp.Body.Close()
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			err = pkgError.WebhookError(fmt.Sprintf("webhook responded with status %s", resp.Status))
+		} else {
+			response, _ = io.ReadAll(io.LimitReader(resp.Body, webhookResponseMaxSize))
 		}
-		logrus.Warnf("Attempt %d to submit webhook failed: %v", attempt+1, err)
-		time.Sleep(sleepDuration)
-		sleepDuration *= 2
 	}
+	recordWebhookAttempt(endpoint.URL, time.Since(startedAt), err)
 
-	return pkgError.WebhookError(fmt.Sprintf("error when submit webhook after %d attempts: %v", attempt, err))
+	return response, err
 }
 
 // forwardReceiptToWebhook is a helper function to forward receipt event to webhook url
@@ -184,7 +343,11 @@ func forwardReceiptToWebhook(evt *events.Receipt) error {
 	if err != nil {
 		return err
 	}
-	return forwardEventToWebhook("receipt event", payload)
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "receipt",
+		Payload: payload,
+		Source:  &webhookEventSource{Chat: evt.Chat, Sender: evt.Sender},
+	})
 }
 
 func createReceiptPayload(evt *events.Receipt) (map[string]interface{}, error) {
@@ -203,11 +366,26 @@ func createReceiptPayload(evt *events.Receipt) (map[string]interface{}, error) {
 		body["sender"] = sender
 	}
 
-	// Add receipt type (delivered/read)
+	// Add the chat of the message and who the receipt is from
+	if !evt.Chat.IsEmpty() {
+		body["chat"] = evt.Chat.String()
+	}
+	if !evt.Sender.IsEmpty() {
+		body["recipient"] = evt.Sender.ToNonAD().String()
+	}
+
+	// In groups, add who sent the message the receipt is about
+	if evt.IsGroup && !evt.MessageSender.IsEmpty() {
+		body["message_sender"] = evt.MessageSender.ToNonAD().String()
+	}
+
+	// Add receipt type (delivered/read/played)
 	var receiptType string
 	switch evt.Type {
 	case types.ReceiptTypeRead, types.ReceiptTypeReadSelf:
 		receiptType = "read"
+	case types.ReceiptTypePlayed, types.ReceiptTypePlayedSelf:
+		receiptType = "played"
 	case types.ReceiptTypeDelivered:
 		receiptType = "delivered"
 	default:
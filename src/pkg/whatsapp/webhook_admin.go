@@ -0,0 +1,158 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+var webhookEndpointMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_endpoints (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		url        TEXT    NOT NULL UNIQUE,
+		config     BLOB    NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+}
+
+// storedWebhookEndpoint is how a registered endpoint is persisted, including the settings the
+// WebhookEndpoint JSON representation hides
+type storedWebhookEndpoint struct {
+	WebhookEndpoint
+	Secret      string            `json:"secret,omitempty"`
+	PrivateKey  string            `json:"private_key,omitempty"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	BearerToken string            `json:"bearer_token,omitempty"`
+}
+
+func newStoredWebhookEndpoint(endpoint WebhookEndpoint) storedWebhookEndpoint {
+	return storedWebhookEndpoint{
+		WebhookEndpoint: endpoint,
+		Secret:          endpoint.Secret,
+		PrivateKey:      endpoint.PrivateKey,
+		Headers:         endpoint.Headers,
+		BearerToken:     endpoint.BearerToken,
+	}
+}
+
+func (stored storedWebhookEndpoint) endpoint() WebhookEndpoint {
+	endpoint := stored.WebhookEndpoint
+	endpoint.Secret = stored.Secret
+	endpoint.PrivateKey = stored.PrivateKey
+	endpoint.Headers = stored.Headers
+	endpoint.BearerToken = stored.BearerToken
+	return endpoint
+}
+
+// loadStoredWebhookEndpoints adds the endpoints registered through the admin API to the configured ones
+func loadStoredWebhookEndpoints() error {
+	rows, err := appStore.Query(`SELECT id, config FROM webhook_endpoints ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("failed to load webhook endpoints: %w", err)
+	}
+	defer rows.Close()
+
+	var endpoints []WebhookEndpoint
+	for rows.Next() {
+		var (
+			id     int64
+			config []byte
+			stored storedWebhookEndpoint
+		)
+		if err = rows.Scan(&id, &config); err != nil {
+			return fmt.Errorf("failed to load webhook endpoints: %w", err)
+		}
+		if err = json.Unmarshal(config, &stored); err != nil {
+			logrus.Errorf("Skip stored webhook endpoint %d: %v", id, err)
+			continue
+		}
+
+		endpoint := stored.endpoint()
+		endpoint.ID = id
+		if err = endpoint.prepare(); err != nil {
+			logrus.Errorf("Skip stored webhook endpoint %s: %v", endpoint.URL, err)
+			continue
+		}
+		endpoints = append(endpoints, endpoint)
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed to load webhook endpoints: %w", err)
+	}
+
+	webhookEndpointsMu.Lock()
+	webhookEndpoints = append(webhookEndpoints, endpoints...)
+	webhookEndpointsMu.Unlock()
+	return nil
+}
+
+// RegisterWebhookEndpoint adds a webhook endpoint at runtime and persists it so it survives restarts
+func RegisterWebhookEndpoint(endpoint WebhookEndpoint) (WebhookEndpoint, error) {
+	if appStore == nil {
+		return endpoint, errStoreNotInitialized
+	}
+
+	endpoint.ID = 0
+	endpoint.URL = strings.TrimSpace(endpoint.URL)
+	if err := endpoint.prepare(); err != nil {
+		return endpoint, pkgError.ValidationError(err.Error())
+	}
+
+	config, err := json.Marshal(newStoredWebhookEndpoint(endpoint))
+	if err != nil {
+		return endpoint, err
+	}
+
+	webhookEndpointsMu.Lock()
+	defer webhookEndpointsMu.Unlock()
+
+	for _, existing := range webhookEndpoints {
+		if existing.URL == endpoint.URL {
+			return endpoint, pkgError.ValidationError(fmt.Sprintf("webhook %s is already registered", endpoint.URL))
+		}
+	}
+
+	result, err := appStore.Exec(`INSERT INTO webhook_endpoints (url, config, created_at) VALUES (?, ?, ?)`, endpoint.URL, config, time.Now().Unix())
+	if err != nil {
+		return endpoint, err
+	}
+	if endpoint.ID, err = result.LastInsertId(); err != nil {
+		return endpoint, err
+	}
+
+	webhookEndpoints = append(webhookEndpoints, endpoint)
+	logrus.Infof("Registered webhook endpoint %d: %s", endpoint.ID, endpoint.URL)
+	return endpoint, nil
+}
+
+// ListWebhookEndpoints returns every webhook endpoint, the ones from the config first
+func ListWebhookEndpoints() []WebhookEndpoint {
+	return currentWebhookEndpoints()
+}
+
+// DeleteWebhookEndpoint removes an endpoint registered through the admin API.
+// Endpoints from the flags or the config file can only be removed from there.
+func DeleteWebhookEndpoint(id int64) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	webhookEndpointsMu.Lock()
+	defer webhookEndpointsMu.Unlock()
+
+	for i, endpoint := range webhookEndpoints {
+		if endpoint.ID != id {
+			continue
+		}
+		if _, err := appStore.Exec(`DELETE FROM webhook_endpoints WHERE id = ?`, id); err != nil {
+			return err
+		}
+		webhookEndpoints = append(webhookEndpoints[:i:i], webhookEndpoints[i+1:]...)
+		logrus.Infof("Removed webhook endpoint %d: %s", id, endpoint.URL)
+		return nil
+	}
+	return pkgError.ValidationError(fmt.Sprintf("webhook endpoint %d not found", id))
+}
@@ -0,0 +1,56 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterWebhookEndpoint(t *testing.T) {
+	originalPath, originalStore, originalEndpoints := config.PathStoreDB, appStore, webhookEndpoints
+	defer func() {
+		config.PathStoreDB, appStore, webhookEndpoints = originalPath, originalStore, originalEndpoints
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	webhookEndpoints = []WebhookEndpoint{{URL: "https://static.example.com"}}
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	registered, err := RegisterWebhookEndpoint(WebhookEndpoint{
+		URL:         "https://runtime.example.com",
+		Events:      []string{"message"},
+		Secret:      "runtime-secret",
+		BearerToken: "token",
+		Headers:     map[string]string{"X-Tenant-ID": "acme"},
+		Rules:       []WebhookRule{{ChatType: "group"}},
+	})
+	assert.NoError(t, err)
+	assert.Greater(t, registered.ID, int64(0))
+
+	_, err = RegisterWebhookEndpoint(WebhookEndpoint{URL: "https://static.example.com"})
+	assert.Error(t, err, "duplicated url must be rejected")
+	_, err = RegisterWebhookEndpoint(WebhookEndpoint{URL: "https://invalid.example.com", Signature: "md5"})
+	assert.Error(t, err)
+
+	// the registered endpoint survives a restart, secrets included
+	assert.NoError(t, appStore.Close())
+	webhookEndpoints = []WebhookEndpoint{{URL: "https://static.example.com"}}
+	assert.NoError(t, InitStore())
+	assert.NoError(t, loadStoredWebhookEndpoints())
+
+	endpoints := ListWebhookEndpoints()
+	assert.Len(t, endpoints, 2)
+	assert.Equal(t, registered.ID, endpoints[1].ID)
+	assert.Equal(t, "runtime-secret", endpoints[1].Secret)
+	assert.Equal(t, "token", endpoints[1].BearerToken)
+	assert.Equal(t, map[string]string{"X-Tenant-ID": "acme"}, endpoints[1].Headers)
+	assert.Equal(t, []string{"message"}, endpoints[1].Events)
+
+	assert.NoError(t, DeleteWebhookEndpoint(registered.ID))
+	assert.Error(t, DeleteWebhookEndpoint(registered.ID))
+	assert.Len(t, ListWebhookEndpoints(), 1)
+}
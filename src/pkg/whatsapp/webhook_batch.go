@@ -0,0 +1,113 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+// Defaults of the batching mode when the endpoint leaves them empty
+const (
+	webhookBatchDefaultSize     = 100
+	webhookBatchDefaultInterval = 5 * time.Second
+)
+
+// webhookBatchEventType is the event type of batched deliveries stored in the outbox
+const webhookBatchEventType = "batch"
+
+// WebhookBatchConfig enables the batching mode of an endpoint: events are delivered as one
+// `{"events": [...]}` request once Size events are buffered or Interval elapsed, whichever comes first
+type WebhookBatchConfig struct {
+	Size     int           `json:"size,omitempty" mapstructure:"size"`
+	Interval time.Duration `json:"interval,omitempty" mapstructure:"interval"`
+}
+
+type webhookBatch struct {
+	events []json.RawMessage
+	timer  *time.Timer
+}
+
+var (
+	webhookBatchesMu sync.Mutex
+	webhookBatches   = make(map[string]*webhookBatch)
+)
+
+// prepareBatch fills in the defaults of the batching mode
+func (endpoint *WebhookEndpoint) prepareBatch() error {
+	if endpoint.Batch == nil {
+		return nil
+	}
+	if endpoint.Batch.Size < 0 || endpoint.Batch.Interval < 0 {
+		return fmt.Errorf("batch size and interval can't be negative")
+	}
+	if endpoint.Batch.Size == 0 {
+		endpoint.Batch.Size = webhookBatchDefaultSize
+	}
+	if endpoint.Batch.Interval == 0 {
+		endpoint.Batch.Interval = webhookBatchDefaultInterval
+	}
+	return nil
+}
+
+// enqueueWebhookBatch buffers a rendered event for a batching endpoint, flushing the batch when it's full
+func enqueueWebhookBatch(endpoint WebhookEndpoint, postBody []byte) {
+	webhookBatchesMu.Lock()
+	batch, ok := webhookBatches[endpoint.URL]
+	if !ok {
+		batch = &webhookBatch{}
+		webhookBatches[endpoint.URL] = batch
+		batch.timer = time.AfterFunc(endpoint.Batch.Interval, func() {
+			flushWebhookBatch(endpoint.URL)
+		})
+	}
+	batch.events = append(batch.events, postBody)
+	full := len(batch.events) >= endpoint.Batch.Size
+	webhookBatchesMu.Unlock()
+
+	if full {
+		flushWebhookBatch(endpoint.URL)
+	}
+}
+
+// flushWebhookBatch delivers the buffered events of the url as a single signed request
+func flushWebhookBatch(url string) {
+	webhookBatchesMu.Lock()
+	batch, ok := webhookBatches[url]
+	if ok {
+		delete(webhookBatches, url)
+		batch.timer.Stop()
+	}
+	webhookBatchesMu.Unlock()
+
+	if !ok || len(batch.events) == 0 {
+		return
+	}
+
+	if err := deliverWebhookBatch(url, batch.events); err != nil {
+		logrus.Errorf("Failed to deliver webhook batch of %d events to %s: %v", len(batch.events), url, err)
+	}
+}
+
+func deliverWebhookBatch(url string, events []json.RawMessage) error {
+	postBody, err := json.Marshal(map[string]interface{}{"events": events})
+	if err != nil {
+		return pkgError.WebhookError(fmt.Sprintf("Failed to marshal batch: %v", err))
+	}
+
+	_, err = submitWebhook(context.Background(), webhookEndpointByURL(url), postBody)
+	if err == nil {
+		logrus.Infof("Webhook batch of %d events delivered to %s", len(events), url)
+		return nil
+	}
+
+	if errQueue := queueWebhookRetry(webhookBatchEventType, url, postBody, err); errQueue != nil {
+		return fmt.Errorf("%w (queue retry: %v)", err, errQueue)
+	}
+	logrus.Warnf("Webhook batch to %s failed, queued in outbox for retry: %v", url, err)
+	return nil
+}
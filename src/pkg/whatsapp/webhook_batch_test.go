@@ -0,0 +1,52 @@
+package whatsapp
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookBatch(t *testing.T) {
+	received := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.NotEmpty(t, r.Header.Get("X-Webhook-Signature"))
+		received <- body
+	}))
+	defer server.Close()
+
+	endpoint := WebhookEndpoint{URL: server.URL, Batch: &WebhookBatchConfig{Size: 2, Interval: time.Minute}}
+	assert.NoError(t, endpoint.prepare())
+
+	enqueueWebhookBatch(endpoint, []byte(`{"id":1}`))
+	enqueueWebhookBatch(endpoint, []byte(`{"id":2}`))
+
+	select {
+	case body := <-received:
+		var batch struct {
+			Events []json.RawMessage `json:"events"`
+		}
+		assert.NoError(t, json.Unmarshal(body, &batch))
+		assert.Len(t, batch.Events, 2)
+		assert.JSONEq(t, `{"id":1}`, string(batch.Events[0]))
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch was not flushed when full")
+	}
+
+	t.Run("should flush after the interval", func(t *testing.T) {
+		endpoint.Batch.Interval = 50 * time.Millisecond
+		enqueueWebhookBatch(endpoint, []byte(`{"id":3}`))
+
+		select {
+		case body := <-received:
+			assert.JSONEq(t, `{"events":[{"id":3}]}`, string(body))
+		case <-time.After(5 * time.Second):
+			t.Fatal("batch was not flushed after the interval")
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardBlocklistToWebhook is a helper function to forward a blocklist change to webhook url
+func forwardBlocklistToWebhook(evt *events.Blocklist) error {
+	body := createBlocklistPayload(evt)
+
+	// a modify action carries no change, the whole blocklist has to be fetched again
+	if evt.Action == events.BlocklistActionModify {
+		if blocklist, err := cli.GetBlocklist(); err != nil {
+			logrus.Warnf("Failed to get the blocklist: %v", err)
+		} else {
+			body["blocklist"] = jidStrings(blocklist.JIDs)
+		}
+	}
+	return forwardEventToWebhook("blocklist", body)
+}
+
+func createBlocklistPayload(evt *events.Blocklist) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "blocklist"
+
+	if evt.Action == events.BlocklistActionModify {
+		body["action"] = "modify"
+		return body
+	}
+
+	blocked, unblocked := []string{}, []string{}
+	for _, change := range evt.Changes {
+		switch change.Action {
+		case events.BlocklistChangeActionBlock:
+			blocked = append(blocked, change.JID.ToNonAD().String())
+		case events.BlocklistChangeActionUnblock:
+			unblocked = append(unblocked, change.JID.ToNonAD().String())
+		}
+	}
+
+	switch {
+	case len(unblocked) == 0:
+		body["action"] = "block"
+	case len(blocked) == 0:
+		body["action"] = "unblock"
+	default:
+		body["action"] = "change"
+	}
+	body["blocked"] = blocked
+	body["unblocked"] = unblocked
+	return body
+}
+
+func jidStrings(jids []types.JID) []string {
+	result := make([]string, 0, len(jids))
+	for _, jid := range jids {
+		result = append(result, jid.ToNonAD().String())
+	}
+	return result
+}
@@ -0,0 +1,55 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateBlocklistPayload(t *testing.T) {
+	first := types.NewJID("628123456789", types.DefaultUserServer)
+	second := types.NewADJID("628987654321", 0, 1)
+
+	testCases := []struct {
+		name     string
+		evt      *events.Blocklist
+		expected map[string]interface{}
+	}{
+		{
+			name: "should list the blocked contacts",
+			evt:  &events.Blocklist{Changes: []events.BlocklistChange{{JID: first, Action: events.BlocklistChangeActionBlock}}},
+			expected: map[string]interface{}{
+				"event_type": "blocklist",
+				"action":     "block",
+				"blocked":    []string{"628123456789@s.whatsapp.net"},
+				"unblocked":  []string{},
+			},
+		},
+		{
+			name: "should report mixed changes",
+			evt: &events.Blocklist{Changes: []events.BlocklistChange{
+				{JID: first, Action: events.BlocklistChangeActionBlock},
+				{JID: second, Action: events.BlocklistChangeActionUnblock},
+			}},
+			expected: map[string]interface{}{
+				"event_type": "blocklist",
+				"action":     "change",
+				"blocked":    []string{"628123456789@s.whatsapp.net"},
+				"unblocked":  []string{"628987654321@s.whatsapp.net"},
+			},
+		},
+		{
+			name:     "should report a replaced blocklist",
+			evt:      &events.Blocklist{Action: events.BlocklistActionModify},
+			expected: map[string]interface{}{"event_type": "blocklist", "action": "modify"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, createBlocklistPayload(tc.evt))
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"time"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardCallToWebhook is a helper function to forward call events to webhook url
+func forwardCallToWebhook(rawEvt interface{}) error {
+	payload, meta := createCallPayload(rawEvt)
+	if payload == nil {
+		return nil
+	}
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "call",
+		Payload: payload,
+		Source:  &webhookEventSource{Chat: meta.From, Sender: meta.CallCreator},
+	})
+}
+
+// createCallPayload builds the `call` payload of an offer, accept, reject or terminate event
+func createCallPayload(rawEvt interface{}) (map[string]interface{}, types.BasicCallMeta) {
+	body := make(map[string]interface{})
+	body["event_type"] = "call"
+
+	var meta types.BasicCallMeta
+	switch evt := rawEvt.(type) {
+	case *events.CallOffer:
+		meta = evt.BasicCallMeta
+		body["action"] = "offer"
+		body["is_video"] = hasCallChild(evt.Data, "video")
+		addCallRemoteMeta(body, evt.CallRemoteMeta)
+	case *events.CallOfferNotice:
+		meta = evt.BasicCallMeta
+		body["action"] = "offer"
+		body["is_video"] = evt.Media == "video"
+		body["is_group"] = evt.Type == "group"
+	case *events.CallAccept:
+		meta = evt.BasicCallMeta
+		body["action"] = "accept"
+		addCallRemoteMeta(body, evt.CallRemoteMeta)
+	case *events.CallReject:
+		meta = evt.BasicCallMeta
+		body["action"] = "reject"
+	case *events.CallTerminate:
+		meta = evt.BasicCallMeta
+		body["action"] = "terminate"
+		if evt.Reason != "" {
+			body["reason"] = evt.Reason
+		}
+	default:
+		return nil, meta
+	}
+
+	body["call_id"] = meta.CallID
+	if !meta.From.IsEmpty() {
+		body["from"] = meta.From.ToNonAD().String()
+	}
+	if !meta.CallCreator.IsEmpty() {
+		body["call_creator"] = meta.CallCreator.ToNonAD().String()
+	}
+	if !meta.Timestamp.IsZero() {
+		body["timestamp"] = meta.Timestamp.Format(time.RFC3339)
+	}
+	return body, meta
+}
+
+func addCallRemoteMeta(body map[string]interface{}, meta types.CallRemoteMeta) {
+	if meta.RemotePlatform != "" {
+		body["remote_platform"] = meta.RemotePlatform
+	}
+	if meta.RemoteVersion != "" {
+		body["remote_version"] = meta.RemoteVersion
+	}
+}
+
+func hasCallChild(node *waBinary.Node, tag string) bool {
+	if node == nil {
+		return false
+	}
+	_, ok := node.GetOptionalChildByTag(tag)
+	return ok
+}
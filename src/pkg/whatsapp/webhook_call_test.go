@@ -0,0 +1,39 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateCallPayload(t *testing.T) {
+	caller := types.NewADJID("628123456789", 0, 1)
+	meta := types.BasicCallMeta{From: caller, CallCreator: caller, CallID: "CALL1", Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	payload, _ := createCallPayload(&events.CallOffer{
+		BasicCallMeta:  meta,
+		CallRemoteMeta: types.CallRemoteMeta{RemotePlatform: "android"},
+		Data:           &waBinary.Node{Tag: "offer", Content: []waBinary.Node{{Tag: "audio"}, {Tag: "video"}}},
+	})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":      "call",
+		"action":          "offer",
+		"is_video":        true,
+		"remote_platform": "android",
+		"call_id":         "CALL1",
+		"from":            "628123456789@s.whatsapp.net",
+		"call_creator":    "628123456789@s.whatsapp.net",
+		"timestamp":       "2025-01-02T03:04:05Z",
+	}, payload)
+
+	payload, _ = createCallPayload(&events.CallTerminate{BasicCallMeta: meta, Reason: "timeout"})
+	assert.Equal(t, "terminate", payload["action"])
+	assert.Equal(t, "timeout", payload["reason"])
+
+	payload, _ = createCallPayload(&events.CallRelayLatency{BasicCallMeta: meta})
+	assert.Nil(t, payload)
+}
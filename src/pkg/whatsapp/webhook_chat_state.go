@@ -0,0 +1,86 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardChatStateToWebhook is a helper function to forward a chat archived, pinned, muted or a message starred
+// on the phone or another linked device to webhook url
+func forwardChatStateToWebhook(rawEvt interface{}) error {
+	payload, chat := createChatStatePayload(rawEvt)
+	if payload == nil {
+		return nil
+	}
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "chat_state",
+		Payload: payload,
+		Source:  &webhookEventSource{Chat: chat},
+	})
+}
+
+// createChatStatePayload builds the `chat_state` payload of an app state change. The full sync after pairing,
+// which replays the state of every chat, is skipped.
+func createChatStatePayload(rawEvt interface{}) (map[string]interface{}, types.JID) {
+	body := make(map[string]interface{})
+	body["event_type"] = "chat_state"
+
+	var (
+		chat      types.JID
+		timestamp time.Time
+	)
+	switch evt := rawEvt.(type) {
+	case *events.Archive:
+		if evt.FromFullSync {
+			return nil, chat
+		}
+		chat, timestamp = evt.JID, evt.Timestamp
+		body["action"] = toggleAction(evt.Action.GetArchived(), "archive", "unarchive")
+	case *events.Pin:
+		if evt.FromFullSync {
+			return nil, chat
+		}
+		chat, timestamp = evt.JID, evt.Timestamp
+		body["action"] = toggleAction(evt.Action.GetPinned(), "pin", "unpin")
+	case *events.Mute:
+		if evt.FromFullSync {
+			return nil, chat
+		}
+		chat, timestamp = evt.JID, evt.Timestamp
+		body["action"] = toggleAction(evt.Action.GetMuted(), "mute", "unmute")
+		if evt.Action.GetMuted() {
+			// the end of the mute is in milliseconds, -1 when the chat is muted forever
+			if mutedUntil := evt.Action.GetMuteEndTimestamp(); mutedUntil > 0 {
+				body["muted_until"] = time.UnixMilli(mutedUntil).UTC().Format(time.RFC3339)
+			} else {
+				body["muted_forever"] = true
+			}
+		}
+	case *events.Star:
+		if evt.FromFullSync {
+			return nil, chat
+		}
+		chat, timestamp = evt.ChatJID, evt.Timestamp
+		body["action"] = toggleAction(evt.Action.GetStarred(), "star", "unstar")
+		body["message_id"] = evt.MessageID
+		body["from_me"] = evt.IsFromMe
+		if !evt.SenderJID.IsEmpty() {
+			body["message_sender"] = evt.SenderJID.ToNonAD().String()
+		}
+	default:
+		return nil, chat
+	}
+
+	body["chat"] = chat.String()
+	body["timestamp"] = timestamp.Format(time.RFC3339)
+	return body, chat
+}
+
+func toggleAction(enabled bool, on, off string) string {
+	if enabled {
+		return on
+	}
+	return off
+}
@@ -0,0 +1,73 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waSyncAction"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateChatStatePayload(t *testing.T) {
+	chat := types.NewJID("628123456789", types.DefaultUserServer)
+	timestamp := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	mutedUntil := time.Date(2025, 1, 9, 3, 4, 5, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		evt      interface{}
+		expected map[string]interface{}
+	}{
+		{
+			name: "should report an archived chat",
+			evt:  &events.Archive{JID: chat, Timestamp: timestamp, Action: &waSyncAction.ArchiveChatAction{Archived: proto.Bool(true)}},
+			expected: map[string]interface{}{
+				"event_type": "chat_state",
+				"action":     "archive",
+				"chat":       chat.String(),
+				"timestamp":  "2025-01-02T03:04:05Z",
+			},
+		},
+		{
+			name: "should report the end of a mute",
+			evt: &events.Mute{JID: chat, Timestamp: timestamp, Action: &waSyncAction.MuteAction{
+				Muted:            proto.Bool(true),
+				MuteEndTimestamp: proto.Int64(mutedUntil.UnixMilli()),
+			}},
+			expected: map[string]interface{}{
+				"event_type":  "chat_state",
+				"action":      "mute",
+				"muted_until": "2025-01-09T03:04:05Z",
+				"chat":        chat.String(),
+				"timestamp":   "2025-01-02T03:04:05Z",
+			},
+		},
+		{
+			name: "should report an unstarred message",
+			evt:  &events.Star{ChatJID: chat, MessageID: "MSG1", IsFromMe: true, Timestamp: timestamp, Action: &waSyncAction.StarAction{Starred: proto.Bool(false)}},
+			expected: map[string]interface{}{
+				"event_type": "chat_state",
+				"action":     "unstar",
+				"message_id": "MSG1",
+				"from_me":    true,
+				"chat":       chat.String(),
+				"timestamp":  "2025-01-02T03:04:05Z",
+			},
+		},
+		{
+			name:     "should skip the full sync",
+			evt:      &events.Pin{JID: chat, Action: &waSyncAction.PinAction{Pinned: proto.Bool(true)}, FromFullSync: true},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload, _ := createChatStatePayload(tc.evt)
+			assert.Equal(t, tc.expected, payload)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package whatsapp
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/spf13/viper"
+)
+
+// WebhookEndpoint describes a single webhook target and the event types it subscribes to
+type WebhookEndpoint struct {
+	ID       int64         `json:"id,omitempty" mapstructure:"-"` // set on endpoints registered through the admin API
+	URL      string        `json:"url" mapstructure:"url"`
+	Events   []string      `json:"events,omitempty" mapstructure:"events"`
+	Rules    []WebhookRule `json:"rules,omitempty" mapstructure:"rules"`
+	Template string        `json:"template,omitempty" mapstructure:"template"` // text/template reshaping the JSON payload
+
+	Signature  string `json:"signature,omitempty" mapstructure:"signature"` // sha256 (default), sha512 or ed25519
+	Secret     string `json:"-" mapstructure:"secret"`                      // HMAC secret, defaults to --webhook-secret
+	PrivateKey string `json:"-" mapstructure:"private_key"`                 // ed25519 key, PKCS#8 PEM or base64 seed
+
+	TLS *WebhookTLSConfig `json:"tls,omitempty" mapstructure:"tls"`
+
+	Headers     map[string]string `json:"-" mapstructure:"headers"`      // static headers, e.g. a tenant id
+	BearerToken string            `json:"-" mapstructure:"bearer_token"` // sent as "Authorization: Bearer <token>"
+
+	Batch       *WebhookBatchConfig       `json:"batch,omitempty" mapstructure:"batch"`
+	InlineMedia *WebhookInlineMediaConfig `json:"inline_media,omitempty" mapstructure:"inline_media"`
+
+	GzipThreshold int `json:"gzip_threshold,omitempty" mapstructure:"gzip_threshold"` // bytes, bodies from this size are gzipped, 0 disables
+
+	Timeout     time.Duration `json:"timeout,omitempty" mapstructure:"timeout"`           // per attempt, default 10s
+	MaxAttempts int           `json:"max_attempts,omitempty" mapstructure:"max_attempts"` // before queueing in the outbox, default 5
+	Backoff     time.Duration `json:"backoff,omitempty" mapstructure:"backoff"`           // delay after the first failed attempt, default 1s
+	MaxBackoff  time.Duration `json:"max_backoff,omitempty" mapstructure:"max_backoff"`   // default 30s
+
+	template   *template.Template
+	privateKey ed25519.PrivateKey
+	client     *http.Client
+}
+
+// AcceptsEvent reports whether the endpoint subscribed to the given event type.
+// An endpoint without any event type (or with "*") receives every event.
+func (endpoint WebhookEndpoint) AcceptsEvent(eventType string) bool {
+	if len(endpoint.Events) == 0 {
+		return true
+	}
+	for _, event := range endpoint.Events {
+		if event == "*" || strings.EqualFold(event, eventType) {
+			return true
+		}
+	}
+	return false
+}
+
+// prepare compiles the optional settings of the endpoint
+func (endpoint *WebhookEndpoint) prepare() (err error) {
+	if endpoint.Template != "" {
+		if endpoint.template, err = compileWebhookTemplate(endpoint.Template); err != nil {
+			return fmt.Errorf("invalid template: %w", err)
+		}
+	}
+	if err = endpoint.prepareSignature(); err != nil {
+		return err
+	}
+	if err = endpoint.prepareBatch(); err != nil {
+		return err
+	}
+	if err = endpoint.prepareInlineMedia(); err != nil {
+		return err
+	}
+	if err = endpoint.prepareDelivery(); err != nil {
+		return err
+	}
+	if err = endpoint.prepareRules(); err != nil {
+		return err
+	}
+	return endpoint.prepareClient()
+}
+
+var (
+	webhookEndpointsMu sync.RWMutex
+	webhookEndpoints   []WebhookEndpoint
+)
+
+// LoadWebhookEndpoints builds the webhook endpoint list from the flat webhook URLs
+// and the optional structured webhook config file (yaml/json), followed by the endpoints
+// registered through the admin API once the store is open
+func LoadWebhookEndpoints() error {
+	var endpoints []WebhookEndpoint
+	for _, url := range config.WhatsappWebhook {
+		if url = strings.TrimSpace(url); url != "" {
+			endpoints = append(endpoints, WebhookEndpoint{URL: url})
+		}
+	}
+
+	if config.WhatsappWebhookConfig != "" {
+		fileEndpoints, err := readWebhookConfigFile(config.WhatsappWebhookConfig)
+		if err != nil {
+			return err
+		}
+		endpoints = append(endpoints, fileEndpoints...)
+	}
+
+	webhookEndpointsMu.Lock()
+	webhookEndpoints = endpoints
+	webhookEndpointsMu.Unlock()
+	if appStore == nil {
+		return nil
+	}
+	return loadStoredWebhookEndpoints()
+}
+
+// readWebhookConfigFile reads the `webhooks` list from the given config file
+func readWebhookConfigFile(path string) ([]WebhookEndpoint, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook config %s: %w", path, err)
+	}
+
+	var endpoints []WebhookEndpoint
+	if err := v.UnmarshalKey("webhooks", &endpoints); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook config %s: %w", path, err)
+	}
+
+	for i := range endpoints {
+		if strings.TrimSpace(endpoints[i].URL) == "" {
+			return nil, fmt.Errorf("webhook config %s: webhook #%d has no url", path, i+1)
+		}
+		if err := endpoints[i].prepare(); err != nil {
+			return nil, fmt.Errorf("webhook config %s: webhook %s: %w", path, endpoints[i].URL, err)
+		}
+	}
+	return endpoints, nil
+}
+
+// hasWebhookEndpoints reports whether at least one webhook endpoint is configured
+func hasWebhookEndpoints() bool {
+	webhookEndpointsMu.RLock()
+	defer webhookEndpointsMu.RUnlock()
+	return len(webhookEndpoints) > 0
+}
+
+// currentWebhookEndpoints returns a snapshot of the configured endpoints
+func currentWebhookEndpoints() []WebhookEndpoint {
+	webhookEndpointsMu.RLock()
+	defer webhookEndpointsMu.RUnlock()
+	return append([]WebhookEndpoint(nil), webhookEndpoints...)
+}
+
+// setHeaders applies the static headers and bearer token of the endpoint to the request.
+// The signature headers are set afterwards so they can't be overridden by the config.
+func (endpoint WebhookEndpoint) setHeaders(req *http.Request) {
+	for key, value := range endpoint.Headers {
+		req.Header.Set(key, value)
+	}
+	if endpoint.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+endpoint.BearerToken)
+	}
+}
+
+// webhookEndpointByURL returns the endpoint settings of the url, used when retrying stored deliveries
+func webhookEndpointByURL(url string) WebhookEndpoint {
+	webhookEndpointsMu.RLock()
+	defer webhookEndpointsMu.RUnlock()
+	for _, endpoint := range webhookEndpoints {
+		if endpoint.URL == url {
+			return endpoint
+		}
+	}
+	return WebhookEndpoint{URL: url}
+}
+
+// webhookEndpointsForEvent returns the endpoints subscribed to the given event type whose rules accept the source
+func webhookEndpointsForEvent(eventType string, source *webhookEventSource) (result []WebhookEndpoint) {
+	webhookEndpointsMu.RLock()
+	defer webhookEndpointsMu.RUnlock()
+	for _, endpoint := range webhookEndpoints {
+		if endpoint.AcceptsEvent(eventType) && endpoint.AcceptsSource(source) {
+			result = append(result, endpoint)
+		}
+	}
+	return result
+}
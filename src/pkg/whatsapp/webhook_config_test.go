@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookEndpointAcceptsEvent(t *testing.T) {
+	tests := []struct {
+		name      string
+		endpoint  WebhookEndpoint
+		eventType string
+		want      bool
+	}{
+		{
+			name:      "should accept every event without filter",
+			endpoint:  WebhookEndpoint{URL: "https://example.com"},
+			eventType: "receipt",
+			want:      true,
+		},
+		{
+			name:      "should accept subscribed event",
+			endpoint:  WebhookEndpoint{URL: "https://example.com", Events: []string{"message", "receipt"}},
+			eventType: "receipt",
+			want:      true,
+		},
+		{
+			name:      "should reject unsubscribed event",
+			endpoint:  WebhookEndpoint{URL: "https://example.com", Events: []string{"message"}},
+			eventType: "receipt",
+			want:      false,
+		},
+		{
+			name:      "should accept wildcard",
+			endpoint:  WebhookEndpoint{URL: "https://example.com", Events: []string{"*"}},
+			eventType: "call",
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.endpoint.AcceptsEvent(tt.eventType))
+		})
+	}
+}
+
+func TestReadWebhookConfigFile(t *testing.T) {
+	dir := t.TempDir()
+
+	yamlPath := filepath.Join(dir, "webhooks.yaml")
+	err := os.WriteFile(yamlPath, []byte("webhooks:\n  - url: https://a.example.com\n    events: [message]\n  - url: https://b.example.com\n"), 0644)
+	assert.NoError(t, err)
+
+	endpoints, err := readWebhookConfigFile(yamlPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []WebhookEndpoint{
+		{URL: "https://a.example.com", Events: []string{"message"}},
+		{URL: "https://b.example.com"},
+	}, endpoints)
+
+	jsonPath := filepath.Join(dir, "webhooks.json")
+	err = os.WriteFile(jsonPath, []byte(`{"webhooks": [{"events": ["receipt"]}]}`), 0644)
+	assert.NoError(t, err)
+
+	_, err = readWebhookConfigFile(jsonPath)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has no url")
+}
+
+func TestWebhookEndpointSetHeaders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "webhooks.yaml")
+	err := os.WriteFile(path, []byte("webhooks:\n  - url: https://a.example.com\n    bearer_token: token-123\n    headers:\n      X-Tenant-ID: acme\n"), 0644)
+	assert.NoError(t, err)
+
+	endpoints, err := readWebhookConfigFile(path)
+	assert.NoError(t, err)
+	assert.Len(t, endpoints, 1)
+
+	req, _ := http.NewRequest(http.MethodPost, endpoints[0].URL, nil)
+	endpoints[0].setHeaders(req)
+	assert.Equal(t, "acme", req.Header.Get("X-Tenant-Id"))
+	assert.Equal(t, "Bearer token-123", req.Header.Get("Authorization"))
+}
@@ -0,0 +1,96 @@
+package whatsapp
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var webhookContactMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_contacts (
+		jid        TEXT    PRIMARY KEY,
+		full_name  TEXT    NOT NULL,
+		first_name TEXT    NOT NULL,
+		updated_at INTEGER NOT NULL
+	)`,
+}
+
+type evtContactName struct {
+	FullName  string `json:"full_name"`
+	FirstName string `json:"first_name"`
+}
+
+// forwardContactToWebhook is a helper function to forward a contact or pushname change to webhook url
+func forwardContactToWebhook(rawEvt interface{}) error {
+	switch evt := rawEvt.(type) {
+	case *events.Contact:
+		newName := evtContactName{FullName: evt.Action.GetFullName(), FirstName: evt.Action.GetFirstName()}
+		oldName := swapWebhookContactName(evt.JID, newName)
+		// the full sync after pairing replays the whole address book, only remember the names
+		if evt.FromFullSync || oldName == newName {
+			return nil
+		}
+		return dispatchWebhookEvent(webhookEvent{
+			Type:    "contact",
+			Payload: createContactPayload(evt, oldName, newName),
+			Source:  &webhookEventSource{Chat: evt.JID, Sender: evt.JID},
+		})
+	case *events.PushName:
+		return dispatchWebhookEvent(webhookEvent{
+			Type:    "pushname",
+			Payload: createPushNamePayload(evt),
+			Source:  &webhookEventSource{Chat: evt.JID, Sender: evt.JID},
+		})
+	default:
+		return nil
+	}
+}
+
+func createContactPayload(evt *events.Contact, oldName, newName evtContactName) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "contact"
+	body["jid"] = evt.JID.ToNonAD().String()
+	body["old"] = oldName
+	body["new"] = newName
+	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+	return body
+}
+
+func createPushNamePayload(evt *events.PushName) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "pushname"
+	body["jid"] = evt.JID.ToNonAD().String()
+	body["old_pushname"] = evt.OldPushName
+	body["new_pushname"] = evt.NewPushName
+	if evt.Message != nil {
+		body["message_id"] = evt.Message.ID
+		body["timestamp"] = evt.Message.Timestamp.Format(time.RFC3339)
+	}
+	return body
+}
+
+// swapWebhookContactName stores the new name of the contact and returns the previous one,
+// whatsmeow overwrites its contact store before emitting the event
+func swapWebhookContactName(jid types.JID, name evtContactName) (previous evtContactName) {
+	if appStore == nil {
+		return previous
+	}
+
+	err := appStore.QueryRow(`SELECT full_name, first_name FROM webhook_contacts WHERE jid = ?`, jid.ToNonAD().String()).
+		Scan(&previous.FullName, &previous.FirstName)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		logrus.Errorf("Failed to read previous name of %s: %v", jid, err)
+	}
+
+	if _, err = appStore.Exec(
+		`INSERT OR REPLACE INTO webhook_contacts (jid, full_name, first_name, updated_at) VALUES (?, ?, ?, ?)`,
+		jid.ToNonAD().String(), name.FullName, name.FirstName, time.Now().Unix(),
+	); err != nil {
+		logrus.Errorf("Failed to store name of %s: %v", jid, err)
+	}
+	return previous
+}
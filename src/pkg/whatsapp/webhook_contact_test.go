@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestSwapWebhookContactName(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	contact := types.NewADJID("628123456789", 0, 1)
+	assert.Equal(t, evtContactName{}, swapWebhookContactName(contact, evtContactName{FullName: "John Doe", FirstName: "John"}))
+	assert.Equal(t, evtContactName{FullName: "John Doe", FirstName: "John"}, swapWebhookContactName(contact.ToNonAD(), evtContactName{FullName: "Johnny"}))
+}
+
+func TestCreatePushNamePayload(t *testing.T) {
+	contact := types.NewADJID("628123456789", 0, 1)
+	payload := createPushNamePayload(&events.PushName{
+		JID:         contact,
+		Message:     &types.MessageInfo{ID: "MSG1", Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)},
+		OldPushName: "John",
+		NewPushName: "Johnny",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":   "pushname",
+		"jid":          "628123456789@s.whatsapp.net",
+		"old_pushname": "John",
+		"new_pushname": "Johnny",
+		"message_id":   "MSG1",
+		"timestamp":    "2025-01-02T03:04:05Z",
+	}, payload)
+}
@@ -0,0 +1,123 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+)
+
+var webhookDeadLetterMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_dead_letters (
+		id         INTEGER PRIMARY KEY AUTOINCREMENT,
+		url        TEXT    NOT NULL,
+		event_type TEXT    NOT NULL,
+		payload    BLOB    NOT NULL,
+		attempts   INTEGER NOT NULL,
+		reason     TEXT    NOT NULL,
+		created_at INTEGER NOT NULL,
+		failed_at  INTEGER NOT NULL
+	)`,
+}
+
+// WebhookDeadLetter is a webhook delivery that permanently failed after every retry
+type WebhookDeadLetter struct {
+	ID        int64           `json:"id"`
+	URL       string          `json:"url"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	Reason    string          `json:"reason"`
+	CreatedAt time.Time       `json:"created_at"`
+	FailedAt  time.Time       `json:"failed_at"`
+}
+
+// moveWebhookOutboxToDeadLetter stores the outbox entry as dead letter and removes it from the outbox
+func moveWebhookOutboxToDeadLetter(entry WebhookOutboxEntry, cause error) error {
+	tx, err := appStore.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO webhook_dead_letters (url, event_type, payload, attempts, reason, created_at, failed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		entry.URL, entry.EventType, []byte(entry.Payload), entry.Attempts, cause.Error(), entry.CreatedAt.Unix(), time.Now().Unix(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err = tx.Exec(`DELETE FROM webhook_outbox WHERE id = ?`, entry.ID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func listWebhookDeadLetters(condition string, args ...interface{}) (letters []WebhookDeadLetter, err error) {
+	rows, err := appStore.Query(`SELECT id, url, event_type, payload, attempts, reason, created_at, failed_at FROM webhook_dead_letters `+condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			letter    WebhookDeadLetter
+			payload   []byte
+			createdAt int64
+			failedAt  int64
+		)
+		if err = rows.Scan(&letter.ID, &letter.URL, &letter.EventType, &payload, &letter.Attempts, &letter.Reason, &createdAt, &failedAt); err != nil {
+			return nil, err
+		}
+		letter.Payload = payload
+		letter.CreatedAt = time.Unix(createdAt, 0)
+		letter.FailedAt = time.Unix(failedAt, 0)
+		letters = append(letters, letter)
+	}
+	return letters, rows.Err()
+}
+
+// ListWebhookDeadLetters returns every permanently failed delivery, latest failure first
+func ListWebhookDeadLetters() ([]WebhookDeadLetter, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+	return listWebhookDeadLetters(`ORDER BY failed_at DESC, id DESC`)
+}
+
+// ReplayWebhookDeadLetter submits the dead letter again and removes it once delivered
+func ReplayWebhookDeadLetter(ctx context.Context, id int64) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	letters, err := listWebhookDeadLetters(`WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+	if len(letters) == 0 {
+		return pkgError.WebhookError(fmt.Sprintf("dead letter %d not found", id))
+	}
+	letter := letters[0]
+
+	recordWebhookRetry(letter.URL)
+	if _, errSubmit := postWebhook(ctx, webhookEndpointByURL(letter.URL), letter.Payload); errSubmit != nil {
+		_, err = appStore.Exec(
+			`UPDATE webhook_dead_letters SET attempts = attempts + 1, reason = ?, failed_at = ? WHERE id = ?`,
+			errSubmit.Error(), time.Now().Unix(), letter.ID,
+		)
+		if err != nil {
+			logrus.Errorf("Failed to update dead letter %d: %v", letter.ID, err)
+		}
+		return pkgError.WebhookError(fmt.Sprintf("failed to replay dead letter %d: %v", letter.ID, errSubmit))
+	}
+
+	logrus.Infof("Dead letter %d replayed to %s", letter.ID, letter.URL)
+	_, err = appStore.Exec(`DELETE FROM webhook_dead_letters WHERE id = ?`, letter.ID)
+	return err
+}
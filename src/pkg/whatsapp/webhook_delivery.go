@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"fmt"
+	"time"
+)
+
+// Delivery defaults of endpoints without their own timeout or backoff settings
+const (
+	webhookDefaultTimeout     = 10 * time.Second
+	webhookDefaultMaxAttempts = 5
+	webhookDefaultBackoff     = 1 * time.Second
+	webhookDefaultMaxBackoff  = 30 * time.Second
+)
+
+// prepareDelivery validates the delivery settings of the endpoint
+func (endpoint *WebhookEndpoint) prepareDelivery() error {
+	if endpoint.Timeout < 0 || endpoint.MaxAttempts < 0 || endpoint.Backoff < 0 || endpoint.MaxBackoff < 0 {
+		return fmt.Errorf("timeout, max_attempts, backoff and max_backoff can't be negative")
+	}
+	return nil
+}
+
+// timeout returns how long a single delivery attempt may take
+func (endpoint WebhookEndpoint) timeout() time.Duration {
+	if endpoint.Timeout > 0 {
+		return endpoint.Timeout
+	}
+	return webhookDefaultTimeout
+}
+
+// maxAttempts returns how many attempts a direct delivery makes before it's queued in the outbox
+func (endpoint WebhookEndpoint) maxAttempts() int {
+	if endpoint.MaxAttempts > 0 {
+		return endpoint.MaxAttempts
+	}
+	return webhookDefaultMaxAttempts
+}
+
+// backoff returns the delay after the given failed attempt (starting at 1), doubling up to the max backoff
+func (endpoint WebhookEndpoint) backoff(attempt int) time.Duration {
+	delay, maxDelay := webhookDefaultBackoff, webhookDefaultMaxBackoff
+	if endpoint.Backoff > 0 {
+		delay = endpoint.Backoff
+	}
+	if endpoint.MaxBackoff > 0 {
+		maxDelay = endpoint.MaxBackoff
+	}
+
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
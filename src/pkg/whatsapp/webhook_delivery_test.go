@@ -0,0 +1,74 @@
+package whatsapp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookEndpointBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, WebhookEndpoint{}.backoff(1))
+	assert.Equal(t, 4*time.Second, WebhookEndpoint{}.backoff(3))
+	assert.Equal(t, webhookDefaultMaxBackoff, WebhookEndpoint{}.backoff(20))
+
+	endpoint := WebhookEndpoint{Backoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	assert.Equal(t, 200*time.Millisecond, endpoint.backoff(2))
+	assert.Equal(t, 300*time.Millisecond, endpoint.backoff(3))
+}
+
+func TestSubmitWebhook(t *testing.T) {
+	t.Run("should send a fresh body on every attempt", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			assert.Equal(t, `{"id":1}`, string(body))
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusBadGateway)
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true}`))
+		}))
+		defer server.Close()
+
+		endpoint := WebhookEndpoint{URL: server.URL, Backoff: time.Millisecond}
+		response, err := submitWebhook(context.Background(), endpoint, []byte(`{"id":1}`))
+		assert.NoError(t, err)
+		assert.Equal(t, `{"ok":true}`, string(response))
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should stop after max attempts and honour the endpoint timeout", func(t *testing.T) {
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		endpoint := WebhookEndpoint{URL: server.URL, Timeout: 20 * time.Millisecond, MaxAttempts: 2, Backoff: time.Millisecond}
+		_, err := submitWebhook(context.Background(), endpoint, []byte(`{}`))
+		assert.Error(t, err)
+		assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+
+	t.Run("should stop retrying when the context is cancelled", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		startedAt := time.Now()
+		_, err := submitWebhook(ctx, WebhookEndpoint{URL: server.URL, Backoff: time.Minute}, []byte(`{}`))
+		assert.Error(t, err)
+		assert.Less(t, time.Since(startedAt), 5*time.Second)
+	})
+}
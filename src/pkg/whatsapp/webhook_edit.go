@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// createEditPayload builds the payload of an edited message, nil when the event isn't an edit
+func createEditPayload(evt *events.Message) map[string]interface{} {
+	protocolMessage := evt.Message.GetProtocolMessage()
+	if protocolMessage == nil || protocolMessage.GetType() != waE2E.ProtocolMessage_MESSAGE_EDIT {
+		return nil
+	}
+
+	body := make(map[string]interface{})
+	body["event_type"] = "edit"
+	body["chat"] = evt.Info.Chat.String()
+	body["from"] = evt.Info.SourceString()
+	body["from_me"] = evt.Info.IsFromMe
+	body["message_id"] = protocolMessage.GetKey().GetID()
+	body["text"] = webhookMessageText(protocolMessage.GetEditedMessage())
+
+	editedAt := evt.Info.Timestamp
+	if timestampMS := protocolMessage.GetTimestampMS(); timestampMS > 0 {
+		editedAt = time.UnixMilli(timestampMS)
+	}
+	body["edited_at"] = editedAt.UTC().Format(time.RFC3339)
+	return body
+}
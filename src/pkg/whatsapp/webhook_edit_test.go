@@ -0,0 +1,43 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateEditPayload(t *testing.T) {
+	sender := types.NewJID("628123456789", types.DefaultUserServer)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: sender, Sender: sender},
+		ID:            "EDIT1",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	editedAt := time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC)
+
+	payload := createEditPayload(&events.Message{Info: info, IsEdit: true, Message: &waE2E.Message{
+		ProtocolMessage: &waE2E.ProtocolMessage{
+			Type:          waE2E.ProtocolMessage_MESSAGE_EDIT.Enum(),
+			Key:           &waCommon.MessageKey{ID: proto.String("ORIGINAL1")},
+			EditedMessage: &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String("fixed typo")}},
+			TimestampMS:   proto.Int64(editedAt.UnixMilli()),
+		},
+	}})
+	assert.Equal(t, map[string]interface{}{
+		"event_type": "edit",
+		"chat":       sender.String(),
+		"from":       sender.String(),
+		"from_me":    false,
+		"message_id": "ORIGINAL1",
+		"text":       "fixed typo",
+		"edited_at":  "2025-01-02T03:04:00Z",
+	}, payload)
+
+	assert.Nil(t, createEditPayload(&events.Message{Info: info, Message: &waE2E.Message{Conversation: proto.String("hello")}}))
+}
@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+type evtEphemeral struct {
+	Expiration       uint32 `json:"expiration"` // seconds
+	SettingTimestamp string `json:"setting_timestamp,omitempty"`
+	ExpiresAt        string `json:"expires_at,omitempty"`
+}
+
+// buildEventEphemeral returns the disappearing messages settings of the message, nil when it won't disappear
+func buildEventEphemeral(evt *events.Message) *evtEphemeral {
+	contextInfo := getContextInfo(evt.Message)
+	expiration := contextInfo.GetExpiration()
+	if !evt.IsEphemeral && expiration == 0 {
+		return nil
+	}
+
+	ephemeral := &evtEphemeral{Expiration: expiration}
+	if settingTimestamp := contextInfo.GetEphemeralSettingTimestamp(); settingTimestamp > 0 {
+		ephemeral.SettingTimestamp = time.Unix(settingTimestamp, 0).UTC().Format(time.RFC3339)
+	}
+	if expiration > 0 && !evt.Info.Timestamp.IsZero() {
+		ephemeral.ExpiresAt = evt.Info.Timestamp.Add(time.Duration(expiration) * time.Second).UTC().Format(time.RFC3339)
+	}
+	return ephemeral
+}
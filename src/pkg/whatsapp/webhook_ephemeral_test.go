@@ -0,0 +1,33 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildEventEphemeral(t *testing.T) {
+	info := types.MessageInfo{Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}
+
+	ephemeral := buildEventEphemeral(&events.Message{Info: info, IsEphemeral: true, Message: &waE2E.Message{
+		ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text: proto.String("gone in a day"),
+			ContextInfo: &waE2E.ContextInfo{
+				Expiration:                proto.Uint32(86400),
+				EphemeralSettingTimestamp: proto.Int64(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC).Unix()),
+			},
+		},
+	}})
+	assert.Equal(t, &evtEphemeral{
+		Expiration:       86400,
+		SettingTimestamp: "2025-01-01T00:00:00Z",
+		ExpiresAt:        "2025-01-03T03:04:05Z",
+	}, ephemeral)
+
+	assert.Nil(t, buildEventEphemeral(&events.Message{Info: info, Message: &waE2E.Message{Conversation: proto.String("hello")}}))
+}
@@ -0,0 +1,191 @@
+package whatsapp
+
+import (
+	"time"
+
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardGroupToWebhook is a helper function to forward group changes to webhook url
+func forwardGroupToWebhook(rawEvt interface{}) error {
+	payload, source := createGroupPayload(rawEvt)
+	if payload == nil {
+		return nil
+	}
+	return dispatchWebhookEvent(webhookEvent{Type: "group", Payload: payload, Source: source})
+}
+
+// GroupLeft drops the cached group list and forwards the leave of this account as a `group` event with the `leave`
+// action, whatsmeow doesn't emit the changes we make ourselves
+func GroupLeft(group types.JID) {
+	invalidateJoinedGroups()
+	if !hasWebhookEndpoints() || cli == nil || cli.Store.ID == nil {
+		return
+	}
+
+	evt := groupLeftEvent(group, cli.Store.ID.ToNonAD(), time.Now())
+	enqueueWebhook("group", func() error {
+		return forwardGroupToWebhook(evt)
+	})
+}
+
+// groupLeftEvent is the group change whatsmeow would emit for the leave of self
+func groupLeftEvent(group, self types.JID, timestamp time.Time) *events.GroupInfo {
+	return &events.GroupInfo{
+		JID:       group,
+		Sender:    &self,
+		Timestamp: timestamp,
+		Leave:     []types.JID{self},
+	}
+}
+
+// createGroupPayload builds the `group` payload of a group change, `actions` lists the changes it contains
+func createGroupPayload(rawEvt interface{}) (map[string]interface{}, *webhookEventSource) {
+	body := make(map[string]interface{})
+	body["event_type"] = "group"
+
+	var (
+		actions []string
+		source  = &webhookEventSource{}
+	)
+	addJIDs := func(action string, jids []types.JID) {
+		if len(jids) == 0 {
+			return
+		}
+		body[action] = jidStrings(jids)
+		actions = append(actions, action)
+	}
+
+	switch evt := rawEvt.(type) {
+	case *events.GroupInfo:
+		source.Chat = evt.JID
+		body["group"] = evt.JID.String()
+		if evt.Sender != nil {
+			source.Sender = *evt.Sender
+			body["sender"] = evt.Sender.ToNonAD().String()
+		}
+		if evt.SenderPN != nil {
+			body["sender_pn"] = evt.SenderPN.ToNonAD().String()
+		}
+		if !evt.Timestamp.IsZero() {
+			body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+		}
+
+		addJIDs("join", evt.Join)
+		addJIDs("leave", evt.Leave)
+		addJIDs("promote", evt.Promote)
+		addJIDs("demote", evt.Demote)
+		if evt.JoinReason != "" {
+			body["join_reason"] = evt.JoinReason
+		}
+
+		if evt.Name != nil {
+			body["name"] = evt.Name.Name
+			actions = append(actions, "name")
+		}
+		if evt.Topic != nil {
+			body["topic"] = evt.Topic.Topic
+			if evt.Topic.TopicDeleted {
+				body["topic_deleted"] = true
+			}
+			actions = append(actions, "topic")
+		}
+		if evt.Locked != nil {
+			body["locked"] = evt.Locked.IsLocked
+			actions = append(actions, "locked")
+		}
+		if evt.Announce != nil {
+			body["announce"] = evt.Announce.IsAnnounce
+			actions = append(actions, "announce")
+		}
+		if evt.Ephemeral != nil {
+			body["ephemeral"] = map[string]interface{}{
+				"enabled":    evt.Ephemeral.IsEphemeral,
+				"expiration": evt.Ephemeral.DisappearingTimer,
+			}
+			actions = append(actions, "ephemeral")
+		}
+		if evt.MembershipApprovalMode != nil {
+			body["membership_approval"] = evt.MembershipApprovalMode.IsJoinApprovalRequired
+			actions = append(actions, "membership_approval")
+		}
+		if evt.NewInviteLink != nil {
+			body["invite_link"] = *evt.NewInviteLink
+			actions = append(actions, "invite_link")
+		}
+		if evt.Delete != nil {
+			body["delete_reason"] = evt.Delete.DeleteReason
+			actions = append(actions, "delete")
+		}
+		// whatsmeow leaves the join requests of the groups with membership approval unparsed
+		for _, change := range evt.UnknownChanges {
+			switch change.Tag {
+			case "created_membership_requests":
+				addJIDs("join_request", requestedUsers(change))
+				if method, ok := change.Attrs["request_method"].(string); ok {
+					body["request_method"] = method
+				}
+			case "revoked_membership_requests":
+				addJIDs("join_request_revoked", requestedUsers(change))
+			}
+		}
+	case *events.JoinedGroup:
+		source.Chat = evt.JID
+		body["group"] = evt.JID.String()
+		body["name"] = evt.Name
+		if evt.Sender != nil {
+			source.Sender = *evt.Sender
+			body["sender"] = evt.Sender.ToNonAD().String()
+		}
+		if evt.Reason != "" {
+			body["join_reason"] = evt.Reason
+		}
+		participants := make([]types.JID, 0, len(evt.Participants))
+		for _, participant := range evt.Participants {
+			participants = append(participants, participant.JID)
+		}
+		addJIDs("participants", participants)
+		actions = []string{"joined"}
+		if evt.Type == "new" {
+			actions = []string{"created"}
+		}
+	case *events.Picture:
+		if evt.JID.Server != types.GroupServer {
+			return nil, nil
+		}
+		source.Chat, source.Sender = evt.JID, evt.Author
+		body["group"] = evt.JID.String()
+		if !evt.Author.IsEmpty() {
+			body["sender"] = evt.Author.ToNonAD().String()
+		}
+		if !evt.Timestamp.IsZero() {
+			body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+		}
+		body["photo"] = map[string]interface{}{
+			"removed":    evt.Remove,
+			"picture_id": evt.PictureID,
+		}
+		actions = append(actions, "photo")
+	default:
+		return nil, nil
+	}
+
+	if len(actions) == 0 {
+		return nil, nil
+	}
+	body["actions"] = actions
+	return body, source
+}
+
+// requestedUsers returns the users of a join request change, the JIDs are kept as sent and can be LIDs
+func requestedUsers(change *waBinary.Node) []types.JID {
+	var jids []types.JID
+	for _, user := range change.GetChildrenByTag("requested_user") {
+		if jid, ok := user.Attrs["jid"].(types.JID); ok {
+			jids = append(jids, jid)
+		}
+	}
+	return jids
+}
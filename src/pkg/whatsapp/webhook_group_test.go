@@ -0,0 +1,78 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateGroupPayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	admin := types.NewJID("628123456789", types.DefaultUserServer)
+	member := types.NewADJID("628111111111", 0, 3)
+
+	payload, source := createGroupPayload(&events.GroupInfo{
+		JID:     group,
+		Sender:  &admin,
+		Join:    []types.JID{member},
+		Promote: []types.JID{member},
+		Name:    &types.GroupName{Name: "Support"},
+	})
+	assert.Equal(t, group.String(), payload["group"])
+	assert.Equal(t, admin.String(), payload["sender"])
+	assert.Equal(t, []string{"628111111111@s.whatsapp.net"}, payload["join"])
+	assert.Equal(t, []string{"628111111111@s.whatsapp.net"}, payload["promote"])
+	assert.Equal(t, "Support", payload["name"])
+	assert.Equal(t, []string{"join", "promote", "name"}, payload["actions"])
+	assert.Equal(t, group, source.Chat)
+	assert.Equal(t, admin, source.Sender)
+
+	payload, _ = createGroupPayload(&events.Picture{JID: group, Author: admin, PictureID: "123"})
+	assert.Equal(t, []string{"photo"}, payload["actions"])
+
+	payload, _ = createGroupPayload(&events.Picture{JID: admin, PictureID: "123"})
+	assert.Nil(t, payload, "user pictures are not group changes")
+
+	requester := types.NewJID("123456789012345", types.HiddenUserServer)
+	payload, source = createGroupPayload(&events.GroupInfo{
+		JID: group,
+		UnknownChanges: []*waBinary.Node{{
+			Tag:     "created_membership_requests",
+			Attrs:   waBinary.Attrs{"request_method": "invite_link"},
+			Content: []waBinary.Node{{Tag: "requested_user", Attrs: waBinary.Attrs{"jid": requester}}},
+		}},
+	})
+	assert.Equal(t, []string{"123456789012345@lid"}, payload["join_request"])
+	assert.Equal(t, "invite_link", payload["request_method"])
+	assert.Equal(t, []string{"join_request"}, payload["actions"])
+	assert.Equal(t, group, source.Chat)
+
+	payload, _ = createGroupPayload(&events.GroupInfo{
+		JID: group,
+		UnknownChanges: []*waBinary.Node{{
+			Tag:     "revoked_membership_requests",
+			Content: []waBinary.Node{{Tag: "requested_user", Attrs: waBinary.Attrs{"jid": requester}}},
+		}},
+	})
+	assert.Equal(t, []string{"join_request_revoked"}, payload["actions"])
+
+	payload, _ = createGroupPayload(&events.GroupInfo{JID: group})
+	assert.Nil(t, payload, "events without known changes are skipped")
+}
+
+func TestGroupLeftEvent(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	self := types.NewJID("628123456789", types.DefaultUserServer)
+
+	payload, source := createGroupPayload(groupLeftEvent(group, self, time.Now()))
+	assert.Equal(t, group.String(), payload["group"])
+	assert.Equal(t, self.String(), payload["sender"])
+	assert.Equal(t, []string{self.String()}, payload["leave"])
+	assert.Equal(t, []string{"leave"}, payload["actions"])
+	assert.Equal(t, group, source.Chat)
+	assert.Equal(t, self, source.Sender)
+}
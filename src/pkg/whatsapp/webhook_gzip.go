@@ -0,0 +1,24 @@
+package whatsapp
+
+import (
+	"bytes"
+	"compress/gzip"
+)
+
+// encodeWebhookBody returns the request body to send and its Content-Encoding, compressing it
+// with gzip when the endpoint enabled compression and the body reaches the threshold
+func encodeWebhookBody(endpoint WebhookEndpoint, postBody []byte) ([]byte, string, error) {
+	if endpoint.GzipThreshold <= 0 || len(postBody) < endpoint.GzipThreshold {
+		return postBody, "", nil
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(postBody); err != nil {
+		return nil, "", err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, "", err
+	}
+	return compressed.Bytes(), "gzip", nil
+}
@@ -0,0 +1,34 @@
+package whatsapp
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeWebhookBody(t *testing.T) {
+	postBody := bytes.Repeat([]byte(`{"event":"message"}`), 10)
+
+	body, encoding, err := encodeWebhookBody(WebhookEndpoint{}, postBody)
+	assert.NoError(t, err)
+	assert.Empty(t, encoding)
+	assert.Equal(t, postBody, body)
+
+	body, encoding, err = encodeWebhookBody(WebhookEndpoint{GzipThreshold: len(postBody) + 1}, postBody)
+	assert.NoError(t, err)
+	assert.Empty(t, encoding, "bodies below the threshold are sent as is")
+	assert.Equal(t, postBody, body)
+
+	body, encoding, err = encodeWebhookBody(WebhookEndpoint{GzipThreshold: 64}, postBody)
+	assert.NoError(t, err)
+	assert.Equal(t, "gzip", encoding)
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	assert.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, postBody, decompressed)
+}
@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"errors"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waHistorySync"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// forwardHistorySyncToWebhook forwards the messages of a history sync in chunks of --webhook-history-sync-chunk
+// messages. Media isn't downloaded, a past chat can easily hold thousands of them.
+func forwardHistorySyncToWebhook(data *waHistorySync.HistorySync) error {
+	messages := parseHistorySyncMessages(data)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	chunks := chunkHistorySyncMessages(messages, config.WhatsappWebhookHistorySyncChunk)
+	var errs []error
+	for i, chunk := range chunks {
+		payload := map[string]interface{}{
+			"event_type": "history_sync",
+			"sync_type":  data.GetSyncType().String(),
+			"progress":   data.GetProgress(),
+			"chunk":      i + 1,
+			"chunks":     len(chunks),
+			"messages":   chunk,
+		}
+		if err := forwardEventToWebhook("history_sync", payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// parseHistorySyncMessages flattens the conversations of a history sync into message payloads
+func parseHistorySyncMessages(data *waHistorySync.HistorySync) (messages []map[string]interface{}) {
+	for _, conversation := range data.GetConversations() {
+		chatJID, err := types.ParseJID(conversation.GetID())
+		if err != nil {
+			logrus.Warnf("Skipping history sync of conversation %s: %v", conversation.GetID(), err)
+			continue
+		}
+
+		for _, historyMsg := range conversation.GetMessages() {
+			evt, err := cli.ParseWebMessage(chatJID, historyMsg.GetMessage())
+			if err != nil {
+				logrus.Warnf("Skipping history sync message in %s: %v", chatJID, err)
+				continue
+			}
+
+			message := map[string]interface{}{
+				"id":        evt.Info.ID,
+				"chat":      evt.Info.Chat.String(),
+				"from":      evt.Info.Sender.ToNonAD().String(),
+				"from_me":   evt.Info.IsFromMe,
+				"type":      webhookMessageType(evt.Message),
+				"timestamp": evt.Info.Timestamp.Format(time.RFC3339),
+			}
+			if name := conversation.GetName(); name != "" {
+				message["chat_name"] = name
+			}
+			if evt.Info.PushName != "" {
+				message["pushname"] = evt.Info.PushName
+			}
+			if text := webhookMessageText(evt.Message); text != "" {
+				message["text"] = text
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages
+}
+
+// chunkHistorySyncMessages splits the messages in chunks of at most size messages
+func chunkHistorySyncMessages(messages []map[string]interface{}, size int) (chunks [][]map[string]interface{}) {
+	if size <= 0 {
+		size = len(messages)
+	}
+	for start := 0; start < len(messages); start += size {
+		end := min(start+size, len(messages))
+		chunks = append(chunks, messages[start:end])
+	}
+	return chunks
+}
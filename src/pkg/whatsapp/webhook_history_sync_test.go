@@ -0,0 +1,22 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChunkHistorySyncMessages(t *testing.T) {
+	messages := make([]map[string]interface{}, 5)
+	for i := range messages {
+		messages[i] = map[string]interface{}{"id": i}
+	}
+
+	chunks := chunkHistorySyncMessages(messages, 2)
+	assert.Len(t, chunks, 3)
+	assert.Equal(t, messages[:2], chunks[0])
+	assert.Equal(t, messages[4:], chunks[2])
+
+	assert.Len(t, chunkHistorySyncMessages(messages, 0), 1, "an invalid size sends everything at once")
+	assert.Empty(t, chunkHistorySyncMessages(nil, 2))
+}
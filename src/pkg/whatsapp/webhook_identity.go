@@ -0,0 +1,27 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardIdentityChangeToWebhook is a helper function to forward a changed security code to webhook url
+func forwardIdentityChangeToWebhook(evt *events.IdentityChange) error {
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "identity_change",
+		Payload: createIdentityChangePayload(evt),
+		Source:  &webhookEventSource{Chat: evt.JID.ToNonAD(), Sender: evt.JID},
+	})
+}
+
+func createIdentityChangePayload(evt *events.IdentityChange) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "identity_change"
+	body["jid"] = evt.JID.ToNonAD().String()
+	body["device"] = evt.JID.Device
+	// implicit changes were detected by a message failing to decrypt, not notified by the server
+	body["implicit"] = evt.Implicit
+	body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+	return body
+}
@@ -0,0 +1,25 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateIdentityChangePayload(t *testing.T) {
+	payload := createIdentityChangePayload(&events.IdentityChange{
+		JID:       types.NewADJID("628123456789", 0, 2),
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Implicit:  true,
+	})
+	assert.Equal(t, map[string]interface{}{
+		"event_type": "identity_change",
+		"jid":        "628123456789@s.whatsapp.net",
+		"device":     uint16(2),
+		"implicit":   true,
+		"timestamp":  "2025-01-02T03:04:05Z",
+	}, payload)
+}
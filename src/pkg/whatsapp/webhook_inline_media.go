@@ -0,0 +1,134 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// webhookInlineMediaDefaultMaxSize is used when the endpoint enables inline media without a size cap
+const webhookInlineMediaDefaultMaxSize = 1 << 20 // 1MB
+
+// webhookMediaFields are the payload fields holding downloaded media
+var webhookMediaFields = []string{"audio", "document", "image", "sticker", "video"}
+
+// WebhookInlineMediaConfig enables embedding downloaded media as base64 in the payload of an endpoint
+type WebhookInlineMediaConfig struct {
+	MaxSize   int64    `json:"max_size,omitempty" mapstructure:"max_size"`     // bytes, larger media keep the local path only
+	MimeTypes []string `json:"mime_types,omitempty" mapstructure:"mime_types"` // e.g. image/*, empty allows every type
+}
+
+// webhookInlineMedia is the media field of a payload with the file content embedded
+type webhookInlineMedia struct {
+	ExtractedMedia
+	Size int64  `json:"size"`
+	Data string `json:"data"`
+}
+
+// prepareInlineMedia fills in the defaults of the inline media option
+func (endpoint *WebhookEndpoint) prepareInlineMedia() error {
+	if endpoint.InlineMedia == nil {
+		return nil
+	}
+	if endpoint.InlineMedia.MaxSize < 0 {
+		return fmt.Errorf("inline_media max_size can't be negative")
+	}
+	if endpoint.InlineMedia.MaxSize == 0 {
+		endpoint.InlineMedia.MaxSize = webhookInlineMediaDefaultMaxSize
+	}
+	return nil
+}
+
+// acceptsMimeType reports whether media of the mime type may be embedded
+func (cfg WebhookInlineMediaConfig) acceptsMimeType(mimeType string) bool {
+	if len(cfg.MimeTypes) == 0 {
+		return true
+	}
+	for _, allowed := range cfg.MimeTypes {
+		if matchMimeType(allowed, mimeType) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchMimeType reports whether the mime type matches the pattern, e.g. image/png, image/* or *
+func matchMimeType(pattern, mimeType string) bool {
+	mimeType = strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	return pattern == "*" || pattern == mimeType ||
+		(strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mimeType, strings.TrimSuffix(pattern, "*")))
+}
+
+// inlineWebhookMedia returns a copy of the payload where the media within the endpoint limits is embedded as base64.
+// The payload itself is shared by every endpoint, so it's never modified.
+func inlineWebhookMedia(endpoint WebhookEndpoint, payload map[string]interface{}) map[string]interface{} {
+	if endpoint.InlineMedia == nil {
+		return payload
+	}
+
+	var result map[string]interface{}
+	for _, field := range webhookMediaFields {
+		media, ok := payload[field].(ExtractedMedia)
+		if !ok || !endpoint.InlineMedia.acceptsMimeType(media.MimeType) {
+			continue
+		}
+
+		data, err := readInlineMedia(media, endpoint.InlineMedia.MaxSize)
+		if err != nil {
+			logrus.Warnf("Failed to inline webhook %s %s%s: %v", field, media.MediaPath, media.MediaKey, err)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]interface{}, len(payload))
+			for key, value := range payload {
+				result[key] = value
+			}
+		}
+		result[field] = webhookInlineMedia{
+			ExtractedMedia: media,
+			Size:           int64(len(data)),
+			Data:           base64.StdEncoding.EncodeToString(data),
+		}
+	}
+
+	if result == nil {
+		return payload
+	}
+	return result
+}
+
+// readInlineMedia reads the local copy of the media, or the uploaded one when it was removed. Media larger than
+// maxSize, or without any copy, are nil.
+func readInlineMedia(media ExtractedMedia, maxSize int64) ([]byte, error) {
+	if media.MediaPath != "" {
+		info, err := os.Stat(media.MediaPath)
+		if err != nil || info.Size() > maxSize {
+			return nil, err
+		}
+		return ReadMediaFile(media.MediaPath)
+	}
+	if media.MediaKey == "" || mediaStorage == nil {
+		return nil, nil
+	}
+
+	file, err := mediaStorage.Open(context.Background(), media.MediaKey)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil || int64(len(data)) > maxSize {
+		return nil, err
+	}
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package whatsapp
+
+import (
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInlineWebhookMedia(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.jpg")
+	assert.NoError(t, os.WriteFile(path, []byte("jpeg-bytes"), 0600))
+
+	image := ExtractedMedia{MediaPath: path, MimeType: "image/jpeg", Caption: "hi"}
+	payload := map[string]interface{}{"event_type": "message", "image": image}
+
+	t.Run("should keep payload without option", func(t *testing.T) {
+		assert.Equal(t, payload, inlineWebhookMedia(WebhookEndpoint{}, payload))
+	})
+
+	t.Run("should embed allowed media within the size cap", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com", InlineMedia: &WebhookInlineMediaConfig{MimeTypes: []string{"image/*"}}}
+		assert.NoError(t, endpoint.prepare())
+
+		result := inlineWebhookMedia(endpoint, payload)
+		assert.Equal(t, webhookInlineMedia{
+			ExtractedMedia: image,
+			Size:           10,
+			Data:           base64.StdEncoding.EncodeToString([]byte("jpeg-bytes")),
+		}, result["image"])
+		assert.Equal(t, image, payload["image"], "shared payload must not be modified")
+	})
+
+	t.Run("should skip media above the size cap or with other mime type", func(t *testing.T) {
+		tooSmall := WebhookEndpoint{InlineMedia: &WebhookInlineMediaConfig{MaxSize: 5}}
+		assert.Equal(t, image, inlineWebhookMedia(tooSmall, payload)["image"])
+
+		videoOnly := WebhookEndpoint{InlineMedia: &WebhookInlineMediaConfig{MaxSize: 1024, MimeTypes: []string{"video/mp4"}}}
+		assert.Equal(t, image, inlineWebhookMedia(videoOnly, payload)["image"])
+	})
+
+	t.Run("should read back the uploaded media", func(t *testing.T) {
+		originalStorage, originalKeepLocal := mediaStorage, config.WhatsappMediaStorageKeepLocal
+		defer func() { mediaStorage, config.WhatsappMediaStorageKeepLocal = originalStorage, originalKeepLocal }()
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = &fakeMediaStorage{}, false
+
+		uploadedPath := filepath.Join(t.TempDir(), "uploaded.jpg")
+		assert.NoError(t, os.WriteFile(uploadedPath, []byte("jpeg-bytes"), 0600))
+		uploaded := storeMedia(ExtractedMedia{MediaPath: uploadedPath, MimeType: "image/jpeg"})
+		assert.Empty(t, uploaded.MediaPath)
+		payload := map[string]interface{}{"event_type": "message", "image": uploaded}
+
+		endpoint := WebhookEndpoint{InlineMedia: &WebhookInlineMediaConfig{MaxSize: 1024}}
+		assert.Equal(t, webhookInlineMedia{
+			ExtractedMedia: uploaded,
+			Size:           10,
+			Data:           base64.StdEncoding.EncodeToString([]byte("jpeg-bytes")),
+		}, inlineWebhookMedia(endpoint, payload)["image"])
+
+		tooSmall := WebhookEndpoint{InlineMedia: &WebhookInlineMediaConfig{MaxSize: 5}}
+		assert.Equal(t, uploaded, inlineWebhookMedia(tooSmall, payload)["image"])
+	})
+}
@@ -0,0 +1,148 @@
+package whatsapp
+
+import (
+	"encoding/json"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+type evtInteractive struct {
+	Type    string      `json:"type"` // buttons, template or interactive
+	Header  string      `json:"header,omitempty"`
+	Body    string      `json:"body,omitempty"`
+	Footer  string      `json:"footer,omitempty"`
+	Buttons []evtButton `json:"buttons,omitempty"`
+}
+
+type evtButton struct {
+	Type        string `json:"type"` // reply, url, call, or the name of a native flow button (quick_reply, cta_url, ...)
+	ID          string `json:"id,omitempty"`
+	Text        string `json:"text,omitempty"`
+	URL         string `json:"url,omitempty"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	Params      string `json:"params,omitempty"` // raw JSON params of native flow buttons
+}
+
+type evtButtonReply struct {
+	Type        string `json:"type"` // buttons, template, interactive or list
+	ID          string `json:"id,omitempty"`
+	Text        string `json:"text,omitempty"`
+	Description string `json:"description,omitempty"` // description of the selected list row
+	Index       uint32 `json:"index,omitempty"`
+	Params      string `json:"params,omitempty"` // raw JSON params of native flow responses
+}
+
+// buildEventInteractive returns the header, body, footer and buttons of a buttons, template or interactive message
+func buildEventInteractive(msg *waE2E.Message) *evtInteractive {
+	switch {
+	case msg.GetButtonsMessage() != nil:
+		buttonsMessage := msg.GetButtonsMessage()
+		result := &evtInteractive{
+			Type:   "buttons",
+			Header: buttonsMessage.GetText(),
+			Body:   buttonsMessage.GetContentText(),
+			Footer: buttonsMessage.GetFooterText(),
+		}
+		for _, button := range buttonsMessage.GetButtons() {
+			if nativeFlow := button.GetNativeFlowInfo(); nativeFlow != nil {
+				result.Buttons = append(result.Buttons, nativeFlowButton(nativeFlow.GetName(), nativeFlow.GetParamsJSON()))
+				continue
+			}
+			result.Buttons = append(result.Buttons, evtButton{
+				Type: "reply",
+				ID:   button.GetButtonID(),
+				Text: button.GetButtonText().GetDisplayText(),
+			})
+		}
+		return result
+	case msg.GetTemplateMessage() != nil:
+		templateMessage := msg.GetTemplateMessage()
+		if interactive := templateMessage.GetInteractiveMessageTemplate(); interactive != nil {
+			return buildInteractiveMessage(interactive)
+		}
+		template := templateMessage.GetHydratedTemplate()
+		if template == nil {
+			template = templateMessage.GetHydratedFourRowTemplate()
+		}
+		result := &evtInteractive{
+			Type:   "template",
+			Header: template.GetHydratedTitleText(),
+			Body:   template.GetHydratedContentText(),
+			Footer: template.GetHydratedFooterText(),
+		}
+		for _, button := range template.GetHydratedButtons() {
+			switch {
+			case button.GetQuickReplyButton() != nil:
+				result.Buttons = append(result.Buttons, evtButton{Type: "reply", ID: button.GetQuickReplyButton().GetID(), Text: button.GetQuickReplyButton().GetDisplayText()})
+			case button.GetUrlButton() != nil:
+				result.Buttons = append(result.Buttons, evtButton{Type: "url", Text: button.GetUrlButton().GetDisplayText(), URL: button.GetUrlButton().GetURL()})
+			case button.GetCallButton() != nil:
+				result.Buttons = append(result.Buttons, evtButton{Type: "call", Text: button.GetCallButton().GetDisplayText(), PhoneNumber: button.GetCallButton().GetPhoneNumber()})
+			}
+		}
+		return result
+	case msg.GetInteractiveMessage() != nil:
+		return buildInteractiveMessage(msg.GetInteractiveMessage())
+	default:
+		return nil
+	}
+}
+
+func buildInteractiveMessage(interactive *waE2E.InteractiveMessage) *evtInteractive {
+	result := &evtInteractive{
+		Type:   "interactive",
+		Header: interactive.GetHeader().GetTitle(),
+		Body:   interactive.GetBody().GetText(),
+		Footer: interactive.GetFooter().GetText(),
+	}
+	for _, button := range interactive.GetNativeFlowMessage().GetButtons() {
+		result.Buttons = append(result.Buttons, nativeFlowButton(button.GetName(), button.GetButtonParamsJSON()))
+	}
+	return result
+}
+
+// nativeFlowButton reads the usual fields out of the JSON params of a native flow button
+func nativeFlowButton(name, paramsJSON string) evtButton {
+	button := evtButton{Type: name, Params: paramsJSON}
+
+	var params struct {
+		ID          string `json:"id"`
+		DisplayText string `json:"display_text"`
+		URL         string `json:"url"`
+		PhoneNumber string `json:"phone_number"`
+	}
+	if json.Unmarshal([]byte(paramsJSON), &params) == nil {
+		button.ID, button.Text, button.URL, button.PhoneNumber = params.ID, params.DisplayText, params.URL, params.PhoneNumber
+	}
+	return button
+}
+
+// buildEventButtonReply returns the button or list row a user selected, nil when the message isn't a reply to one
+func buildEventButtonReply(msg *waE2E.Message) *evtButtonReply {
+	switch {
+	case msg.GetButtonsResponseMessage() != nil:
+		response := msg.GetButtonsResponseMessage()
+		return &evtButtonReply{Type: "buttons", ID: response.GetSelectedButtonID(), Text: response.GetSelectedDisplayText()}
+	case msg.GetTemplateButtonReplyMessage() != nil:
+		response := msg.GetTemplateButtonReplyMessage()
+		return &evtButtonReply{Type: "template", ID: response.GetSelectedID(), Text: response.GetSelectedDisplayText(), Index: response.GetSelectedIndex()}
+	case msg.GetInteractiveResponseMessage() != nil:
+		response := msg.GetInteractiveResponseMessage()
+		reply := &evtButtonReply{Type: "interactive", Text: response.GetBody().GetText()}
+		if nativeFlow := response.GetNativeFlowResponseMessage(); nativeFlow != nil {
+			reply.Params = nativeFlow.GetParamsJSON()
+			reply.ID = nativeFlowButton(nativeFlow.GetName(), nativeFlow.GetParamsJSON()).ID
+		}
+		return reply
+	case msg.GetListResponseMessage() != nil:
+		response := msg.GetListResponseMessage()
+		return &evtButtonReply{
+			Type:        "list",
+			ID:          response.GetSingleSelectReply().GetSelectedRowID(),
+			Text:        response.GetTitle(),
+			Description: response.GetDescription(),
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildEventInteractive(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msg      *waE2E.Message
+		expected *evtInteractive
+	}{
+		{
+			name: "should parse a buttons message",
+			msg: &waE2E.Message{ButtonsMessage: &waE2E.ButtonsMessage{
+				Header:      &waE2E.ButtonsMessage_Text{Text: "Order #42"},
+				ContentText: proto.String("Confirm your order?"),
+				FooterText:  proto.String("Shop"),
+				Buttons: []*waE2E.ButtonsMessage_Button{{
+					ButtonID:   proto.String("yes"),
+					ButtonText: &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String("Yes")},
+				}},
+			}},
+			expected: &evtInteractive{
+				Type: "buttons", Header: "Order #42", Body: "Confirm your order?", Footer: "Shop",
+				Buttons: []evtButton{{Type: "reply", ID: "yes", Text: "Yes"}},
+			},
+		},
+		{
+			name: "should parse a template message",
+			msg: &waE2E.Message{TemplateMessage: &waE2E.TemplateMessage{HydratedTemplate: &waE2E.TemplateMessage_HydratedFourRowTemplate{
+				HydratedContentText: proto.String("Track your parcel"),
+				HydratedButtons: []*waE2E.HydratedTemplateButton{
+					{HydratedButton: &waE2E.HydratedTemplateButton_UrlButton{UrlButton: &waE2E.HydratedTemplateButton_HydratedURLButton{
+						DisplayText: proto.String("Track"), URL: proto.String("https://example.com/track"),
+					}}},
+					{HydratedButton: &waE2E.HydratedTemplateButton_CallButton{CallButton: &waE2E.HydratedTemplateButton_HydratedCallButton{
+						DisplayText: proto.String("Call us"), PhoneNumber: proto.String("+628123456789"),
+					}}},
+				},
+			}}},
+			expected: &evtInteractive{
+				Type: "template", Body: "Track your parcel",
+				Buttons: []evtButton{
+					{Type: "url", Text: "Track", URL: "https://example.com/track"},
+					{Type: "call", Text: "Call us", PhoneNumber: "+628123456789"},
+				},
+			},
+		},
+		{
+			name: "should parse the native flow buttons of an interactive message",
+			msg: &waE2E.Message{InteractiveMessage: &waE2E.InteractiveMessage{
+				Body: &waE2E.InteractiveMessage_Body{Text: proto.String("Pick one")},
+				InteractiveMessage: &waE2E.InteractiveMessage_NativeFlowMessage_{NativeFlowMessage: &waE2E.InteractiveMessage_NativeFlowMessage{
+					Buttons: []*waE2E.InteractiveMessage_NativeFlowMessage_NativeFlowButton{{
+						Name:             proto.String("quick_reply"),
+						ButtonParamsJSON: proto.String(`{"display_text":"Blue","id":"color_blue"}`),
+					}},
+				}},
+			}},
+			expected: &evtInteractive{
+				Type: "interactive", Body: "Pick one",
+				Buttons: []evtButton{{Type: "quick_reply", ID: "color_blue", Text: "Blue", Params: `{"display_text":"Blue","id":"color_blue"}`}},
+			},
+		},
+		{
+			name:     "should skip other messages",
+			msg:      &waE2E.Message{Conversation: proto.String("hello")},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildEventInteractive(tc.msg))
+		})
+	}
+}
+
+func TestBuildEventButtonReply(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msg      *waE2E.Message
+		expected *evtButtonReply
+	}{
+		{
+			name: "should parse a buttons reply",
+			msg: &waE2E.Message{ButtonsResponseMessage: &waE2E.ButtonsResponseMessage{
+				SelectedButtonID: proto.String("yes"),
+				Response:         &waE2E.ButtonsResponseMessage_SelectedDisplayText{SelectedDisplayText: "Yes"},
+			}},
+			expected: &evtButtonReply{Type: "buttons", ID: "yes", Text: "Yes"},
+		},
+		{
+			name: "should parse a template reply",
+			msg: &waE2E.Message{TemplateButtonReplyMessage: &waE2E.TemplateButtonReplyMessage{
+				SelectedID: proto.String("track"), SelectedDisplayText: proto.String("Track"), SelectedIndex: proto.Uint32(1),
+			}},
+			expected: &evtButtonReply{Type: "template", ID: "track", Text: "Track", Index: 1},
+		},
+		{
+			name: "should parse a native flow reply",
+			msg: &waE2E.Message{InteractiveResponseMessage: &waE2E.InteractiveResponseMessage{
+				Body: &waE2E.InteractiveResponseMessage_Body{Text: proto.String("Blue")},
+				InteractiveResponseMessage: &waE2E.InteractiveResponseMessage_NativeFlowResponseMessage_{
+					NativeFlowResponseMessage: &waE2E.InteractiveResponseMessage_NativeFlowResponseMessage{
+						Name: proto.String("quick_reply"), ParamsJSON: proto.String(`{"id":"color_blue"}`),
+					},
+				},
+			}},
+			expected: &evtButtonReply{Type: "interactive", ID: "color_blue", Text: "Blue", Params: `{"id":"color_blue"}`},
+		},
+		{
+			name: "should parse a list reply",
+			msg: &waE2E.Message{ListResponseMessage: &waE2E.ListResponseMessage{
+				Title:             proto.String("Medium"),
+				Description:       proto.String("Fits 38 to 40"),
+				SingleSelectReply: &waE2E.ListResponseMessage_SingleSelectReply{SelectedRowID: proto.String("size_m")},
+			}},
+			expected: &evtButtonReply{Type: "list", ID: "size_m", Text: "Medium", Description: "Fits 38 to 40"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildEventButtonReply(tc.msg))
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package whatsapp
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+type evtLocation struct {
+	Latitude  float64         `json:"latitude"`
+	Longitude float64         `json:"longitude"`
+	Name      string          `json:"name,omitempty"`
+	Address   string          `json:"address,omitempty"`
+	URL       string          `json:"url,omitempty"`
+	Comment   string          `json:"comment,omitempty"`
+	Thumbnail *ExtractedMedia `json:"thumbnail,omitempty"` // the map preview, stored like the other media
+}
+
+// buildEventLocation returns the fields of a location message, its map preview is stored in the media folder and
+// uploaded to the media storage
+func buildEventLocation(location *waE2E.LocationMessage) evtLocation {
+	result := evtLocation{
+		Latitude:  location.GetDegreesLatitude(),
+		Longitude: location.GetDegreesLongitude(),
+		Name:      location.GetName(),
+		Address:   location.GetAddress(),
+		URL:       location.GetURL(),
+		Comment:   location.GetComment(),
+	}
+
+	if thumbnail := location.GetJPEGThumbnail(); len(thumbnail) > 0 {
+		path := fmt.Sprintf("%s/%d-%s.jpg", config.PathMedia, time.Now().Unix(), uuid.NewString())
+		if err := writeMediaFile(path, thumbnail, os.O_TRUNC); err != nil {
+			logrus.Warnf("Failed to store location thumbnail: %v", err)
+		} else {
+			thumbnail := storeMedia(ExtractedMedia{MediaPath: path, MimeType: "image/jpeg"})
+			result.Thumbnail = &thumbnail
+		}
+	}
+	return result
+}
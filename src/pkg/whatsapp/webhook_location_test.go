@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildEventLocation(t *testing.T) {
+	originalPath := config.PathMedia
+	defer func() { config.PathMedia = originalPath }()
+	config.PathMedia = t.TempDir()
+
+	location := buildEventLocation(&waE2E.LocationMessage{
+		DegreesLatitude:  proto.Float64(-6.2),
+		DegreesLongitude: proto.Float64(106.816666),
+		Name:             proto.String("Monas"),
+		Address:          proto.String("Gambir, Central Jakarta"),
+		JPEGThumbnail:    []byte{0xff, 0xd8, 0xff, 0xd9},
+	})
+	assert.Equal(t, -6.2, location.Latitude)
+	assert.Equal(t, 106.816666, location.Longitude)
+	assert.Equal(t, "Monas", location.Name)
+	assert.Equal(t, "Gambir, Central Jakarta", location.Address)
+
+	if assert.NotNil(t, location.Thumbnail) {
+		assert.Equal(t, "image/jpeg", location.Thumbnail.MimeType)
+	}
+	thumbnail, err := ReadMediaFile(location.Thumbnail.MediaPath)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0xff, 0xd8, 0xff, 0xd9}, thumbnail)
+
+	assert.Nil(t, buildEventLocation(&waE2E.LocationMessage{}).Thumbnail)
+
+	t.Run("should upload the thumbnail", func(t *testing.T) {
+		originalStorage, originalKeepLocal := mediaStorage, config.WhatsappMediaStorageKeepLocal
+		defer func() { mediaStorage, config.WhatsappMediaStorageKeepLocal = originalStorage, originalKeepLocal }()
+		mediaStorage, config.WhatsappMediaStorageKeepLocal = &fakeMediaStorage{}, false
+
+		location := buildEventLocation(&waE2E.LocationMessage{JPEGThumbnail: []byte{0xff, 0xd8, 0xff, 0xd9}})
+		assert.Empty(t, location.Thumbnail.MediaPath)
+		assert.NotEmpty(t, location.Thumbnail.MediaURL)
+		assert.NotEmpty(t, location.Thumbnail.MediaKey)
+	})
+}
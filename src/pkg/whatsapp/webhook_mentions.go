@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type evtGroupMention struct {
+	Group   string `json:"group"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// addEventMentions adds the tagged users (`mentions`), whether this account is one of them (`mentioned_me`),
+// and the tagged groups of a community (`group_mentions`) to the payload
+func addEventMentions(body map[string]interface{}, msg *waE2E.Message) {
+	contextInfo := getContextInfo(msg)
+
+	if mentioned := contextInfo.GetMentionedJID(); len(mentioned) > 0 {
+		body["mentions"] = mentioned
+
+		var own []types.JID
+		if cli != nil && cli.Store.ID != nil {
+			own = append(own, *cli.Store.ID, cli.Store.LID)
+		}
+		body["mentioned_me"] = mentionsJID(mentioned, own...)
+	}
+
+	if groupMentions := contextInfo.GetGroupMentions(); len(groupMentions) > 0 {
+		groups := make([]evtGroupMention, 0, len(groupMentions))
+		for _, mention := range groupMentions {
+			groups = append(groups, evtGroupMention{Group: mention.GetGroupJID(), Subject: mention.GetGroupSubject()})
+		}
+		body["group_mentions"] = groups
+	}
+}
+
+// mentionsJID reports whether one of the mentioned JIDs is one of the given users, whatever their device
+func mentionsJID(mentioned []string, users ...types.JID) bool {
+	for _, value := range mentioned {
+		jid, err := types.ParseJID(value)
+		if err != nil {
+			continue
+		}
+		for _, user := range users {
+			if !user.IsEmpty() && jid.User == user.User && jid.Server == user.Server {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestAddEventMentions(t *testing.T) {
+	body := make(map[string]interface{})
+	addEventMentions(body, &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+		Text: proto.String("@628123456789 check the @Announcements group"),
+		ContextInfo: &waE2E.ContextInfo{
+			MentionedJID:  []string{"628123456789@s.whatsapp.net"},
+			GroupMentions: []*waE2E.GroupMention{{GroupJID: proto.String("120363025246125888@g.us"), GroupSubject: proto.String("Announcements")}},
+		},
+	}})
+	assert.Equal(t, []string{"628123456789@s.whatsapp.net"}, body["mentions"])
+	assert.Equal(t, false, body["mentioned_me"])
+	assert.Equal(t, []evtGroupMention{{Group: "120363025246125888@g.us", Subject: "Announcements"}}, body["group_mentions"])
+
+	body = make(map[string]interface{})
+	addEventMentions(body, &waE2E.Message{Conversation: proto.String("hello")})
+	assert.Empty(t, body)
+}
+
+func TestMentionsJID(t *testing.T) {
+	me := types.NewADJID("628123456789", 0, 3)
+	myLID := types.NewJID("123456789012345", types.HiddenUserServer)
+
+	assert.True(t, mentionsJID([]string{"628987654321@s.whatsapp.net", "628123456789@s.whatsapp.net"}, me))
+	assert.True(t, mentionsJID([]string{"123456789012345@lid"}, me, myLID))
+	assert.False(t, mentionsJID([]string{"628987654321@s.whatsapp.net", "invalid@@"}, me, types.EmptyJID))
+}
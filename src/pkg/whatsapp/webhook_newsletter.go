@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// isNewsletterMessage reports whether the message was posted in a channel (newsletter)
+func isNewsletterMessage(evt *events.Message) bool {
+	return evt.Info.Chat.Server == types.NewsletterServer
+}
+
+// forwardNewsletterToWebhook forwards a channel post, with its media extracted like a normal message
+func forwardNewsletterToWebhook(evt *events.Message, source *webhookEventSource) error {
+	// skip downloading the media when no endpoint wants the post
+	if len(webhookEndpointsForEvent("newsletter", source)) == 0 {
+		return nil
+	}
+
+	body, err := createPayload(evt)
+	if err != nil {
+		return err
+	}
+	defer forwardPendingMedia(evt, source, body)
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "newsletter",
+		Payload: addNewsletterFields(body, evt),
+		Source:  source,
+	})
+}
+
+// addNewsletterFields turns a message payload into a `newsletter` payload
+func addNewsletterFields(body map[string]interface{}, evt *events.Message) map[string]interface{} {
+	body["event_type"] = "newsletter"
+	body["newsletter"] = evt.Info.Chat.String()
+	body["server_id"] = evt.Info.ServerID
+	// posts are signed by the channel itself, there is no sender
+	delete(body, "from_me")
+	delete(body, "pushname")
+
+	// channel edits aren't protocol messages, the post is replaced by its new content
+	if meta := evt.NewsletterMeta; meta != nil && !meta.EditTS.IsZero() {
+		body["edited_at"] = meta.EditTS.UTC().Format(time.RFC3339)
+		if !meta.OriginalTS.IsZero() {
+			body["original_timestamp"] = meta.OriginalTS.UTC().Format(time.RFC3339)
+		}
+	}
+	return body
+}
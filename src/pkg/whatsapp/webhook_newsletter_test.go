@@ -0,0 +1,45 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestAddNewsletterFields(t *testing.T) {
+	channel := types.NewJID("120363144038483540", types.NewsletterServer)
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: channel, Sender: channel},
+			ID:            "POST1",
+			ServerID:      128,
+		},
+		NewsletterMeta: &events.NewsletterMessageMeta{
+			EditTS:     time.Date(2025, 1, 2, 4, 0, 0, 0, time.UTC),
+			OriginalTS: time.Date(2025, 1, 2, 3, 0, 0, 0, time.UTC),
+		},
+	}
+	assert.True(t, isNewsletterMessage(evt))
+
+	payload := addNewsletterFields(map[string]interface{}{
+		"event_type": "message",
+		"from":       channel.String(),
+		"from_me":    false,
+		"message":    evtMessage{ID: "POST1", Text: "Hello subscribers"},
+	}, evt)
+	assert.Equal(t, map[string]interface{}{
+		"event_type":         "newsletter",
+		"from":               channel.String(),
+		"newsletter":         channel.String(),
+		"server_id":          types.MessageServerID(128),
+		"message":            evtMessage{ID: "POST1", Text: "Hello subscribers"},
+		"edited_at":          "2025-01-02T04:00:00Z",
+		"original_timestamp": "2025-01-02T03:00:00Z",
+	}, payload)
+
+	evt.Info.Chat = types.NewJID("628123456789", types.DefaultUserServer)
+	assert.False(t, isNewsletterMessage(evt))
+}
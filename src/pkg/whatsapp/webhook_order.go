@@ -0,0 +1,116 @@
+package whatsapp
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+)
+
+type evtOrder struct {
+	ID        string         `json:"id"`
+	Title     string         `json:"title,omitempty"`
+	Message   string         `json:"message,omitempty"`
+	Seller    string         `json:"seller,omitempty"`
+	Status    string         `json:"status,omitempty"`  // inquiry, accepted or declined
+	Surface   string         `json:"surface,omitempty"` // catalog
+	ItemCount int32          `json:"item_count"`
+	Total     float64        `json:"total"`
+	Currency  string         `json:"currency,omitempty"`
+	Items     []evtOrderItem `json:"items,omitempty"`
+}
+
+type evtOrderItem struct {
+	ID       string  `json:"id"` // product id in the catalog of the seller
+	Name     string  `json:"name"`
+	ImageURL string  `json:"image_url,omitempty"`
+	Price    float64 `json:"price"`
+	Currency string  `json:"currency,omitempty"`
+	Quantity int     `json:"quantity"`
+}
+
+// buildEventOrder returns the order summary carried by the message, and its items when they can be fetched.
+// WhatsApp amounts are in thousandths of the currency unit.
+func buildEventOrder(order *waE2E.OrderMessage) evtOrder {
+	result := evtOrder{
+		ID:        order.GetOrderID(),
+		Title:     order.GetOrderTitle(),
+		Message:   order.GetMessage(),
+		Seller:    order.GetSellerJID(),
+		ItemCount: order.GetItemCount(),
+		Total:     float64(order.GetTotalAmount1000()) / 1000,
+		Currency:  order.GetTotalCurrencyCode(),
+	}
+	if order.Status != nil {
+		result.Status = strings.ToLower(order.GetStatus().String())
+	}
+	if order.Surface != nil {
+		result.Surface = strings.ToLower(order.GetSurface().String())
+	}
+
+	if cli != nil && cli.IsConnected() && order.GetToken() != "" {
+		items, err := getOrderItems(order.GetOrderID(), order.GetToken())
+		if err != nil {
+			logrus.Warnf("Failed to get the items of order %s: %v", order.GetOrderID(), err)
+		} else {
+			result.Items = items
+		}
+	}
+	return result
+}
+
+// getOrderItems queries the items of an order, only the buyer and the seller can read them with the order token
+func getOrderItems(orderID, token string) ([]evtOrderItem, error) {
+	resp, err := cli.DangerousInternals().SendIQ(whatsmeow.DangerousInfoQuery{
+		Namespace: "fb:thrift_iq",
+		Type:      whatsmeow.DangerousInfoQueryType("get"),
+		To:        types.ServerJID,
+		Content: []waBinary.Node{{
+			Tag:   "order",
+			Attrs: waBinary.Attrs{"op": "get", "id": orderID},
+			Content: []waBinary.Node{
+				{Tag: "image_dimensions", Content: []waBinary.Node{
+					{Tag: "width", Content: []byte("100")},
+					{Tag: "height", Content: []byte("100")},
+				}},
+				{Tag: "token", Content: []byte(token)},
+			},
+		}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseOrderItems(resp), nil
+}
+
+func parseOrderItems(resp *waBinary.Node) []evtOrderItem {
+	order := resp.GetChildByTag("order")
+	products := order.GetChildrenByTag("product")
+
+	items := make([]evtOrderItem, 0, len(products))
+	for _, product := range products {
+		image := product.GetChildByTag("image")
+		price, _ := strconv.ParseFloat(nodeText(product.GetChildByTag("price")), 64)
+		quantity, _ := strconv.Atoi(nodeText(product.GetChildByTag("quantity")))
+		items = append(items, evtOrderItem{
+			ID:       nodeText(product.GetChildByTag("id")),
+			Name:     nodeText(product.GetChildByTag("name")),
+			ImageURL: nodeText(image.GetChildByTag("url")),
+			Price:    price / 1000,
+			Currency: nodeText(product.GetChildByTag("currency")),
+			Quantity: quantity,
+		})
+	}
+	return items
+}
+
+func nodeText(node waBinary.Node) string {
+	if content, ok := node.Content.([]byte); ok {
+		return string(content)
+	}
+	return ""
+}
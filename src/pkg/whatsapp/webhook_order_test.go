@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	waBinary "go.mau.fi/whatsmeow/binary"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildEventOrder(t *testing.T) {
+	order := buildEventOrder(&waE2E.OrderMessage{
+		OrderID:           proto.String("ORDER1"),
+		OrderTitle:        proto.String("Coffee shop"),
+		SellerJID:         proto.String("628123456789@s.whatsapp.net"),
+		Status:            waE2E.OrderMessage_INQUIRY.Enum(),
+		Surface:           waE2E.OrderMessage_CATALOG.Enum(),
+		ItemCount:         proto.Int32(3),
+		TotalAmount1000:   proto.Int64(45500000),
+		TotalCurrencyCode: proto.String("IDR"),
+	})
+	assert.Equal(t, evtOrder{
+		ID:        "ORDER1",
+		Title:     "Coffee shop",
+		Seller:    "628123456789@s.whatsapp.net",
+		Status:    "inquiry",
+		Surface:   "catalog",
+		ItemCount: 3,
+		Total:     45500,
+		Currency:  "IDR",
+	}, order)
+}
+
+func TestParseOrderItems(t *testing.T) {
+	text := func(tag, value string) waBinary.Node {
+		return waBinary.Node{Tag: tag, Content: []byte(value)}
+	}
+	resp := &waBinary.Node{Tag: "iq", Content: []waBinary.Node{{
+		Tag: "order",
+		Content: []waBinary.Node{{
+			Tag: "product",
+			Content: []waBinary.Node{
+				text("id", "PRODUCT1"),
+				text("name", "Latte"),
+				{Tag: "image", Content: []waBinary.Node{text("url", "https://example.com/latte.jpg")}},
+				text("price", "15000000"),
+				text("currency", "IDR"),
+				text("quantity", "2"),
+			},
+		}},
+	}}}
+
+	assert.Equal(t, []evtOrderItem{{
+		ID:       "PRODUCT1",
+		Name:     "Latte",
+		ImageURL: "https://example.com/latte.jpg",
+		Price:    15000,
+		Currency: "IDR",
+		Quantity: 2,
+	}}, parseOrderItems(resp))
+}
@@ -0,0 +1,198 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+var webhookOutboxMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_outbox (
+		id              INTEGER PRIMARY KEY AUTOINCREMENT,
+		url             TEXT    NOT NULL,
+		event_type      TEXT    NOT NULL,
+		payload         BLOB    NOT NULL,
+		attempts        INTEGER NOT NULL DEFAULT 0,
+		last_error      TEXT    NOT NULL DEFAULT '',
+		next_attempt_at INTEGER NOT NULL,
+		created_at      INTEGER NOT NULL
+	)`,
+}
+
+// WebhookOutboxEntry is a failed webhook delivery waiting to be retried
+type WebhookOutboxEntry struct {
+	ID            int64           `json:"id"`
+	URL           string          `json:"url"`
+	EventType     string          `json:"event_type"`
+	Payload       json.RawMessage `json:"payload"`
+	Attempts      int             `json:"attempts"`
+	LastError     string          `json:"last_error"`
+	NextAttemptAt time.Time       `json:"next_attempt_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+// queueWebhookRetry persists a failed delivery so the outbox dispatcher can retry it later
+func queueWebhookRetry(eventType string, url string, body []byte, cause error) error {
+	if appStore == nil {
+		return errStoreNotInitialized
+	}
+
+	now := time.Now()
+	_, err := appStore.Exec(
+		`INSERT INTO webhook_outbox (url, event_type, payload, attempts, last_error, next_attempt_at, created_at) VALUES (?, ?, ?, 1, ?, ?, ?)`,
+		url, eventType, body, cause.Error(), now.Add(webhookOutboxBackoff(1)).Unix(), now.Unix(),
+	)
+	return err
+}
+
+// webhookOutboxBackoff returns the exponential delay before the next retry attempt
+func webhookOutboxBackoff(attempts int) time.Duration {
+	delay := config.WhatsappWebhookOutboxBaseDelay
+	for i := 1; i < attempts && delay < config.WhatsappWebhookOutboxMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > config.WhatsappWebhookOutboxMaxDelay {
+		delay = config.WhatsappWebhookOutboxMaxDelay
+	}
+	return delay
+}
+
+// StartWebhookOutboxDispatcher periodically retries the pending deliveries in the outbox
+func StartWebhookOutboxDispatcher() {
+	if appStore == nil {
+		return
+	}
+
+	ticker := time.NewTicker(config.WhatsappWebhookOutboxInterval)
+	defer ticker.Stop()
+
+	logrus.Infof("Webhook outbox dispatcher started, checking every %s", config.WhatsappWebhookOutboxInterval)
+	for range ticker.C {
+		if err := dispatchWebhookOutbox(); err != nil {
+			logrus.Errorf("Error dispatching webhook outbox: %v", err)
+		}
+	}
+}
+
+// dispatchWebhookOutbox retries the due entries, each url in its own goroutine so one
+// unreachable endpoint can't hold back the retries of the others
+func dispatchWebhookOutbox() error {
+	entries, err := listWebhookOutbox(`WHERE next_attempt_at <= ? ORDER BY next_attempt_at LIMIT 50`, time.Now().Unix())
+	if err != nil {
+		return err
+	}
+
+	entriesByURL := make(map[string][]WebhookOutboxEntry)
+	for _, entry := range entries {
+		entriesByURL[entry.URL] = append(entriesByURL[entry.URL], entry)
+	}
+
+	var wg sync.WaitGroup
+	for url, urlEntries := range entriesByURL {
+		wg.Add(1)
+		go func(url string, urlEntries []WebhookOutboxEntry) {
+			defer wg.Done()
+			for _, entry := range urlEntries {
+				delivered, err := retryWebhookOutboxEntry(entry)
+				if err != nil {
+					logrus.Errorf("Error retrying webhook outbox entry %d: %v", entry.ID, err)
+				}
+				if !delivered {
+					// the endpoint is still failing, leave its remaining entries for the next round
+					break
+				}
+			}
+		}(url, urlEntries)
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// retryWebhookOutboxEntry attempts a single outbox entry and reports whether it was delivered
+func retryWebhookOutboxEntry(entry WebhookOutboxEntry) (bool, error) {
+	recordWebhookRetry(entry.URL)
+	_, errSubmit := postWebhook(context.Background(), webhookEndpointByURL(entry.URL), entry.Payload)
+	if errSubmit == nil {
+		logrus.Infof("Webhook outbox entry %d delivered to %s after %d attempts", entry.ID, entry.URL, entry.Attempts+1)
+		_, err := deleteWebhookOutbox(`WHERE id = ?`, entry.ID)
+		return true, err
+	}
+
+	attempts := entry.Attempts + 1
+	if attempts >= config.WhatsappWebhookOutboxMaxAttempts {
+		logrus.Errorf("Giving up webhook outbox entry %d to %s after %d attempts, moving to dead letter: %v", entry.ID, entry.URL, attempts, errSubmit)
+		entry.Attempts = attempts
+		return false, moveWebhookOutboxToDeadLetter(entry, errSubmit)
+	}
+
+	logrus.Warnf("Retry %d of webhook outbox entry %d to %s failed: %v", attempts, entry.ID, entry.URL, errSubmit)
+	_, err := appStore.Exec(
+		`UPDATE webhook_outbox SET attempts = ?, last_error = ?, next_attempt_at = ? WHERE id = ?`,
+		attempts, errSubmit.Error(), time.Now().Add(webhookOutboxBackoff(attempts)).Unix(), entry.ID,
+	)
+	return false, err
+}
+
+func listWebhookOutbox(condition string, args ...interface{}) (entries []WebhookOutboxEntry, err error) {
+	rows, err := appStore.Query(`SELECT id, url, event_type, payload, attempts, last_error, next_attempt_at, created_at FROM webhook_outbox `+condition, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var (
+			entry         WebhookOutboxEntry
+			payload       []byte
+			nextAttemptAt int64
+			createdAt     int64
+		)
+		if err = rows.Scan(&entry.ID, &entry.URL, &entry.EventType, &payload, &entry.Attempts, &entry.LastError, &nextAttemptAt, &createdAt); err != nil {
+			return nil, err
+		}
+		entry.Payload = payload
+		entry.NextAttemptAt = time.Unix(nextAttemptAt, 0)
+		entry.CreatedAt = time.Unix(createdAt, 0)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func deleteWebhookOutbox(condition string, args ...interface{}) (int64, error) {
+	result, err := appStore.Exec(`DELETE FROM webhook_outbox `+condition, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ListWebhookOutbox returns every pending delivery, oldest first
+func ListWebhookOutbox() ([]WebhookOutboxEntry, error) {
+	if appStore == nil {
+		return nil, errStoreNotInitialized
+	}
+	return listWebhookOutbox(`ORDER BY created_at, id`)
+}
+
+// PurgeWebhookOutbox removes pending deliveries, filtered by id and/or url when provided
+func PurgeWebhookOutbox(id int64, url string) (int64, error) {
+	if appStore == nil {
+		return 0, errStoreNotInitialized
+	}
+
+	switch {
+	case id > 0 && url != "":
+		return deleteWebhookOutbox(`WHERE id = ? AND url = ?`, id, url)
+	case id > 0:
+		return deleteWebhookOutbox(`WHERE id = ?`, id)
+	case url != "":
+		return deleteWebhookOutbox(`WHERE url = ?`, url)
+	default:
+		return deleteWebhookOutbox(``)
+	}
+}
@@ -0,0 +1,50 @@
+package whatsapp
+
+import (
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// RecordSentMessage records, stores and forwards a message sent from this device, content is its text for the chat storage
+func RecordSentMessage(recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message, content string) {
+	utils.RecordMessage(resp.ID, cli.Store.ID.String(), content)
+	StoreSentMessage(recipient, resp, msg)
+	ForwardSentMessageToWebhook(recipient, resp, msg)
+}
+
+// ForwardSentMessageToWebhook forwards a message sent from this device as a `message` event flagged
+// `from_me`, whatsmeow doesn't emit events for the messages we send ourselves
+func ForwardSentMessageToWebhook(recipient types.JID, resp whatsmeow.SendResponse, msg *waE2E.Message) {
+	if !hasWebhookEndpoints() || cli == nil || cli.Store.ID == nil {
+		return
+	}
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{
+				Chat:     recipient,
+				Sender:   cli.Store.ID.ToNonAD(),
+				IsFromMe: true,
+				IsGroup:  recipient.Server == types.GroupServer,
+			},
+			ID:        resp.ID,
+			PushName:  cli.Store.PushName,
+			Timestamp: resp.Timestamp,
+		},
+		Message: msg,
+	}
+
+	if !config.WhatsappWebhookFromMe {
+		// the options of our own polls are still needed to decode their votes
+		storeWebhookPoll(evt)
+		return
+	}
+
+	enqueueWebhook("sent message", func() error {
+		return forwardToWebhook(evt)
+	})
+}
@@ -0,0 +1,72 @@
+package whatsapp
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardPictureToWebhook is a helper function to forward a profile or group picture change to webhook url
+func forwardPictureToWebhook(evt *events.Picture) error {
+	source := &webhookEventSource{Chat: evt.JID, Sender: evt.Author}
+	if len(webhookEndpointsForEvent("picture", source)) == 0 {
+		return nil
+	}
+
+	body := createPicturePayload(evt)
+	if !evt.Remove && config.WhatsappWebhookPictureDownload {
+		addPictureDownload(body, evt.JID)
+	}
+	return dispatchWebhookEvent(webhookEvent{Type: "picture", Payload: body, Source: source})
+}
+
+func createPicturePayload(evt *events.Picture) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "picture"
+	body["jid"] = evt.JID.ToNonAD().String()
+	body["is_group"] = evt.JID.Server == types.GroupServer
+	body["removed"] = evt.Remove
+	if evt.PictureID != "" {
+		body["picture_id"] = evt.PictureID
+	}
+	if !evt.Author.IsEmpty() {
+		body["author"] = evt.Author.ToNonAD().String()
+	}
+	if !evt.Timestamp.IsZero() {
+		body["timestamp"] = evt.Timestamp.Format(time.RFC3339)
+	}
+	return body
+}
+
+// addPictureDownload adds the url of the new picture and its downloaded copy to the payload. A failed
+// download doesn't hold back the event, the url alone is still useful.
+func addPictureDownload(body map[string]interface{}, jid types.JID) {
+	info, err := cli.GetProfilePictureInfo(jid, &whatsmeow.GetProfilePictureParams{})
+	if err != nil || info == nil {
+		logrus.Warnf("Failed to get the new picture of %s: %v", jid, err)
+		return
+	}
+	body["url"] = info.URL
+
+	data, fileName, err := utils.DownloadImageFromURL(info.URL)
+	if err != nil {
+		logrus.Warnf("Failed to download the new picture of %s: %v", jid, err)
+		return
+	}
+
+	path := fmt.Sprintf("%s/%d-%s%s", config.PathMedia, time.Now().Unix(), uuid.NewString(), filepath.Ext(fileName))
+	if err = writeMediaFile(path, data, os.O_TRUNC); err != nil {
+		logrus.Warnf("Failed to store the new picture of %s: %v", jid, err)
+		return
+	}
+	body["picture"] = storeMedia(ExtractedMedia{MediaPath: path, MimeType: "image/jpeg"})
+}
@@ -0,0 +1,36 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreatePicturePayload(t *testing.T) {
+	contact := types.NewADJID("628123456789", 0, 1)
+
+	payload := createPicturePayload(&events.Picture{
+		JID:       contact,
+		Author:    contact,
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		PictureID: "1234567890",
+	})
+	assert.Equal(t, map[string]interface{}{
+		"event_type": "picture",
+		"jid":        "628123456789@s.whatsapp.net",
+		"is_group":   false,
+		"removed":    false,
+		"picture_id": "1234567890",
+		"author":     "628123456789@s.whatsapp.net",
+		"timestamp":  "2025-01-02T03:04:05Z",
+	}, payload)
+
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	payload = createPicturePayload(&events.Picture{JID: group, Remove: true})
+	assert.Equal(t, true, payload["is_group"])
+	assert.Equal(t, true, payload["removed"])
+	assert.NotContains(t, payload, "picture_id")
+}
@@ -0,0 +1,143 @@
+package whatsapp
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+var webhookPollMigrations = []string{
+	`CREATE TABLE IF NOT EXISTS webhook_polls (
+		message_id TEXT    PRIMARY KEY,
+		chat       TEXT    NOT NULL,
+		options    BLOB    NOT NULL,
+		created_at INTEGER NOT NULL
+	)`,
+}
+
+type evtPoll struct {
+	Question        string   `json:"question"`
+	Options         []string `json:"options"`
+	SelectableCount uint32   `json:"selectable_count"`
+}
+
+// getPollCreation returns the poll of the message whatever its version, nil when it isn't a poll
+func getPollCreation(msg *waE2E.Message) *waE2E.PollCreationMessage {
+	switch {
+	case msg.GetPollCreationMessage() != nil:
+		return msg.GetPollCreationMessage()
+	case msg.GetPollCreationMessageV2() != nil:
+		return msg.GetPollCreationMessageV2()
+	case msg.GetPollCreationMessageV3() != nil:
+		return msg.GetPollCreationMessageV3()
+	default:
+		return nil
+	}
+}
+
+func buildEventPoll(evt *events.Message) *evtPoll {
+	pollCreation := getPollCreation(evt.Message)
+	if pollCreation == nil {
+		return nil
+	}
+
+	poll := &evtPoll{Question: pollCreation.GetName(), SelectableCount: pollCreation.GetSelectableOptionsCount()}
+	for _, option := range pollCreation.GetOptions() {
+		poll.Options = append(poll.Options, option.GetOptionName())
+	}
+	return poll
+}
+
+// storeWebhookPoll keeps the options of a poll, votes only carry the hashes of the selected options
+func storeWebhookPoll(evt *events.Message) {
+	poll := buildEventPoll(evt)
+	if poll == nil || appStore == nil {
+		return
+	}
+
+	options, err := json.Marshal(poll.Options)
+	if err != nil {
+		logrus.Errorf("Failed to encode options of poll %s: %v", evt.Info.ID, err)
+		return
+	}
+	if _, err = appStore.Exec(
+		`INSERT OR REPLACE INTO webhook_polls (message_id, chat, options, created_at) VALUES (?, ?, ?, ?)`,
+		evt.Info.ID, evt.Info.Chat.String(), options, time.Now().Unix(),
+	); err != nil {
+		logrus.Errorf("Failed to store poll %s: %v", evt.Info.ID, err)
+	}
+}
+
+// webhookPollOptions returns the stored options of the poll, nil when the poll is unknown
+func webhookPollOptions(pollID string) []string {
+	if appStore == nil {
+		return nil
+	}
+
+	var options []byte
+	if err := appStore.QueryRow(`SELECT options FROM webhook_polls WHERE message_id = ?`, pollID).Scan(&options); err != nil {
+		return nil
+	}
+
+	var names []string
+	if err := json.Unmarshal(options, &names); err != nil {
+		logrus.Errorf("Failed to decode options of poll %s: %v", pollID, err)
+		return nil
+	}
+	return names
+}
+
+// pollVoteOptionNames maps the selected option hashes back to their names,
+// hashes of unknown options are returned hex encoded
+func pollVoteOptionNames(options []string, selected [][]byte) []string {
+	names := make(map[string]string, len(options))
+	for _, option := range options {
+		hash := sha256.Sum256([]byte(option))
+		names[string(hash[:])] = option
+	}
+
+	result := make([]string, 0, len(selected))
+	for _, hash := range selected {
+		if name, ok := names[string(hash)]; ok {
+			result = append(result, name)
+		} else {
+			result = append(result, hex.EncodeToString(hash))
+		}
+	}
+	return result
+}
+
+// createPollVotePayload decrypts a poll vote, nil when the event isn't a vote
+func createPollVotePayload(evt *events.Message) (map[string]interface{}, error) {
+	pollUpdate := evt.Message.GetPollUpdateMessage()
+	if pollUpdate == nil {
+		return nil, nil
+	}
+	if cli == nil {
+		return nil, pkgError.WebhookError("whatsapp client is not initialized")
+	}
+
+	vote, err := cli.DecryptPollVote(evt)
+	if err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("failed to decrypt vote %s: %v", evt.Info.ID, err))
+	}
+
+	pollID := pollUpdate.GetPollCreationMessageKey().GetID()
+
+	body := make(map[string]interface{})
+	body["event_type"] = "poll_vote"
+	body["chat"] = evt.Info.Chat.String()
+	body["voter"] = evt.Info.Sender.ToNonAD().String()
+	body["from_me"] = evt.Info.IsFromMe
+	body["poll_id"] = pollID
+	body["selected_options"] = pollVoteOptionNames(webhookPollOptions(pollID), vote.GetSelectedOptions())
+	body["timestamp"] = evt.Info.Timestamp.Format(time.RFC3339)
+	return body, nil
+}
@@ -0,0 +1,53 @@
+package whatsapp
+
+import (
+	"encoding/hex"
+	"path/filepath"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStoreWebhookPoll(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	evt := &events.Message{
+		Info: types.MessageInfo{
+			MessageSource: types.MessageSource{Chat: types.NewJID("628123456789", types.DefaultUserServer)},
+			ID:            "POLL1",
+		},
+		Message: &waE2E.Message{PollCreationMessageV3: &waE2E.PollCreationMessage{
+			Name:                   proto.String("Lunch?"),
+			Options:                []*waE2E.PollCreationMessage_Option{{OptionName: proto.String("Pizza")}, {OptionName: proto.String("Sushi")}},
+			SelectableOptionsCount: proto.Uint32(1),
+		}},
+	}
+	assert.Equal(t, &evtPoll{Question: "Lunch?", Options: []string{"Pizza", "Sushi"}, SelectableCount: 1}, buildEventPoll(evt))
+
+	storeWebhookPoll(evt)
+	assert.Equal(t, []string{"Pizza", "Sushi"}, webhookPollOptions("POLL1"))
+	assert.Nil(t, webhookPollOptions("UNKNOWN"))
+}
+
+func TestPollVoteOptionNames(t *testing.T) {
+	options := []string{"Pizza", "Sushi", "Tacos"}
+	unknown := whatsmeow.HashPollOptions([]string{"Removed"})[0]
+	selected := append(whatsmeow.HashPollOptions([]string{"Tacos", "Pizza"}), unknown)
+
+	assert.Equal(t, []string{"Tacos", "Pizza", hex.EncodeToString(unknown)}, pollVoteOptionNames(options, selected))
+	assert.Empty(t, pollVoteOptionNames(options, nil))
+}
@@ -0,0 +1,60 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardPresenceToWebhook is a helper function to forward a presence update to webhook url
+func forwardPresenceToWebhook(evt *events.Presence) error {
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "presence",
+		Payload: createPresencePayload(evt),
+		Source:  &webhookEventSource{Chat: evt.From, Sender: evt.From},
+	})
+}
+
+func createPresencePayload(evt *events.Presence) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "presence"
+	body["from"] = evt.From.ToNonAD().String()
+
+	if evt.Unavailable {
+		body["status"] = "offline"
+		if !evt.LastSeen.IsZero() {
+			body["last_seen"] = evt.LastSeen.Format(time.RFC3339)
+		}
+	} else {
+		body["status"] = "online"
+	}
+	return body
+}
+
+// forwardChatPresenceToWebhook is a helper function to forward a chat state (typing, recording) to webhook url
+func forwardChatPresenceToWebhook(evt *events.ChatPresence) error {
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "chat_presence",
+		Payload: createChatPresencePayload(evt),
+		Source:  &webhookEventSource{Chat: evt.Chat, Sender: evt.Sender},
+	})
+}
+
+func createChatPresencePayload(evt *events.ChatPresence) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "chat_presence"
+	body["chat"] = evt.Chat.String()
+	body["sender"] = evt.Sender.ToNonAD().String()
+
+	switch {
+	case evt.State == types.ChatPresencePaused:
+		body["state"] = "paused"
+	case evt.Media == types.ChatPresenceMediaAudio:
+		body["state"] = "recording"
+	default:
+		body["state"] = "typing"
+	}
+	body["timestamp"] = time.Now().Format(time.RFC3339)
+	return body
+}
@@ -0,0 +1,53 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreatePresencePayload(t *testing.T) {
+	contact := types.NewADJID("628123456789", 0, 1)
+
+	payload := createPresencePayload(&events.Presence{From: contact})
+	assert.Equal(t, map[string]interface{}{
+		"event_type": "presence",
+		"from":       "628123456789@s.whatsapp.net",
+		"status":     "online",
+	}, payload)
+
+	payload = createPresencePayload(&events.Presence{From: contact, Unavailable: true, LastSeen: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)})
+	assert.Equal(t, "offline", payload["status"])
+	assert.Equal(t, "2025-01-02T03:04:05Z", payload["last_seen"])
+}
+
+func TestCreateChatPresencePayload(t *testing.T) {
+	source := types.MessageSource{
+		Chat:   types.NewJID("120363000000000000", types.GroupServer),
+		Sender: types.NewADJID("628123456789", 0, 1),
+	}
+
+	testCases := []struct {
+		name     string
+		state    types.ChatPresence
+		media    types.ChatPresenceMedia
+		expected string
+	}{
+		{name: "should report typing", state: types.ChatPresenceComposing, media: types.ChatPresenceMediaText, expected: "typing"},
+		{name: "should report recording", state: types.ChatPresenceComposing, media: types.ChatPresenceMediaAudio, expected: "recording"},
+		{name: "should report paused", state: types.ChatPresencePaused, expected: "paused"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			payload := createChatPresencePayload(&events.ChatPresence{MessageSource: source, State: tc.state, Media: tc.media})
+			assert.Equal(t, "chat_presence", payload["event_type"])
+			assert.Equal(t, "120363000000000000@g.us", payload["chat"])
+			assert.Equal(t, "628123456789@s.whatsapp.net", payload["sender"])
+			assert.Equal(t, tc.expected, payload["state"])
+		})
+	}
+}
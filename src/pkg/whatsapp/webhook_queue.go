@@ -0,0 +1,169 @@
+package whatsapp
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	"github.com/sirupsen/logrus"
+)
+
+// Overflow policies of the webhook queue
+const (
+	WebhookOverflowBlock      = "block"       // wait for a free slot, back-pressuring the event handler
+	WebhookOverflowDropNewest = "drop_newest" // drop the event being queued
+	WebhookOverflowDropOldest = "drop_oldest" // drop the oldest queued event to make room
+)
+
+// webhookJob is a queued webhook forward, name is used for logging only
+type webhookJob struct {
+	name string
+	run  func() error
+}
+
+var webhookQueue chan webhookJob
+
+// StartWebhookDispatcher starts the webhook queue and its dispatcher goroutines, so event handlers
+// only enqueue webhook forwards instead of waiting for slow endpoints
+func StartWebhookDispatcher() error {
+	switch config.WhatsappWebhookQueueOverflow {
+	case WebhookOverflowBlock, WebhookOverflowDropNewest, WebhookOverflowDropOldest:
+	default:
+		return fmt.Errorf("unknown webhook queue overflow policy %q, use %s, %s or %s",
+			config.WhatsappWebhookQueueOverflow, WebhookOverflowBlock, WebhookOverflowDropNewest, WebhookOverflowDropOldest)
+	}
+	if config.WhatsappWebhookQueueSize < 1 || config.WhatsappWebhookWorkers < 1 {
+		return fmt.Errorf("webhook queue size and workers must be at least 1")
+	}
+
+	webhookQueue = make(chan webhookJob, config.WhatsappWebhookQueueSize)
+	for i := 0; i < config.WhatsappWebhookWorkers; i++ {
+		go runWebhookWorker(webhookQueue)
+	}
+	return nil
+}
+
+func runWebhookWorker(queue <-chan webhookJob) {
+	for job := range queue {
+		if err := job.run(); err != nil {
+			logrus.Errorf("Failed forward %s to webhook: %v", job.name, err)
+		}
+	}
+}
+
+// enqueueWebhook queues a webhook forward according to the overflow policy.
+// Without a running dispatcher the forward runs in its own goroutine.
+func enqueueWebhook(name string, run func() error) {
+	job := webhookJob{name: name, run: run}
+	if webhookQueue == nil {
+		go runWebhookWorker(singleWebhookJob(job))
+		return
+	}
+
+	switch config.WhatsappWebhookQueueOverflow {
+	case WebhookOverflowDropNewest:
+		select {
+		case webhookQueue <- job:
+		default:
+			logrus.Warnf("Webhook queue is full, dropped %s", name)
+		}
+	case WebhookOverflowDropOldest:
+		for {
+			select {
+			case webhookQueue <- job:
+				return
+			default:
+			}
+			select {
+			case dropped := <-webhookQueue:
+				logrus.Warnf("Webhook queue is full, dropped oldest %s", dropped.name)
+			default:
+			}
+		}
+	default:
+		webhookQueue <- job
+	}
+}
+
+func singleWebhookJob(job webhookJob) <-chan webhookJob {
+	queue := make(chan webhookJob, 1)
+	queue <- job
+	close(queue)
+	return queue
+}
+
+const (
+	// webhookEndpointQueueSize is how many deliveries wait for a busy endpoint, the next ones go to the outbox
+	webhookEndpointQueueSize = 100
+	// webhookEndpointIdleTimeout stops the worker of an endpoint that had nothing to deliver for that long
+	webhookEndpointIdleTimeout = time.Minute
+)
+
+// webhookDelivery is an event rendered for one endpoint
+type webhookDelivery struct {
+	event    webhookEvent
+	endpoint WebhookEndpoint
+	body     []byte
+}
+
+var (
+	webhookEndpointQueuesMu sync.Mutex
+	webhookEndpointQueues   = map[string]chan webhookDelivery{}
+)
+
+// enqueueEndpointDelivery hands the delivery to the worker of its endpoint, so the inline retries of a broken
+// endpoint only hold back its own deliveries. A delivery that finds the endpoint queue full goes to the outbox.
+func enqueueEndpointDelivery(delivery webhookDelivery) {
+	url := delivery.endpoint.URL
+
+	webhookEndpointQueuesMu.Lock()
+	queue, ok := webhookEndpointQueues[url]
+	if !ok {
+		queue = make(chan webhookDelivery, webhookEndpointQueueSize)
+		webhookEndpointQueues[url] = queue
+		go runWebhookEndpointWorker(url, queue)
+	}
+	var queued bool
+	select {
+	case queue <- delivery:
+		queued = true
+	default:
+	}
+	webhookEndpointQueuesMu.Unlock()
+
+	if queued {
+		return
+	}
+	cause := fmt.Errorf("%d deliveries already waiting for the endpoint", webhookEndpointQueueSize)
+	if err := queueWebhookRetry(delivery.event.Type, url, delivery.body, cause); err != nil {
+		logrus.Errorf("Webhook %s is behind, dropped %s event: %v", url, delivery.event.Type, err)
+		return
+	}
+	logrus.Warnf("Webhook %s is behind, queued %s event in outbox", url, delivery.event.Type)
+}
+
+// runWebhookEndpointWorker delivers the queued events of the endpoint in order until it stays idle
+func runWebhookEndpointWorker(url string, queue chan webhookDelivery) {
+	idle := time.NewTimer(webhookEndpointIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case delivery := <-queue:
+			if err := deliverToEndpoint(delivery.event, delivery.endpoint, delivery.body); err != nil {
+				logrus.Errorf("Failed forward %s to webhook: %v", delivery.event.Type, err)
+			}
+			idle.Reset(webhookEndpointIdleTimeout)
+		case <-idle.C:
+			webhookEndpointQueuesMu.Lock()
+			if len(queue) == 0 {
+				delete(webhookEndpointQueues, url)
+				webhookEndpointQueuesMu.Unlock()
+				return
+			}
+			webhookEndpointQueuesMu.Unlock()
+			idle.Reset(webhookEndpointIdleTimeout)
+		}
+	}
+}
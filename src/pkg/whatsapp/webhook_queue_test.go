@@ -0,0 +1,102 @@
+package whatsapp
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnqueueWebhookOverflow(t *testing.T) {
+	originalQueue, originalOverflow := webhookQueue, config.WhatsappWebhookQueueOverflow
+	defer func() {
+		webhookQueue, config.WhatsappWebhookQueueOverflow = originalQueue, originalOverflow
+	}()
+
+	queuedNames := func() (names []string) {
+		for len(webhookQueue) > 0 {
+			names = append(names, (<-webhookQueue).name)
+		}
+		return names
+	}
+	noop := func() error { return nil }
+
+	t.Run("should drop the newest event", func(t *testing.T) {
+		webhookQueue, config.WhatsappWebhookQueueOverflow = make(chan webhookJob, 2), WebhookOverflowDropNewest
+		enqueueWebhook("first", noop)
+		enqueueWebhook("second", noop)
+		enqueueWebhook("third", noop)
+		assert.Equal(t, []string{"first", "second"}, queuedNames())
+	})
+
+	t.Run("should drop the oldest event", func(t *testing.T) {
+		webhookQueue, config.WhatsappWebhookQueueOverflow = make(chan webhookJob, 2), WebhookOverflowDropOldest
+		enqueueWebhook("first", noop)
+		enqueueWebhook("second", noop)
+		enqueueWebhook("third", noop)
+		assert.Equal(t, []string{"second", "third"}, queuedNames())
+	})
+
+	t.Run("should reject unknown policy", func(t *testing.T) {
+		config.WhatsappWebhookQueueOverflow = "discard"
+		assert.Error(t, StartWebhookDispatcher())
+	})
+}
+
+func TestEnqueueEndpointDelivery(t *testing.T) {
+	originalPath, originalStore := config.PathStoreDB, appStore
+	defer func() {
+		config.PathStoreDB, appStore = originalPath, originalStore
+	}()
+
+	config.PathStoreDB = filepath.Join(t.TempDir(), "app.db")
+	assert.NoError(t, InitStore())
+	defer appStore.Close()
+
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	defer close(release)
+
+	delivered := make(chan string, 1)
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered <- r.URL.Path
+	}))
+	defer healthy.Close()
+
+	event := webhookEvent{Type: "message"}
+	brokenEndpoint := WebhookEndpoint{URL: broken.URL, MaxAttempts: 1}
+	enqueueEndpointDelivery(webhookDelivery{event: event, endpoint: brokenEndpoint, body: []byte(`{"id":0}`)})
+	<-started
+	for i := 1; i < webhookEndpointQueueSize+2; i++ {
+		enqueueEndpointDelivery(webhookDelivery{event: event, endpoint: brokenEndpoint, body: []byte(fmt.Sprintf(`{"id":%d}`, i))})
+	}
+	enqueueEndpointDelivery(webhookDelivery{event: event, endpoint: WebhookEndpoint{URL: healthy.URL + "/healthy"}, body: []byte(`{}`)})
+
+	select {
+	case path := <-delivered:
+		assert.Equal(t, "/healthy", path)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the healthy endpoint waited for the broken one")
+	}
+
+	var queued int
+	assert.NoError(t, appStore.QueryRow(`SELECT COUNT(*) FROM webhook_outbox WHERE url = ?`, broken.URL).Scan(&queued))
+	assert.Equal(t, 1, queued, "the delivery finding the endpoint queue full goes to the outbox")
+
+	webhookEndpointQueuesMu.Lock()
+	for queue := webhookEndpointQueues[broken.URL]; len(queue) > 0; {
+		<-queue
+	}
+	webhookEndpointQueuesMu.Unlock()
+}
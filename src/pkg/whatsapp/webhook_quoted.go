@@ -0,0 +1,88 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+)
+
+type evtQuoted struct {
+	ID          string `json:"id"`
+	Participant string `json:"participant,omitempty"`
+	Chat        string `json:"chat,omitempty"` // only set when the quoted message is from another chat
+	Type        string `json:"type,omitempty"`
+	Text        string `json:"text,omitempty"`
+}
+
+type contextInfoMessage interface {
+	GetContextInfo() *waE2E.ContextInfo
+}
+
+// getContextInfo returns the context info (reply, mentions, ephemeral settings, ...) of the message whatever its type
+func getContextInfo(msg *waE2E.Message) *waE2E.ContextInfo {
+	candidates := []contextInfoMessage{
+		msg.GetExtendedTextMessage(),
+		msg.GetImageMessage(),
+		msg.GetVideoMessage(),
+		msg.GetAudioMessage(),
+		msg.GetDocumentMessage(),
+		msg.GetStickerMessage(),
+		msg.GetContactMessage(),
+		msg.GetContactsArrayMessage(),
+		msg.GetLocationMessage(),
+		msg.GetLiveLocationMessage(),
+		msg.GetListMessage(),
+		msg.GetListResponseMessage(),
+		msg.GetButtonsMessage(),
+		msg.GetButtonsResponseMessage(),
+		msg.GetTemplateMessage(),
+		msg.GetTemplateButtonReplyMessage(),
+		msg.GetInteractiveMessage(),
+		msg.GetInteractiveResponseMessage(),
+		msg.GetPollCreationMessage(),
+		msg.GetPollCreationMessageV3(),
+		msg.GetProductMessage(),
+	}
+	for _, candidate := range candidates {
+		// the getters are nil safe, a missing message type has no context info
+		if contextInfo := candidate.GetContextInfo(); contextInfo != nil {
+			return contextInfo
+		}
+	}
+	return nil
+}
+
+// buildEventQuoted describes the message replied to, so consumers don't need their own message store
+func buildEventQuoted(msg *waE2E.Message) *evtQuoted {
+	contextInfo := getContextInfo(msg)
+	if contextInfo.GetStanzaID() == "" {
+		return nil
+	}
+
+	quotedMessage := contextInfo.GetQuotedMessage()
+	quoted := &evtQuoted{
+		ID:          contextInfo.GetStanzaID(),
+		Participant: contextInfo.GetParticipant(),
+		Chat:        contextInfo.GetRemoteJID(),
+		Type:        webhookMessageType(quotedMessage),
+		Text:        webhookMessageText(quotedMessage),
+	}
+	if quoted.Text == "" && quotedMessage != nil {
+		quoted.Text = quotedMessageSummary(quotedMessage)
+	}
+	return quoted
+}
+
+// quotedMessageSummary describes the quoted messages without text or caption
+func quotedMessageSummary(msg *waE2E.Message) string {
+	switch {
+	case msg.GetContactMessage() != nil:
+		return msg.GetContactMessage().GetDisplayName()
+	case msg.GetLocationMessage() != nil:
+		return msg.GetLocationMessage().GetName()
+	case getPollCreation(msg) != nil:
+		return getPollCreation(msg).GetName()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetFileName()
+	default:
+		return ""
+	}
+}
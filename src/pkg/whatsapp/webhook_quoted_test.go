@@ -0,0 +1,51 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestBuildEventQuoted(t *testing.T) {
+	testCases := []struct {
+		name     string
+		msg      *waE2E.Message
+		expected *evtQuoted
+	}{
+		{
+			name: "should describe a quoted text",
+			msg: &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+				Text: proto.String("sure"),
+				ContextInfo: &waE2E.ContextInfo{
+					StanzaID:      proto.String("QUOTED1"),
+					Participant:   proto.String("628123456789@s.whatsapp.net"),
+					QuotedMessage: &waE2E.Message{Conversation: proto.String("lunch?")},
+				},
+			}},
+			expected: &evtQuoted{ID: "QUOTED1", Participant: "628123456789@s.whatsapp.net", Type: "text", Text: "lunch?"},
+		},
+		{
+			name: "should describe a quoted location from an image reply",
+			msg: &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+				ContextInfo: &waE2E.ContextInfo{
+					StanzaID:      proto.String("QUOTED2"),
+					QuotedMessage: &waE2E.Message{LocationMessage: &waE2E.LocationMessage{Name: proto.String("Office")}},
+				},
+			}},
+			expected: &evtQuoted{ID: "QUOTED2", Type: "location", Text: "Office"},
+		},
+		{
+			name:     "should skip messages that aren't replies",
+			msg:      &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String("hello"), ContextInfo: &waE2E.ContextInfo{}}},
+			expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, buildEventQuoted(tc.msg))
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateReceiptPayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	reader := types.NewADJID("628123456789", 0, 2)
+	me := types.NewJID("628987654321", types.DefaultUserServer)
+
+	payload, err := createReceiptPayload(&events.Receipt{
+		MessageSource: types.MessageSource{Chat: group, Sender: reader, IsGroup: true},
+		MessageIDs:    []types.MessageID{"ABC"},
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		Type:          types.ReceiptTypePlayed,
+		MessageSender: me,
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "played", payload["type"])
+	assert.Equal(t, group.String(), payload["chat"])
+	assert.Equal(t, "628123456789@s.whatsapp.net", payload["recipient"])
+	assert.Equal(t, me.String(), payload["message_sender"])
+	assert.Equal(t, []types.MessageID{"ABC"}, payload["message_ids"])
+}
@@ -0,0 +1,79 @@
+package whatsapp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+// webhookResponseMaxSize caps how much of a webhook response body is read
+const webhookResponseMaxSize = 64 << 10
+
+// webhookReply is the message a webhook receiver may answer with, either
+// `{"reply": "text"}` or `{"reply": {"text": "text", "quote": true}}`
+type webhookReply struct {
+	Text  string `json:"text"`
+	Quote bool   `json:"quote"`
+}
+
+func (reply *webhookReply) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		reply.Text = text
+		return nil
+	}
+
+	type plainReply webhookReply
+	return json.Unmarshal(data, (*plainReply)(reply))
+}
+
+// parseWebhookReply extracts the reply of a webhook response, ok is false when it has none
+func parseWebhookReply(response []byte) (reply webhookReply, ok bool) {
+	var body struct {
+		Reply *webhookReply `json:"reply"`
+	}
+	if len(response) == 0 || json.Unmarshal(response, &body) != nil || body.Reply == nil {
+		return reply, false
+	}
+	if strings.TrimSpace(body.Reply.Text) == "" {
+		return reply, false
+	}
+	return *body.Reply, true
+}
+
+// handleWebhookReply sends the reply returned by a webhook receiver into the chat of the message
+func handleWebhookReply(evt *events.Message, endpoint WebhookEndpoint, response []byte) {
+	reply, ok := parseWebhookReply(response)
+	if !ok || cli == nil {
+		return
+	}
+	// never answer our own messages, a receiver replying to every message would loop forever
+	if evt.Info.IsFromMe {
+		return
+	}
+
+	msg := &waE2E.Message{Conversation: proto.String(reply.Text)}
+	if reply.Quote {
+		msg = &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{
+			Text: proto.String(reply.Text),
+			ContextInfo: &waE2E.ContextInfo{
+				StanzaID:      proto.String(evt.Info.ID),
+				Participant:   proto.String(evt.Info.Sender.ToNonAD().String()),
+				QuotedMessage: evt.Message,
+			},
+		}}
+	}
+
+	resp, err := SendOutbound(context.Background(), cli, evt.Info.Chat, msg)
+	if err != nil {
+		logrus.Errorf("Failed to send webhook reply from %s to %s: %v", endpoint.URL, evt.Info.Chat, err)
+		return
+	}
+	RecordSentMessage(evt.Info.Chat, resp, msg, reply.Text)
+	logrus.Infof("Sent webhook reply from %s to %s", endpoint.URL, evt.Info.Chat)
+}
@@ -0,0 +1,31 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookReply(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     webhookReply
+		wantOk   bool
+	}{
+		{name: "should parse text reply", response: `{"reply": "hello"}`, want: webhookReply{Text: "hello"}, wantOk: true},
+		{name: "should parse message spec", response: `{"reply": {"text": "hello", "quote": true}}`, want: webhookReply{Text: "hello", Quote: true}, wantOk: true},
+		{name: "should ignore empty response", response: ``},
+		{name: "should ignore response without reply", response: `{"status": "ok"}`},
+		{name: "should ignore blank reply", response: `{"reply": "  "}`},
+		{name: "should ignore non json response", response: `OK`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reply, ok := parseWebhookReply([]byte(tt.response))
+			assert.Equal(t, tt.wantOk, ok)
+			assert.Equal(t, tt.want, reply)
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// createRevokePayload builds the payload of a message deleted for everyone, nil when the event isn't a revoke
+func createRevokePayload(evt *events.Message) map[string]interface{} {
+	protocolMessage := evt.Message.GetProtocolMessage()
+	if protocolMessage == nil || protocolMessage.GetType() != waE2E.ProtocolMessage_REVOKE {
+		return nil
+	}
+
+	body := make(map[string]interface{})
+	body["event_type"] = "revoke"
+	body["chat"] = evt.Info.Chat.String()
+	body["message_id"] = protocolMessage.GetKey().GetID()
+	body["revoked_by"] = evt.Info.Sender.ToNonAD().String()
+	body["from_me"] = evt.Info.IsFromMe
+	body["timestamp"] = evt.Info.Timestamp.Format(time.RFC3339)
+
+	// group admins can revoke the messages of other participants
+	if participant := protocolMessage.GetKey().GetParticipant(); participant != "" {
+		body["message_sender"] = participant
+	}
+	return body
+}
@@ -0,0 +1,44 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waCommon"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCreateRevokePayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	admin := types.NewADJID("628123456789", 0, 1)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: group, Sender: admin, IsGroup: true},
+		ID:            "REVOKE1",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	payload := createRevokePayload(&events.Message{Info: info, Message: &waE2E.Message{
+		ProtocolMessage: &waE2E.ProtocolMessage{
+			Type: waE2E.ProtocolMessage_REVOKE.Enum(),
+			Key:  &waCommon.MessageKey{ID: proto.String("ORIGINAL1"), Participant: proto.String("628987654321@s.whatsapp.net")},
+		},
+	}})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":     "revoke",
+		"chat":           group.String(),
+		"message_id":     "ORIGINAL1",
+		"revoked_by":     "628123456789@s.whatsapp.net",
+		"message_sender": "628987654321@s.whatsapp.net",
+		"from_me":        false,
+		"timestamp":      "2025-01-02T03:04:05Z",
+	}, payload)
+
+	assert.Nil(t, createRevokePayload(&events.Message{Info: info, Message: &waE2E.Message{Conversation: proto.String("hello")}}))
+	assert.Nil(t, createRevokePayload(&events.Message{Info: info, Message: &waE2E.Message{
+		ProtocolMessage: &waE2E.ProtocolMessage{Type: waE2E.ProtocolMessage_MESSAGE_EDIT.Enum()},
+	}}))
+}
@@ -0,0 +1,197 @@
+package whatsapp
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// Chat types a webhook rule can match
+const (
+	WebhookChatTypeGroup   = "group"
+	WebhookChatTypePrivate = "private"
+)
+
+// WebhookRule filters the events delivered to an endpoint, every condition that is set must match.
+// An endpoint with rules receives an event when at least one of its rules matches.
+type WebhookRule struct {
+	Chats        []string `json:"chats,omitempty" mapstructure:"chats"`                 // chat JIDs or phone numbers
+	ChatType     string   `json:"chat_type,omitempty" mapstructure:"chat_type"`         // group or private
+	MessageTypes []string `json:"message_types,omitempty" mapstructure:"message_types"` // e.g. text, image, document
+	Senders      []string `json:"senders,omitempty" mapstructure:"senders"`             // sender JIDs or phone numbers
+	Keyword      string   `json:"keyword,omitempty" mapstructure:"keyword"`             // regular expression on the message text
+
+	keyword *regexp.Regexp
+}
+
+// webhookEventSource describes where an event comes from, it's what the endpoint rules are evaluated on
+type webhookEventSource struct {
+	Chat        types.JID
+	Sender      types.JID
+	MessageType string
+	Text        string
+}
+
+// newMessageEventSource builds the rule source of a message event
+func newMessageEventSource(evt *events.Message) *webhookEventSource {
+	return &webhookEventSource{
+		Chat:        evt.Info.Chat,
+		Sender:      evt.Info.Sender,
+		MessageType: webhookMessageType(evt.Message),
+		Text:        webhookMessageText(evt.Message),
+	}
+}
+
+// prepareRules validates the rules and compiles their keyword expressions
+func (endpoint *WebhookEndpoint) prepareRules() (err error) {
+	for i := range endpoint.Rules {
+		rule := &endpoint.Rules[i]
+		rule.ChatType = strings.ToLower(strings.TrimSpace(rule.ChatType))
+		if rule.ChatType != "" && rule.ChatType != WebhookChatTypeGroup && rule.ChatType != WebhookChatTypePrivate {
+			return fmt.Errorf("rule #%d: unknown chat_type %q, use %s or %s", i+1, rule.ChatType, WebhookChatTypeGroup, WebhookChatTypePrivate)
+		}
+		if rule.Keyword != "" {
+			if rule.keyword, err = regexp.Compile(rule.Keyword); err != nil {
+				return fmt.Errorf("rule #%d: invalid keyword: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// AcceptsSource reports whether the event source passes the rules of the endpoint
+func (endpoint WebhookEndpoint) AcceptsSource(source *webhookEventSource) bool {
+	if len(endpoint.Rules) == 0 {
+		return true
+	}
+	if source == nil {
+		source = &webhookEventSource{}
+	}
+	for _, rule := range endpoint.Rules {
+		if rule.matches(*source) {
+			return true
+		}
+	}
+	return false
+}
+
+func (rule WebhookRule) matches(source webhookEventSource) bool {
+	if len(rule.Chats) > 0 && !matchesJID(rule.Chats, source.Chat) {
+		return false
+	}
+	if len(rule.Senders) > 0 && !matchesJID(rule.Senders, source.Sender) {
+		return false
+	}
+	switch rule.ChatType {
+	case WebhookChatTypeGroup:
+		if source.Chat.Server != types.GroupServer {
+			return false
+		}
+	case WebhookChatTypePrivate:
+		if source.Chat.IsEmpty() || source.Chat.Server == types.GroupServer {
+			return false
+		}
+	}
+	if len(rule.MessageTypes) > 0 && !containsFold(rule.MessageTypes, source.MessageType) {
+		return false
+	}
+	if rule.Keyword != "" && (rule.keyword == nil || !rule.keyword.MatchString(source.Text)) {
+		return false
+	}
+	return true
+}
+
+// matchesJID reports whether the jid equals one of the values, given as full JID or phone number
+func matchesJID(values []string, jid types.JID) bool {
+	if jid.IsEmpty() {
+		return false
+	}
+	nonAD := jid.ToNonAD().String()
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == nonAD || value == jid.User || value == jid.String() {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(values []string, value string) bool {
+	for _, candidate := range values {
+		if strings.EqualFold(strings.TrimSpace(candidate), value) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookMessageType returns the content type of a message, as used by the webhook rules
+func webhookMessageType(msg *waE2E.Message) string {
+	switch {
+	case msg == nil:
+		return ""
+	case msg.GetConversation() != "" || msg.GetExtendedTextMessage() != nil:
+		return "text"
+	case msg.GetImageMessage() != nil:
+		return "image"
+	case msg.GetVideoMessage() != nil:
+		return "video"
+	case msg.GetAudioMessage() != nil:
+		return "audio"
+	case msg.GetDocumentMessage() != nil:
+		return "document"
+	case msg.GetStickerMessage() != nil:
+		return "sticker"
+	case msg.GetContactMessage() != nil || msg.GetContactsArrayMessage() != nil:
+		return "contact"
+	case msg.GetLocationMessage() != nil:
+		return "location"
+	case msg.GetLiveLocationMessage() != nil:
+		return "live_location"
+	case msg.GetListMessage() != nil:
+		return "list"
+	case msg.GetOrderMessage() != nil:
+		return "order"
+	case msg.GetButtonsMessage() != nil || msg.GetTemplateMessage() != nil || msg.GetInteractiveMessage() != nil:
+		return "interactive"
+	case msg.GetButtonsResponseMessage() != nil || msg.GetTemplateButtonReplyMessage() != nil ||
+		msg.GetInteractiveResponseMessage() != nil || msg.GetListResponseMessage() != nil:
+		return "button_reply"
+	case getPollCreation(msg) != nil:
+		return "poll"
+	case msg.GetPollUpdateMessage() != nil:
+		return "poll_vote"
+	case msg.GetReactionMessage() != nil:
+		return "reaction"
+	case msg.GetPinInChatMessage() != nil:
+		return "pin"
+	case msg.GetKeepInChatMessage() != nil:
+		return "keep_in_chat"
+	case msg.GetProtocolMessage() != nil:
+		return "protocol"
+	default:
+		return "unknown"
+	}
+}
+
+// webhookMessageText returns the text or caption of a message, as matched by the keyword rules
+func webhookMessageText(msg *waE2E.Message) string {
+	switch {
+	case msg.GetConversation() != "":
+		return msg.GetConversation()
+	case msg.GetExtendedTextMessage() != nil:
+		return msg.GetExtendedTextMessage().GetText()
+	case msg.GetImageMessage() != nil:
+		return msg.GetImageMessage().GetCaption()
+	case msg.GetVideoMessage() != nil:
+		return msg.GetVideoMessage().GetCaption()
+	case msg.GetDocumentMessage() != nil:
+		return msg.GetDocumentMessage().GetCaption()
+	default:
+		return ""
+	}
+}
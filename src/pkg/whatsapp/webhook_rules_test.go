@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestWebhookEndpointAcceptsSource(t *testing.T) {
+	supportGroup := types.NewJID("120363025246125888", types.GroupServer)
+	customer := types.NewJID("628123456789", types.DefaultUserServer)
+
+	groupText := &webhookEventSource{Chat: supportGroup, Sender: customer, MessageType: "text", Text: "I need HELP please"}
+	privateImage := &webhookEventSource{Chat: customer, Sender: customer, MessageType: "image"}
+
+	tests := []struct {
+		name   string
+		rules  []WebhookRule
+		source *webhookEventSource
+		want   bool
+	}{
+		{name: "should accept everything without rules", source: groupText, want: true},
+		{name: "should match chat jid", rules: []WebhookRule{{Chats: []string{supportGroup.String()}}}, source: groupText, want: true},
+		{name: "should reject other chat", rules: []WebhookRule{{Chats: []string{supportGroup.String()}}}, source: privateImage, want: false},
+		{name: "should match group chat type", rules: []WebhookRule{{ChatType: "group"}}, source: groupText, want: true},
+		{name: "should match private chat type", rules: []WebhookRule{{ChatType: "private"}}, source: groupText, want: false},
+		{name: "should match sender phone number", rules: []WebhookRule{{Senders: []string{"628123456789"}}}, source: privateImage, want: true},
+		{name: "should match message type", rules: []WebhookRule{{MessageTypes: []string{"Image"}}}, source: privateImage, want: true},
+		{name: "should match keyword", rules: []WebhookRule{{Keyword: "(?i)help"}}, source: groupText, want: true},
+		{name: "should require every condition of a rule", rules: []WebhookRule{{ChatType: "group", MessageTypes: []string{"image"}}}, source: groupText, want: false},
+		{name: "should reject when no rule matches", rules: []WebhookRule{{ChatType: "private"}, {Keyword: "help"}}, source: groupText, want: false},
+		{name: "should accept when any rule matches", rules: []WebhookRule{{ChatType: "private"}, {Keyword: "HELP"}}, source: groupText, want: true},
+		{name: "should reject source-less event on chat rules", rules: []WebhookRule{{ChatType: "group"}}, source: nil, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			endpoint := WebhookEndpoint{URL: "https://example.com", Rules: tt.rules}
+			assert.NoError(t, endpoint.prepare())
+			assert.Equal(t, tt.want, endpoint.AcceptsSource(tt.source))
+		})
+	}
+}
+
+func TestWebhookRuleValidation(t *testing.T) {
+	assert.Error(t, (&WebhookEndpoint{Rules: []WebhookRule{{ChatType: "channel"}}}).prepare())
+	assert.Error(t, (&WebhookEndpoint{Rules: []WebhookRule{{Keyword: "(unclosed"}}}).prepare())
+}
+
+func TestWebhookMessageType(t *testing.T) {
+	assert.Equal(t, "text", webhookMessageType(&waE2E.Message{Conversation: proto.String("hi")}))
+	assert.Equal(t, "image", webhookMessageType(&waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String("look")}}))
+	assert.Equal(t, "look", webhookMessageText(&waE2E.Message{ImageMessage: &waE2E.ImageMessage{Caption: proto.String("look")}}))
+}
@@ -0,0 +1,142 @@
+package whatsapp
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"hash"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+)
+
+// Supported webhook signature algorithms
+const (
+	WebhookSignatureSHA256  = "sha256"
+	WebhookSignatureSHA512  = "sha512"
+	WebhookSignatureEd25519 = "ed25519"
+)
+
+// prepareSignature validates the signature algorithm and loads the Ed25519 private key
+func (endpoint *WebhookEndpoint) prepareSignature() (err error) {
+	endpoint.Signature = strings.ToLower(strings.TrimSpace(endpoint.Signature))
+	switch endpoint.Signature {
+	case "", WebhookSignatureSHA256, WebhookSignatureSHA512:
+		return nil
+	case WebhookSignatureEd25519:
+		if endpoint.PrivateKey == "" {
+			return fmt.Errorf("ed25519 signature requires a private_key")
+		}
+		endpoint.privateKey, err = parseEd25519PrivateKey(endpoint.PrivateKey)
+		return err
+	default:
+		return fmt.Errorf("unsupported signature %q, use %s, %s or %s", endpoint.Signature, WebhookSignatureSHA256, WebhookSignatureSHA512, WebhookSignatureEd25519)
+	}
+}
+
+// parseEd25519PrivateKey accepts a PKCS#8 PEM block, or a base64 encoded 32 byte seed / 64 byte private key
+func parseEd25519PrivateKey(value string) (ed25519.PrivateKey, error) {
+	if block, _ := pem.Decode([]byte(value)); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ed25519 private key: %w", err)
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key is not an ed25519 key")
+		}
+		return privateKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ed25519 private key: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	default:
+		return nil, fmt.Errorf("invalid ed25519 private key length %d", len(raw))
+	}
+}
+
+// webhookSecret returns the HMAC secret of the endpoint, falling back to the global webhook secret
+func (endpoint WebhookEndpoint) webhookSecret() []byte {
+	if endpoint.Secret != "" {
+		return []byte(endpoint.Secret)
+	}
+	return []byte(config.WhatsappWebhookSecret)
+}
+
+// signWebhookRequest sets the signature headers of a delivery attempt.
+// X-Hub-Signature-256 keeps signing the body only for existing receivers, while X-Webhook-Signature
+// signs "<timestamp>.<nonce>.<body>" so receivers can reject stale or replayed requests.
+func signWebhookRequest(req *http.Request, endpoint WebhookEndpoint, postBody []byte) error {
+	legacySignature, err := getMessageDigestOrSignature(postBody, endpoint.webhookSecret())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Hub-Signature-256", fmt.Sprintf("sha256=%s", legacySignature))
+
+	nonce := make([]byte, 16)
+	if _, err = rand.Read(nonce); err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	nonceHex := hex.EncodeToString(nonce)
+
+	signature, err := endpoint.sign(webhookSignedContent(timestamp, nonceHex, postBody))
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("X-Webhook-Timestamp", timestamp)
+	req.Header.Set("X-Webhook-Nonce", nonceHex)
+	req.Header.Set("X-Webhook-Signature", signature)
+	return nil
+}
+
+// webhookSignedContent builds the content covered by X-Webhook-Signature
+func webhookSignedContent(timestamp string, nonce string, postBody []byte) []byte {
+	content := make([]byte, 0, len(timestamp)+len(nonce)+len(postBody)+2)
+	content = append(content, timestamp...)
+	content = append(content, '.')
+	content = append(content, nonce...)
+	content = append(content, '.')
+	return append(content, postBody...)
+}
+
+// sign returns the "<algorithm>=<signature>" value of the content for the endpoint algorithm
+func (endpoint WebhookEndpoint) sign(content []byte) (string, error) {
+	switch endpoint.Signature {
+	case WebhookSignatureEd25519:
+		if endpoint.privateKey == nil {
+			return "", fmt.Errorf("ed25519 private key is not loaded")
+		}
+		return fmt.Sprintf("%s=%s", WebhookSignatureEd25519, base64.StdEncoding.EncodeToString(ed25519.Sign(endpoint.privateKey, content))), nil
+	case WebhookSignatureSHA512:
+		return hmacSignature(WebhookSignatureSHA512, sha512.New, endpoint.webhookSecret(), content)
+	default:
+		return hmacSignature(WebhookSignatureSHA256, sha256.New, endpoint.webhookSecret(), content)
+	}
+}
+
+func hmacSignature(algorithm string, hashFunc func() hash.Hash, key []byte, content []byte) (string, error) {
+	mac := hmac.New(hashFunc, key)
+	if _, err := mac.Write(content); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s=%s", algorithm, hex.EncodeToString(mac.Sum(nil))), nil
+}
@@ -0,0 +1,62 @@
+package whatsapp
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignWebhookRequest(t *testing.T) {
+	postBody := []byte(`{"event_type":"message"}`)
+
+	t.Run("should sign timestamp, nonce and body with sha512", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com", Signature: "SHA512", Secret: "top-secret"}
+		assert.NoError(t, endpoint.prepare())
+
+		req, _ := http.NewRequest(http.MethodPost, endpoint.URL, nil)
+		assert.NoError(t, signWebhookRequest(req, endpoint, postBody))
+
+		mac := hmac.New(sha512.New, []byte("top-secret"))
+		mac.Write(webhookSignedContent(req.Header.Get("X-Webhook-Timestamp"), req.Header.Get("X-Webhook-Nonce"), postBody))
+		assert.Equal(t, "sha512="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get("X-Webhook-Signature"))
+		assert.True(t, strings.HasPrefix(req.Header.Get("X-Hub-Signature-256"), "sha256="))
+		assert.Len(t, req.Header.Get("X-Webhook-Nonce"), 32)
+	})
+
+	t.Run("should sign with ed25519", func(t *testing.T) {
+		publicKey, privateKey, err := ed25519.GenerateKey(nil)
+		assert.NoError(t, err)
+
+		endpoint := WebhookEndpoint{URL: "https://example.com", Signature: "ed25519", PrivateKey: base64.StdEncoding.EncodeToString(privateKey.Seed())}
+		assert.NoError(t, endpoint.prepare())
+
+		req, _ := http.NewRequest(http.MethodPost, endpoint.URL, nil)
+		assert.NoError(t, signWebhookRequest(req, endpoint, postBody))
+
+		signature, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(req.Header.Get("X-Webhook-Signature"), "ed25519="))
+		assert.NoError(t, err)
+		content := webhookSignedContent(req.Header.Get("X-Webhook-Timestamp"), req.Header.Get("X-Webhook-Nonce"), postBody)
+		assert.True(t, ed25519.Verify(publicKey, content, signature))
+	})
+
+	t.Run("should use a new nonce for every request", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com"}
+		first, _ := http.NewRequest(http.MethodPost, endpoint.URL, nil)
+		second, _ := http.NewRequest(http.MethodPost, endpoint.URL, nil)
+		assert.NoError(t, signWebhookRequest(first, endpoint, postBody))
+		assert.NoError(t, signWebhookRequest(second, endpoint, postBody))
+		assert.NotEqual(t, first.Header.Get("X-Webhook-Nonce"), second.Header.Get("X-Webhook-Nonce"))
+	})
+
+	t.Run("should reject unknown algorithm or missing key", func(t *testing.T) {
+		assert.Error(t, (&WebhookEndpoint{URL: "https://example.com", Signature: "md5"}).prepare())
+		assert.Error(t, (&WebhookEndpoint{URL: "https://example.com", Signature: "ed25519"}).prepare())
+	})
+}
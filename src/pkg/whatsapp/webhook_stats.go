@@ -0,0 +1,109 @@
+package whatsapp
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// WebhookEndpointStats holds the delivery counters of a single webhook url
+type WebhookEndpointStats struct {
+	URL              string
+	Delivered        int64
+	Failed           int64
+	Retried          int64
+	Pending          int64
+	LastError        string
+	LastErrorAt      time.Time
+	LastSuccessAt    time.Time
+	AverageLatency   time.Duration
+	deliveredLatency time.Duration
+}
+
+var (
+	webhookStatsMu sync.Mutex
+	webhookStats   = make(map[string]*WebhookEndpointStats)
+)
+
+func webhookStatsFor(url string) *WebhookEndpointStats {
+	stats, ok := webhookStats[url]
+	if !ok {
+		stats = &WebhookEndpointStats{URL: url}
+		webhookStats[url] = stats
+	}
+	return stats
+}
+
+// recordWebhookAttempt collects the result of a single delivery attempt
+func recordWebhookAttempt(url string, latency time.Duration, err error) {
+	webhookStatsMu.Lock()
+	defer webhookStatsMu.Unlock()
+
+	stats := webhookStatsFor(url)
+	if err != nil {
+		stats.Failed++
+		stats.LastError = err.Error()
+		stats.LastErrorAt = time.Now()
+		return
+	}
+
+	stats.Delivered++
+	stats.LastSuccessAt = time.Now()
+	stats.deliveredLatency += latency
+	stats.AverageLatency = stats.deliveredLatency / time.Duration(stats.Delivered)
+}
+
+// recordWebhookRetry counts a delivery attempt that is a retry of an earlier failure
+func recordWebhookRetry(url string) {
+	webhookStatsMu.Lock()
+	defer webhookStatsMu.Unlock()
+
+	webhookStatsFor(url).Retried++
+}
+
+// GetWebhookStats returns the delivery statistics of every known webhook url, sorted by url
+func GetWebhookStats() ([]WebhookEndpointStats, error) {
+	pending := make(map[string]int64)
+	if appStore != nil {
+		rows, err := appStore.Query(`SELECT url, COUNT(*) FROM webhook_outbox GROUP BY url`)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var (
+				url   string
+				count int64
+			)
+			if err = rows.Scan(&url, &count); err != nil {
+				return nil, err
+			}
+			pending[url] = count
+		}
+		if err = rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	webhookStatsMu.Lock()
+	defer webhookStatsMu.Unlock()
+
+	for _, endpoint := range currentWebhookEndpoints() {
+		webhookStatsFor(endpoint.URL)
+	}
+	for url := range pending {
+		webhookStatsFor(url)
+	}
+
+	result := make([]WebhookEndpointStats, 0, len(webhookStats))
+	for url, stats := range webhookStats {
+		snapshot := *stats
+		snapshot.Pending = pending[url]
+		result = append(result, snapshot)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].URL < result[j].URL
+	})
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package whatsapp
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"text/template"
+
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+)
+
+var webhookTemplateFuncs = template.FuncMap{
+	// json renders any value as a JSON literal, e.g. {{ json .from }}
+	"json": func(value interface{}) (string, error) {
+		encoded, err := json.Marshal(value)
+		return string(encoded), err
+	},
+	// default returns the fallback when the value is empty, e.g. {{ default "unknown" .pushname }}
+	"default": func(fallback interface{}, value interface{}) interface{} {
+		if value == nil || value == "" {
+			return fallback
+		}
+		return value
+	},
+}
+
+// compileWebhookTemplate parses the payload template of a webhook endpoint
+func compileWebhookTemplate(text string) (*template.Template, error) {
+	return template.New("webhook").Funcs(webhookTemplateFuncs).Option("missingkey=zero").Parse(text)
+}
+
+// renderWebhookBody builds the request body for the endpoint, reshaping the payload
+// through the endpoint template when one is configured
+func renderWebhookBody(endpoint WebhookEndpoint, payload map[string]interface{}) ([]byte, error) {
+	body, err := json.Marshal(inlineWebhookMedia(endpoint, payload))
+	if err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to marshal body: %v", err))
+	}
+	if endpoint.template == nil {
+		return body, nil
+	}
+
+	// templates work on the plain JSON representation so field names match the default payload
+	var data map[string]interface{}
+	if err = json.Unmarshal(body, &data); err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to prepare template data: %v", err))
+	}
+
+	var rendered bytes.Buffer
+	if err = endpoint.template.Execute(&rendered, data); err != nil {
+		return nil, pkgError.WebhookError(fmt.Sprintf("Failed to render webhook template for %s: %v", endpoint.URL, err))
+	}
+	if !json.Valid(rendered.Bytes()) {
+		return nil, pkgError.WebhookError(fmt.Sprintf("webhook template for %s did not render valid JSON", endpoint.URL))
+	}
+	return rendered.Bytes(), nil
+}
@@ -0,0 +1,42 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWebhookBody(t *testing.T) {
+	payload := map[string]interface{}{
+		"from":    "628123456789@s.whatsapp.net",
+		"message": evtMessage{ID: "ABC", Text: "hello \"world\""},
+	}
+
+	t.Run("should send default payload without template", func(t *testing.T) {
+		body, err := renderWebhookBody(WebhookEndpoint{URL: "https://example.com"}, payload)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"from":"628123456789@s.whatsapp.net","message":{"id":"ABC","text":"hello \"world\""}}`, string(body))
+	})
+
+	t.Run("should reshape payload with template", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com", Template: `{"sender": {{ json .from }}, "body": {{ json .message.text }}, "name": {{ json (default "unknown" .pushname) }}}`}
+		assert.NoError(t, endpoint.prepare())
+
+		body, err := renderWebhookBody(endpoint, payload)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"sender":"628123456789@s.whatsapp.net","body":"hello \"world\"","name":"unknown"}`, string(body))
+	})
+
+	t.Run("should reject template rendering invalid json", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com", Template: `sender={{ .from }}`}
+		assert.NoError(t, endpoint.prepare())
+
+		_, err := renderWebhookBody(endpoint, payload)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject invalid template", func(t *testing.T) {
+		endpoint := WebhookEndpoint{URL: "https://example.com", Template: `{{ .from `}
+		assert.Error(t, endpoint.prepare())
+	})
+}
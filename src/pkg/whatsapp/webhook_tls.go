@@ -0,0 +1,78 @@
+package whatsapp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// WebhookTLSConfig configures the TLS client of a webhook endpoint, e.g. for receivers requiring mutual TLS
+type WebhookTLSConfig struct {
+	CertFile           string `json:"cert_file,omitempty" mapstructure:"cert_file"`
+	KeyFile            string `json:"key_file,omitempty" mapstructure:"key_file"`
+	CAFile             string `json:"ca_file,omitempty" mapstructure:"ca_file"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty" mapstructure:"insecure_skip_verify"`
+}
+
+// webhookDefaultClient is used by endpoints without a custom TLS config,
+// the timeout of every attempt is set on the request context
+var webhookDefaultClient = &http.Client{}
+
+// prepareClient builds the HTTP client of the endpoint when it has a custom TLS config
+func (endpoint *WebhookEndpoint) prepareClient() error {
+	if endpoint.TLS == nil {
+		return nil
+	}
+
+	tlsConfig, err := endpoint.TLS.build()
+	if err != nil {
+		return err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	endpoint.client = &http.Client{Transport: transport}
+	return nil
+}
+
+func (cfg WebhookTLSConfig) build() (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: cfg.InsecureSkipVerify,
+	}
+
+	if cfg.CertFile != "" || cfg.KeyFile != "" {
+		if cfg.CertFile == "" || cfg.KeyFile == "" {
+			return nil, fmt.Errorf("tls cert_file and key_file must be set together")
+		}
+		certificate, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load tls client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	if cfg.CAFile != "" {
+		caBundle, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls ca_file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("tls ca_file %s has no valid certificate", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	return tlsConfig, nil
+}
+
+// httpClient returns the HTTP client used to deliver to the endpoint
+func (endpoint WebhookEndpoint) httpClient() *http.Client {
+	if endpoint.client != nil {
+		return endpoint.client
+	}
+	return webhookDefaultClient
+}
@@ -0,0 +1,38 @@
+package whatsapp
+
+import (
+	"time"
+
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// forwardUndecryptableToWebhook is a helper function to forward a message that failed to decrypt to webhook url
+func forwardUndecryptableToWebhook(evt *events.UndecryptableMessage) error {
+	return dispatchWebhookEvent(webhookEvent{
+		Type:    "undecryptable",
+		Payload: createUndecryptablePayload(evt),
+		Source:  &webhookEventSource{Chat: evt.Info.Chat, Sender: evt.Info.Sender},
+	})
+}
+
+func createUndecryptablePayload(evt *events.UndecryptableMessage) map[string]interface{} {
+	body := make(map[string]interface{})
+	body["event_type"] = "undecryptable"
+	body["chat"] = evt.Info.Chat.String()
+	body["sender"] = evt.Info.Sender.ToNonAD().String()
+	body["from_me"] = evt.Info.IsFromMe
+	body["message_id"] = evt.Info.ID
+	body["timestamp"] = evt.Info.Timestamp.Format(time.RFC3339)
+
+	// unavailable messages weren't encrypted for this device at all. Whether a resend is asked for isn't known
+	// from the event, the message event follows if one succeeds.
+	body["is_unavailable"] = evt.IsUnavailable
+	if evt.UnavailableType != events.UnavailableTypeUnknown {
+		body["unavailable_type"] = evt.UnavailableType
+	}
+	if evt.DecryptFailMode == events.DecryptFailHide {
+		// the sender asked not to show a placeholder for this message, e.g. a reaction or a poll vote
+		body["hidden"] = true
+	}
+	return body
+}
@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/types"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+func TestCreateUndecryptablePayload(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	sender := types.NewADJID("628123456789", 0, 3)
+	info := types.MessageInfo{
+		MessageSource: types.MessageSource{Chat: group, Sender: sender, IsGroup: true},
+		ID:            "MSG1",
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	payload := createUndecryptablePayload(&events.UndecryptableMessage{Info: info, DecryptFailMode: events.DecryptFailHide})
+	assert.Equal(t, map[string]interface{}{
+		"event_type":     "undecryptable",
+		"chat":           group.String(),
+		"sender":         "628123456789@s.whatsapp.net",
+		"from_me":        false,
+		"message_id":     "MSG1",
+		"timestamp":      "2025-01-02T03:04:05Z",
+		"is_unavailable": false,
+		"hidden":         true,
+	}, payload)
+
+	payload = createUndecryptablePayload(&events.UndecryptableMessage{Info: info, IsUnavailable: true, UnavailableType: events.UnavailableTypeViewOnce})
+	assert.Equal(t, true, payload["is_unavailable"])
+	assert.Equal(t, events.UnavailableTypeViewOnce, payload["unavailable_type"])
+	assert.NotContains(t, payload, "hidden")
+}
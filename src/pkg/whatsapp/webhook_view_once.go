@@ -0,0 +1,37 @@
+package whatsapp
+
+import (
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+)
+
+// unwrapViewOnce returns the event with the media of its view once wrapper, so it's extracted like regular media.
+// whatsmeow only unwraps the messages it receives, the messages we send and the ones built from raw messages
+// keep their wrapper. The media sent as view once without wrapper are flagged too.
+func unwrapViewOnce(evt *events.Message) *events.Message {
+	msg := evt.Message
+	inner := msg.GetViewOnceMessage().GetMessage()
+	if inner == nil {
+		inner = msg.GetViewOnceMessageV2().GetMessage()
+	}
+	if inner == nil {
+		inner = msg.GetViewOnceMessageV2Extension().GetMessage()
+	}
+
+	if inner == nil && (evt.IsViewOnce || !isViewOnceMedia(msg)) {
+		return evt
+	}
+
+	unwrapped := *evt
+	unwrapped.IsViewOnce = true
+	if inner != nil {
+		unwrapped.Message = inner
+	}
+	return &unwrapped
+}
+
+func isViewOnceMedia(msg *waE2E.Message) bool {
+	return msg.GetImageMessage().GetViewOnce() ||
+		msg.GetVideoMessage().GetViewOnce() ||
+		msg.GetAudioMessage().GetViewOnce()
+}
@@ -0,0 +1,48 @@
+package whatsapp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types/events"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnwrapViewOnce(t *testing.T) {
+	image := &waE2E.ImageMessage{Caption: proto.String("secret")}
+
+	testCases := []struct {
+		name             string
+		evt              *events.Message
+		expectedMessage  *waE2E.Message
+		expectedViewOnce bool
+	}{
+		{
+			name:             "should unwrap the view once wrapper",
+			evt:              &events.Message{Message: &waE2E.Message{ViewOnceMessageV2: &waE2E.FutureProofMessage{Message: &waE2E.Message{ImageMessage: image}}}},
+			expectedMessage:  &waE2E.Message{ImageMessage: image},
+			expectedViewOnce: true,
+		},
+		{
+			name:             "should flag view once media without wrapper",
+			evt:              &events.Message{Message: &waE2E.Message{VideoMessage: &waE2E.VideoMessage{ViewOnce: proto.Bool(true)}}},
+			expectedMessage:  &waE2E.Message{VideoMessage: &waE2E.VideoMessage{ViewOnce: proto.Bool(true)}},
+			expectedViewOnce: true,
+		},
+		{
+			name:             "should keep regular media",
+			evt:              &events.Message{Message: &waE2E.Message{ImageMessage: image}},
+			expectedMessage:  &waE2E.Message{ImageMessage: image},
+			expectedViewOnce: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unwrapped := unwrapViewOnce(tc.evt)
+			assert.True(t, proto.Equal(tc.expectedMessage, unwrapped.Message))
+			assert.Equal(t, tc.expectedViewOnce, unwrapped.IsViewOnce)
+		})
+	}
+}
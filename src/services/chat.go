@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+// chatPresenceRefresh resends the typing indicators, which the phones hide after about 25 seconds
+const chatPresenceRefresh = 10 * time.Second
+
+var (
+	chatPresenceMu    sync.Mutex
+	chatPresenceHolds = make(map[types.JID]*chatPresenceHold)
+)
+
+type chatPresenceHold struct {
+	cancel context.CancelFunc
+}
+
+type serviceChat struct {
+	WaCli *whatsmeow.Client
+}
+
+func NewChatService(waCli *whatsmeow.Client) domainChat.IChatService {
+	return &serviceChat{
+		WaCli: waCli,
+	}
+}
+
+func (service serviceChat) MarkAsRead(ctx context.Context, request domainChat.MarkAsReadRequest) (response domainChat.MarkAsReadResponse, err error) {
+	if err = validations.ValidateMarkChatAsRead(ctx, request); err != nil {
+		return response, err
+	}
+	chat, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	var messages []whatsapp.StoredMessage
+	if request.Until > 0 {
+		if messages, err = whatsapp.FindStoredMessagesUntil(chat, time.Unix(request.Until, 0)); err != nil {
+			return response, err
+		}
+	} else if messages, err = service.messagesToRead(chat, request); err != nil {
+		return response, err
+	}
+
+	// a receipt can only hold the messages of one sender
+	var senders []types.JID
+	idsBySender := make(map[types.JID][]types.MessageID)
+	for _, message := range messages {
+		if _, ok := idsBySender[message.Sender]; !ok {
+			senders = append(senders, message.Sender)
+		}
+		idsBySender[message.Sender] = append(idsBySender[message.Sender], message.ID)
+	}
+
+	response.JID = chat.String()
+	response.MessageIDs = []string{}
+	for _, sender := range senders {
+		if err = service.WaCli.MarkRead(idsBySender[sender], time.Now(), chat, sender); err != nil {
+			return response, err
+		}
+		response.MessageIDs = append(response.MessageIDs, idsBySender[sender]...)
+	}
+	return response, nil
+}
+
+// messagesToRead finds the sender of the message IDs, the other party in private chats
+func (service serviceChat) messagesToRead(chat types.JID, request domainChat.MarkAsReadRequest) ([]whatsapp.StoredMessage, error) {
+	sender := types.EmptyJID
+	if request.Sender != "" {
+		var err error
+		if sender, err = whatsapp.ParseJID(request.Sender); err != nil {
+			return nil, err
+		}
+	} else if chat.Server != types.GroupServer {
+		sender = chat
+	}
+
+	var messages []whatsapp.StoredMessage
+	for _, id := range request.MessageIDs {
+		stored, err := whatsapp.FindStoredMessage(chat, id)
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case stored != nil:
+			messages = append(messages, *stored)
+		case !sender.IsEmpty():
+			messages = append(messages, whatsapp.StoredMessage{Chat: chat, ID: id, Sender: sender})
+		default:
+			return nil, pkgError.ValidationError(fmt.Sprintf("message %s isn't stored, give its sender", id))
+		}
+	}
+	return messages, nil
+}
+
+func (service serviceChat) SendPresence(ctx context.Context, request domainChat.PresenceRequest) (response domainChat.PresenceResponse, err error) {
+	if err = validations.ValidateChatPresence(ctx, request); err != nil {
+		return response, err
+	}
+	chat, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	var (
+		hold        *chatPresenceHold
+		presenceCtx context.Context
+	)
+	if request.Duration > 0 {
+		var cancel context.CancelFunc
+		presenceCtx, cancel = context.WithTimeout(context.Background(), time.Duration(request.Duration)*time.Second)
+		hold = &chatPresenceHold{cancel: cancel}
+	}
+
+	// a new state replaces the one still running in the chat, swapped at once so concurrent sends keep a single hold
+	chatPresenceMu.Lock()
+	if previous, ok := chatPresenceHolds[chat]; ok {
+		previous.cancel()
+		delete(chatPresenceHolds, chat)
+	}
+	if hold != nil {
+		chatPresenceHolds[chat] = hold
+	}
+	chatPresenceMu.Unlock()
+
+	state, media := chatPresenceState(request.State)
+	if err = service.WaCli.SendChatPresence(chat, state, media); err != nil {
+		if hold != nil {
+			chatPresenceMu.Lock()
+			if chatPresenceHolds[chat] == hold {
+				delete(chatPresenceHolds, chat)
+			}
+			chatPresenceMu.Unlock()
+			hold.cancel()
+		}
+		return response, err
+	}
+	if hold != nil {
+		go service.holdChatPresence(presenceCtx, hold, chat, state, media)
+	}
+
+	response.JID = chat.String()
+	response.State = request.State
+	response.Duration = request.Duration
+	return response, nil
+}
+
+// holdChatPresence keeps the state until the context ends, then reverts to paused unless it was replaced
+func (service serviceChat) holdChatPresence(ctx context.Context, hold *chatPresenceHold, chat types.JID, state types.ChatPresence, media types.ChatPresenceMedia) {
+	ticker := time.NewTicker(chatPresenceRefresh)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := service.WaCli.SendChatPresence(chat, state, media); err != nil {
+				logrus.Warnf("Failed to refresh the presence of %s: %v", chat, err)
+			}
+		case <-ctx.Done():
+			chatPresenceMu.Lock()
+			current := chatPresenceHolds[chat] == hold
+			if current {
+				delete(chatPresenceHolds, chat)
+			}
+			chatPresenceMu.Unlock()
+			if !current || ctx.Err() == context.Canceled {
+				return
+			}
+			hold.cancel()
+			if err := service.WaCli.SendChatPresence(chat, types.ChatPresencePaused, types.ChatPresenceMediaText); err != nil {
+				logrus.Warnf("Failed to pause the presence of %s: %v", chat, err)
+			}
+			return
+		}
+	}
+}
+
+func chatPresenceState(state string) (types.ChatPresence, types.ChatPresenceMedia) {
+	switch state {
+	case "composing":
+		return types.ChatPresenceComposing, types.ChatPresenceMediaText
+	case "recording":
+		return types.ChatPresenceComposing, types.ChatPresenceMediaAudio
+	default:
+		return types.ChatPresencePaused, types.ChatPresenceMediaText
+	}
+}
+
+func (service serviceChat) SetDisappearingTimer(ctx context.Context, request domainChat.DisappearingTimerRequest) (response domainChat.DisappearingTimerResponse, err error) {
+	if err = validations.ValidateDisappearingTimer(ctx, request); err != nil {
+		return response, err
+	}
+	chat, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.JID)
+	if err != nil {
+		return response, err
+	}
+
+	timer, _ := whatsmeow.ParseDisappearingTimerString(request.Timer)
+	if err = service.WaCli.SetDisappearingTimer(chat, timer); err != nil {
+		return response, err
+	}
+
+	response.JID = chat.String()
+	response.Timer = request.Timer
+	return response, nil
+}
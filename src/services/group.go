@@ -2,13 +2,18 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/internal/rest/helpers"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/types"
 )
@@ -46,12 +51,16 @@ func (service groupService) LeaveGroup(ctx context.Context, request domainGroup.
 		return err
 	}
 
-	return service.WaCli.LeaveGroup(JID)
+	if err = service.WaCli.LeaveGroup(JID); err != nil {
+		return err
+	}
+	whatsapp.GroupLeft(JID)
+	return nil
 }
 
-func (service groupService) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (groupID string, err error) {
+func (service groupService) CreateGroup(ctx context.Context, request domainGroup.CreateGroupRequest) (response domainGroup.CreateGroupResponse, err error) {
 	if err = validations.ValidateCreateGroup(ctx, request); err != nil {
-		return groupID, err
+		return response, err
 	}
 	whatsapp.MustLogin(service.WaCli)
 
@@ -60,6 +69,14 @@ func (service groupService) CreateGroup(ctx context.Context, request domainGroup
 		return
 	}
 
+	// the photo is checked before creating the group, a group without its photo is only a warning afterwards
+	var photo []byte
+	if request.Photo != nil {
+		if photo, err = whatsapp.GroupPicture(helpers.MultipartFormFileHeaderToBytes(request.Photo)); err != nil {
+			return response, err
+		}
+	}
+
 	groupConfig := whatsmeow.ReqCreateGroup{
 		Name:              request.Title,
 		Participants:      participantsJID,
@@ -72,7 +89,84 @@ func (service groupService) CreateGroup(ctx context.Context, request domainGroup
 		return
 	}
 
-	return groupInfo.JID.String(), nil
+	response.GroupID = groupInfo.JID.String()
+	for _, participant := range groupInfo.Participants {
+		if service.isOwnParticipant(participant) {
+			continue
+		}
+		response.Participants = append(response.Participants, participantStatus(participant, whatsmeow.ParticipantChangeAdd))
+	}
+	service.offerInviteLink(groupInfo.JID, response.Participants)
+
+	if request.Description != "" {
+		if err = service.WaCli.SetGroupTopic(groupInfo.JID, groupInfo.TopicID, "", request.Description); err != nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("failed to set the description: %v", err))
+		}
+	}
+	if photo != nil {
+		if _, err = service.WaCli.SetGroupPhoto(groupInfo.JID, photo); err != nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("failed to set the photo: %v", err))
+		}
+	}
+	return response, nil
+}
+
+// participantStatus maps the result of a participant change. Users who only accept invites are refused with a
+// 403 and an add request, they can be sent the invite link instead.
+func participantStatus(participant types.GroupParticipant, action whatsmeow.ParticipantChange) domainGroup.ParticipantStatus {
+	status := domainGroup.ParticipantStatus{Participant: participant.JID.String(), Code: participant.Error}
+	switch {
+	case participant.Error == 0:
+		status.Status = "success"
+		status.Message = fmt.Sprintf("Action %s success", action)
+	case participant.Error == 403 && participant.AddRequest != nil:
+		status.Status = "error"
+		status.InviteOnly = true
+		status.Message = "The user only accepts invites, send them the invite link of the group"
+	default:
+		status.Status = "error"
+		status.Message = fmt.Sprintf("Action %s failed (code %d)", action, participant.Error)
+	}
+	return status
+}
+
+// offerInviteLink gives the invite link of the group to the participants who only accept invites, the statuses
+// are kept without it when the link can't be fetched
+func (service groupService) offerInviteLink(groupJID types.JID, statuses []domainGroup.ParticipantStatus) {
+	err := addInviteLinks(statuses, func() (string, error) {
+		return service.WaCli.GetGroupInviteLink(groupJID, false)
+	})
+	if err != nil {
+		logrus.Warnf("Failed to get the invite link of group %s: %v", groupJID, err)
+	}
+}
+
+// addInviteLinks sets the invite link on the statuses of the participants who only accept invites, the link is
+// fetched once and only when one of them does
+func addInviteLinks(statuses []domainGroup.ParticipantStatus, inviteLink func() (string, error)) error {
+	var link string
+	for i := range statuses {
+		if !statuses[i].InviteOnly {
+			continue
+		}
+		if link == "" {
+			var err error
+			if link, err = inviteLink(); err != nil {
+				return err
+			}
+		}
+		statuses[i].InviteLink = link
+	}
+	return nil
+}
+
+func (service groupService) isOwnParticipant(participant types.GroupParticipant) bool {
+	own := service.WaCli.Store.ID
+	if own == nil {
+		return false
+	}
+	return participant.JID.User == own.User || participant.PhoneNumber.User == own.User ||
+		(participant.LID.User != "" && participant.LID.User == service.WaCli.Store.LID.User)
 }
 
 func (service groupService) ManageParticipant(ctx context.Context, request domainGroup.ParticipantRequest) (result []domainGroup.ParticipantStatus, err error) {
@@ -97,20 +191,9 @@ func (service groupService) ManageParticipant(ctx context.Context, request domai
 	}
 
 	for _, participant := range participants {
-		if participant.Error == 403 && participant.AddRequest != nil {
-			result = append(result, domainGroup.ParticipantStatus{
-				Participant: participant.JID.String(),
-				Status:      "error",
-				Message:     "Failed to add participant",
-			})
-		} else {
-			result = append(result, domainGroup.ParticipantStatus{
-				Participant: participant.JID.String(),
-				Status:      "success",
-				Message:     "Action success",
-			})
-		}
+		result = append(result, participantStatus(participant, request.Action))
 	}
+	service.offerInviteLink(groupJID, result)
 
 	return result, nil
 }
@@ -166,6 +249,7 @@ func (service groupService) ManageGroupRequestParticipants(ctx context.Context,
 				Participant: participant.JID.String(),
 				Status:      "error",
 				Message:     fmt.Sprintf("Action %s failed (code %d)", request.Action, participant.Error),
+				Code:        participant.Error,
 			})
 		} else {
 			result = append(result, domainGroup.ParticipantStatus{
@@ -182,6 +266,16 @@ func (service groupService) ManageGroupRequestParticipants(ctx context.Context,
 func (service groupService) participantToJID(participants []string) ([]types.JID, error) {
 	var participantsJID []types.JID
 	for _, participant := range participants {
+		// the JIDs of the join requests and webhooks are taken as they are, they can be LIDs
+		if strings.Contains(participant, "@") {
+			participantJID, err := types.ParseJID(participant)
+			if err != nil {
+				return nil, pkgError.ValidationError(fmt.Sprintf("participant %s is not a valid JID", participant))
+			}
+			participantsJID = append(participantsJID, participantJID)
+			continue
+		}
+
 		formattedParticipant := participant + config.WhatsappTypeUser
 
 		if !whatsapp.IsOnWhatsapp(service.WaCli, formattedParticipant) {
@@ -194,3 +288,377 @@ func (service groupService) participantToJID(participants []string) ([]types.JID
 	}
 	return participantsJID, nil
 }
+
+func (service groupService) SetGroupSubject(ctx context.Context, request domainGroup.SetGroupSubjectRequest) (err error) {
+	if err = validations.ValidateSetGroupSubject(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	return service.WaCli.SetGroupName(groupJID, request.Subject)
+}
+
+func (service groupService) SetGroupDescription(ctx context.Context, request domainGroup.SetGroupDescriptionRequest) (err error) {
+	if err = validations.ValidateSetGroupDescription(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	// the ID of the current description is looked up by whatsmeow, an empty description removes it
+	return service.WaCli.SetGroupTopic(groupJID, "", "", request.Description)
+}
+
+func (service groupService) SetGroupPhoto(ctx context.Context, request domainGroup.SetGroupPhotoRequest) (pictureID string, err error) {
+	if err = validations.ValidateSetGroupPhoto(ctx, request); err != nil {
+		return pictureID, err
+	}
+
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return pictureID, err
+	}
+
+	photo, err := whatsapp.GroupPicture(helpers.MultipartFormFileHeaderToBytes(request.Photo))
+	if err != nil {
+		return pictureID, err
+	}
+
+	return service.WaCli.SetGroupPhoto(groupJID, photo)
+}
+
+func (service groupService) RemoveGroupPhoto(ctx context.Context, request domainGroup.RemoveGroupPhotoRequest) (err error) {
+	if err = validations.ValidateRemoveGroupPhoto(ctx, request); err != nil {
+		return err
+	}
+
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return err
+	}
+
+	_, err = service.WaCli.SetGroupPhoto(groupJID, nil)
+	return err
+}
+
+func (service groupService) UpdateGroupSettings(ctx context.Context, request domainGroup.GroupSettingsRequest) (response domainGroup.GroupSettings, err error) {
+	if err = validations.ValidateGroupSettings(ctx, request); err != nil {
+		return response, err
+	}
+
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return response, err
+	}
+
+	// every setting is its own request, a failure keeps the settings changed before it
+	if request.Announce != nil {
+		if err = service.WaCli.SetGroupAnnounce(groupJID, *request.Announce); err != nil {
+			return response, fmt.Errorf("failed to set announce: %w", err)
+		}
+	}
+	if request.Locked != nil {
+		if err = service.WaCli.SetGroupLocked(groupJID, *request.Locked); err != nil {
+			return response, fmt.Errorf("failed to set locked: %w", err)
+		}
+	}
+	if request.JoinApproval != nil {
+		if err = service.WaCli.SetGroupJoinApprovalMode(groupJID, *request.JoinApproval); err != nil {
+			return response, fmt.Errorf("failed to set join_approval: %w", err)
+		}
+	}
+	if request.MemberAddMode != "" {
+		if err = service.WaCli.SetGroupMemberAddMode(groupJID, types.GroupMemberAddMode(request.MemberAddMode)); err != nil {
+			return response, fmt.Errorf("failed to set member_add_mode: %w", err)
+		}
+	}
+
+	groupInfo, err := service.WaCli.GetGroupInfo(groupJID)
+	if err != nil {
+		return response, err
+	}
+	response = groupSettings(groupInfo)
+	response.GroupID = groupJID.String()
+	return response, nil
+}
+
+func groupSettings(groupInfo *types.GroupInfo) domainGroup.GroupSettings {
+	return domainGroup.GroupSettings{
+		Announce:          groupInfo.IsAnnounce,
+		Locked:            groupInfo.IsLocked,
+		JoinApproval:      groupInfo.IsJoinApprovalRequired,
+		MemberAddMode:     string(groupInfo.MemberAddMode),
+		DisappearingTimer: whatsapp.DisappearingTimerName(groupInfo.DisappearingTimer),
+	}
+}
+
+// defaultListGroupsLimit is the page size of GET /groups without a limit
+const defaultListGroupsLimit = 50
+
+func (service groupService) ListGroups(ctx context.Context, request domainGroup.ListGroupsRequest) (response domainGroup.ListGroupsResponse, err error) {
+	if err = validations.ValidateListGroups(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	groups, err := whatsapp.JoinedGroups(service.WaCli.GetJoinedGroups, config.WhatsappGroupListCache, request.Refresh)
+	if err != nil {
+		return response, err
+	}
+
+	// the groups are sorted so the pages stay the same between two requests, the cached slice is copied first
+	sorted := slices.Clone(groups)
+	slices.SortFunc(sorted, func(a, b *types.GroupInfo) int {
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c
+		}
+		return strings.Compare(a.JID.String(), b.JID.String())
+	})
+
+	response.Limit = request.Limit
+	if response.Limit == 0 {
+		response.Limit = defaultListGroupsLimit
+	}
+	response.Offset = request.Offset
+	response.Total = len(sorted)
+	response.Groups = []domainGroup.GroupDetail{}
+
+	start := min(response.Offset, len(sorted))
+	end := min(start+response.Limit, len(sorted))
+	for _, group := range sorted[start:end] {
+		response.Groups = append(response.Groups, groupDetail(group))
+	}
+	return response, nil
+}
+
+func groupDetail(group *types.GroupInfo) domainGroup.GroupDetail {
+	detail := domainGroup.GroupDetail{
+		GroupID:      group.JID.String(),
+		Subject:      group.Name,
+		Description:  group.Topic,
+		CreatedAt:    group.GroupCreated,
+		IsCommunity:  group.IsParent,
+		Participants: make([]domainGroup.GroupParticipant, 0, len(group.Participants)),
+		Settings:     groupSettings(group),
+	}
+	if !group.OwnerJID.IsEmpty() {
+		detail.Owner = group.OwnerJID.String()
+	}
+	if !group.LinkedParentJID.IsEmpty() {
+		detail.CommunityID = group.LinkedParentJID.String()
+	}
+	for _, participant := range group.Participants {
+		member := domainGroup.GroupParticipant{
+			JID:          participant.JID.String(),
+			DisplayName:  participant.DisplayName,
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		}
+		if !participant.PhoneNumber.IsEmpty() {
+			member.PhoneNumber = participant.PhoneNumber.String()
+		}
+		detail.Participants = append(detail.Participants, member)
+	}
+	return detail
+}
+
+func (service groupService) CreateCommunity(ctx context.Context, request domainGroup.CreateCommunityRequest) (response domainGroup.CreateCommunityResponse, err error) {
+	if err = validations.ValidateCreateCommunity(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	parent := types.GroupParent{IsParent: true}
+	if request.JoinApproval {
+		parent.DefaultMembershipApprovalMode = "request_required"
+	}
+	communityInfo, err := service.WaCli.CreateGroup(whatsmeow.ReqCreateGroup{
+		Name:        request.Name,
+		GroupParent: parent,
+	})
+	if err != nil {
+		return response, err
+	}
+
+	response.CommunityID = communityInfo.JID.String()
+	if request.Description != "" {
+		if err = service.WaCli.SetGroupTopic(communityInfo.JID, communityInfo.TopicID, "", request.Description); err != nil {
+			response.Warnings = append(response.Warnings, fmt.Sprintf("failed to set the description: %v", err))
+		}
+	}
+	return response, nil
+}
+
+func (service groupService) LinkCommunityGroup(ctx context.Context, request domainGroup.CommunityGroupRequest) (err error) {
+	communityJID, groupJID, err := service.communityGroupJIDs(ctx, request)
+	if err != nil {
+		return err
+	}
+	return service.WaCli.LinkGroup(communityJID, groupJID)
+}
+
+func (service groupService) UnlinkCommunityGroup(ctx context.Context, request domainGroup.CommunityGroupRequest) (err error) {
+	communityJID, groupJID, err := service.communityGroupJIDs(ctx, request)
+	if err != nil {
+		return err
+	}
+	return service.WaCli.UnlinkGroup(communityJID, groupJID)
+}
+
+func (service groupService) communityGroupJIDs(ctx context.Context, request domainGroup.CommunityGroupRequest) (communityJID, groupJID types.JID, err error) {
+	if err = validations.ValidateCommunityGroup(ctx, request); err != nil {
+		return communityJID, groupJID, err
+	}
+	if communityJID, err = whatsapp.ValidateJidWithLogin(service.WaCli, request.CommunityID); err != nil {
+		return communityJID, groupJID, err
+	}
+	groupJID, err = whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	return communityJID, groupJID, err
+}
+
+func (service groupService) ListCommunityGroups(ctx context.Context, request domainGroup.CommunityRequest) (result []domainGroup.CommunityGroup, err error) {
+	if err = validations.ValidateCommunity(ctx, request); err != nil {
+		return result, err
+	}
+	communityJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.CommunityID)
+	if err != nil {
+		return result, err
+	}
+
+	groups, err := service.WaCli.GetSubGroups(communityJID)
+	if err != nil {
+		return result, err
+	}
+	result = []domainGroup.CommunityGroup{}
+	for _, group := range groups {
+		result = append(result, domainGroup.CommunityGroup{
+			GroupID:        group.JID.String(),
+			Name:           group.Name,
+			IsAnnouncement: group.IsDefaultSubGroup,
+		})
+	}
+	return result, nil
+}
+
+func (service groupService) ListCommunityParticipants(ctx context.Context, request domainGroup.CommunityRequest) (result []string, err error) {
+	if err = validations.ValidateCommunity(ctx, request); err != nil {
+		return result, err
+	}
+	communityJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.CommunityID)
+	if err != nil {
+		return result, err
+	}
+
+	participants, err := service.WaCli.GetLinkedGroupsParticipants(communityJID)
+	if err != nil {
+		return result, err
+	}
+	result = []string{}
+	for _, participant := range participants {
+		result = append(result, participant.String())
+	}
+	return result, nil
+}
+
+// GetGroupInviteInfo looks up the group of an invite link without joining it
+func (service groupService) GetGroupInviteInfo(ctx context.Context, request domainGroup.GroupInviteInfoRequest) (response domainGroup.GroupInviteInfo, err error) {
+	if err = validations.ValidateGroupInviteInfo(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	groupInfo, err := service.WaCli.GetGroupInfoFromLink(strings.TrimSpace(request.Code))
+	if err != nil {
+		return response, inviteInfoError(err)
+	}
+	return toGroupInviteInfo(groupInfo), nil
+}
+
+// inviteInfoError tells the revoked and invalid invite codes apart from the other failures of the lookup
+func inviteInfoError(err error) error {
+	switch {
+	case errors.Is(err, whatsmeow.ErrInviteLinkRevoked):
+		return pkgError.NotFoundError("the invite link was revoked")
+	case errors.Is(err, whatsmeow.ErrInviteLinkInvalid):
+		return pkgError.ValidationError("the invite code is invalid")
+	default:
+		return err
+	}
+}
+
+func toGroupInviteInfo(groupInfo *types.GroupInfo) domainGroup.GroupInviteInfo {
+	response := domainGroup.GroupInviteInfo{
+		GroupID:      groupInfo.JID.String(),
+		Name:         groupInfo.Name,
+		Description:  groupInfo.Topic,
+		Size:         len(groupInfo.Participants),
+		CreatedAt:    groupInfo.GroupCreated,
+		IsCommunity:  groupInfo.IsParent,
+		JoinApproval: groupInfo.IsJoinApprovalRequired,
+	}
+	if !groupInfo.OwnerJID.IsEmpty() {
+		response.Owner = groupInfo.OwnerJID.String()
+	}
+	if !groupInfo.LinkedParentJID.IsEmpty() {
+		response.CommunityID = groupInfo.LinkedParentJID.String()
+	}
+	return response
+}
+
+func (service groupService) ExportParticipants(ctx context.Context, request domainGroup.ExportParticipantsRequest) (result []domainGroup.ExportedParticipant, err error) {
+	if err = validations.ValidateExportParticipants(ctx, request); err != nil {
+		return result, err
+	}
+	groupJID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.GroupID)
+	if err != nil {
+		return result, err
+	}
+
+	groupInfo, err := service.WaCli.GetGroupInfo(groupJID)
+	if err != nil {
+		return result, err
+	}
+	joins, err := whatsapp.GroupJoinDates(groupJID)
+	if err != nil {
+		logrus.Warnf("Failed to get the joins of group %s: %v", groupJID, err)
+	}
+
+	result = make([]domainGroup.ExportedParticipant, 0, len(groupInfo.Participants))
+	for _, participant := range groupInfo.Participants {
+		// the participants of groups with LIDs have their phone number aside
+		phoneJID := participant.PhoneNumber
+		if phoneJID.IsEmpty() && participant.JID.Server == types.DefaultUserServer {
+			phoneJID = participant.JID
+		}
+		exported := domainGroup.ExportedParticipant{
+			JID:          participant.JID.String(),
+			Phone:        phoneJID.User,
+			IsAdmin:      participant.IsAdmin,
+			IsSuperAdmin: participant.IsSuperAdmin,
+		}
+
+		contactJID := participant.JID
+		if !phoneJID.IsEmpty() {
+			contactJID = phoneJID
+		}
+		if contact, err := service.WaCli.Store.Contacts.GetContact(contactJID); err == nil {
+			exported.PushName = contact.PushName
+		}
+
+		for _, jid := range []types.JID{participant.JID, phoneJID} {
+			if joinedAt, ok := joins[jid.String()]; ok && !jid.IsEmpty() {
+				exported.JoinedAt = &joinedAt
+				break
+			}
+		}
+		result = append(result, exported)
+	}
+	return result, nil
+}
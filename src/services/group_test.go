@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/types"
+)
+
+func TestParticipantStatus(t *testing.T) {
+	jid := types.NewJID("628123456789", types.DefaultUserServer)
+	tests := []struct {
+		name        string
+		participant types.GroupParticipant
+		want        domainGroup.ParticipantStatus
+	}{
+		{
+			name:        "should succeed with a 200",
+			participant: types.GroupParticipant{JID: jid},
+			want: domainGroup.ParticipantStatus{
+				Participant: jid.String(), Status: "success", Message: "Action add success",
+			},
+		},
+		{
+			name:        "should be invite only with a 403 and an add request",
+			participant: types.GroupParticipant{JID: jid, Error: 403, AddRequest: &types.GroupParticipantAddRequest{Code: "AbCd"}},
+			want: domainGroup.ParticipantStatus{
+				Participant: jid.String(), Status: "error", Code: 403, InviteOnly: true,
+				Message: "The user only accepts invites, send them the invite link of the group",
+			},
+		},
+		{
+			name:        "should fail with a 403 without an add request",
+			participant: types.GroupParticipant{JID: jid, Error: 403},
+			want: domainGroup.ParticipantStatus{
+				Participant: jid.String(), Status: "error", Code: 403, Message: "Action add failed (code 403)",
+			},
+		},
+		{
+			name:        "should fail with the other errors",
+			participant: types.GroupParticipant{JID: jid, Error: 409},
+			want: domainGroup.ParticipantStatus{
+				Participant: jid.String(), Status: "error", Code: 409, Message: "Action add failed (code 409)",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, participantStatus(tt.participant, whatsmeow.ParticipantChangeAdd))
+		})
+	}
+}
+
+func TestAddInviteLinks(t *testing.T) {
+	tests := []struct {
+		name      string
+		statuses  []domainGroup.ParticipantStatus
+		linkErr   error
+		wantLinks []string
+		wantFetch int
+		wantErr   bool
+	}{
+		{
+			name:      "should not fetch the link without invite only participants",
+			statuses:  []domainGroup.ParticipantStatus{{Status: "success"}, {Status: "error", Code: 409}},
+			wantLinks: []string{"", ""},
+		},
+		{
+			name:      "should fetch the link once for the invite only participants",
+			statuses:  []domainGroup.ParticipantStatus{{InviteOnly: true}, {Status: "success"}, {InviteOnly: true}},
+			wantLinks: []string{"https://chat.whatsapp.com/AbCd", "", "https://chat.whatsapp.com/AbCd"},
+			wantFetch: 1,
+		},
+		{
+			name:      "should keep the statuses when the link can't be fetched",
+			statuses:  []domainGroup.ParticipantStatus{{InviteOnly: true}},
+			linkErr:   errors.New("not an admin"),
+			wantLinks: []string{""},
+			wantFetch: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetches := 0
+			err := addInviteLinks(tt.statuses, func() (string, error) {
+				fetches++
+				if tt.linkErr != nil {
+					return "", tt.linkErr
+				}
+				return "https://chat.whatsapp.com/AbCd", nil
+			})
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantFetch, fetches)
+
+			links := make([]string, 0, len(tt.statuses))
+			for _, status := range tt.statuses {
+				links = append(links, status.InviteLink)
+			}
+			assert.Equal(t, tt.wantLinks, links)
+		})
+	}
+}
+
+func TestInviteInfoError(t *testing.T) {
+	offline := errors.New("offline")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "should be not found when revoked", err: fmt.Errorf("lookup: %w", whatsmeow.ErrInviteLinkRevoked), want: pkgError.NotFoundError("the invite link was revoked")},
+		{name: "should be a validation error when invalid", err: whatsmeow.ErrInviteLinkInvalid, want: pkgError.ValidationError("the invite code is invalid")},
+		{name: "should keep the other errors", err: offline, want: offline},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, inviteInfoError(tt.err))
+		})
+	}
+}
+
+func TestToGroupInviteInfo(t *testing.T) {
+	group := types.NewJID("120363025246125888", types.GroupServer)
+	community := types.NewJID("120363000000000001", types.GroupServer)
+	owner := types.NewJID("628123456789", types.DefaultUserServer)
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	info := &types.GroupInfo{
+		JID:                         group,
+		OwnerJID:                    owner,
+		GroupName:                   types.GroupName{Name: "Golang Indonesia"},
+		GroupTopic:                  types.GroupTopic{Topic: "Gophers"},
+		GroupLinkedParent:           types.GroupLinkedParent{LinkedParentJID: community},
+		GroupMembershipApprovalMode: types.GroupMembershipApprovalMode{IsJoinApprovalRequired: true},
+		GroupCreated:                created,
+		Participants:                []types.GroupParticipant{{JID: owner}, {JID: types.NewJID("628111111111", types.DefaultUserServer)}},
+	}
+	assert.Equal(t, domainGroup.GroupInviteInfo{
+		GroupID:      group.String(),
+		Name:         "Golang Indonesia",
+		Description:  "Gophers",
+		Size:         2,
+		CreatedAt:    created,
+		Owner:        owner.String(),
+		CommunityID:  community.String(),
+		JoinApproval: true,
+	}, toGroupInviteInfo(info))
+
+	minimal := toGroupInviteInfo(&types.GroupInfo{JID: group, GroupParent: types.GroupParent{IsParent: true}})
+	assert.True(t, minimal.IsCommunity)
+	assert.Empty(t, minimal.Owner, "the owner is left out when unknown")
+	assert.Empty(t, minimal.CommunityID)
+}
@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainMedia "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/media"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
+)
+
+type mediaService struct{}
+
+func NewMediaService() domainMedia.IMediaService {
+	return &mediaService{}
+}
+
+func (service mediaService) Cleanup(_ context.Context) (response domainMedia.CleanupReport, err error) {
+	return toCleanupReport(whatsapp.CleanupMedia()), nil
+}
+
+func (service mediaService) CleanupStatus(_ context.Context) (response domainMedia.CleanupStatus, err error) {
+	response.Policy = domainMedia.RetentionPolicy{
+		MaxSize:  config.WhatsappMediaRetentionMaxSize,
+		Rules:    config.WhatsappMediaRetentionRules,
+		Interval: config.WhatsappMediaRetentionInterval.String(),
+	}
+	if config.WhatsappMediaRetentionMaxAge > 0 {
+		response.Policy.MaxAge = config.WhatsappMediaRetentionMaxAge.String()
+	}
+	if last := whatsapp.LastMediaCleanup(); last != nil {
+		report := toCleanupReport(*last)
+		response.LastCleanup = &report
+	}
+	return response, nil
+}
+
+func toCleanupReport(report whatsapp.MediaCleanupReport) domainMedia.CleanupReport {
+	return domainMedia.CleanupReport{
+		StartedAt:      report.StartedAt,
+		FinishedAt:     report.FinishedAt,
+		Scanned:        report.Scanned,
+		Deleted:        report.Deleted,
+		FreedBytes:     report.FreedBytes,
+		RemainingBytes: report.RemainingBytes,
+		Error:          report.Error,
+	}
+}
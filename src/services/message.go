@@ -6,18 +6,21 @@ import (
 	"time"
 
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/sirupsen/logrus"
 	"go.mau.fi/whatsmeow"
 	"go.mau.fi/whatsmeow/appstate"
-	"go.mau.fi/whatsmeow/proto/waCommon"
 	"go.mau.fi/whatsmeow/proto/waE2E"
 	"go.mau.fi/whatsmeow/proto/waSyncAction"
 	"go.mau.fi/whatsmeow/types"
 	"google.golang.org/protobuf/proto"
 )
 
+// messageEditWindow is how long WhatsApp lets a sent message be edited
+const messageEditWindow = 15 * time.Minute
+
 type serviceMessage struct {
 	WaCli *whatsmeow.Client
 }
@@ -63,24 +66,24 @@ func (service serviceMessage) ReactMessage(ctx context.Context, request domainMe
 		return response, err
 	}
 
-	msg := &waE2E.Message{
-		ReactionMessage: &waE2E.ReactionMessage{
-			Key: &waCommon.MessageKey{
-				FromMe:    proto.Bool(true),
-				ID:        proto.String(request.MessageID),
-				RemoteJID: proto.String(dataWaRecipient.String()),
-			},
-			Text:              proto.String(request.Emoji),
-			SenderTimestampMS: proto.Int64(time.Now().UnixMilli()),
-		},
+	// without the author, the reaction would be on a message of this account
+	sender, err := service.messageSender(dataWaRecipient, request.MessageID, request.Sender)
+	if err != nil {
+		return response, err
 	}
-	ts, err := service.WaCli.SendMessage(ctx, dataWaRecipient, msg)
+
+	msg := service.WaCli.BuildReaction(dataWaRecipient, sender, request.MessageID, request.Emoji)
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, dataWaRecipient, msg)
 	if err != nil {
 		return response, err
 	}
 
 	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	if request.Emoji == "" {
+		response.Status = fmt.Sprintf("Reaction removed from %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	} else {
+		response.Status = fmt.Sprintf("Reaction sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp)
+	}
 	return response, nil
 }
 
@@ -93,7 +96,27 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 		return response, err
 	}
 
-	ts, err := service.WaCli.SendMessage(context.Background(), dataWaRecipient, service.WaCli.BuildRevoke(dataWaRecipient, types.EmptyJID, request.MessageID))
+	sender := types.EmptyJID
+	if request.Sender != "" {
+		if sender, err = whatsapp.ParseJID(request.Sender); err != nil {
+			return response, err
+		}
+	} else if stored, err := whatsapp.FindStoredMessage(dataWaRecipient, request.MessageID); err != nil {
+		return response, err
+	} else if stored != nil && !stored.FromMe {
+		sender = stored.Sender
+	}
+
+	if !sender.IsEmpty() && !service.isOwnJID(sender) {
+		if dataWaRecipient.Server != types.GroupServer {
+			return response, pkgError.ValidationError("only your own messages can be revoked in this chat")
+		}
+		if err = service.checkGroupAdmin(dataWaRecipient); err != nil {
+			return response, err
+		}
+	}
+
+	ts, err := whatsapp.SendOutbound(context.Background(), service.WaCli, dataWaRecipient, service.WaCli.BuildRevoke(dataWaRecipient, sender, request.MessageID))
 	if err != nil {
 		return response, err
 	}
@@ -103,6 +126,31 @@ func (service serviceMessage) RevokeMessage(ctx context.Context, request domainM
 	return response, nil
 }
 
+// checkGroupAdmin fails when this account isn't an admin of the group
+func (service serviceMessage) checkGroupAdmin(group types.JID) error {
+	info, err := service.WaCli.GetGroupInfo(group)
+	if err != nil {
+		return err
+	}
+	for _, participant := range info.Participants {
+		if service.isOwnJID(participant.JID) || service.isOwnJID(participant.LID) {
+			if participant.IsAdmin || participant.IsSuperAdmin {
+				return nil
+			}
+			break
+		}
+	}
+	return pkgError.ValidationError("only group admins can revoke the messages of other participants")
+}
+
+// isOwnJID tells if the phone number or LID is the one of this account
+func (service serviceMessage) isOwnJID(jid types.JID) bool {
+	if jid.IsEmpty() {
+		return false
+	}
+	return jid.User == service.WaCli.Store.ID.User || jid.User == service.WaCli.Store.LID.User
+}
+
 func (service serviceMessage) DeleteMessage(ctx context.Context, request domainMessage.DeleteRequest) (err error) {
 	if err = validations.ValidateDeleteMessage(ctx, request); err != nil {
 		return err
@@ -148,7 +196,21 @@ func (service serviceMessage) UpdateMessage(ctx context.Context, request domainM
 	}
 
 	msg := &waE2E.Message{Conversation: proto.String(request.Message)}
-	ts, err := service.WaCli.SendMessage(context.Background(), dataWaRecipient, service.WaCli.BuildEdit(dataWaRecipient, request.MessageID, msg))
+	stored, err := whatsapp.FindStoredMessage(dataWaRecipient, request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	if stored != nil {
+		if !stored.FromMe {
+			return response, pkgError.ValidationError("only your own messages can be edited")
+		}
+		if time.Since(stored.Timestamp) > messageEditWindow {
+			return response, pkgError.ValidationError(fmt.Sprintf("message %s can only be edited within %.0f minutes of being sent", request.MessageID, messageEditWindow.Minutes()))
+		}
+		msg = whatsapp.BuildEditedContent(stored.Message, request.Message)
+	}
+
+	ts, err := whatsapp.SendOutbound(context.Background(), service.WaCli, dataWaRecipient, service.WaCli.BuildEdit(dataWaRecipient, request.MessageID, msg))
 	if err != nil {
 		return response, err
 	}
@@ -169,15 +231,186 @@ func (service serviceMessage) StarMessage(ctx context.Context, request domainMes
 		return err
 	}
 
-	isFromMe := true
-	if len(request.MessageID) > 22 {
-		isFromMe = false
+	sender := types.EmptyJID
+	var isFromMe bool
+	if request.Sender != "" {
+		if sender, err = whatsapp.ParseJID(request.Sender); err != nil {
+			return err
+		}
+		isFromMe = service.isOwnJID(sender)
+	} else if stored, err := whatsapp.FindStoredMessage(dataWaRecipient, request.MessageID); err != nil {
+		return err
+	} else if stored != nil {
+		isFromMe, sender = stored.FromMe, stored.Sender
+	} else {
+		// the IDs of the messages sent from here are 22 characters at most, the longer ones come from the phone
+		isFromMe = len(request.MessageID) <= 22
+	}
+	if !isFromMe && dataWaRecipient.Server == types.GroupServer && sender.IsEmpty() {
+		return pkgError.ValidationError(fmt.Sprintf("sender: the author of message %s is needed to star it in a group.", request.MessageID))
 	}
 
-	patchInfo := appstate.BuildStar(dataWaRecipient.ToNonAD(), *service.WaCli.Store.ID, request.MessageID, isFromMe, request.IsStarred)
+	patchInfo := whatsapp.BuildStarPatch(dataWaRecipient, sender, request.MessageID, isFromMe, request.IsStarred)
 
 	if err = service.WaCli.SendAppState(patchInfo); err != nil {
 		return err
 	}
 	return nil
 }
+
+func (service serviceMessage) PinMessage(ctx context.Context, request domainMessage.PinRequest) (response domainMessage.GenericResponse, err error) {
+	if err = validations.ValidatePinMessage(ctx, request); err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	sender, err := service.messageSender(dataWaRecipient, request.MessageID, request.Sender)
+	if err != nil {
+		return response, err
+	}
+
+	duration := whatsapp.PinDurations["7d"]
+	if request.Duration != "" {
+		duration = whatsapp.PinDurations[request.Duration]
+	}
+	key := service.WaCli.BuildMessageKey(dataWaRecipient, sender, request.MessageID)
+	msg := whatsapp.NewPinMessage(key, request.IsPinned, duration, time.Now())
+
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, dataWaRecipient, msg)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	if request.IsPinned {
+		response.Status = fmt.Sprintf("Pinned message %s for %s (server timestamp: %s)", request.MessageID, duration, ts.Timestamp)
+	} else {
+		response.Status = fmt.Sprintf("Unpinned message %s (server timestamp: %s)", request.MessageID, ts.Timestamp)
+	}
+	return response, nil
+}
+
+// KeepMessage keeps a disappearing message in the chat for everyone, or lets it disappear again
+func (service serviceMessage) KeepMessage(ctx context.Context, request domainMessage.KeepRequest) (response domainMessage.GenericResponse, err error) {
+	if err = validations.ValidateKeepMessage(ctx, request); err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	sender, err := service.messageSender(dataWaRecipient, request.MessageID, request.Sender)
+	if err != nil {
+		return response, err
+	}
+	key := service.WaCli.BuildMessageKey(dataWaRecipient, sender, request.MessageID)
+
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, dataWaRecipient, whatsapp.NewKeepInChatMessage(key, request.IsKept, time.Now()))
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	if request.IsKept {
+		response.Status = fmt.Sprintf("Kept message %s in the chat (server timestamp: %s)", request.MessageID, ts.Timestamp)
+	} else {
+		response.Status = fmt.Sprintf("Message %s disappears again (server timestamp: %s)", request.MessageID, ts.Timestamp)
+	}
+	return response, nil
+}
+
+// messageSender returns the author of a message of the chat, the given sender or the one of the message store.
+// It's empty for the messages of this account and the ones that aren't stored.
+func (service serviceMessage) messageSender(chat types.JID, id types.MessageID, sender string) (types.JID, error) {
+	if sender != "" {
+		return whatsapp.ParseJID(sender)
+	}
+	stored, err := whatsapp.FindStoredMessage(chat, id)
+	if err != nil || stored == nil || stored.FromMe {
+		return types.EmptyJID, err
+	}
+	return stored.Sender, nil
+}
+
+// DownloadMedia implements message.IMessageService.
+func (service serviceMessage) DownloadMedia(ctx context.Context, request domainMessage.DownloadMediaRequest) (response domainMessage.DownloadMediaResponse, err error) {
+	if err = validations.ValidateDownloadMedia(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	media, err := whatsapp.DownloadMediaReference(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	// the scanner quarantined it, there's nothing to return
+	if media.MediaBlocked {
+		return response, pkgError.MediaBlockedError(fmt.Sprintf("media of message %s is blocked: %s", request.MessageID, media.Detection))
+	}
+
+	response = domainMessage.DownloadMediaResponse{
+		MessageID:         request.MessageID,
+		MediaPath:         media.MediaPath,
+		MediaURL:          media.MediaURL,
+		MediaKey:          media.MediaKey,
+		MimeType:          media.MimeType,
+		Caption:           media.Caption,
+		ConvertedPath:     media.ConvertedPath,
+		ConvertedURL:      media.ConvertedURL,
+		ConvertedKey:      media.ConvertedKey,
+		ConvertedMimeType: media.ConvertedMimeType,
+	}
+	return response, nil
+}
+
+func (service serviceMessage) ForwardMessage(ctx context.Context, request domainMessage.ForwardRequest) (response domainMessage.ForwardResponse, err error) {
+	if err = validations.ValidateForwardMessage(ctx, request); err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	stored, err := whatsapp.FindStoredMessage(dataWaRecipient, request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	if stored == nil {
+		return response, pkgError.ValidationError(fmt.Sprintf("message %s of %s isn't stored, it can't be forwarded", request.MessageID, request.Phone))
+	}
+	msg, err := whatsapp.BuildForwardedMessage(stored.Message)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	for _, to := range request.To {
+		result := domainMessage.ForwardResult{Phone: to}
+		if err = service.forwardTo(ctx, to, proto.Clone(msg).(*waE2E.Message), &result); err != nil {
+			result.Error = err.Error()
+		}
+		response.Results = append(response.Results, result)
+	}
+	return response, nil
+}
+
+func (service serviceMessage) forwardTo(ctx context.Context, to string, msg *waE2E.Message, result *domainMessage.ForwardResult) error {
+	recipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, to)
+	if err != nil {
+		return err
+	}
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, recipient, msg)
+	if err != nil {
+		return err
+	}
+
+	whatsapp.StoreSentMessage(recipient, ts, msg)
+	whatsapp.ForwardSentMessageToWebhook(recipient, ts, msg)
+	result.MessageID = ts.ID
+	return nil
+}
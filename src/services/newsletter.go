@@ -2,10 +2,21 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainNewsletter "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/newsletter"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/internal/rest/helpers"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+	"github.com/dustin/go-humanize"
 	"go.mau.fi/whatsmeow"
+	"go.mau.fi/whatsmeow/proto/waE2E"
+	"go.mau.fi/whatsmeow/types"
+	"google.golang.org/protobuf/proto"
 )
 
 type newsletterService struct {
@@ -30,3 +41,62 @@ func (service newsletterService) Unfollow(ctx context.Context, request domainNew
 
 	return service.WaCli.UnfollowNewsletter(JID)
 }
+
+// Post publishes a text, image or video post to a newsletter the account owns or administers
+func (service newsletterService) Post(ctx context.Context, request domainNewsletter.PostRequest) (response domainNewsletter.PostResponse, err error) {
+	if err = validations.ValidatePostNewsletter(ctx, request); err != nil {
+		return response, err
+	}
+
+	JID, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.NewsletterID)
+	if err != nil {
+		return response, err
+	}
+	if JID.Server != types.NewsletterServer {
+		return response, pkgError.ValidationError(fmt.Sprintf("newsletter_id: %s is not a newsletter.", request.NewsletterID))
+	}
+	metadata, err := service.WaCli.GetNewsletterInfo(JID)
+	if err != nil {
+		return response, err
+	}
+	if !whatsapp.IsNewsletterAdmin(metadata) {
+		return response, pkgError.ValidationError(fmt.Sprintf("only the owner and admins of newsletter %s can post to it", JID))
+	}
+
+	msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String(request.Message)}}
+	var extra whatsmeow.SendRequestExtra
+	if request.Media != nil {
+		media := helpers.MultipartFormFileHeaderToBytes(request.Media)
+		mimeType := http.DetectContentType(media)
+
+		mediaType, maxSize, uploadType := "image", config.WhatsappSettingMaxImageSize, whatsmeow.MediaImage
+		switch {
+		case strings.HasPrefix(mimeType, "video/"):
+			mediaType, maxSize, uploadType = "video", config.WhatsappSettingMaxVideoSize, whatsmeow.MediaVideo
+		case !strings.HasPrefix(mimeType, "image/"):
+			return response, pkgError.ValidationError(fmt.Sprintf("media: %s posts aren't supported, only images and videos.", mimeType))
+		}
+		if int64(len(media)) > maxSize {
+			return response, pkgError.ValidationError(fmt.Sprintf("max %s upload is %s", mediaType, humanize.Bytes(uint64(maxSize))))
+		}
+
+		// newsletter media is uploaded unencrypted to its own path and referenced by its handle
+		uploaded, err := service.WaCli.UploadNewsletter(ctx, media, uploadType)
+		if err != nil {
+			return response, pkgError.WaUploadMediaError(fmt.Sprintf("Failed to upload %s: %v", mediaType, err))
+		}
+		msg = whatsapp.NewsletterMediaMessage(mediaType, uploaded, mimeType, request.Message, mediaThumbnail(mediaType, media))
+		extra.MediaHandle = uploaded.Handle
+	}
+
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, JID, msg, extra)
+	if err != nil {
+		return response, err
+	}
+	whatsapp.RecordSentMessage(JID, ts, msg, request.Message)
+
+	response.MessageID = ts.ID
+	response.ServerID = int(ts.ServerID)
+	response.Status = fmt.Sprintf("Post to newsletter %s success (server timestamp: %s)", JID, ts.Timestamp.String())
+	return response, nil
+}
@@ -1,11 +1,22 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"image"
+	"math"
+	"math/rand/v2"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/domains/app"
@@ -17,6 +28,7 @@ import (
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
 	"github.com/disintegration/imaging"
 	fiberUtils "github.com/gofiber/fiber/v2/utils"
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 	"go.mau.fi/whatsmeow"
@@ -37,14 +49,15 @@ func NewSendService(waCli *whatsmeow.Client, appService app.IAppService) domainS
 	}
 }
 
-// wrapSendMessage wraps the message sending process with message ID saving
+// wrapSendMessage wraps the message sending process with message ID saving, a send lost with the connection fails
+// with a whatsapp.SendRetryQueued and is saved once it's sent again
 func (service serviceSend) wrapSendMessage(ctx context.Context, recipient types.JID, msg *waE2E.Message, content string) (whatsmeow.SendResponse, error) {
-	ts, err := service.WaCli.SendMessage(ctx, recipient, msg)
+	ts, err := whatsapp.SendWithRetry(ctx, service.WaCli, recipient, msg, content)
 	if err != nil {
 		return whatsmeow.SendResponse{}, err
 	}
 
-	utils.RecordMessage(ts.ID, service.WaCli.Store.ID.String(), content)
+	whatsapp.RecordSentMessage(recipient, ts, msg, content)
 
 	return ts, nil
 }
@@ -58,6 +71,21 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+	// the deprecated reply_message_id is sent without the reply when the message isn't found
+	if request.ReplyTo == nil && request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
+		if options.quote, err = service.getQuote(dataWaRecipient, &domainSend.ReplyTo{MessageID: *request.ReplyMessageID}); err != nil {
+			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *request.ReplyMessageID)
+		}
+	}
+
+	parsedMentions, err := service.getMentions(dataWaRecipient, request.Message, request.Mentions)
+	if err != nil {
+		return response, err
+	}
 
 	// Create base message
 	msg := &waE2E.Message{
@@ -73,33 +101,11 @@ func (service serviceSend) SendText(ctx context.Context, request domainSend.Mess
 		msg.ExtendedTextMessage.ContextInfo.ForwardingScore = proto.Uint32(100)
 	}
 
-	parsedMentions := service.getMentionFromText(ctx, request.Message)
 	if len(parsedMentions) > 0 {
 		msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
 	}
 
-	// Reply message
-	if request.ReplyMessageID != nil && *request.ReplyMessageID != "" {
-		record, err := utils.FindRecordFromStorage(*request.ReplyMessageID)
-		if err == nil { // Only set reply context if we found the message ID
-			msg.ExtendedTextMessage = &waE2E.ExtendedTextMessage{
-				Text: proto.String(request.Message),
-				ContextInfo: &waE2E.ContextInfo{
-					StanzaID:    request.ReplyMessageID,
-					Participant: proto.String(record.JID),
-					QuotedMessage: &waE2E.Message{
-						Conversation: proto.String(record.MessageContent),
-					},
-				},
-			}
-
-			if len(parsedMentions) > 0 {
-				msg.ExtendedTextMessage.ContextInfo.MentionedJID = parsedMentions
-			}
-		} else {
-			logrus.Warnf("Reply message ID %s not found in storage, continuing without reply context", *request.ReplyMessageID)
-		}
-	}
+	options.apply(msg)
 
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, request.Message)
 	if err != nil {
@@ -120,140 +126,196 @@ func (service serviceSend) SendImage(ctx context.Context, request domainSend.Ima
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+	if request.ViewOnce && dataWaRecipient.Server == types.NewsletterServer {
+		return response, pkgError.ValidationError("view once media can't be sent to newsletters")
+	}
+	mentions, err := service.getMentions(dataWaRecipient, request.Caption, request.Mentions)
+	if err != nil {
+		return response, err
+	}
+	if request.MediaID != "" {
+		ts, err := service.sendUploadedMedia(ctx, dataWaRecipient, request.MediaID, "image", request.Caption, request.ViewOnce, request.IsForwarded, mentions, options)
+		if err != nil {
+			return response, err
+		}
+		response.MessageID = ts.ID
+		response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+		return response, nil
+	}
+
+	prepared, err := whatsapp.RequestMedia(ctx, "image", func() (preparedImage, error) {
+		return prepareImage(request)
+	})
+	if err != nil {
+		return response, err
+	}
+
+	// Send to WA server
+	uploadedImage, err := service.uploadMedia(ctx, whatsmeow.MediaImage, prepared.data, dataWaRecipient)
+	if err != nil {
+		fmt.Printf("failed to upload file: %v", err)
+		return response, err
+	}
+
+	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
+		JPEGThumbnail: prepared.thumbnail,
+		Caption:       proto.String(request.Caption),
+		URL:           proto.String(uploadedImage.URL),
+		DirectPath:    proto.String(uploadedImage.DirectPath),
+		MediaKey:      uploadedImage.MediaKey,
+		Mimetype:      proto.String(http.DetectContentType(prepared.data)),
+		FileEncSHA256: uploadedImage.FileEncSHA256,
+		FileSHA256:    uploadedImage.FileSHA256,
+		FileLength:    proto.Uint64(uint64(len(prepared.data))),
+		ViewOnce:      proto.Bool(request.ViewOnce),
+	}}
+
+	msg.ImageMessage.ContextInfo = mediaContextInfo(request.IsForwarded, mentions)
+
+	caption := "🖼️ Image"
+	if request.Caption != "" {
+		caption = "🖼️ " + request.Caption
+	}
+
+	options.apply(msg)
+	if request.ViewOnce {
+		msg = whatsapp.WrapViewOnce(msg)
+	}
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+// preparedImage is the image of a send request, compressed and with its thumbnail
+type preparedImage struct {
+	data      []byte
+	thumbnail []byte
+}
+
+// prepareImage downloads or saves the image of the request, compresses it and creates its thumbnail. Its files are
+// written in a folder of their own, removed before returning.
+func prepareImage(request domainSend.ImageRequest) (prepared preparedImage, err error) {
+	dir, err := os.MkdirTemp(config.PathSendItems, "image-")
+	if err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to create temporary folder %v", err))
+	}
+	defer func() {
+		if errDelete := os.RemoveAll(dir); errDelete != nil {
+			logrus.Errorf("error when deleting picture: %v", errDelete)
+		}
+	}()
 
 	var (
-		imagePath      string
-		imageThumbnail string
-		imageName      string
-		deletedItems   []string
-		oriImagePath   string
+		imagePath    string
+		imageName    string
+		oriImagePath string
 	)
 
 	if request.ImageURL != nil && *request.ImageURL != "" {
 		// Download image from URL
 		imageData, fileName, err := utils.DownloadImageFromURL(*request.ImageURL)
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, fileName)
 		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
+			return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to download image from URL %v", err))
 		}
-		imageName = fileName
+		imageName = filepath.Base(fileName)
+		oriImagePath = filepath.Join(dir, imageName)
 		err = os.WriteFile(oriImagePath, imageData, 0644)
 		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save downloaded image %v", err))
+			return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to save downloaded image %v", err))
 		}
 	} else if request.Image != nil {
 		// Save image to server
-		oriImagePath = fmt.Sprintf("%s/%s", config.PathSendItems, request.Image.Filename)
+		imageName = filepath.Base(request.Image.Filename)
+		oriImagePath = filepath.Join(dir, imageName)
 		err = fasthttp.SaveMultipartFile(request.Image, oriImagePath)
 		if err != nil {
-			return response, err
+			return prepared, err
 		}
-		imageName = request.Image.Filename
 	}
-	deletedItems = append(deletedItems, oriImagePath)
 
 	/* Generate thumbnail with smalled image size */
 	srcImage, err := imaging.Open(oriImagePath)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
 	}
 
 	// Resize Thumbnail
 	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	imageThumbnail = fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, imageName)
+	imageThumbnail := filepath.Join(dir, "thumbnails-"+imageName)
 	if err = imaging.Save(resizedImage, imageThumbnail); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
 	}
-	deletedItems = append(deletedItems, imageThumbnail)
 
 	if request.Compress {
 		// Resize image
-		openImageBuffer, err := imaging.Open(oriImagePath)
-		if err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
-		}
-		newImage := imaging.Resize(openImageBuffer, 600, 0, imaging.Lanczos)
-		newImagePath := fmt.Sprintf("%s/new-%s", config.PathSendItems, imageName)
+		newImage := imaging.Resize(srcImage, 600, 0, imaging.Lanczos)
+		newImagePath := filepath.Join(dir, "new-"+imageName)
 		if err = imaging.Save(newImage, newImagePath); err != nil {
-			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
+			return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to save image %v", err))
 		}
-		deletedItems = append(deletedItems, newImagePath)
 		imagePath = newImagePath
 	} else {
 		imagePath = oriImagePath
 	}
 
-	// Send to WA server
-	dataWaCaption := request.Caption
-	dataWaImage, err := os.ReadFile(imagePath)
-	if err != nil {
-		return response, err
+	if prepared.data, err = os.ReadFile(imagePath); err != nil {
+		return prepared, err
 	}
-	uploadedImage, err := service.uploadMedia(ctx, whatsmeow.MediaImage, dataWaImage, dataWaRecipient)
+	if prepared.thumbnail, err = os.ReadFile(imageThumbnail); err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to read thumbnail %v", err))
+	}
+	return prepared, nil
+}
+
+func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendFile(ctx, request)
 	if err != nil {
-		fmt.Printf("failed to upload file: %v", err)
 		return response, err
 	}
-	dataWaThumbnail, err := os.ReadFile(imageThumbnail)
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to read thumbnail %v", err))
-	}
-
-	msg := &waE2E.Message{ImageMessage: &waE2E.ImageMessage{
-		JPEGThumbnail: dataWaThumbnail,
-		Caption:       proto.String(dataWaCaption),
-		URL:           proto.String(uploadedImage.URL),
-		DirectPath:    proto.String(uploadedImage.DirectPath),
-		MediaKey:      uploadedImage.MediaKey,
-		Mimetype:      proto.String(http.DetectContentType(dataWaImage)),
-		FileEncSHA256: uploadedImage.FileEncSHA256,
-		FileSHA256:    uploadedImage.FileSHA256,
-		FileLength:    proto.Uint64(uint64(len(dataWaImage))),
-		ViewOnce:      proto.Bool(request.ViewOnce),
-	}}
-
-	if request.IsForwarded {
-		msg.ImageMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
-
-	caption := "🖼️ Image"
-	if request.Caption != "" {
-		caption = "🖼️ " + request.Caption
+		return response, err
 	}
-	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
-	go func() {
-		errDelete := utils.RemoveFile(0, deletedItems...)
-		if errDelete != nil {
-			fmt.Println("error when deleting picture: ", errDelete)
-		}
-	}()
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
 	if err != nil {
 		return response, err
 	}
-
-	response.MessageID = ts.ID
-	response.Status = fmt.Sprintf("Message sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
-	return response, nil
-}
-
-func (service serviceSend) SendFile(ctx context.Context, request domainSend.FileRequest) (response domainSend.GenericResponse, err error) {
-	err = validations.ValidateSendFile(ctx, request)
+	mentions, err := service.getMentions(dataWaRecipient, request.Caption, request.Mentions)
 	if err != nil {
 		return response, err
 	}
-	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if request.MediaID != "" {
+		ts, err := service.sendUploadedMedia(ctx, dataWaRecipient, request.MediaID, "document", request.Caption, false, request.IsForwarded, mentions, options)
+		if err != nil {
+			return response, err
+		}
+		response.MessageID = ts.ID
+		response.Status = fmt.Sprintf("Document sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+		return response, nil
+	}
+
+	document, err := whatsapp.RequestMedia(ctx, "document", func() (preparedDocument, error) {
+		data := helpers.MultipartFormFileHeaderToBytes(request.File)
+		mimeType := http.DetectContentType(data)
+		thumbnail, err := documentThumbnail(request.Thumbnail, data, mimeType)
+		return preparedDocument{data: data, mimeType: mimeType, thumbnail: thumbnail}, err
+	})
 	if err != nil {
 		return response, err
 	}
-
-	fileBytes := helpers.MultipartFormFileHeaderToBytes(request.File)
-	fileMimeType := http.DetectContentType(fileBytes)
+	fileMimeType, thumbnail := document.mimeType, document.thumbnail
 
 	// Send to WA server
-	uploadedFile, err := service.uploadMedia(ctx, whatsmeow.MediaDocument, fileBytes, dataWaRecipient)
+	uploadedFile, err := service.uploadMedia(ctx, whatsmeow.MediaDocument, document.data, dataWaRecipient)
 	if err != nil {
 		fmt.Printf("Failed to upload file: %v", err)
 		return response, err
@@ -272,17 +334,19 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 		Caption:       proto.String(request.Caption),
 	}}
 
-	if request.IsForwarded {
-		msg.DocumentMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
+	msg.DocumentMessage.ContextInfo = mediaContextInfo(request.IsForwarded, mentions)
+	if len(thumbnail.JPEG) > 0 {
+		msg.DocumentMessage.JPEGThumbnail = thumbnail.JPEG
+		msg.DocumentMessage.ThumbnailWidth = proto.Uint32(thumbnail.Width)
+		msg.DocumentMessage.ThumbnailHeight = proto.Uint32(thumbnail.Height)
 	}
 
 	caption := "📄 Document"
 	if request.Caption != "" {
 		caption = "📄 " + request.Caption
 	}
+
+	options.apply(msg)
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
 	if err != nil {
 		return response, err
@@ -293,6 +357,33 @@ func (service serviceSend) SendFile(ctx context.Context, request domainSend.File
 	return response, nil
 }
 
+// preparedDocument is the document of a send request with its preview
+type preparedDocument struct {
+	data      []byte
+	mimeType  string
+	thumbnail whatsapp.DocumentThumbnail
+}
+
+// documentThumbnail is the preview shown above the document, the given image or the first page of a PDF.
+// A PDF that can't be rendered is sent without preview.
+func documentThumbnail(image *multipart.FileHeader, file []byte, mimeType string) (whatsapp.DocumentThumbnail, error) {
+	if image != nil {
+		thumbnail, err := whatsapp.EncodeDocumentThumbnail(helpers.MultipartFormFileHeaderToBytes(image))
+		if err != nil {
+			return thumbnail, pkgError.ValidationError(err.Error())
+		}
+		return thumbnail, nil
+	}
+	if mimeType == "application/pdf" {
+		thumbnail, err := whatsapp.PDFThumbnail(file)
+		if err != nil {
+			logrus.Warnf("Sending the PDF without preview: %v", err)
+		}
+		return thumbnail, nil
+	}
+	return whatsapp.DocumentThumbnail{}, nil
+}
+
 func (service serviceSend) SendVideo(ctx context.Context, request domainSend.VideoRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendVideo(ctx, request)
 	if err != nil {
@@ -302,79 +393,40 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	if err != nil {
 		return response, err
 	}
-
-	var (
-		videoPath      string
-		videoThumbnail string
-		deletedItems   []string
-	)
-
-	generateUUID := fiberUtils.UUIDv4()
-	// Save video to server
-	oriVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+request.Video.Filename)
-	err = fasthttp.SaveMultipartFile(request.Video, oriVideoPath)
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store video in server %v", err))
-	}
-
-	// Check if ffmpeg is installed
-	_, err = exec.LookPath("ffmpeg")
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
 	if err != nil {
-		return response, pkgError.InternalServerError("ffmpeg not installed")
+		return response, err
 	}
-
-	// Get thumbnail video with ffmpeg
-	thumbnailVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".png")
-	cmdThumbnail := exec.Command("ffmpeg", "-i", oriVideoPath, "-ss", "00:00:01.000", "-vframes", "1", thumbnailVideoPath)
-	err = cmdThumbnail.Run()
-	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to create thumbnail %v", err))
+	if request.ViewOnce && dataWaRecipient.Server == types.NewsletterServer {
+		return response, pkgError.ValidationError("view once media can't be sent to newsletters")
 	}
-
-	// Resize Thumbnail
-	srcImage, err := imaging.Open(thumbnailVideoPath)
+	mentions, err := service.getMentions(dataWaRecipient, request.Caption, request.Mentions)
 	if err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
-	}
-	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
-	thumbnailResizeVideoPath := fmt.Sprintf("%s/thumbnails-%s", config.PathSendItems, generateUUID+".png")
-	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
-		return response, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+		return response, err
 	}
-
-	deletedItems = append(deletedItems, thumbnailVideoPath)
-	deletedItems = append(deletedItems, thumbnailResizeVideoPath)
-	videoThumbnail = thumbnailResizeVideoPath
-
-	if request.Compress {
-		compresVideoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID+".mp4")
-
-		cmdCompress := exec.Command("ffmpeg", "-i", oriVideoPath, "-strict", "-2", compresVideoPath)
-		err = cmdCompress.Run()
+	if request.MediaID != "" {
+		ts, err := service.sendUploadedMedia(ctx, dataWaRecipient, request.MediaID, "video", request.Caption, request.ViewOnce, request.IsForwarded, mentions, options)
 		if err != nil {
-			return response, pkgError.InternalServerError("failed to compress video")
+			return response, err
 		}
-
-		videoPath = compresVideoPath
-		deletedItems = append(deletedItems, compresVideoPath)
-	} else {
-		videoPath = oriVideoPath
-		deletedItems = append(deletedItems, oriVideoPath)
+		response.MessageID = ts.ID
+		response.Status = fmt.Sprintf("Video sent to %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+		return response, nil
 	}
 
-	//Send to WA server
-	dataWaVideo, err := os.ReadFile(videoPath)
+	video, err := whatsapp.RequestMedia(ctx, "video", func() (preparedVideo, error) {
+		return prepareVideo(request)
+	})
 	if err != nil {
 		return response, err
 	}
-	uploaded, err := service.uploadMedia(ctx, whatsmeow.MediaVideo, dataWaVideo, dataWaRecipient)
+
+	//Send to WA server
+	uploaded, err := service.uploadMedia(ctx, whatsmeow.MediaVideo, video.data, dataWaRecipient)
 	if err != nil {
 		return response, pkgError.InternalServerError(fmt.Sprintf("Failed to upload file: %v", err))
 	}
-	dataWaThumbnail, err := os.ReadFile(videoThumbnail)
-	if err != nil {
-		return response, err
-	}
+	dataWaVideo, dataWaThumbnail, isGif := video.data, video.thumbnail, video.isGif
 
 	msg := &waE2E.Message{VideoMessage: &waE2E.VideoMessage{
 		URL:                 proto.String(uploaded.URL),
@@ -390,26 +442,24 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 		ThumbnailEncSHA256:  dataWaThumbnail,
 		ThumbnailSHA256:     dataWaThumbnail,
 		ThumbnailDirectPath: proto.String(uploaded.DirectPath),
+		GifPlayback:         proto.Bool(request.GifPlayback || isGif),
 	}}
 
-	if request.IsForwarded {
-		msg.VideoMessage.ContextInfo = &waE2E.ContextInfo{
-			IsForwarded:     proto.Bool(true),
-			ForwardingScore: proto.Uint32(100),
-		}
-	}
+	msg.VideoMessage.ContextInfo = mediaContextInfo(request.IsForwarded, mentions)
 
 	caption := "🎥 Video"
+	if msg.VideoMessage.GetGifPlayback() {
+		caption = "🎞️ GIF"
+	}
 	if request.Caption != "" {
 		caption = "🎥 " + request.Caption
 	}
+
+	options.apply(msg)
+	if request.ViewOnce {
+		msg = whatsapp.WrapViewOnce(msg)
+	}
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, caption)
-	go func() {
-		errDelete := utils.RemoveFile(1, deletedItems...)
-		if errDelete != nil {
-			logrus.Infof("error when deleting picture: %v", errDelete)
-		}
-	}()
 	if err != nil {
 		return response, err
 	}
@@ -419,6 +469,91 @@ func (service serviceSend) SendVideo(ctx context.Context, request domainSend.Vid
 	return response, nil
 }
 
+// preparedVideo is the video of a send request, converted or compressed, with its thumbnail
+type preparedVideo struct {
+	data      []byte
+	thumbnail []byte
+	isGif     bool
+}
+
+// prepareVideo converts a GIF to a video, compresses the video when asked and creates its thumbnail with ffmpeg.
+// Its files are written in a folder of their own, removed before returning.
+func prepareVideo(request domainSend.VideoRequest) (prepared preparedVideo, err error) {
+	// Check if ffmpeg is installed
+	if _, err = exec.LookPath("ffmpeg"); err != nil {
+		return prepared, pkgError.InternalServerError("ffmpeg not installed")
+	}
+
+	dir, err := os.MkdirTemp(config.PathSendItems, "video-")
+	if err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to create temporary folder %v", err))
+	}
+	defer func() {
+		if errDelete := os.RemoveAll(dir); errDelete != nil {
+			logrus.Infof("error when deleting video: %v", errDelete)
+		}
+	}()
+
+	// Save video to server
+	oriVideoPath := filepath.Join(dir, "original-"+filepath.Base(request.Video.Filename))
+	err = fasthttp.SaveMultipartFile(request.Video, oriVideoPath)
+	if err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to store video in server %v", err))
+	}
+
+	// WhatsApp doesn't play GIF files, they're sent as MP4s with gif playback
+	prepared.isGif = request.Video.Header.Get("Content-Type") == "image/gif"
+	thumbnailAt := "00:00:01.000"
+	if prepared.isGif {
+		gifVideoPath := filepath.Join(dir, "gif.mp4")
+		if output, err := exec.Command("ffmpeg", gifToVideoArgs(oriVideoPath, gifVideoPath)...).CombinedOutput(); err != nil {
+			return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to convert gif %v: %s", err, output))
+		}
+		oriVideoPath = gifVideoPath
+		// GIFs are often shorter than a second
+		thumbnailAt = "00:00:00.000"
+	}
+
+	// Get thumbnail video with ffmpeg
+	thumbnailVideoPath := filepath.Join(dir, "thumbnail.png")
+	cmdThumbnail := exec.Command("ffmpeg", "-i", oriVideoPath, "-ss", thumbnailAt, "-vframes", "1", thumbnailVideoPath)
+	err = cmdThumbnail.Run()
+	if err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to create thumbnail %v", err))
+	}
+
+	// Resize Thumbnail
+	srcImage, err := imaging.Open(thumbnailVideoPath)
+	if err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to open image %v", err))
+	}
+	resizedImage := imaging.Resize(srcImage, 100, 0, imaging.Lanczos)
+	thumbnailResizeVideoPath := filepath.Join(dir, "thumbnails.png")
+	if err = imaging.Save(resizedImage, thumbnailResizeVideoPath); err != nil {
+		return prepared, pkgError.InternalServerError(fmt.Sprintf("failed to save thumbnail %v", err))
+	}
+
+	videoPath := oriVideoPath
+	if request.Compress && !prepared.isGif {
+		compresVideoPath := filepath.Join(dir, "compressed.mp4")
+
+		cmdCompress := exec.Command("ffmpeg", "-i", oriVideoPath, "-strict", "-2", compresVideoPath)
+		err = cmdCompress.Run()
+		if err != nil {
+			return prepared, pkgError.InternalServerError("failed to compress video")
+		}
+		videoPath = compresVideoPath
+	}
+
+	if prepared.data, err = os.ReadFile(videoPath); err != nil {
+		return prepared, err
+	}
+	if prepared.thumbnail, err = os.ReadFile(thumbnailResizeVideoPath); err != nil {
+		return prepared, err
+	}
+	return prepared, nil
+}
+
 func (service serviceSend) SendContact(ctx context.Context, request domainSend.ContactRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendContact(ctx, request)
 	if err != nil {
@@ -428,23 +563,53 @@ func (service serviceSend) SendContact(ctx context.Context, request domainSend.C
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
 
-	msgVCard := fmt.Sprintf("BEGIN:VCARD\nVERSION:3.0\nN:;%v;;;\nFN:%v\nTEL;type=CELL;waid=%v:+%v\nEND:VCARD",
-		request.ContactName, request.ContactName, request.ContactPhone, request.ContactPhone)
-	msg := &waE2E.Message{ContactMessage: &waE2E.ContactMessage{
-		DisplayName: proto.String(request.ContactName),
-		Vcard:       proto.String(msgVCard),
-	}}
+	contacts := request.Contacts
+	if len(contacts) == 0 {
+		contacts = []domainSend.ContactCard{{Name: request.ContactName, Phone: request.ContactPhone}}
+	}
+	var contactMessages []*waE2E.ContactMessage
+	for _, contact := range contacts {
+		vcard, displayName := contact.VCard, contact.Name
+		if vcard == "" {
+			vcard = utils.VCard{Name: contact.Name, Phone: contact.Phone, Organization: contact.Organization, Email: contact.Email}.String()
+		} else if displayName == "" {
+			displayName = utils.VCardDisplayName(vcard)
+		}
+		contactMessages = append(contactMessages, &waE2E.ContactMessage{
+			DisplayName: proto.String(displayName),
+			Vcard:       proto.String(vcard),
+		})
+	}
 
+	var contextInfo *waE2E.ContextInfo
 	if request.IsForwarded {
-		msg.ContactMessage.ContextInfo = &waE2E.ContextInfo{
+		contextInfo = &waE2E.ContextInfo{
 			IsForwarded:     proto.Bool(true),
 			ForwardingScore: proto.Uint32(100),
 		}
 	}
 
-	content := "👤 " + request.ContactName
+	msg := &waE2E.Message{}
+	var content string
+	if len(contactMessages) == 1 {
+		msg.ContactMessage = contactMessages[0]
+		msg.ContactMessage.ContextInfo = contextInfo
+		content = "👤 " + contactMessages[0].GetDisplayName()
+	} else {
+		msg.ContactsArrayMessage = &waE2E.ContactsArrayMessage{
+			DisplayName: proto.String(fmt.Sprintf("%d contacts", len(contactMessages))),
+			Contacts:    contactMessages,
+			ContextInfo: contextInfo,
+		}
+		content = fmt.Sprintf("👥 %d contacts", len(contactMessages))
+	}
 
+	options.apply(msg)
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -464,6 +629,10 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
 
 	metadata, err := utils.GetMetaDataFromURL(request.Link)
 	if err != nil {
@@ -513,6 +682,8 @@ func (service serviceSend) SendLink(ctx context.Context, request domainSend.Link
 	if request.Caption != "" {
 		content = "🔗 " + request.Caption
 	}
+
+	options.apply(msg)
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -532,6 +703,10 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
 
 	// Compose WhatsApp Proto
 	msg := &waE2E.Message{
@@ -540,6 +715,19 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 			DegreesLongitude: proto.Float64(utils.StrToFloat64(request.Longitude)),
 		},
 	}
+	if request.Name != "" {
+		msg.LocationMessage.Name = proto.String(request.Name)
+	}
+	if request.Address != "" {
+		msg.LocationMessage.Address = proto.String(request.Address)
+	}
+	if request.ThumbnailURL != "" {
+		thumbnail, _, err := utils.DownloadImageFromURL(request.ThumbnailURL)
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to download thumbnail: %v", err))
+		}
+		msg.LocationMessage.JPEGThumbnail = mediaThumbnail("image", thumbnail)
+	}
 
 	if request.IsForwarded {
 		msg.LocationMessage.ContextInfo = &waE2E.ContextInfo{
@@ -549,7 +737,11 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	}
 
 	content := "📍 " + request.Latitude + ", " + request.Longitude
+	if request.Name != "" {
+		content = "📍 " + request.Name
+	}
 
+	options.apply(msg)
 	// Send WhatsApp Message Proto
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
@@ -561,6 +753,122 @@ func (service serviceSend) SendLocation(ctx context.Context, request domainSend.
 	return response, nil
 }
 
+func (service serviceSend) SendLiveLocation(ctx context.Context, request domainSend.LiveLocationRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendLiveLocation(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	now := time.Now()
+	liveLocation := &waE2E.LiveLocationMessage{
+		DegreesLatitude:                   proto.Float64(utils.StrToFloat64(request.Latitude)),
+		DegreesLongitude:                  proto.Float64(utils.StrToFloat64(request.Longitude)),
+		AccuracyInMeters:                  proto.Uint32(request.Accuracy),
+		SpeedInMps:                        proto.Float32(request.Speed),
+		DegreesClockwiseFromMagneticNorth: proto.Uint32(request.Heading),
+		Caption:                           proto.String(request.Caption),
+	}
+
+	if request.MessageID == "" {
+		liveLocation.SequenceNumber = proto.Int64(1)
+		liveLocation.TimeOffset = proto.Uint32(0)
+
+		// a share queued until the reconnection is kept too, so it can be updated once it's sent
+		ts, sendErr := service.wrapSendMessage(ctx, dataWaRecipient, &waE2E.Message{LiveLocationMessage: liveLocation}, "📍 Live location")
+		id := ts.ID
+		var queued *whatsapp.SendRetryQueued
+		if errors.As(sendErr, &queued) {
+			id = queued.MessageID
+		} else if sendErr != nil {
+			return response, sendErr
+		}
+		if err = whatsapp.SaveLiveLocation(whatsapp.LiveLocation{
+			Chat:      dataWaRecipient,
+			MessageID: id,
+			Latitude:  liveLocation.GetDegreesLatitude(),
+			Longitude: liveLocation.GetDegreesLongitude(),
+			Sequence:  1,
+			StartedAt: now,
+			ExpiresAt: now.Add(time.Duration(request.Duration) * time.Second),
+		}); err != nil {
+			return response, err
+		}
+		if sendErr != nil {
+			return response, sendErr
+		}
+
+		response.MessageID = ts.ID
+		response.Status = fmt.Sprintf("Live location shared with %s for %s (server timestamp: %s)", request.Phone, time.Duration(request.Duration)*time.Second, ts.Timestamp.String())
+		return response, nil
+	}
+
+	location, err := whatsapp.GetLiveLocation(dataWaRecipient, request.MessageID)
+	if err != nil {
+		return response, err
+	}
+	ts, err := service.sendLiveLocationUpdate(ctx, &location, liveLocation, now)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Live location updated for %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) StopLiveLocation(ctx context.Context, request domainSend.StopLiveLocationRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateStopLiveLocation(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+
+	location, err := whatsapp.GetLiveLocation(dataWaRecipient, request.MessageID)
+	if err != nil {
+		return response, err
+	}
+
+	// the last position is sent a final time, ending the share now instead of at its expiry
+	now := time.Now()
+	location.ExpiresAt = now
+	ts, err := service.sendLiveLocationUpdate(ctx, &location, &waE2E.LiveLocationMessage{
+		DegreesLatitude:  proto.Float64(location.Latitude),
+		DegreesLongitude: proto.Float64(location.Longitude),
+	}, now)
+	if err != nil {
+		return response, err
+	}
+	if err = whatsapp.DeleteLiveLocation(dataWaRecipient, request.MessageID); err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	response.Status = fmt.Sprintf("Live location stopped for %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+// sendLiveLocationUpdate sends the new position as the next live location message of the share. The updates
+// aren't edits, an edit of the first message is refused once the edit window has passed.
+func (service serviceSend) sendLiveLocationUpdate(ctx context.Context, location *whatsapp.LiveLocation, liveLocation *waE2E.LiveLocationMessage, now time.Time) (whatsmeow.SendResponse, error) {
+	location.Sequence++
+	location.Latitude, location.Longitude = liveLocation.GetDegreesLatitude(), liveLocation.GetDegreesLongitude()
+	liveLocation.SequenceNumber = proto.Int64(location.Sequence)
+	liveLocation.TimeOffset = proto.Uint32(uint32(now.Sub(location.StartedAt).Seconds()))
+
+	ts, err := whatsapp.SendOutbound(ctx, service.WaCli, location.Chat, &waE2E.Message{LiveLocationMessage: liveLocation})
+	if err != nil {
+		return ts, err
+	}
+	return ts, whatsapp.SaveLiveLocation(*location)
+}
+
 func (service serviceSend) SendAudio(ctx context.Context, request domainSend.AudioRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendAudio(ctx, request)
 	if err != nil {
@@ -570,9 +878,30 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+	if request.ViewOnce && dataWaRecipient.Server == types.NewsletterServer {
+		return response, pkgError.ValidationError("view once media can't be sent to newsletters")
+	}
+	if request.MediaID != "" {
+		ts, err := service.sendUploadedMedia(ctx, dataWaRecipient, request.MediaID, "audio", "", request.ViewOnce, request.IsForwarded, nil, options)
+		if err != nil {
+			return response, err
+		}
+		response.MessageID = ts.ID
+		response.Status = fmt.Sprintf("Send audio success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+		return response, nil
+	}
 
-	autioBytes := helpers.MultipartFormFileHeaderToBytes(request.Audio)
-	audioMimeType := http.DetectContentType(autioBytes)
+	audio, err := whatsapp.RequestMedia(ctx, "audio", func() (preparedAudio, error) {
+		return prepareAudio(request)
+	})
+	if err != nil {
+		return response, err
+	}
+	autioBytes, audioMimeType, voiceNote := audio.data, audio.mimeType, audio.voiceNote
 
 	audioUploaded, err := service.uploadMedia(ctx, whatsmeow.MediaAudio, autioBytes, dataWaRecipient)
 	if err != nil {
@@ -591,6 +920,14 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 			MediaKey:      audioUploaded.MediaKey,
 		},
 	}
+	content := "🎵 Audio"
+
+	if request.PTT {
+		msg.AudioMessage.PTT = proto.Bool(true)
+		msg.AudioMessage.Seconds = proto.Uint32(voiceNote.Seconds)
+		msg.AudioMessage.Waveform = voiceNote.Waveform
+		content = "🎤 Voice note"
+	}
 
 	if request.IsForwarded {
 		msg.AudioMessage.ContextInfo = &waE2E.ContextInfo{
@@ -599,8 +936,10 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 		}
 	}
 
-	content := "🎵 Audio"
-
+	options.apply(msg)
+	if request.ViewOnce {
+		msg = whatsapp.WrapViewOnce(msg)
+	}
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -611,6 +950,90 @@ func (service serviceSend) SendAudio(ctx context.Context, request domainSend.Aud
 	return response, nil
 }
 
+// preparedAudio is the audio of a send request, a voice note is converted to Opus
+type preparedAudio struct {
+	data      []byte
+	mimeType  string
+	voiceNote whatsapp.VoiceNote
+}
+
+func prepareAudio(request domainSend.AudioRequest) (prepared preparedAudio, err error) {
+	prepared.data = helpers.MultipartFormFileHeaderToBytes(request.Audio)
+	prepared.mimeType = http.DetectContentType(prepared.data)
+	if request.PTT {
+		if prepared.voiceNote, err = whatsapp.ConvertVoiceNote(prepared.data); err != nil {
+			return prepared, pkgError.InternalServerError(err.Error())
+		}
+		prepared.data, prepared.mimeType = prepared.voiceNote.Audio, whatsapp.VoiceNoteMimeType
+	}
+	return prepared, nil
+}
+
+func (service serviceSend) SendSticker(ctx context.Context, request domainSend.StickerRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendSticker(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+
+	sticker, err := whatsapp.RequestMedia(ctx, "sticker", func() (whatsapp.Sticker, error) {
+		imageBytes := helpers.MultipartFormFileHeaderToBytes(request.Sticker)
+		return whatsapp.EncodeSticker(imageBytes, http.DetectContentType(imageBytes), whatsapp.StickerMetadata{
+			PackName:      request.PackName,
+			PackPublisher: request.PackPublisher,
+			Emojis:        request.Emojis,
+		})
+	})
+	if err != nil {
+		return response, pkgError.InternalServerError(err.Error())
+	}
+
+	stickerUploaded, err := service.uploadMedia(ctx, whatsmeow.MediaImage, sticker.WebP, dataWaRecipient)
+	if err != nil {
+		err = pkgError.WaUploadMediaError(fmt.Sprintf("Failed to upload sticker: %v", err))
+		return response, err
+	}
+
+	msg := &waE2E.Message{
+		StickerMessage: &waE2E.StickerMessage{
+			URL:           proto.String(stickerUploaded.URL),
+			DirectPath:    proto.String(stickerUploaded.DirectPath),
+			Mimetype:      proto.String("image/webp"),
+			FileLength:    proto.Uint64(stickerUploaded.FileLength),
+			FileSHA256:    stickerUploaded.FileSHA256,
+			FileEncSHA256: stickerUploaded.FileEncSHA256,
+			MediaKey:      stickerUploaded.MediaKey,
+			Width:         proto.Uint32(whatsapp.StickerSize),
+			Height:        proto.Uint32(whatsapp.StickerSize),
+			IsAnimated:    proto.Bool(sticker.IsAnimated),
+		},
+	}
+
+	if request.IsForwarded {
+		msg.StickerMessage.ContextInfo = &waE2E.ContextInfo{
+			IsForwarded:     proto.Bool(true),
+			ForwardingScore: proto.Uint32(100),
+		}
+	}
+
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, "🏷️ Sticker")
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send sticker success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
 func (service serviceSend) SendPoll(ctx context.Context, request domainSend.PollRequest) (response domainSend.GenericResponse, err error) {
 	err = validations.ValidateSendPoll(ctx, request)
 	if err != nil {
@@ -620,11 +1043,16 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	if err != nil {
 		return response, err
 	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
 
 	content := "📊 " + request.Question
 
 	msg := service.WaCli.BuildPollCreation(request.Question, request.Options, request.MaxAnswer)
 
+	options.apply(msg)
 	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
@@ -635,38 +1063,1223 @@ func (service serviceSend) SendPoll(ctx context.Context, request domainSend.Poll
 	return response, nil
 }
 
-func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
-	err = validations.ValidateSendPresence(ctx, request)
+func (service serviceSend) SendButtons(ctx context.Context, request domainSend.ButtonsRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendButtons(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
 	if err != nil {
 		return response, err
 	}
 
-	err = service.WaCli.SendPresence(types.Presence(request.Type))
+	buttonsMessage := &waE2E.ButtonsMessage{
+		ContentText: proto.String(request.Body),
+		HeaderType:  waE2E.ButtonsMessage_EMPTY.Enum(),
+	}
+	if request.Footer != "" {
+		buttonsMessage.FooterText = proto.String(request.Footer)
+	}
+	for _, button := range request.Buttons {
+		buttonsMessage.Buttons = append(buttonsMessage.Buttons, &waE2E.ButtonsMessage_Button{
+			ButtonID:   proto.String(button.ID),
+			ButtonText: &waE2E.ButtonsMessage_Button_ButtonText{DisplayText: proto.String(button.Text)},
+			Type:       waE2E.ButtonsMessage_Button_RESPONSE.Enum(),
+		})
+	}
+
+	switch {
+	case request.Header != "":
+		buttonsMessage.HeaderType = waE2E.ButtonsMessage_TEXT.Enum()
+		buttonsMessage.Header = &waE2E.ButtonsMessage_Text{Text: request.Header}
+	case request.HeaderMediaID != "":
+		if dataWaRecipient.Server == types.NewsletterServer {
+			return response, pkgError.ValidationError("uploaded media can't be sent to newsletters")
+		}
+		uploaded, err := whatsapp.GetUploadedMedia(request.HeaderMediaID, request.HeaderMediaType)
+		if err != nil {
+			return response, err
+		}
+		header, _ := uploadedMediaMessage(uploaded, "", false, nil)
+		switch uploaded.Type {
+		case "image":
+			buttonsMessage.HeaderType = waE2E.ButtonsMessage_IMAGE.Enum()
+			buttonsMessage.Header = &waE2E.ButtonsMessage_ImageMessage{ImageMessage: header.ImageMessage}
+		case "video":
+			buttonsMessage.HeaderType = waE2E.ButtonsMessage_VIDEO.Enum()
+			buttonsMessage.Header = &waE2E.ButtonsMessage_VideoMessage{VideoMessage: header.VideoMessage}
+		case "document":
+			buttonsMessage.HeaderType = waE2E.ButtonsMessage_DOCUMENT.Enum()
+			buttonsMessage.Header = &waE2E.ButtonsMessage_DocumentMessage{DocumentMessage: header.DocumentMessage}
+		}
+	}
+
+	content := "🔘 " + request.Body
+
+	msg := &waE2E.Message{ButtonsMessage: buttonsMessage}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
 	if err != nil {
 		return response, err
 	}
 
-	response.MessageID = "presence"
-	response.Status = fmt.Sprintf("Send presence success %s", request.Type)
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send buttons success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
 	return response, nil
 }
 
-func (service serviceSend) getMentionFromText(_ context.Context, messages string) (result []string) {
-	mentions := utils.ContainsMention(messages)
-	for _, mention := range mentions {
-		// Get JID from phone number
-		if dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, mention); err == nil {
-			result = append(result, dataWaRecipient.String())
+func (service serviceSend) SendList(ctx context.Context, request domainSend.ListRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendList(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+
+	listMessage := &waE2E.ListMessage{
+		Title:       proto.String(request.Title),
+		Description: proto.String(request.Body),
+		ButtonText:  proto.String(request.ButtonText),
+		ListType:    waE2E.ListMessage_SINGLE_SELECT.Enum(),
+	}
+	if request.Footer != "" {
+		listMessage.FooterText = proto.String(request.Footer)
+	}
+	for _, section := range request.Sections {
+		listSection := &waE2E.ListMessage_Section{Title: proto.String(section.Title)}
+		for _, row := range section.Rows {
+			listSection.Rows = append(listSection.Rows, &waE2E.ListMessage_Row{
+				RowID:       proto.String(row.ID),
+				Title:       proto.String(row.Title),
+				Description: proto.String(row.Description),
+			})
 		}
+		listMessage.Sections = append(listMessage.Sections, listSection)
 	}
-	return result
+
+	content := "📋 " + request.Body
+	if request.Title != "" {
+		content = "📋 " + request.Title + ": " + request.Body
+	}
+
+	msg := &waE2E.Message{ListMessage: listMessage}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, content)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send list success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
 }
 
-func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
-	if recipient.Server == types.NewsletterServer {
-		uploaded, err = service.WaCli.UploadNewsletter(ctx, media, mediaType)
-	} else {
-		uploaded, err = service.WaCli.Upload(ctx, media, mediaType)
+func (service serviceSend) SendCarousel(ctx context.Context, request domainSend.CarouselRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendCarousel(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	if dataWaRecipient.Server == types.NewsletterServer {
+		return response, pkgError.ValidationError("uploaded media can't be sent to newsletters")
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+
+	var cards []*waE2E.InteractiveMessage
+	var headerType string
+	for i, card := range request.Cards {
+		uploaded, err := whatsapp.FindUploadedMedia(card.MediaID)
+		if err != nil {
+			return response, err
+		}
+		if uploaded.Type != "image" && uploaded.Type != "video" {
+			return response, pkgError.ValidationError(fmt.Sprintf("cards[%d] media_id: the header of a card is an image or a video", i))
+		}
+		if headerType == "" {
+			headerType = uploaded.Type
+		} else if uploaded.Type != headerType {
+			return response, pkgError.ValidationError(fmt.Sprintf("cards[%d] media_id: every card has a %s header like the first one", i, headerType))
+		}
+		header, _ := uploadedMediaMessage(uploaded, "", false, nil)
+
+		var buttons []whatsapp.CarouselButton
+		for _, button := range card.Buttons {
+			buttons = append(buttons, whatsapp.CarouselButton{
+				Type:        button.Type,
+				ID:          button.ID,
+				Text:        button.Text,
+				URL:         button.URL,
+				PhoneNumber: button.PhoneNumber,
+			})
+		}
+		cards = append(cards, whatsapp.CarouselCard(header, card.Title, card.Body, card.Footer, buttons))
+	}
+
+	msg := &waE2E.Message{InteractiveMessage: whatsapp.NewCarouselMessage(request.Body, request.Footer, cards)}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, "🎠 "+request.Body)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send carousel success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) SendProduct(ctx context.Context, request domainSend.ProductRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendProduct(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+	owner, err := service.businessOwner(request.BusinessOwnerJID)
+	if err != nil {
+		return response, err
+	}
+
+	product := &waE2E.ProductMessage_ProductSnapshot{
+		ProductID: proto.String(request.ProductID),
+		Title:     proto.String(request.Title),
+	}
+	if request.Description != "" {
+		product.Description = proto.String(request.Description)
+	}
+	if request.CurrencyCode != "" {
+		product.CurrencyCode = proto.String(request.CurrencyCode)
+		product.PriceAmount1000 = proto.Int64(int64(math.Round(request.Price * 1000)))
+	}
+	if request.SalePrice > 0 {
+		product.SalePriceAmount1000 = proto.Int64(int64(math.Round(request.SalePrice * 1000)))
+	}
+	if request.RetailerID != "" {
+		product.RetailerID = proto.String(request.RetailerID)
+	}
+	if request.URL != "" {
+		product.URL = proto.String(request.URL)
+	}
+	if product.ProductImage, err = service.productImage(ctx, dataWaRecipient, request.Image, request.MediaID); err != nil {
+		return response, err
+	}
+	if product.ProductImage != nil {
+		product.ProductImageCount = proto.Uint32(1)
+	}
+
+	productMessage := &waE2E.ProductMessage{
+		Product:          product,
+		BusinessOwnerJID: proto.String(owner.String()),
+	}
+	if request.Body != "" {
+		productMessage.Body = proto.String(request.Body)
+	}
+	if request.Footer != "" {
+		productMessage.Footer = proto.String(request.Footer)
+	}
+
+	msg := &waE2E.Message{ProductMessage: productMessage}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, "🛍️ "+request.Title)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send product success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) SendProductList(ctx context.Context, request domainSend.ProductListRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendProductList(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+	owner, err := service.businessOwner(request.BusinessOwnerJID)
+	if err != nil {
+		return response, err
+	}
+
+	headerProductID := request.HeaderProductID
+	if headerProductID == "" {
+		headerProductID = request.Sections[0].ProductIDs[0]
+	}
+	productList := &waE2E.ListMessage_ProductListInfo{
+		HeaderImage:      &waE2E.ListMessage_ProductListHeaderImage{ProductID: proto.String(headerProductID)},
+		BusinessOwnerJID: proto.String(owner.String()),
+	}
+	if request.HeaderMediaID != "" {
+		uploaded, err := whatsapp.GetUploadedMedia(request.HeaderMediaID, "image")
+		if err != nil {
+			return response, err
+		}
+		productList.HeaderImage.JPEGThumbnail = uploaded.Thumbnail
 	}
-	return uploaded, err
+	for _, section := range request.Sections {
+		productSection := &waE2E.ListMessage_ProductSection{Title: proto.String(section.Title)}
+		for _, productID := range section.ProductIDs {
+			productSection.Products = append(productSection.Products, &waE2E.ListMessage_Product{ProductID: proto.String(productID)})
+		}
+		productList.ProductSections = append(productList.ProductSections, productSection)
+	}
+
+	listMessage := &waE2E.ListMessage{
+		Title:           proto.String(request.Title),
+		Description:     proto.String(request.Body),
+		ButtonText:      proto.String(request.ButtonText),
+		ListType:        waE2E.ListMessage_PRODUCT_LIST.Enum(),
+		ProductListInfo: productList,
+	}
+	if request.Footer != "" {
+		listMessage.FooterText = proto.String(request.Footer)
+	}
+
+	msg := &waE2E.Message{ListMessage: listMessage}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, "🛍️ "+request.Title+": "+request.Body)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send product list success %s (server timestamp: %s)", request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) SendOrder(ctx context.Context, request domainSend.OrderRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendOrder(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, request.Phone)
+	if err != nil {
+		return response, err
+	}
+	options, err := service.getSendOptions(dataWaRecipient, request.ReplyTo, request.Ephemeral)
+	if err != nil {
+		return response, err
+	}
+
+	order := whatsapp.Order{
+		ID:       request.OrderID,
+		Title:    request.Title,
+		Note:     request.Note,
+		Seller:   service.WaCli.Store.ID.ToNonAD().String(),
+		Currency: request.CurrencyCode,
+		Tax:      request.Tax,
+		Shipping: request.Shipping,
+		Discount: request.Discount,
+	}
+	if order.ID == "" {
+		order.ID = uuid.NewString()
+	}
+	for _, item := range request.Items {
+		order.Items = append(order.Items, whatsapp.OrderItem{Name: item.Name, Price: item.Price, Quantity: item.Quantity})
+	}
+	if request.ThumbnailMediaID != "" {
+		uploaded, err := whatsapp.GetUploadedMedia(request.ThumbnailMediaID, "image")
+		if err != nil {
+			return response, err
+		}
+		order.Thumbnail = uploaded.Thumbnail
+	}
+
+	msg := &waE2E.Message{OrderMessage: whatsapp.NewOrderMessage(order)}
+	options.apply(msg)
+	ts, err := service.wrapSendMessage(ctx, dataWaRecipient, msg, "🛍️ "+request.Title+"\n"+msg.OrderMessage.GetMessage())
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Send order %s success %s (server timestamp: %s)", order.ID, request.Phone, ts.Timestamp.String())
+	return response, nil
+}
+
+// businessOwner is the owner of the catalog the products are from, this account by default
+func (service serviceSend) businessOwner(jid string) (types.JID, error) {
+	if jid == "" {
+		return service.WaCli.Store.ID.ToNonAD(), nil
+	}
+	return whatsapp.ParseJID(jid)
+}
+
+// productImage uploads the image of the product card, or reuses an image uploaded with UploadMedia
+func (service serviceSend) productImage(ctx context.Context, recipient types.JID, image *multipart.FileHeader, mediaID string) (*waE2E.ImageMessage, error) {
+	if mediaID != "" {
+		uploaded, err := whatsapp.GetUploadedMedia(mediaID, "image")
+		if err != nil {
+			return nil, err
+		}
+		msg, _ := uploadedMediaMessage(uploaded, "", false, nil)
+		return msg.ImageMessage, nil
+	}
+	if image == nil {
+		return nil, nil
+	}
+
+	imageBytes := helpers.MultipartFormFileHeaderToBytes(image)
+	uploaded, err := service.uploadMedia(ctx, whatsmeow.MediaImage, imageBytes, recipient)
+	if err != nil {
+		return nil, pkgError.WaUploadMediaError(fmt.Sprintf("Failed to upload product image: %v", err))
+	}
+	return &waE2E.ImageMessage{
+		JPEGThumbnail: mediaThumbnail("image", imageBytes),
+		URL:           proto.String(uploaded.URL),
+		DirectPath:    proto.String(uploaded.DirectPath),
+		MediaKey:      uploaded.MediaKey,
+		Mimetype:      proto.String(http.DetectContentType(imageBytes)),
+		FileEncSHA256: uploaded.FileEncSHA256,
+		FileSHA256:    uploaded.FileSHA256,
+		FileLength:    proto.Uint64(uploaded.FileLength),
+	}, nil
+}
+
+func (service serviceSend) SendStatusText(ctx context.Context, request domainSend.StatusTextRequest) (response domainSend.GenericResponse, err error) {
+	if err = validations.ValidateSendStatusText(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	text := &waE2E.ExtendedTextMessage{Text: proto.String(request.Text)}
+	if request.BackgroundColor != "" {
+		background, err := whatsapp.ParseARGB(request.BackgroundColor)
+		if err != nil {
+			return response, err
+		}
+		text.BackgroundArgb = proto.Uint32(background)
+	}
+	if request.TextColor != "" {
+		color, err := whatsapp.ParseARGB(request.TextColor)
+		if err != nil {
+			return response, err
+		}
+		text.TextArgb = proto.Uint32(color)
+	}
+	if font, ok := whatsapp.StatusFont(request.Font); ok {
+		text.Font = font.Enum()
+	}
+
+	ts, err := service.wrapSendMessage(ctx, types.StatusBroadcastJID, &waE2E.Message{ExtendedTextMessage: text}, request.Text)
+	if err = service.saveStatus(ts, err, "text", request.Text); err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Post text status success (server timestamp: %s)", ts.Timestamp.String())
+	return response, nil
+}
+
+func (service serviceSend) SendStatusMedia(ctx context.Context, request domainSend.StatusMediaRequest) (response domainSend.GenericResponse, err error) {
+	if err = validations.ValidateSendStatusMedia(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	mediaID := request.MediaID
+	if request.Media != nil {
+		uploaded, err := service.UploadMedia(ctx, domainSend.UploadMediaRequest{Type: request.Type, Media: request.Media})
+		if err != nil {
+			return response, err
+		}
+		mediaID = uploaded.MediaID
+	}
+
+	ts, err := service.sendUploadedMedia(ctx, types.StatusBroadcastJID, mediaID, request.Type, request.Caption, false, false, nil, sendOptions{})
+	if err = service.saveStatus(ts, err, request.Type, request.Caption); err != nil {
+		return response, err
+	}
+
+	response.MessageID = ts.ID
+	response.Status = fmt.Sprintf("Post %s status success (server timestamp: %s)", request.Type, ts.Timestamp.String())
+	return response, nil
+}
+
+// saveStatus keeps the posted status to count its views, a status queued until the reconnection is kept too
+func (service serviceSend) saveStatus(ts whatsmeow.SendResponse, sendErr error, statusType, content string) error {
+	id := ts.ID
+	var queued *whatsapp.SendRetryQueued
+	if errors.As(sendErr, &queued) {
+		id = queued.MessageID
+	} else if sendErr != nil {
+		return sendErr
+	}
+
+	if err := whatsapp.SaveStatus(whatsapp.Status{ID: id, Type: statusType, Content: content, CreatedAt: time.Now()}); err != nil {
+		logrus.Errorf("Failed to save status %s: %v", id, err)
+	}
+	return sendErr
+}
+
+func (service serviceSend) ListStatuses(_ context.Context) (response domainSend.StatusListResponse, err error) {
+	statuses, err := whatsapp.ListStatuses(time.Now())
+	if err != nil {
+		return response, err
+	}
+
+	response.Statuses = make([]domainSend.StatusItem, 0, len(statuses))
+	for _, status := range statuses {
+		response.Statuses = append(response.Statuses, domainSend.StatusItem{
+			MessageID: status.ID,
+			Type:      status.Type,
+			Content:   status.Content,
+			PostedAt:  status.CreatedAt.UTC().Format(time.RFC3339),
+			Views:     status.Views,
+		})
+	}
+	return response, nil
+}
+
+func (service serviceSend) GetStatusViewers(_ context.Context, request domainSend.StatusViewersRequest) (response domainSend.StatusViewersResponse, err error) {
+	viewers, err := whatsapp.GetStatusViewers(request.MessageID)
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = request.MessageID
+	response.Viewers = make([]domainSend.StatusViewer, 0, len(viewers))
+	for _, viewer := range viewers {
+		response.Viewers = append(response.Viewers, domainSend.StatusViewer{
+			JID:      viewer.JID,
+			ViewedAt: viewer.ViewedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	return response, nil
+}
+
+func (service serviceSend) CreateTemplate(ctx context.Context, request domainSend.TemplateRequest) (response domainSend.TemplateResponse, err error) {
+	if err = validations.ValidateTemplate(ctx, request); err != nil {
+		return response, err
+	}
+
+	now := time.Now()
+	template := whatsapp.MessageTemplate{Name: request.Name, Content: request.Content, CreatedAt: now, UpdatedAt: now}
+	if err = whatsapp.CreateMessageTemplate(template); err != nil {
+		return response, err
+	}
+	return toDomainTemplate(template), nil
+}
+
+func (service serviceSend) UpdateTemplate(ctx context.Context, request domainSend.TemplateRequest) (response domainSend.TemplateResponse, err error) {
+	if err = validations.ValidateTemplate(ctx, request); err != nil {
+		return response, err
+	}
+
+	if err = whatsapp.UpdateMessageTemplate(whatsapp.MessageTemplate{Name: request.Name, Content: request.Content, UpdatedAt: time.Now()}); err != nil {
+		return response, err
+	}
+	template, err := whatsapp.GetMessageTemplate(request.Name)
+	if err != nil {
+		return response, err
+	}
+	return toDomainTemplate(template), nil
+}
+
+func (service serviceSend) GetTemplate(_ context.Context, request domainSend.TemplateNameRequest) (response domainSend.TemplateResponse, err error) {
+	template, err := whatsapp.GetMessageTemplate(request.Name)
+	if err != nil {
+		return response, err
+	}
+	return toDomainTemplate(template), nil
+}
+
+func (service serviceSend) ListTemplates(_ context.Context) (response []domainSend.TemplateResponse, err error) {
+	templates, err := whatsapp.ListMessageTemplates()
+	if err != nil {
+		return response, err
+	}
+
+	response = make([]domainSend.TemplateResponse, 0, len(templates))
+	for _, template := range templates {
+		response = append(response, toDomainTemplate(template))
+	}
+	return response, nil
+}
+
+func (service serviceSend) DeleteTemplate(_ context.Context, request domainSend.TemplateNameRequest) (err error) {
+	return whatsapp.DeleteMessageTemplate(request.Name)
+}
+
+// SendTemplate renders the template with the variables and sends it as a text message
+func (service serviceSend) SendTemplate(ctx context.Context, request domainSend.SendTemplateRequest) (response domainSend.GenericResponse, err error) {
+	if err = validations.ValidateSendTemplate(ctx, request); err != nil {
+		return response, err
+	}
+
+	template, err := whatsapp.GetMessageTemplate(request.TemplateName)
+	if err != nil {
+		return response, err
+	}
+	message, err := whatsapp.RenderMessageTemplate(template.Content, request.Variables)
+	if err != nil {
+		return response, err
+	}
+
+	return service.SendText(ctx, domainSend.MessageRequest{
+		Phone:     request.Phone,
+		Message:   message,
+		Mentions:  request.Mentions,
+		ReplyTo:   request.ReplyTo,
+		Ephemeral: request.Ephemeral,
+	})
+}
+
+func toDomainTemplate(template whatsapp.MessageTemplate) domainSend.TemplateResponse {
+	return domainSend.TemplateResponse{
+		Name:         template.Name,
+		Content:      template.Content,
+		Placeholders: whatsapp.TemplatePlaceholders(template.Content),
+		CreatedAt:    template.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:    template.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func (service serviceSend) SendPresence(ctx context.Context, request domainSend.PresenceRequest) (response domainSend.GenericResponse, err error) {
+	err = validations.ValidateSendPresence(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	err = service.WaCli.SendPresence(types.Presence(request.Type))
+	if err != nil {
+		return response, err
+	}
+
+	response.MessageID = "presence"
+	response.Status = fmt.Sprintf("Send presence success %s", request.Type)
+	return response, nil
+}
+
+// sendOptions are the reply and disappearing settings shared by the send endpoints
+type sendOptions struct {
+	quote     *waE2E.ContextInfo
+	ephemeral time.Duration
+}
+
+func (options sendOptions) apply(msg *waE2E.Message) {
+	whatsapp.SetQuotedContext(msg, options.quote)
+	whatsapp.SetEphemeralContext(msg, options.ephemeral, time.Now())
+}
+
+func (service serviceSend) getSendOptions(recipient types.JID, replyTo *domainSend.ReplyTo, ephemeral string) (options sendOptions, err error) {
+	if ephemeral != "" {
+		timer, ok := whatsmeow.ParseDisappearingTimerString(ephemeral)
+		if !ok || timer == whatsmeow.DisappearingTimerOff {
+			return options, pkgError.ValidationError("ephemeral: must be 24h, 7d or 90d.")
+		}
+		options.ephemeral = timer
+	}
+	options.quote, err = service.getQuote(recipient, replyTo)
+	return options, err
+}
+
+// getQuote builds the context quoting the replied message, found in the message store or the chat storage
+func (service serviceSend) getQuote(recipient types.JID, replyTo *domainSend.ReplyTo) (*waE2E.ContextInfo, error) {
+	if replyTo == nil || replyTo.MessageID == "" {
+		return nil, nil
+	}
+
+	quote := &waE2E.ContextInfo{StanzaID: proto.String(replyTo.MessageID)}
+	if replyTo.Participant != "" {
+		participant, err := whatsapp.ParseJID(replyTo.Participant)
+		if err != nil {
+			return nil, err
+		}
+		quote.Participant = proto.String(participant.ToNonAD().String())
+	}
+
+	stored, err := whatsapp.FindStoredMessage(recipient, replyTo.MessageID)
+	if err != nil {
+		return nil, err
+	}
+	if stored != nil {
+		if quote.Participant == nil {
+			quote.Participant = proto.String(stored.Sender.String())
+		}
+		quote.QuotedMessage = stored.Message
+		return quote, nil
+	}
+
+	if record, err := utils.FindRecordFromStorage(replyTo.MessageID); err == nil {
+		if quote.Participant == nil {
+			quote.Participant = proto.String(record.JID)
+		}
+		quote.QuotedMessage = &waE2E.Message{Conversation: proto.String(record.MessageContent)}
+		return quote, nil
+	}
+
+	if quote.Participant == nil {
+		return nil, pkgError.ValidationError(fmt.Sprintf("message %s isn't stored, give the participant of reply_to", replyTo.MessageID))
+	}
+	return quote, nil
+}
+
+// getMentions resolves the @-tags of the text and the requested mentions. In a group they have to be its
+// participants, elsewhere the tags of numbers not on WhatsApp are left as plain text.
+func (service serviceSend) getMentions(recipient types.JID, text string, mentions []string) (result []string, err error) {
+	tags := utils.ContainsMention(text)
+	if len(tags) == 0 && len(mentions) == 0 {
+		return nil, nil
+	}
+
+	var participants map[string]bool
+	if recipient.Server == types.GroupServer {
+		group, err := service.WaCli.GetGroupInfo(recipient)
+		if err != nil {
+			return nil, err
+		}
+		participants = make(map[string]bool)
+		for _, participant := range group.Participants {
+			participants[participant.JID.User] = true
+			participants[participant.PhoneNumber.User] = true
+			participants[participant.LID.User] = true
+		}
+		delete(participants, "")
+	}
+
+	seen := make(map[string]bool)
+	for i, mention := range append(tags, mentions...) {
+		tagged := i < len(tags)
+		var jid types.JID
+		if participants != nil {
+			if jid, err = whatsapp.ParseJID(mention); err != nil {
+				return nil, err
+			}
+			if !participants[jid.User] {
+				return nil, pkgError.ValidationError(fmt.Sprintf("%s isn't a participant of the group", jid.User))
+			}
+		} else if jid, err = whatsapp.ValidateJidWithLogin(service.WaCli, mention); err != nil {
+			if tagged {
+				continue
+			}
+			return nil, err
+		}
+		if !seen[jid.String()] {
+			seen[jid.String()] = true
+			result = append(result, jid.String())
+		}
+	}
+	return result, nil
+}
+
+// mediaContextInfo is the context of a media message, nil when it's neither forwarded nor mentions anyone
+func mediaContextInfo(isForwarded bool, mentions []string) *waE2E.ContextInfo {
+	if !isForwarded && len(mentions) == 0 {
+		return nil
+	}
+	contextInfo := &waE2E.ContextInfo{MentionedJID: mentions}
+	if isForwarded {
+		contextInfo.IsForwarded = proto.Bool(true)
+		contextInfo.ForwardingScore = proto.Uint32(100)
+	}
+	return contextInfo
+}
+
+// uploadMedia uploads the media once for all the recipients of the request, except newsletters which get their own
+// unencrypted upload
+func (service serviceSend) uploadMedia(ctx context.Context, mediaType whatsmeow.MediaType, media []byte, recipient types.JID) (uploaded whatsmeow.UploadResponse, err error) {
+	if recipient.Server == types.NewsletterServer {
+		return service.WaCli.UploadNewsletter(ctx, media, mediaType)
+	}
+	key := fmt.Sprintf("upload/%s/%x", mediaType, sha256.Sum256(media))
+	return whatsapp.RequestMedia(ctx, key, func() (whatsmeow.UploadResponse, error) {
+		return service.WaCli.Upload(ctx, media, mediaType)
+	})
+}
+
+func (service serviceSend) UploadMedia(ctx context.Context, request domainSend.UploadMediaRequest) (response domainSend.UploadMediaResponse, err error) {
+	if err = validations.ValidateUploadMedia(ctx, request); err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	mediaBytes := helpers.MultipartFormFileHeaderToBytes(request.Media)
+	fileSHA256 := sha256.Sum256(mediaBytes)
+
+	// an already uploaded file is reused until it expires, e.g. the same image sent in several broadcasts
+	uploaded, cached := whatsapp.FindCachedUpload(request.Type, fileSHA256[:])
+	if !cached {
+		mediaType, _ := whatsapp.UploadedMediaType(request.Type)
+		upload, err := service.WaCli.Upload(ctx, mediaBytes, mediaType)
+		if err != nil {
+			return response, pkgError.WaUploadMediaError(fmt.Sprintf("Failed to upload %s: %v", request.Type, err))
+		}
+
+		uploaded, err = whatsapp.SaveUploadedMedia(whatsapp.UploadedMedia{
+			Type:      request.Type,
+			MimeType:  http.DetectContentType(mediaBytes),
+			FileName:  request.Media.Filename,
+			Upload:    upload,
+			Thumbnail: mediaThumbnail(request.Type, mediaBytes),
+		})
+		if err != nil {
+			return response, pkgError.InternalServerError(fmt.Sprintf("failed to save uploaded media %v", err))
+		}
+	}
+
+	response.MediaID = uploaded.ID
+	response.Type = uploaded.Type
+	response.MimeType = uploaded.MimeType
+	response.FileName = uploaded.FileName
+	response.FileLength = uploaded.Upload.FileLength
+	response.FileSHA256 = hex.EncodeToString(uploaded.Upload.FileSHA256)
+	response.DirectPath = uploaded.Upload.DirectPath
+	response.MediaKey = uploaded.Upload.MediaKey
+	response.ExpiresAt = uploaded.ExpiresAt().Format(time.RFC3339)
+	response.Cached = cached
+	return response, nil
+}
+
+// sendUploadedMedia sends a media uploaded with UploadMedia without uploading it again
+func (service serviceSend) sendUploadedMedia(ctx context.Context, recipient types.JID, mediaID, mediaType, caption string, viewOnce, isForwarded bool, mentions []string, options sendOptions) (whatsmeow.SendResponse, error) {
+	if recipient.Server == types.NewsletterServer {
+		return whatsmeow.SendResponse{}, pkgError.ValidationError("uploaded media can't be sent to newsletters")
+	}
+	uploaded, err := whatsapp.GetUploadedMedia(mediaID, mediaType)
+	if err != nil {
+		return whatsmeow.SendResponse{}, err
+	}
+
+	msg, content := uploadedMediaMessage(uploaded, caption, viewOnce, mediaContextInfo(isForwarded, mentions))
+	options.apply(msg)
+	if viewOnce {
+		msg = whatsapp.WrapViewOnce(msg)
+	}
+	return service.wrapSendMessage(ctx, recipient, msg, content)
+}
+
+// uploadedMediaMessage builds the message of a media uploaded with POST /send/media/upload, and its content
+// recorded in the chat storage
+func uploadedMediaMessage(uploaded whatsapp.UploadedMedia, caption string, viewOnce bool, contextInfo *waE2E.ContextInfo) (*waE2E.Message, string) {
+	upload := uploaded.Upload
+	msg := &waE2E.Message{}
+	var emoji, content string
+	switch uploaded.Type {
+	case "image":
+		msg.ImageMessage = &waE2E.ImageMessage{
+			JPEGThumbnail: uploaded.Thumbnail,
+			Caption:       proto.String(caption),
+			URL:           proto.String(upload.URL),
+			DirectPath:    proto.String(upload.DirectPath),
+			MediaKey:      upload.MediaKey,
+			Mimetype:      proto.String(uploaded.MimeType),
+			FileEncSHA256: upload.FileEncSHA256,
+			FileSHA256:    upload.FileSHA256,
+			FileLength:    proto.Uint64(upload.FileLength),
+			ViewOnce:      proto.Bool(viewOnce),
+			ContextInfo:   contextInfo,
+		}
+		emoji, content = "🖼️", "🖼️ Image"
+	case "video":
+		msg.VideoMessage = &waE2E.VideoMessage{
+			JPEGThumbnail: uploaded.Thumbnail,
+			Caption:       proto.String(caption),
+			URL:           proto.String(upload.URL),
+			DirectPath:    proto.String(upload.DirectPath),
+			MediaKey:      upload.MediaKey,
+			Mimetype:      proto.String(uploaded.MimeType),
+			FileEncSHA256: upload.FileEncSHA256,
+			FileSHA256:    upload.FileSHA256,
+			FileLength:    proto.Uint64(upload.FileLength),
+			ViewOnce:      proto.Bool(viewOnce),
+			ContextInfo:   contextInfo,
+		}
+		emoji, content = "🎥", "🎥 Video"
+	case "audio":
+		msg.AudioMessage = &waE2E.AudioMessage{
+			URL:           proto.String(upload.URL),
+			DirectPath:    proto.String(upload.DirectPath),
+			MediaKey:      upload.MediaKey,
+			Mimetype:      proto.String(uploaded.MimeType),
+			FileEncSHA256: upload.FileEncSHA256,
+			FileSHA256:    upload.FileSHA256,
+			FileLength:    proto.Uint64(upload.FileLength),
+			ContextInfo:   contextInfo,
+		}
+		emoji, content = "🎵", "🎵 Audio"
+	case "document":
+		msg.DocumentMessage = &waE2E.DocumentMessage{
+			Title:         proto.String(uploaded.FileName),
+			FileName:      proto.String(uploaded.FileName),
+			Caption:       proto.String(caption),
+			URL:           proto.String(upload.URL),
+			DirectPath:    proto.String(upload.DirectPath),
+			MediaKey:      upload.MediaKey,
+			Mimetype:      proto.String(uploaded.MimeType),
+			FileEncSHA256: upload.FileEncSHA256,
+			FileSHA256:    upload.FileSHA256,
+			FileLength:    proto.Uint64(upload.FileLength),
+			ContextInfo:   contextInfo,
+		}
+		emoji, content = "📄", "📄 Document"
+	}
+	if caption != "" {
+		content = emoji + " " + caption
+	}
+
+	return msg, content
+}
+
+// mediaThumbnail creates the JPEG preview of an uploaded image or video, videos need ffmpeg.
+// The media is sent without a preview when it can't be created.
+func mediaThumbnail(mediaType string, media []byte) []byte {
+	var (
+		srcImage image.Image
+		err      error
+	)
+	switch mediaType {
+	case "image":
+		srcImage, err = imaging.Decode(bytes.NewReader(media))
+	case "video":
+		srcImage, err = videoFrame(media)
+	default:
+		return nil
+	}
+	if err != nil {
+		logrus.Warnf("Failed to create thumbnail of uploaded %s: %v", mediaType, err)
+		return nil
+	}
+
+	var thumbnail bytes.Buffer
+	if err = imaging.Encode(&thumbnail, imaging.Resize(srcImage, 100, 0, imaging.Lanczos), imaging.JPEG); err != nil {
+		logrus.Warnf("Failed to create thumbnail of uploaded %s: %v", mediaType, err)
+		return nil
+	}
+	return thumbnail.Bytes()
+}
+
+// gifToVideoArgs converts a GIF to a silent H.264 MP4, the even dimensions are needed by yuv420p
+func gifToVideoArgs(source, destination string) []string {
+	return []string{"-y", "-i", source, "-movflags", "faststart", "-pix_fmt", "yuv420p",
+		"-vf", "scale=trunc(iw/2)*2:trunc(ih/2)*2", "-c:v", "libx264", "-an", destination}
+}
+
+// videoFrame extracts the frame at one second of the video with ffmpeg
+func videoFrame(video []byte) (image.Image, error) {
+	if _, err := exec.LookPath("ffmpeg"); err != nil {
+		return nil, fmt.Errorf("ffmpeg not installed")
+	}
+
+	generateUUID := fiberUtils.UUIDv4()
+	videoPath := fmt.Sprintf("%s/%s", config.PathSendItems, generateUUID)
+	framePath := fmt.Sprintf("%s/%s.png", config.PathSendItems, generateUUID)
+	defer func() { _ = utils.RemoveFile(0, videoPath, framePath) }()
+
+	if err := os.WriteFile(videoPath, video, 0600); err != nil {
+		return nil, err
+	}
+	if output, err := exec.Command("ffmpeg", "-i", videoPath, "-ss", "00:00:01.000", "-vframes", "1", framePath).CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v: %s", err, output)
+	}
+	return imaging.Open(framePath)
+}
+
+var (
+	broadcastsMu      sync.Mutex
+	broadcastsRunning = map[string]bool{}
+)
+
+// broadcastLoginWait is how often a broadcast checks whether the client is logged in again
+const broadcastLoginWait = 5 * time.Second
+
+func (service serviceSend) SendBroadcast(ctx context.Context, request domainSend.BroadcastRequest) (response domainSend.BroadcastResponse, err error) {
+	err = validations.ValidateSendBroadcast(ctx, request)
+	if err != nil {
+		return response, err
+	}
+	whatsapp.MustLogin(service.WaCli)
+
+	if request.TemplateName != "" {
+		template, err := whatsapp.GetMessageTemplate(request.TemplateName)
+		if err != nil {
+			return response, err
+		}
+		// a stored template fails upfront rather than for each recipient missing a variable
+		for _, recipient := range request.Recipients {
+			if missing := whatsapp.MissingTemplateVariables(template.Content, recipient.Variables); len(missing) > 0 {
+				return response, pkgError.ValidationError(fmt.Sprintf("recipients: variables %v of %s are missing.", missing, recipient.Phone))
+			}
+		}
+		request.Message = template.Content
+	}
+	if request.MediaID != "" {
+		uploaded, err := whatsapp.FindUploadedMedia(request.MediaID)
+		if err != nil {
+			return response, err
+		}
+		if uploaded.Type == "audio" {
+			return response, pkgError.ValidationError("uploaded audio can't be broadcast, the message is sent as the caption of the media")
+		}
+	}
+
+	broadcast := whatsapp.Broadcast{
+		ID:        uuid.NewString(),
+		Message:   request.Message,
+		MediaID:   request.MediaID,
+		Delay:     config.WhatsappBroadcastDelay,
+		Jitter:    config.WhatsappBroadcastJitter,
+		Status:    whatsapp.BroadcastStatusRunning,
+		CreatedAt: time.Now(),
+	}
+	if request.Delay != nil {
+		broadcast.Delay = time.Duration(*request.Delay) * time.Second
+	}
+	if request.Jitter != nil {
+		broadcast.Jitter = time.Duration(*request.Jitter) * time.Second
+	}
+	for _, recipient := range request.Recipients {
+		if _, err = whatsapp.ParseJID(recipient.Phone); err != nil {
+			return response, err
+		}
+		broadcast.Recipients = append(broadcast.Recipients, whatsapp.BroadcastRecipient{
+			Position:  len(broadcast.Recipients),
+			Phone:     recipient.Phone,
+			Variables: recipient.Variables,
+			Status:    whatsapp.BroadcastRecipientPending,
+		})
+	}
+
+	if err = whatsapp.CreateBroadcast(broadcast); err != nil {
+		return response, pkgError.InternalServerError(fmt.Sprintf("failed to store the broadcast: %v", err))
+	}
+	service.startBroadcast(broadcast.ID)
+
+	return broadcastResponse(broadcast), nil
+}
+
+func (service serviceSend) GetBroadcast(_ context.Context, request domainSend.GetBroadcastRequest) (response domainSend.BroadcastResponse, err error) {
+	broadcast, err := whatsapp.GetBroadcast(request.BroadcastID)
+	if err != nil {
+		return response, err
+	}
+	return broadcastResponse(broadcast), nil
+}
+
+// ResumeBroadcasts restarts the workers of the broadcasts interrupted by a restart, from their first pending recipient
+func (service serviceSend) ResumeBroadcasts() error {
+	ids, err := whatsapp.RunningBroadcasts()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		service.startBroadcast(id)
+	}
+	return nil
+}
+
+// startBroadcast runs the worker of the broadcast, unless it is already running
+func (service serviceSend) startBroadcast(id string) {
+	broadcastsMu.Lock()
+	defer broadcastsMu.Unlock()
+	if broadcastsRunning[id] {
+		return
+	}
+	broadcastsRunning[id] = true
+
+	go func() {
+		defer func() {
+			broadcastsMu.Lock()
+			delete(broadcastsRunning, id)
+			broadcastsMu.Unlock()
+		}()
+		if err := service.runBroadcast(id); err != nil {
+			logrus.Errorf("Broadcast %s stopped: %v", id, err)
+		}
+	}()
+}
+
+// runBroadcast sends the message to the pending recipients one at a time, pausing the delay and a random jitter
+// between two of them, then forwards the completion to the webhook
+func (service serviceSend) runBroadcast(id string) error {
+	broadcast, err := whatsapp.GetBroadcast(id)
+	if err != nil {
+		return err
+	}
+
+	first := true
+	for i := range broadcast.Recipients {
+		recipient := &broadcast.Recipients[i]
+		if recipient.Status != whatsapp.BroadcastRecipientPending {
+			continue
+		}
+		if !first {
+			time.Sleep(broadcastPause(broadcast.Delay, broadcast.Jitter))
+		}
+		first = false
+
+		for !service.WaCli.IsConnected() || !service.WaCli.IsLoggedIn() {
+			time.Sleep(broadcastLoginWait)
+		}
+
+		messageID, err := service.sendBroadcastMessage(broadcast, *recipient)
+		// a send lost with the connection is sent later with its ID, the message.retry_* webhooks tell the outcome
+		var queued *whatsapp.SendRetryQueued
+		if errors.As(err, &queued) {
+			messageID, err = queued.MessageID, nil
+		}
+		recipient.SentAt = time.Now()
+		if err != nil {
+			recipient.Status, recipient.Error = whatsapp.BroadcastRecipientFailed, err.Error()
+		} else {
+			recipient.Status, recipient.MessageID = whatsapp.BroadcastRecipientSent, messageID
+		}
+		if err = whatsapp.UpdateBroadcastRecipient(id, *recipient); err != nil {
+			return err
+		}
+	}
+
+	broadcast.Status, broadcast.CompletedAt = whatsapp.BroadcastStatusCompleted, time.Now()
+	if err = whatsapp.CompleteBroadcast(id, broadcast.CompletedAt); err != nil {
+		return err
+	}
+	whatsapp.ForwardBroadcastToWebhook(broadcast)
+	return nil
+}
+
+// sendBroadcastMessage sends the rendered message to a recipient, the panics of a lost connection are returned as errors
+func (service serviceSend) sendBroadcastMessage(broadcast whatsapp.Broadcast, recipient whatsapp.BroadcastRecipient) (messageID string, err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+
+	// campaigns give way to the other sends in the outbound queue
+	ctx := whatsapp.WithOutboundPriority(context.Background(), whatsapp.OutboundPriorityLow)
+	dataWaRecipient, err := whatsapp.ValidateJidWithLogin(service.WaCli, recipient.Phone)
+	if err != nil {
+		return "", err
+	}
+	text := whatsapp.RenderBroadcastMessage(broadcast.Message, recipient.Variables)
+
+	var ts whatsmeow.SendResponse
+	if broadcast.MediaID != "" {
+		uploaded, err := whatsapp.FindUploadedMedia(broadcast.MediaID)
+		if err != nil {
+			return "", err
+		}
+		ts, err = service.sendUploadedMedia(ctx, dataWaRecipient, uploaded.ID, uploaded.Type, text, false, false, nil, sendOptions{})
+		if err != nil {
+			return "", err
+		}
+	} else {
+		msg := &waE2E.Message{ExtendedTextMessage: &waE2E.ExtendedTextMessage{Text: proto.String(text)}}
+		if ts, err = service.wrapSendMessage(ctx, dataWaRecipient, msg, text); err != nil {
+			return "", err
+		}
+	}
+	return ts.ID, nil
+}
+
+// broadcastPause is the delay plus a random jitter, so the sends don't follow a visible pattern
+func broadcastPause(delay, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return delay
+	}
+	return delay + rand.N(jitter+1)
+}
+
+func broadcastResponse(broadcast whatsapp.Broadcast) domainSend.BroadcastResponse {
+	response := domainSend.BroadcastResponse{
+		BroadcastID: broadcast.ID,
+		Status:      broadcast.Status,
+		Total:       len(broadcast.Recipients),
+		CreatedAt:   broadcast.CreatedAt.Format(time.RFC3339),
+		Recipients:  make([]domainSend.BroadcastRecipientResponse, 0, len(broadcast.Recipients)),
+	}
+	if !broadcast.CompletedAt.IsZero() {
+		response.CompletedAt = broadcast.CompletedAt.Format(time.RFC3339)
+	}
+	for _, recipient := range broadcast.Recipients {
+		switch recipient.Status {
+		case whatsapp.BroadcastRecipientPending:
+			response.Pending++
+		case whatsapp.BroadcastRecipientSent:
+			response.Sent++
+		case whatsapp.BroadcastRecipientFailed:
+			response.Failed++
+		}
+		item := domainSend.BroadcastRecipientResponse{
+			Phone:     recipient.Phone,
+			Status:    recipient.Status,
+			MessageID: recipient.MessageID,
+			Error:     recipient.Error,
+		}
+		if !recipient.SentAt.IsZero() {
+			item.SentAt = recipient.SentAt.Format(time.RFC3339)
+		}
+		response.Recipients = append(response.Recipients, item)
+	}
+	return response
+}
+
+// defaultOutboundDrainTimeout is how long a drain waits when the request doesn't say
+const defaultOutboundDrainTimeout = 60 * time.Second
+
+func (service serviceSend) OutboundStatus(_ context.Context) (response domainSend.OutboundStatusResponse, err error) {
+	stats := whatsapp.GetOutboundStats()
+	return domainSend.OutboundStatusResponse{
+		Queued:        stats.Queued,
+		Chats:         stats.Chats,
+		Lanes:         stats.Lanes,
+		Sent:          stats.Sent,
+		Rejected:      stats.Rejected,
+		Flushed:       stats.Flushed,
+		Draining:      stats.Draining,
+		RatePerMinute: config.WhatsappOutboundRate,
+		Burst:         config.WhatsappOutboundBurst,
+		ChatRate:      config.WhatsappOutboundChatRate,
+		ChatBurst:     config.WhatsappOutboundChatBurst,
+	}, nil
+}
+
+func (service serviceSend) FlushOutbound(_ context.Context) (response domainSend.FlushOutboundResponse, err error) {
+	response.Cancelled = whatsapp.FlushOutbound()
+	return response, nil
+}
+
+func (service serviceSend) DrainOutbound(ctx context.Context, request domainSend.DrainOutboundRequest) (response domainSend.DrainOutboundResponse, err error) {
+	err = validations.ValidateDrainOutbound(ctx, request)
+	if err != nil {
+		return response, err
+	}
+
+	timeout := defaultOutboundDrainTimeout
+	if request.Timeout > 0 {
+		timeout = time.Duration(request.Timeout) * time.Second
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	response.Drained, err = whatsapp.DrainOutbound(ctx)
+	return response, err
 }
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"bytes"
+	"image"
+	"image/png"
+	"mime/multipart"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
+	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+func imageFileHeader(t *testing.T, name string, width, height int) *multipart.FileHeader {
+	var encoded bytes.Buffer
+	assert.NoError(t, png.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, width, height))))
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, err := writer.CreateFormFile("image", name)
+	assert.NoError(t, err)
+	_, _ = part.Write(encoded.Bytes())
+	assert.NoError(t, writer.Close())
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(1 << 20)
+	assert.NoError(t, err)
+	return form.File["image"][0]
+}
+
+func TestPrepareImage(t *testing.T) {
+	originalPath := config.PathSendItems
+	defer func() { config.PathSendItems = originalPath }()
+	config.PathSendItems = t.TempDir()
+
+	request := domainSend.ImageRequest{Image: imageFileHeader(t, "photo.png", 1200, 800), Compress: true}
+
+	t.Run("should compress the image and remove its files", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				prepared, err := prepareImage(request)
+				assert.NoError(t, err)
+
+				compressed, err := imaging.Decode(bytes.NewReader(prepared.data))
+				assert.NoError(t, err)
+				assert.Equal(t, 600, compressed.Bounds().Dx())
+				thumbnail, err := imaging.Decode(bytes.NewReader(prepared.thumbnail))
+				assert.NoError(t, err)
+				assert.Equal(t, 100, thumbnail.Bounds().Dx())
+			}()
+		}
+		wg.Wait()
+
+		entries, err := os.ReadDir(config.PathSendItems)
+		assert.NoError(t, err)
+		assert.Empty(t, entries, "the sends of the same file don't share their files")
+	})
+
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"context"
+	"sort"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/whatsapp"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/validations"
+)
+
+type webhookService struct{}
+
+func NewWebhookService() domainWebhook.IWebhookService {
+	return &webhookService{}
+}
+
+func (service webhookService) ListOutbox(_ context.Context) (response []domainWebhook.OutboxEntry, err error) {
+	entries, err := whatsapp.ListWebhookOutbox()
+	if err != nil {
+		return response, err
+	}
+
+	for _, entry := range entries {
+		response = append(response, domainWebhook.OutboxEntry{
+			ID:            entry.ID,
+			URL:           entry.URL,
+			EventType:     entry.EventType,
+			Payload:       entry.Payload,
+			Attempts:      entry.Attempts,
+			LastError:     entry.LastError,
+			NextAttemptAt: entry.NextAttemptAt,
+			CreatedAt:     entry.CreatedAt,
+		})
+	}
+	return response, nil
+}
+
+func (service webhookService) PurgeOutbox(_ context.Context, request domainWebhook.PurgeOutboxRequest) (response domainWebhook.PurgeOutboxResponse, err error) {
+	purged, err := whatsapp.PurgeWebhookOutbox(request.ID, request.URL)
+	if err != nil {
+		return response, err
+	}
+
+	response.Purged = purged
+	return response, nil
+}
+
+func (service webhookService) ListDeadLetters(_ context.Context) (response []domainWebhook.DeadLetter, err error) {
+	letters, err := whatsapp.ListWebhookDeadLetters()
+	if err != nil {
+		return response, err
+	}
+
+	for _, letter := range letters {
+		response = append(response, domainWebhook.DeadLetter{
+			ID:        letter.ID,
+			URL:       letter.URL,
+			EventType: letter.EventType,
+			Payload:   letter.Payload,
+			Attempts:  letter.Attempts,
+			Reason:    letter.Reason,
+			CreatedAt: letter.CreatedAt,
+			FailedAt:  letter.FailedAt,
+		})
+	}
+	return response, nil
+}
+
+func (service webhookService) ReplayDeadLetter(ctx context.Context, request domainWebhook.ReplayDeadLetterRequest) (err error) {
+	if err = validations.ValidateReplayDeadLetter(ctx, request); err != nil {
+		return err
+	}
+	return whatsapp.ReplayWebhookDeadLetter(ctx, request.ID)
+}
+
+func (service webhookService) Status(_ context.Context) (response []domainWebhook.EndpointStatus, err error) {
+	stats, err := whatsapp.GetWebhookStats()
+	if err != nil {
+		return response, err
+	}
+
+	for _, stat := range stats {
+		status := domainWebhook.EndpointStatus{
+			URL:              stat.URL,
+			Delivered:        stat.Delivered,
+			Failed:           stat.Failed,
+			Retried:          stat.Retried,
+			Pending:          stat.Pending,
+			LastError:        stat.LastError,
+			AverageLatencyMS: stat.AverageLatency.Milliseconds(),
+		}
+		if !stat.LastErrorAt.IsZero() {
+			status.LastErrorAt = &stat.LastErrorAt
+		}
+		if !stat.LastSuccessAt.IsZero() {
+			status.LastSuccessAt = &stat.LastSuccessAt
+		}
+		response = append(response, status)
+	}
+	return response, nil
+}
+
+func (service webhookService) RegisterEndpoint(ctx context.Context, request domainWebhook.RegisterEndpointRequest) (response domainWebhook.Endpoint, err error) {
+	if err = validations.ValidateRegisterWebhookEndpoint(ctx, request); err != nil {
+		return response, err
+	}
+
+	endpoint := whatsapp.WebhookEndpoint{
+		URL:         request.URL,
+		Events:      request.Events,
+		Template:    request.Template,
+		Signature:   request.Signature,
+		Secret:      request.Secret,
+		Headers:     request.Headers,
+		BearerToken: request.BearerToken,
+	}
+	for _, rule := range request.Rules {
+		endpoint.Rules = append(endpoint.Rules, whatsapp.WebhookRule{
+			Chats:        rule.Chats,
+			ChatType:     rule.ChatType,
+			MessageTypes: rule.MessageTypes,
+			Senders:      rule.Senders,
+			Keyword:      rule.Keyword,
+		})
+	}
+
+	endpoint, err = whatsapp.RegisterWebhookEndpoint(endpoint)
+	if err != nil {
+		return response, err
+	}
+	return toDomainWebhookEndpoint(endpoint), nil
+}
+
+func (service webhookService) ListEndpoints(_ context.Context) (response []domainWebhook.Endpoint, err error) {
+	for _, endpoint := range whatsapp.ListWebhookEndpoints() {
+		response = append(response, toDomainWebhookEndpoint(endpoint))
+	}
+	return response, nil
+}
+
+func (service webhookService) DeleteEndpoint(ctx context.Context, request domainWebhook.DeleteEndpointRequest) (err error) {
+	if err = validations.ValidateDeleteWebhookEndpoint(ctx, request); err != nil {
+		return err
+	}
+	return whatsapp.DeleteWebhookEndpoint(request.ID)
+}
+
+// toDomainWebhookEndpoint maps an endpoint without exposing its secrets or header values
+func toDomainWebhookEndpoint(endpoint whatsapp.WebhookEndpoint) domainWebhook.Endpoint {
+	result := domainWebhook.Endpoint{
+		ID:             endpoint.ID,
+		URL:            endpoint.URL,
+		Source:         "config",
+		Events:         endpoint.Events,
+		Signature:      endpoint.Signature,
+		Template:       endpoint.Template,
+		HasSecret:      endpoint.Secret != "",
+		HasBearerToken: endpoint.BearerToken != "",
+	}
+	if endpoint.ID > 0 {
+		result.Source = "api"
+	}
+	for _, rule := range endpoint.Rules {
+		result.Rules = append(result.Rules, domainWebhook.EndpointRule{
+			Chats:        rule.Chats,
+			ChatType:     rule.ChatType,
+			MessageTypes: rule.MessageTypes,
+			Senders:      rule.Senders,
+			Keyword:      rule.Keyword,
+		})
+	}
+	for header := range endpoint.Headers {
+		result.Headers = append(result.Headers, header)
+	}
+	sort.Strings(result.Headers)
+	return result
+}
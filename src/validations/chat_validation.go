@@ -0,0 +1,60 @@
+package validations
+
+import (
+	"context"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const maxChatPresenceDuration = 300 // seconds
+
+func ValidateMarkChatAsRead(ctx context.Context, request domainChat.MarkAsReadRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JID, validation.Required),
+		validation.Field(&request.MessageIDs, validation.When(request.Until == 0, validation.Required), validation.Each(validation.Required)),
+		validation.Field(&request.Until, validation.Min(int64(0))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if len(request.MessageIDs) > 0 && request.Until > 0 {
+		return pkgError.ValidationError("either message_ids or until can be provided, not both")
+	}
+
+	return nil
+}
+
+func ValidateChatPresence(ctx context.Context, request domainChat.PresenceRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JID, validation.Required),
+		validation.Field(&request.State, validation.Required, validation.In("composing", "recording", "paused")),
+		validation.Field(&request.Duration, validation.Min(0), validation.Max(maxChatPresenceDuration)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.State == "paused" && request.Duration > 0 {
+		return pkgError.ValidationError("duration can't be provided with the paused state")
+	}
+
+	return nil
+}
+
+func ValidateDisappearingTimer(ctx context.Context, request domainChat.DisappearingTimerRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.JID, validation.Required),
+		validation.Field(&request.Timer, validation.Required, validation.In("off", "24h", "7d", "90d")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
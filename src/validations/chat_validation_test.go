@@ -0,0 +1,158 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainChat "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/chat"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMarkChatAsRead(t *testing.T) {
+	type args struct {
+		request domainChat.MarkAsReadRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with message ids",
+			args: args{request: domainChat.MarkAsReadRequest{
+				JID:        "1728937129312@s.whatsapp.net",
+				MessageIDs: []string{"3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with until",
+			args: args{request: domainChat.MarkAsReadRequest{
+				JID:   "1728937129312@s.whatsapp.net",
+				Until: 1700000000,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without message ids nor until",
+			args: args{request: domainChat.MarkAsReadRequest{
+				JID: "1728937129312@s.whatsapp.net",
+			}},
+			err: pkgError.ValidationError("message_ids: cannot be blank."),
+		},
+		{
+			name: "should error with message ids and until",
+			args: args{request: domainChat.MarkAsReadRequest{
+				JID:        "1728937129312@s.whatsapp.net",
+				MessageIDs: []string{"3EB0B430B6F8F1D0E053AC120E0A9E5C"},
+				Until:      1700000000,
+			}},
+			err: pkgError.ValidationError("either message_ids or until can be provided, not both"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateMarkChatAsRead(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateChatPresence(t *testing.T) {
+	type args struct {
+		request domainChat.PresenceRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with duration",
+			args: args{request: domainChat.PresenceRequest{
+				JID:      "1728937129312@s.whatsapp.net",
+				State:    "recording",
+				Duration: 30,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown state",
+			args: args{request: domainChat.PresenceRequest{
+				JID:   "1728937129312@s.whatsapp.net",
+				State: "typing",
+			}},
+			err: pkgError.ValidationError("state: must be a valid value."),
+		},
+		{
+			name: "should error with too long duration",
+			args: args{request: domainChat.PresenceRequest{
+				JID:      "1728937129312@s.whatsapp.net",
+				State:    "composing",
+				Duration: 301,
+			}},
+			err: pkgError.ValidationError("duration: must be no greater than 300."),
+		},
+		{
+			name: "should error with paused and duration",
+			args: args{request: domainChat.PresenceRequest{
+				JID:      "1728937129312@s.whatsapp.net",
+				State:    "paused",
+				Duration: 10,
+			}},
+			err: pkgError.ValidationError("duration can't be provided with the paused state"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateChatPresence(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateDisappearingTimer(t *testing.T) {
+	type args struct {
+		request domainChat.DisappearingTimerRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with 7d",
+			args: args{request: domainChat.DisappearingTimerRequest{
+				JID:   "1728937129312@s.whatsapp.net",
+				Timer: "7d",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with off",
+			args: args{request: domainChat.DisappearingTimerRequest{
+				JID:   "120363024512399999@g.us",
+				Timer: "off",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with unknown timer",
+			args: args{request: domainChat.DisappearingTimerRequest{
+				JID:   "1728937129312@s.whatsapp.net",
+				Timer: "30d",
+			}},
+			err: pkgError.ValidationError("timer: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateDisappearingTimer(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
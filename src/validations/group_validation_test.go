@@ -0,0 +1,61 @@
+package validations
+
+import (
+	"context"
+	"testing"
+
+	domainGroup "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/group"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateGroupSettings(t *testing.T) {
+	enabled := true
+	type args struct {
+		request domainGroup.GroupSettingsRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with one setting",
+			args: args{request: domainGroup.GroupSettingsRequest{GroupID: "120363025246125888@g.us", Announce: &enabled}},
+			err:  nil,
+		},
+		{
+			name: "should success with every setting",
+			args: args{request: domainGroup.GroupSettingsRequest{
+				GroupID:       "120363025246125888@g.us",
+				Announce:      &enabled,
+				Locked:        &enabled,
+				JoinApproval:  &enabled,
+				MemberAddMode: "all_member_add",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty group id",
+			args: args{request: domainGroup.GroupSettingsRequest{Locked: &enabled}},
+			err:  pkgError.ValidationError("group_id: cannot be blank."),
+		},
+		{
+			name: "should error with unknown member add mode",
+			args: args{request: domainGroup.GroupSettingsRequest{GroupID: "120363025246125888@g.us", MemberAddMode: "anyone"}},
+			err:  pkgError.ValidationError("member_add_mode: must be a valid value."),
+		},
+		{
+			name: "should error without any setting",
+			args: args{request: domainGroup.GroupSettingsRequest{GroupID: "120363025246125888@g.us"}},
+			err:  pkgError.ValidationError("at least one of announce, locked, join_approval or member_add_mode is required"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateGroupSettings(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
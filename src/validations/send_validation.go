@@ -3,11 +3,13 @@ package validations
 import (
 	"context"
 	"fmt"
+	"regexp"
 	"sort"
 
 	"github.com/aldinokemal/go-whatsapp-web-multidevice/config"
 	domainSend "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/send"
 	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	"github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/utils"
 	"github.com/dustin/go-humanize"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
@@ -17,6 +19,7 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
 		validation.Field(&request.Message, validation.Required),
+		validation.Field(&request.Mentions, validation.Each(validation.Required)),
 	)
 
 	if err != nil {
@@ -28,14 +31,15 @@ func ValidateSendMessage(ctx context.Context, request domainSend.MessageRequest)
 func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Mentions, validation.Each(validation.Required)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
-	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") {
-		return pkgError.ValidationError("either Image or ImageURL must be provided")
+	if request.Image == nil && (request.ImageURL == nil || *request.ImageURL == "") && request.MediaID == "" {
+		return pkgError.ValidationError("either Image, ImageURL or MediaID must be provided")
 	}
 
 	if request.Image != nil {
@@ -67,17 +71,29 @@ func ValidateSendImage(ctx context.Context, request domainSend.ImageRequest) err
 func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.File, validation.Required),
+		validation.Field(&request.File, validation.When(request.MediaID == "", validation.Required)),
+		validation.Field(&request.Mentions, validation.Each(validation.Required)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
-	if request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
+	if request.File != nil && request.File.Size > config.WhatsappSettingMaxFileSize { // 10MB
 		maxSizeString := humanize.Bytes(uint64(config.WhatsappSettingMaxFileSize))
 		return pkgError.ValidationError(fmt.Sprintf("max file upload is %s, please upload in cloud and send via text if your file is higher than %s", maxSizeString, maxSizeString))
 	}
+	if request.Thumbnail != nil {
+		if request.File == nil {
+			return pkgError.ValidationError("thumbnail needs a file, uploaded media are sent without preview")
+		}
+		if mimeType := request.Thumbnail.Header.Get("Content-Type"); mimeType != "image/jpeg" && mimeType != "image/png" {
+			return pkgError.ValidationError("your thumbnail is not allowed. please use jpg/jpeg/png")
+		}
+		if request.Thumbnail.Size > config.WhatsappSettingMaxImageSize {
+			return pkgError.ValidationError(fmt.Sprintf("max thumbnail upload is %s", humanize.Bytes(uint64(config.WhatsappSettingMaxImageSize))))
+		}
+	}
 
 	return nil
 }
@@ -85,21 +101,29 @@ func ValidateSendFile(ctx context.Context, request domainSend.FileRequest) error
 func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.Video, validation.Required),
+		validation.Field(&request.Video, validation.When(request.MediaID == "", validation.Required)),
+		validation.Field(&request.Mentions, validation.Each(validation.Required)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
+	if request.GifPlayback && request.Video == nil {
+		return pkgError.ValidationError("gif_playback needs a video file, uploaded media can't be sent as a GIF")
+	}
+	if request.Video == nil {
+		return nil
+	}
 
 	availableMimes := map[string]bool{
 		"video/mp4":        true,
 		"video/x-matroska": true,
 		"video/avi":        true,
+		"image/gif":        true,
 	}
 
 	if !availableMimes[request.Video.Header.Get("Content-Type")] {
-		return pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi")
+		return pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi/gif")
 	}
 
 	if request.Video.Size > config.WhatsappSettingMaxVideoSize { // 30MB
@@ -110,17 +134,43 @@ func ValidateSendVideo(ctx context.Context, request domainSend.VideoRequest) err
 	return nil
 }
 
+// maxContacts is the most contacts sent in one message.
+const maxContacts = 50
+
 func ValidateSendContact(ctx context.Context, request domainSend.ContactRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.ContactPhone, validation.Required),
-		validation.Field(&request.ContactName, validation.Required),
+		validation.Field(&request.ContactPhone, validation.When(len(request.Contacts) == 0, validation.Required)),
+		validation.Field(&request.ContactName, validation.When(len(request.Contacts) == 0, validation.Required)),
+		validation.Field(&request.Contacts, validation.Length(0, maxContacts)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
 
+	if len(request.Contacts) > 0 && (request.ContactName != "" || request.ContactPhone != "") {
+		return pkgError.ValidationError("either contacts or contact_name and contact_phone can be provided, not both")
+	}
+
+	for i, contact := range request.Contacts {
+		if contact.VCard != "" {
+			if !utils.IsVCard(contact.VCard) {
+				return pkgError.ValidationError(fmt.Sprintf("contacts[%d] vcard: must start with BEGIN:VCARD and end with END:VCARD.", i))
+			}
+			continue
+		}
+
+		err = validation.ValidateStructWithContext(ctx, &contact,
+			validation.Field(&contact.Name, validation.Required),
+			validation.Field(&contact.Phone, validation.Required),
+			validation.Field(&contact.Email, is.EmailFormat),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("contacts[%d] %s", i, err.Error()))
+		}
+	}
+
 	return nil
 }
 
@@ -143,6 +193,41 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 		validation.Field(&request.Phone, validation.Required),
 		validation.Field(&request.Latitude, validation.Required, is.Latitude),
 		validation.Field(&request.Longitude, validation.Required, is.Longitude),
+		validation.Field(&request.ThumbnailURL, is.URL),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// maxLiveLocationDuration is the longest WhatsApp shares a live location for.
+const maxLiveLocationDuration = 8 * 60 * 60
+
+func ValidateSendLiveLocation(ctx context.Context, request domainSend.LiveLocationRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Latitude, validation.Required, is.Latitude),
+		validation.Field(&request.Longitude, validation.Required, is.Longitude),
+		validation.Field(&request.Heading, validation.Max(uint32(359))),
+		validation.Field(&request.Duration,
+			validation.When(request.MessageID == "", validation.Required, validation.Min(60), validation.Max(maxLiveLocationDuration)),
+		),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateStopLiveLocation(ctx context.Context, request domainSend.StopLiveLocationRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.MessageID, validation.Required),
 	)
 
 	if err != nil {
@@ -155,12 +240,18 @@ func ValidateSendLocation(ctx context.Context, request domainSend.LocationReques
 func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Phone, validation.Required),
-		validation.Field(&request.Audio, validation.Required),
+		validation.Field(&request.Audio, validation.When(request.MediaID == "", validation.Required)),
 	)
 
 	if err != nil {
 		return pkgError.ValidationError(err.Error())
 	}
+	if request.PTT && request.Audio == nil {
+		return pkgError.ValidationError("ptt needs an audio file, uploaded media can't be sent as a voice note")
+	}
+	if request.Audio == nil {
+		return nil
+	}
 
 	availableMimes := map[string]bool{
 		"audio/aac":      true,
@@ -200,6 +291,86 @@ func ValidateSendAudio(ctx context.Context, request domainSend.AudioRequest) err
 	return nil
 }
 
+// maxBroadcastRecipients bounds a broadcast, larger lists are split in several broadcasts.
+const maxBroadcastRecipients = 1000
+
+// maxBroadcastDelay is the longest pause, in seconds, between two recipients of a broadcast.
+const maxBroadcastDelay = 3600
+
+func ValidateSendBroadcast(ctx context.Context, request domainSend.BroadcastRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Recipients, validation.Required, validation.Length(1, maxBroadcastRecipients)),
+		validation.Field(&request.Message, validation.When(request.MediaID == "" && request.TemplateName == "", validation.Required)),
+		validation.Field(&request.Delay, validation.Min(0), validation.Max(maxBroadcastDelay)),
+		validation.Field(&request.Jitter, validation.Min(0), validation.Max(maxBroadcastDelay)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	if request.Message != "" && request.TemplateName != "" {
+		return pkgError.ValidationError("either message or template_name can be provided, not both")
+	}
+
+	seen := make(map[string]bool, len(request.Recipients))
+	for i, recipient := range request.Recipients {
+		if recipient.Phone == "" {
+			return pkgError.ValidationError(fmt.Sprintf("recipients: phone of recipient %d cannot be blank.", i))
+		}
+		if seen[recipient.Phone] {
+			return pkgError.ValidationError(fmt.Sprintf("recipients: %s is given more than once.", recipient.Phone))
+		}
+		seen[recipient.Phone] = true
+	}
+
+	return nil
+}
+
+// maxOutboundDrainTimeout is the longest an admin request waits, in seconds, for the outbound queue to drain.
+const maxOutboundDrainTimeout = 600
+
+func ValidateDrainOutbound(ctx context.Context, request domainSend.DrainOutboundRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Timeout, validation.Min(0), validation.Max(maxOutboundDrainTimeout)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+// maxPollOptions is the most options WhatsApp shows in a poll.
+const maxPollOptions = 12
+
+func ValidateSendSticker(ctx context.Context, request domainSend.StickerRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Sticker, validation.Required),
+		validation.Field(&request.PackName, validation.Length(0, 128)),
+		validation.Field(&request.PackPublisher, validation.Length(0, 128)),
+		validation.Field(&request.Emojis, validation.Length(0, 3), validation.Each(validation.Required)),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	availableMimes := map[string]bool{
+		"image/jpeg": true,
+		"image/jpg":  true,
+		"image/png":  true,
+		"image/gif":  true,
+		"image/webp": true,
+	}
+	if !availableMimes[request.Sticker.Header.Get("Content-Type")] {
+		return pkgError.ValidationError("your sticker is not allowed. please use jpg/jpeg/png/gif/webp")
+	}
+
+	return nil
+}
+
 func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error {
 	// Validate options first to ensure it is not blank before validating MaxAnswer
 	if len(request.Options) == 0 {
@@ -210,7 +381,7 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 		validation.Field(&request.Phone, validation.Required),
 		validation.Field(&request.Question, validation.Required),
 
-		validation.Field(&request.Options, validation.Each(validation.Required)),
+		validation.Field(&request.Options, validation.Length(0, maxPollOptions), validation.Each(validation.Required)),
 
 		validation.Field(&request.MaxAnswer, validation.Required),
 		validation.Field(&request.MaxAnswer, validation.Min(1)),
@@ -233,6 +404,289 @@ func ValidateSendPoll(ctx context.Context, request domainSend.PollRequest) error
 	return nil
 }
 
+// maxButtons is the most quick reply buttons WhatsApp shows in a message.
+const maxButtons = 3
+
+func ValidateSendButtons(ctx context.Context, request domainSend.ButtonsRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Body, validation.Required, validation.Length(0, 1024)),
+		validation.Field(&request.Footer, validation.Length(0, 60)),
+		validation.Field(&request.Header, validation.Length(0, 60)),
+		validation.Field(&request.HeaderMediaType,
+			validation.When(request.HeaderMediaID != "", validation.Required, validation.In("image", "video", "document")),
+		),
+		validation.Field(&request.Buttons, validation.Required, validation.Length(1, maxButtons)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Header != "" && request.HeaderMediaID != "" {
+		return pkgError.ValidationError("either header or header_media_id can be provided, not both")
+	}
+
+	uniqueIDs := make(map[string]bool)
+	for i, button := range request.Buttons {
+		err = validation.ValidateStructWithContext(ctx, &button,
+			validation.Field(&button.ID, validation.Required, validation.Length(0, 256)),
+			validation.Field(&button.Text, validation.Required, validation.Length(0, 20)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("buttons[%d] %s", i, err.Error()))
+		}
+		if uniqueIDs[button.ID] {
+			return pkgError.ValidationError("buttons id should be unique")
+		}
+		uniqueIDs[button.ID] = true
+	}
+
+	return nil
+}
+
+// maxCarouselCards and maxCarouselButtons are the most cards of a carousel and buttons of a card the clients render
+const (
+	maxCarouselCards   = 10
+	maxCarouselButtons = 2
+)
+
+func ValidateSendCarousel(ctx context.Context, request domainSend.CarouselRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Body, validation.Required, validation.Length(0, 1024)),
+		validation.Field(&request.Footer, validation.Length(0, 60)),
+		validation.Field(&request.Cards, validation.Required, validation.Length(1, maxCarouselCards)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	uniqueIDs := make(map[string]bool)
+	for i, card := range request.Cards {
+		err = validation.ValidateStructWithContext(ctx, &card,
+			validation.Field(&card.MediaID, validation.Required),
+			validation.Field(&card.Title, validation.Length(0, 60)),
+			validation.Field(&card.Body, validation.Required, validation.Length(0, 160)),
+			validation.Field(&card.Footer, validation.Length(0, 60)),
+			validation.Field(&card.Buttons, validation.Required, validation.Length(1, maxCarouselButtons)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("cards[%d] %s", i, err.Error()))
+		}
+
+		for j, button := range card.Buttons {
+			err = validation.ValidateStructWithContext(ctx, &button,
+				validation.Field(&button.Type, validation.Required, validation.In("reply", "url", "call")),
+				validation.Field(&button.Text, validation.Required, validation.Length(0, 20)),
+				validation.Field(&button.ID, validation.When(button.Type == "reply", validation.Required), validation.Length(0, 256)),
+				validation.Field(&button.URL, validation.When(button.Type == "url", validation.Required), is.URL),
+				validation.Field(&button.PhoneNumber, validation.When(button.Type == "call", validation.Required)),
+			)
+			if err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("cards[%d].buttons[%d] %s", i, j, err.Error()))
+			}
+			if button.Type != "reply" {
+				continue
+			}
+			if uniqueIDs[button.ID] {
+				return pkgError.ValidationError("buttons id should be unique")
+			}
+			uniqueIDs[button.ID] = true
+		}
+	}
+
+	return nil
+}
+
+func ValidateSendProduct(ctx context.Context, request domainSend.ProductRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.ProductID, validation.Required),
+		validation.Field(&request.Title, validation.Required, validation.Length(0, 256)),
+		validation.Field(&request.Description, validation.Length(0, 1024)),
+		validation.Field(&request.CurrencyCode, validation.When(request.Price > 0, validation.Required), validation.Match(currencyCode)),
+		validation.Field(&request.Price, validation.Min(0.0)),
+		validation.Field(&request.SalePrice, validation.Min(0.0), validation.When(request.SalePrice > 0, validation.Max(request.Price))),
+		validation.Field(&request.URL, is.URL),
+		validation.Field(&request.Body, validation.Length(0, 1024)),
+		validation.Field(&request.Footer, validation.Length(0, 60)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Image != nil {
+		if request.MediaID != "" {
+			return pkgError.ValidationError("either image or media_id can be provided, not both")
+		}
+		if mimeType := request.Image.Header.Get("Content-Type"); mimeType != "image/jpeg" && mimeType != "image/png" {
+			return pkgError.ValidationError("your image is not allowed. please use jpg/jpeg/png")
+		}
+		if request.Image.Size > config.WhatsappSettingMaxImageSize {
+			return pkgError.ValidationError(fmt.Sprintf("max image upload is %s", humanize.Bytes(uint64(config.WhatsappSettingMaxImageSize))))
+		}
+	}
+	return nil
+}
+
+// maxProductListItems is the most products WhatsApp shows in a product list, all sections together.
+const maxProductListItems = 30
+
+func ValidateSendProductList(ctx context.Context, request domainSend.ProductListRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Title, validation.Required, validation.Length(0, 60)),
+		validation.Field(&request.Body, validation.Required, validation.Length(0, 1024)),
+		validation.Field(&request.Footer, validation.Length(0, 60)),
+		validation.Field(&request.ButtonText, validation.Required, validation.Length(0, 20)),
+		validation.Field(&request.Sections, validation.Required, validation.Length(1, 10)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	products := make(map[string]bool)
+	for i, section := range request.Sections {
+		err = validation.ValidateStructWithContext(ctx, &section,
+			validation.Field(&section.Title, validation.Required, validation.Length(0, 24)),
+			validation.Field(&section.ProductIDs, validation.Required, validation.Each(validation.Required)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("sections[%d] %s", i, err.Error()))
+		}
+		for _, productID := range section.ProductIDs {
+			if products[productID] {
+				return pkgError.ValidationError(fmt.Sprintf("product %s is listed more than once", productID))
+			}
+			products[productID] = true
+		}
+	}
+	if len(products) > maxProductListItems {
+		return pkgError.ValidationError(fmt.Sprintf("a product list shows at most %d products", maxProductListItems))
+	}
+	if request.HeaderProductID != "" && !products[request.HeaderProductID] {
+		return pkgError.ValidationError("header_product_id must be one of the listed products")
+	}
+	return nil
+}
+
+// maxOrderItems keeps the invoice of an order readable in a chat bubble
+const maxOrderItems = 50
+
+func ValidateSendOrder(ctx context.Context, request domainSend.OrderRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.OrderID, validation.Length(0, 64)),
+		validation.Field(&request.Title, validation.Required, validation.Length(0, 256)),
+		validation.Field(&request.Note, validation.Length(0, 1024)),
+		validation.Field(&request.CurrencyCode, validation.Required, validation.Match(currencyCode)),
+		validation.Field(&request.Items, validation.Required, validation.Length(1, maxOrderItems)),
+		validation.Field(&request.Tax, validation.Min(0.0)),
+		validation.Field(&request.Shipping, validation.Min(0.0)),
+		validation.Field(&request.Discount, validation.Min(0.0)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	var subtotal float64
+	for i, item := range request.Items {
+		err = validation.ValidateStructWithContext(ctx, &item,
+			validation.Field(&item.Name, validation.Required, validation.Length(0, 256)),
+			validation.Field(&item.Price, validation.Min(0.0)),
+			validation.Field(&item.Quantity, validation.Required, validation.Min(1)),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("items[%d] %s", i, err.Error()))
+		}
+		subtotal += item.Price * float64(item.Quantity)
+	}
+	if request.Discount > subtotal+request.Tax+request.Shipping {
+		return pkgError.ValidationError("discount can't be more than the order total")
+	}
+	return nil
+}
+
+// currencyCode is an ISO 4217 code of the product prices.
+var currencyCode = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// maxListRows is the most rows WhatsApp shows in a list, all sections together.
+const maxListRows = 10
+
+func ValidateSendList(ctx context.Context, request domainSend.ListRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.Title, validation.Length(0, 60)),
+		validation.Field(&request.Body, validation.Required, validation.Length(0, 1024)),
+		validation.Field(&request.Footer, validation.Length(0, 60)),
+		validation.Field(&request.ButtonText, validation.Required, validation.Length(0, 20)),
+		validation.Field(&request.Sections, validation.Required, validation.Length(1, maxListRows)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	rows := 0
+	uniqueIDs := make(map[string]bool)
+	for i, section := range request.Sections {
+		err = validation.ValidateStructWithContext(ctx, &section,
+			// a title tells the sections apart when there are several
+			validation.Field(&section.Title, validation.When(len(request.Sections) > 1, validation.Required), validation.Length(0, 24)),
+			validation.Field(&section.Rows, validation.Required),
+		)
+		if err != nil {
+			return pkgError.ValidationError(fmt.Sprintf("sections[%d] %s", i, err.Error()))
+		}
+
+		for j, row := range section.Rows {
+			err = validation.ValidateStructWithContext(ctx, &row,
+				validation.Field(&row.ID, validation.Required, validation.Length(0, 200)),
+				validation.Field(&row.Title, validation.Required, validation.Length(0, 24)),
+				validation.Field(&row.Description, validation.Length(0, 72)),
+			)
+			if err != nil {
+				return pkgError.ValidationError(fmt.Sprintf("sections[%d].rows[%d] %s", i, j, err.Error()))
+			}
+			if uniqueIDs[row.ID] {
+				return pkgError.ValidationError("rows id should be unique")
+			}
+			uniqueIDs[row.ID] = true
+		}
+		rows += len(section.Rows)
+	}
+	if rows > maxListRows {
+		return pkgError.ValidationError(fmt.Sprintf("sections: a list can have up to %d rows", maxListRows))
+	}
+
+	return nil
+}
+
+// templateName is the name of a message template in its URL, e.g. order_shipped
+var templateName = regexp.MustCompile(`^[a-z0-9_-]+$`)
+
+func ValidateTemplate(ctx context.Context, request domainSend.TemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Name, validation.Required, validation.Length(1, 64), validation.Match(templateName)),
+		validation.Field(&request.Content, validation.Required, validation.Length(0, 4096)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	return nil
+}
+
+func ValidateSendTemplate(ctx context.Context, request domainSend.SendTemplateRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Phone, validation.Required),
+		validation.Field(&request.TemplateName, validation.Required),
+		validation.Field(&request.Mentions, validation.Each(validation.Required)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	return nil
+}
+
 func ValidateSendPresence(ctx context.Context, request domainSend.PresenceRequest) error {
 	err := validation.ValidateStructWithContext(ctx, &request,
 		validation.Field(&request.Type, validation.In("available", "unavailable")),
@@ -244,3 +698,64 @@ func ValidateSendPresence(ctx context.Context, request domainSend.PresenceReques
 
 	return nil
 }
+
+func ValidateSendStatusText(ctx context.Context, request domainSend.StatusTextRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Text, validation.Required, validation.Length(0, 700)),
+		validation.Field(&request.BackgroundColor, validation.Match(argbColor)),
+		validation.Field(&request.TextColor, validation.Match(argbColor)),
+		validation.Field(&request.Font, validation.In(
+			"system", "system_text", "fb_script", "system_bold",
+			"morningbreeze_regular", "calistoga_regular", "exo2_extrabold", "courierprime_bold",
+		)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+	return nil
+}
+
+// argbColor is a #RRGGBB or #AARRGGBB color
+var argbColor = regexp.MustCompile(`^#([0-9a-fA-F]{6}|[0-9a-fA-F]{8})$`)
+
+func ValidateSendStatusMedia(ctx context.Context, request domainSend.StatusMediaRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Type, validation.Required, validation.In("image", "video")),
+		validation.Field(&request.Caption, validation.Length(0, 700)),
+	)
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	if request.Media == nil && request.MediaID == "" {
+		return pkgError.ValidationError(fmt.Sprintf("either media or media_id of the %s is required", request.Type))
+	}
+	if request.Media != nil && request.MediaID != "" {
+		return pkgError.ValidationError("either media or media_id can be provided, not both")
+	}
+	return nil
+}
+
+func ValidateUploadMedia(ctx context.Context, request domainSend.UploadMediaRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.Type, validation.Required, validation.In("image", "video", "audio", "document")),
+		validation.Field(&request.Media, validation.Required),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	maxSize := config.WhatsappSettingMaxFileSize
+	switch request.Type {
+	case "image":
+		maxSize = config.WhatsappSettingMaxImageSize
+	case "video":
+		maxSize = config.WhatsappSettingMaxVideoSize
+	}
+	if request.Media.Size > maxSize {
+		return pkgError.ValidationError(fmt.Sprintf("max %s upload is %s", request.Type, humanize.Bytes(uint64(maxSize))))
+	}
+
+	return nil
+}
@@ -3,6 +3,7 @@ package validations
 import (
 	"context"
 	"mime/multipart"
+	"net/textproto"
 	"testing"
 
 	domainMessage "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/message"
@@ -44,6 +45,24 @@ func TestValidateSendMessage(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("message: cannot be blank."),
 		},
+		{
+			name: "should success with mentions",
+			args: args{request: domainSend.MessageRequest{
+				Phone:    "120363024512399999@g.us",
+				Message:  "Hello @6289685028129",
+				Mentions: []string{"6289685028129", "6289685028130@s.whatsapp.net"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty mention",
+			args: args{request: domainSend.MessageRequest{
+				Phone:    "120363024512399999@g.us",
+				Message:  "Hello",
+				Mentions: []string{""},
+			}},
+			err: pkgError.ValidationError("mentions: (0: cannot be blank.)."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -92,7 +111,7 @@ func TestValidateSendImage(t *testing.T) {
 				Phone: "1728937129312@s.whatsapp.net",
 				Image: nil,
 			}},
-			err: pkgError.ValidationError("either Image or ImageURL must be provided"),
+			err: pkgError.ValidationError("either Image, ImageURL or MediaID must be provided"),
 		},
 		{
 			name: "should error with invalid image type",
@@ -155,6 +174,45 @@ func TestValidateSendFile(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("file: cannot be blank."),
 		},
+		{
+			name: "should success with an uploaded media",
+			args: args{request: domainSend.FileRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				MediaID: "0f3c5b6e-4c3a-4d2b-9a51-6c5f0c8f1e2a",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with a thumbnail",
+			args: args{request: domainSend.FileRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				File:      file,
+				Thumbnail: file,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with a thumbnail of an uploaded media",
+			args: args{request: domainSend.FileRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				MediaID:   "0f3c5b6e-4c3a-4d2b-9a51-6c5f0c8f1e2a",
+				Thumbnail: file,
+			}},
+			err: pkgError.ValidationError("thumbnail needs a file, uploaded media are sent without preview"),
+		},
+		{
+			name: "should error with a gif thumbnail",
+			args: args{request: domainSend.FileRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				File:  file,
+				Thumbnail: &multipart.FileHeader{
+					Filename: "preview.gif",
+					Size:     100,
+					Header:   map[string][]string{"Content-Type": {"image/gif"}},
+				},
+			}},
+			err: pkgError.ValidationError("your thumbnail is not allowed. please use jpg/jpeg/png"),
+		},
 	}
 
 	for _, tt := range tests {
@@ -228,7 +286,28 @@ func TestValidateSendVideo(t *testing.T) {
 				ViewOnce: false,
 				Compress: false,
 			}},
-			err: pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi"),
+			err: pkgError.ValidationError("your video type is not allowed. please use mp4/mkv/avi/gif"),
+		},
+		{
+			name: "should success with a gif",
+			args: args{request: domainSend.VideoRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Video: &multipart.FileHeader{
+					Filename: "sample.gif",
+					Size:     100,
+					Header:   map[string][]string{"Content-Type": {"image/gif"}},
+				},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with gif playback of uploaded media",
+			args: args{request: domainSend.VideoRequest{
+				Phone:       "1728937129312@s.whatsapp.net",
+				MediaID:     "0f3c5b6e-4c3a-4d2b-9a51-6c5f0c8f1e2a",
+				GifPlayback: true,
+			}},
+			err: pkgError.ValidationError("gif_playback needs a video file, uploaded media can't be sent as a GIF"),
 		},
 	}
 
@@ -447,6 +526,42 @@ func TestValidateSendContact(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("contact_phone: cannot be blank."),
 		},
+		{
+			name: "should success with several contacts",
+			args: args{request: domainSend.ContactRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Contacts: []domainSend.ContactCard{
+					{Name: "Aldino", Phone: "62788712738123", Email: "aldino@example.com"},
+					{VCard: "BEGIN:VCARD\nVERSION:3.0\nFN:Jane\nTEL:+62811111111\nEND:VCARD"},
+				},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with contacts and contact name",
+			args: args{request: domainSend.ContactRequest{
+				Phone:       "1728937129312@s.whatsapp.net",
+				ContactName: "Aldino",
+				Contacts:    []domainSend.ContactCard{{Name: "Jane", Phone: "62811111111"}},
+			}},
+			err: pkgError.ValidationError("either contacts or contact_name and contact_phone can be provided, not both"),
+		},
+		{
+			name: "should error with contact without phone",
+			args: args{request: domainSend.ContactRequest{
+				Phone:    "1728937129312@s.whatsapp.net",
+				Contacts: []domainSend.ContactCard{{Name: "Aldino", Phone: "62788712738123"}, {Name: "Jane"}},
+			}},
+			err: pkgError.ValidationError("contacts[1] phone: cannot be blank."),
+		},
+		{
+			name: "should error with invalid vcard",
+			args: args{request: domainSend.ContactRequest{
+				Phone:    "1728937129312@s.whatsapp.net",
+				Contacts: []domainSend.ContactCard{{VCard: "FN:Jane"}},
+			}},
+			err: pkgError.ValidationError("contacts[0] vcard: must start with BEGIN:VCARD and end with END:VCARD."),
+		},
 	}
 
 	for _, tt := range tests {
@@ -530,6 +645,75 @@ func TestValidateSendLocation(t *testing.T) {
 	}
 }
 
+func TestValidateSendLiveLocation(t *testing.T) {
+	type args struct {
+		request domainSend.LiveLocationRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success starting a live location",
+			args: args{request: domainSend.LiveLocationRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Latitude:  "-6.2088",
+				Longitude: "106.8456",
+				Duration:  3600,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success updating a live location without duration",
+			args: args{request: domainSend.LiveLocationRequest{
+				MessageID: "3EB0B430B6F8F1D0E053AC120E0A9E5C",
+				Phone:     "1728937129312@s.whatsapp.net",
+				Latitude:  "-6.2090",
+				Longitude: "106.8460",
+				Heading:   90,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error starting without duration",
+			args: args{request: domainSend.LiveLocationRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Latitude:  "-6.2088",
+				Longitude: "106.8456",
+			}},
+			err: pkgError.ValidationError("duration: cannot be blank."),
+		},
+		{
+			name: "should error with duration over 8 hours",
+			args: args{request: domainSend.LiveLocationRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Latitude:  "-6.2088",
+				Longitude: "106.8456",
+				Duration:  86400,
+			}},
+			err: pkgError.ValidationError("duration: must be no greater than 28800."),
+		},
+		{
+			name: "should error with invalid latitude",
+			args: args{request: domainSend.LiveLocationRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Latitude:  "-96.2088",
+				Longitude: "106.8456",
+				Duration:  3600,
+			}},
+			err: pkgError.ValidationError("latitude: must be a valid latitude."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendLiveLocation(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateSendAudio(t *testing.T) {
 	audio := &multipart.FileHeader{
 		Filename: "sample-audio.mp3",
@@ -569,6 +753,24 @@ func TestValidateSendAudio(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("audio: cannot be blank."),
 		},
+		{
+			name: "should success with a voice note",
+			args: args{request: domainSend.AudioRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Audio: audio,
+				PTT:   true,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with a voice note of uploaded media",
+			args: args{request: domainSend.AudioRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				MediaID: "0f3c5b6e-4c3a-4d2b-9a51-6c5f0c8f1e2a",
+				PTT:     true,
+			}},
+			err: pkgError.ValidationError("ptt needs an audio file, uploaded media can't be sent as a voice note"),
+		},
 		{
 			name: "should error with invalid audio type",
 			args: args{request: domainSend.AudioRequest{
@@ -591,6 +793,69 @@ func TestValidateSendAudio(t *testing.T) {
 	}
 }
 
+func TestValidateSendSticker(t *testing.T) {
+	sticker := &multipart.FileHeader{
+		Filename: "sticker.gif",
+		Size:     100,
+		Header:   map[string][]string{"Content-Type": {"image/gif"}},
+	}
+
+	type args struct {
+		request domainSend.StickerRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.StickerRequest{
+				Phone:    "1728937129312@s.whatsapp.net",
+				Sticker:  sticker,
+				PackName: "My pack",
+				Emojis:   []string{"😀", "🎉"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty sticker",
+			args: args{request: domainSend.StickerRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+			}},
+			err: pkgError.ValidationError("sticker: cannot be blank."),
+		},
+		{
+			name: "should error with too many emojis",
+			args: args{request: domainSend.StickerRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				Sticker: sticker,
+				Emojis:  []string{"😀", "🎉", "👍", "🔥"},
+			}},
+			err: pkgError.ValidationError("emojis: the length must be no more than 3."),
+		},
+		{
+			name: "should error with invalid sticker type",
+			args: args{request: domainSend.StickerRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Sticker: &multipart.FileHeader{
+					Filename: "sticker.mp4",
+					Size:     100,
+					Header:   map[string][]string{"Content-Type": {"video/mp4"}},
+				},
+			}},
+			err: pkgError.ValidationError("your sticker is not allowed. please use jpg/jpeg/png/gif/webp"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendSticker(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
 func TestValidateSendPoll(t *testing.T) {
 	type args struct {
 		request domainSend.PollRequest
@@ -650,6 +915,16 @@ func TestValidateSendPoll(t *testing.T) {
 			}},
 			err: pkgError.ValidationError("options should be unique"),
 		},
+		{
+			name: "should error with more than 12 options",
+			args: args{request: domainSend.PollRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				Question:  "Pick a number",
+				Options:   []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"},
+				MaxAnswer: 1,
+			}},
+			err: pkgError.ValidationError("options: the length must be no more than 12."),
+		},
 		{
 			name: "should error with max answer greater than options",
 			args: args{request: domainSend.PollRequest{
@@ -670,41 +945,891 @@ func TestValidateSendPoll(t *testing.T) {
 	}
 }
 
-func TestValidateSendPresence(t *testing.T) {
+func TestValidateSendButtons(t *testing.T) {
 	type args struct {
-		request domainSend.PresenceRequest
+		request domainSend.ButtonsRequest
 	}
+	buttons := []domainSend.ButtonItem{{ID: "yes", Text: "Yes"}, {ID: "no", Text: "No"}}
 	tests := []struct {
 		name string
 		args args
 		err  any
 	}{
 		{
-			name: "should success with available type",
-			args: args{request: domainSend.PresenceRequest{
-				Type: "available",
+			name: "should success with normal condition",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				Body:    "Do you confirm the order?",
+				Footer:  "Reply with a button",
+				Header:  "Order #42",
+				Buttons: buttons,
 			}},
 			err: nil,
 		},
 		{
-			name: "should success with unavailable type",
-			args: args{request: domainSend.PresenceRequest{
-				Type: "unavailable",
+			name: "should success with header media",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:           "1728937129312@s.whatsapp.net",
+				Body:            "Do you confirm the order?",
+				HeaderMediaID:   "3f6c1d2e",
+				HeaderMediaType: "image",
+				Buttons:         buttons,
 			}},
 			err: nil,
 		},
 		{
-			name: "should error with invalid type",
-			args: args{request: domainSend.PresenceRequest{
-				Type: "invalid",
+			name: "should error with empty body",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				Buttons: buttons,
 			}},
-			err: pkgError.ValidationError("type: must be a valid value."),
+			err: pkgError.ValidationError("body: cannot be blank."),
+		},
+		{
+			name: "should error without buttons",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "Do you confirm the order?",
+			}},
+			err: pkgError.ValidationError("buttons: cannot be blank."),
+		},
+		{
+			name: "should error with more than 3 buttons",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "Pick a size",
+				Buttons: []domainSend.ButtonItem{
+					{ID: "s", Text: "S"}, {ID: "m", Text: "M"}, {ID: "l", Text: "L"}, {ID: "xl", Text: "XL"},
+				},
+			}},
+			err: pkgError.ValidationError("buttons: the length must be between 1 and 3."),
+		},
+		{
+			name: "should error with button without text",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				Body:    "Do you confirm the order?",
+				Buttons: []domainSend.ButtonItem{{ID: "yes", Text: "Yes"}, {ID: "no"}},
+			}},
+			err: pkgError.ValidationError("buttons[1] text: cannot be blank."),
+		},
+		{
+			name: "should error with duplicate button ids",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:   "1728937129312@s.whatsapp.net",
+				Body:    "Do you confirm the order?",
+				Buttons: []domainSend.ButtonItem{{ID: "yes", Text: "Yes"}, {ID: "yes", Text: "Sure"}},
+			}},
+			err: pkgError.ValidationError("buttons id should be unique"),
+		},
+		{
+			name: "should error with both header and header media",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:           "1728937129312@s.whatsapp.net",
+				Body:            "Do you confirm the order?",
+				Header:          "Order #42",
+				HeaderMediaID:   "3f6c1d2e",
+				HeaderMediaType: "image",
+				Buttons:         buttons,
+			}},
+			err: pkgError.ValidationError("either header or header_media_id can be provided, not both"),
+		},
+		{
+			name: "should error with audio header media",
+			args: args{request: domainSend.ButtonsRequest{
+				Phone:           "1728937129312@s.whatsapp.net",
+				Body:            "Do you confirm the order?",
+				HeaderMediaID:   "3f6c1d2e",
+				HeaderMediaType: "audio",
+				Buttons:         buttons,
+			}},
+			err: pkgError.ValidationError("header_media_type: must be a valid value."),
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := ValidateSendPresence(context.Background(), tt.args.request)
+			err := ValidateSendButtons(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendList(t *testing.T) {
+	type args struct {
+		request domainSend.ListRequest
+	}
+	sizes := []domainSend.ListSection{{
+		Title: "Sizes",
+		Rows:  []domainSend.ListRow{{ID: "size_s", Title: "Small"}, {ID: "size_m", Title: "Medium", Description: "Fits 38 to 40"}},
+	}}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Title:      "T-shirt",
+				Body:       "Pick your size",
+				ButtonText: "Sizes",
+				Sections:   sizes,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with empty button text",
+			args: args{request: domainSend.ListRequest{
+				Phone:    "1728937129312@s.whatsapp.net",
+				Body:     "Pick your size",
+				Sections: sizes,
+			}},
+			err: pkgError.ValidationError("button_text: cannot be blank."),
+		},
+		{
+			name: "should error without sections",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Body:       "Pick your size",
+				ButtonText: "Sizes",
+			}},
+			err: pkgError.ValidationError("sections: cannot be blank."),
+		},
+		{
+			name: "should error with untitled sections",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Body:       "Pick your size",
+				ButtonText: "Sizes",
+				Sections: []domainSend.ListSection{
+					sizes[0],
+					{Rows: []domainSend.ListRow{{ID: "size_l", Title: "Large"}}},
+				},
+			}},
+			err: pkgError.ValidationError("sections[1] title: cannot be blank."),
+		},
+		{
+			name: "should error with row without title",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Body:       "Pick your size",
+				ButtonText: "Sizes",
+				Sections:   []domainSend.ListSection{{Rows: []domainSend.ListRow{{ID: "size_s"}}}},
+			}},
+			err: pkgError.ValidationError("sections[0].rows[0] title: cannot be blank."),
+		},
+		{
+			name: "should error with duplicate row ids",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Body:       "Pick your size",
+				ButtonText: "Sizes",
+				Sections: []domainSend.ListSection{
+					sizes[0],
+					{Title: "Kids", Rows: []domainSend.ListRow{{ID: "size_s", Title: "Small"}}},
+				},
+			}},
+			err: pkgError.ValidationError("rows id should be unique"),
+		},
+		{
+			name: "should error with more than 10 rows",
+			args: args{request: domainSend.ListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Body:       "Pick a number",
+				ButtonText: "Numbers",
+				Sections: []domainSend.ListSection{{Rows: []domainSend.ListRow{
+					{ID: "1", Title: "1"}, {ID: "2", Title: "2"}, {ID: "3", Title: "3"}, {ID: "4", Title: "4"},
+					{ID: "5", Title: "5"}, {ID: "6", Title: "6"}, {ID: "7", Title: "7"}, {ID: "8", Title: "8"},
+					{ID: "9", Title: "9"}, {ID: "10", Title: "10"}, {ID: "11", Title: "11"},
+				}}},
+			}},
+			err: pkgError.ValidationError("sections: a list can have up to 10 rows"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendList(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendCarousel(t *testing.T) {
+	type args struct {
+		request domainSend.CarouselRequest
+	}
+	card := domainSend.CarouselCard{
+		MediaID: "3EB0C127D7BACC83D6A1",
+		Title:   "Batik shirt",
+		Body:    "Hand drawn, cotton",
+		Buttons: []domainSend.CarouselButton{
+			{Type: "reply", ID: "buy_1", Text: "Buy"},
+			{Type: "url", Text: "Open", URL: "https://shop.example.com/batik-shirt"},
+		},
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+				Cards: []domainSend.CarouselCard{card},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without cards",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+			}},
+			err: pkgError.ValidationError("cards: cannot be blank."),
+		},
+		{
+			name: "should error with card without media",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+				Cards: []domainSend.CarouselCard{card, {Body: "Sarong", Buttons: card.Buttons[:1]}},
+			}},
+			err: pkgError.ValidationError("cards[1] media_id: cannot be blank."),
+		},
+		{
+			name: "should error with url button without url",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+				Cards: []domainSend.CarouselCard{{
+					MediaID: "3EB0C127D7BACC83D6A1",
+					Body:    "Sarong",
+					Buttons: []domainSend.CarouselButton{{Type: "url", Text: "Open"}},
+				}},
+			}},
+			err: pkgError.ValidationError("cards[0].buttons[0] url: cannot be blank."),
+		},
+		{
+			name: "should error with unknown button type",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+				Cards: []domainSend.CarouselCard{{
+					MediaID: "3EB0C127D7BACC83D6A1",
+					Body:    "Sarong",
+					Buttons: []domainSend.CarouselButton{{Type: "copy", Text: "Copy code"}},
+				}},
+			}},
+			err: pkgError.ValidationError("cards[0].buttons[0] type: must be a valid value."),
+		},
+		{
+			name: "should error with duplicate reply ids across cards",
+			args: args{request: domainSend.CarouselRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Body:  "New arrivals",
+				Cards: []domainSend.CarouselCard{card, card},
+			}},
+			err: pkgError.ValidationError("buttons id should be unique"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendCarousel(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendProduct(t *testing.T) {
+	type args struct {
+		request domainSend.ProductRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.ProductRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				ProductID:    "6289712345678901",
+				Title:        "Batik shirt",
+				CurrencyCode: "IDR",
+				Price:        150000,
+				SalePrice:    120000,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without product id",
+			args: args{request: domainSend.ProductRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Title: "Batik shirt",
+			}},
+			err: pkgError.ValidationError("product_id: cannot be blank."),
+		},
+		{
+			name: "should error with price without currency",
+			args: args{request: domainSend.ProductRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				ProductID: "6289712345678901",
+				Title:     "Batik shirt",
+				Price:     150000,
+			}},
+			err: pkgError.ValidationError("currency_code: cannot be blank."),
+		},
+		{
+			name: "should error with sale price above the price",
+			args: args{request: domainSend.ProductRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				ProductID:    "6289712345678901",
+				Title:        "Batik shirt",
+				CurrencyCode: "IDR",
+				Price:        150000,
+				SalePrice:    200000,
+			}},
+			err: pkgError.ValidationError("sale_price: must be no greater than 150000."),
+		},
+		{
+			name: "should error with invalid currency",
+			args: args{request: domainSend.ProductRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				ProductID:    "6289712345678901",
+				Title:        "Batik shirt",
+				CurrencyCode: "rupiah",
+				Price:        150000,
+			}},
+			err: pkgError.ValidationError("currency_code: must be in a valid format."),
+		},
+		{
+			name: "should error with image and media id",
+			args: args{request: domainSend.ProductRequest{
+				Phone:     "1728937129312@s.whatsapp.net",
+				ProductID: "6289712345678901",
+				Title:     "Batik shirt",
+				Image:     &multipart.FileHeader{Header: textproto.MIMEHeader{"Content-Type": []string{"image/jpeg"}}},
+				MediaID:   "3EB0C127D7BACC83D6A1",
+			}},
+			err: pkgError.ValidationError("either image or media_id can be provided, not both"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendProduct(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendProductList(t *testing.T) {
+	type args struct {
+		request domainSend.ProductListRequest
+	}
+	shirts := []domainSend.ProductSection{{Title: "Shirts", ProductIDs: []string{"6289712345678901", "6289712345678902"}}}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.ProductListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Title:      "New arrivals",
+				Body:       "Our batik collection",
+				ButtonText: "View items",
+				Sections:   shirts,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without sections",
+			args: args{request: domainSend.ProductListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Title:      "New arrivals",
+				Body:       "Our batik collection",
+				ButtonText: "View items",
+			}},
+			err: pkgError.ValidationError("sections: cannot be blank."),
+		},
+		{
+			name: "should error with empty section",
+			args: args{request: domainSend.ProductListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Title:      "New arrivals",
+				Body:       "Our batik collection",
+				ButtonText: "View items",
+				Sections:   []domainSend.ProductSection{shirts[0], {Title: "Pants"}},
+			}},
+			err: pkgError.ValidationError("sections[1] product_ids: cannot be blank."),
+		},
+		{
+			name: "should error with duplicate products",
+			args: args{request: domainSend.ProductListRequest{
+				Phone:      "1728937129312@s.whatsapp.net",
+				Title:      "New arrivals",
+				Body:       "Our batik collection",
+				ButtonText: "View items",
+				Sections:   []domainSend.ProductSection{shirts[0], {Title: "Sale", ProductIDs: []string{"6289712345678902"}}},
+			}},
+			err: pkgError.ValidationError("product 6289712345678902 is listed more than once"),
+		},
+		{
+			name: "should error with header product not listed",
+			args: args{request: domainSend.ProductListRequest{
+				Phone:           "1728937129312@s.whatsapp.net",
+				Title:           "New arrivals",
+				Body:            "Our batik collection",
+				ButtonText:      "View items",
+				HeaderProductID: "6289712345678903",
+				Sections:        shirts,
+			}},
+			err: pkgError.ValidationError("header_product_id must be one of the listed products"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendProductList(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendOrder(t *testing.T) {
+	type args struct {
+		request domainSend.OrderRequest
+	}
+	items := []domainSend.OrderItem{{Name: "Batik shirt", Price: 150000, Quantity: 2}}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.OrderRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				Title:        "Batik order",
+				CurrencyCode: "IDR",
+				Items:        items,
+				Shipping:     20000,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without items",
+			args: args{request: domainSend.OrderRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				Title:        "Batik order",
+				CurrencyCode: "IDR",
+			}},
+			err: pkgError.ValidationError("items: cannot be blank."),
+		},
+		{
+			name: "should error without currency",
+			args: args{request: domainSend.OrderRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+				Title: "Batik order",
+				Items: items,
+			}},
+			err: pkgError.ValidationError("currency_code: cannot be blank."),
+		},
+		{
+			name: "should error with item without quantity",
+			args: args{request: domainSend.OrderRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				Title:        "Batik order",
+				CurrencyCode: "IDR",
+				Items:        []domainSend.OrderItem{items[0], {Name: "Sarong", Price: 75000}},
+			}},
+			err: pkgError.ValidationError("items[1] quantity: cannot be blank."),
+		},
+		{
+			name: "should error with discount above the total",
+			args: args{request: domainSend.OrderRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				Title:        "Batik order",
+				CurrencyCode: "IDR",
+				Items:        items,
+				Discount:     400000,
+			}},
+			err: pkgError.ValidationError("discount can't be more than the order total"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendOrder(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendPresence(t *testing.T) {
+	type args struct {
+		request domainSend.PresenceRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with available type",
+			args: args{request: domainSend.PresenceRequest{
+				Type: "available",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with unavailable type",
+			args: args{request: domainSend.PresenceRequest{
+				Type: "unavailable",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid type",
+			args: args{request: domainSend.PresenceRequest{
+				Type: "invalid",
+			}},
+			err: pkgError.ValidationError("type: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendPresence(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendStatusText(t *testing.T) {
+	type args struct {
+		request domainSend.StatusTextRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with colors and font",
+			args: args{request: domainSend.StatusTextRequest{
+				Text:            "Open today until 9 PM",
+				BackgroundColor: "#1E88E5",
+				TextColor:       "#CCFFFFFF",
+				Font:            "system_bold",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without text",
+			args: args{request: domainSend.StatusTextRequest{}},
+			err:  pkgError.ValidationError("text: cannot be blank."),
+		},
+		{
+			name: "should error with invalid color",
+			args: args{request: domainSend.StatusTextRequest{
+				Text:            "Open today",
+				BackgroundColor: "blue",
+			}},
+			err: pkgError.ValidationError("background_color: must be in a valid format."),
+		},
+		{
+			name: "should error with unknown font",
+			args: args{request: domainSend.StatusTextRequest{
+				Text: "Open today",
+				Font: "comic_sans",
+			}},
+			err: pkgError.ValidationError("font: must be a valid value."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendStatusText(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendStatusMedia(t *testing.T) {
+	type args struct {
+		request domainSend.StatusMediaRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with media id",
+			args: args{request: domainSend.StatusMediaRequest{
+				Type:    "image",
+				MediaID: "9f3c2b1a",
+				Caption: "New arrivals",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without media",
+			args: args{request: domainSend.StatusMediaRequest{
+				Type: "video",
+			}},
+			err: pkgError.ValidationError("either media or media_id of the video is required"),
+		},
+		{
+			name: "should error with media and media id",
+			args: args{request: domainSend.StatusMediaRequest{
+				Type:    "image",
+				Media:   &multipart.FileHeader{Filename: "promo.jpg"},
+				MediaID: "9f3c2b1a",
+			}},
+			err: pkgError.ValidationError("either media or media_id can be provided, not both"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendStatusMedia(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateTemplate(t *testing.T) {
+	type args struct {
+		request domainSend.TemplateRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.TemplateRequest{
+				Name:    "order_shipped",
+				Content: "Hi {{name}}, order {{order_id}} is shipped",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without content",
+			args: args{request: domainSend.TemplateRequest{
+				Name: "order_shipped",
+			}},
+			err: pkgError.ValidationError("content: cannot be blank."),
+		},
+		{
+			name: "should error with invalid name",
+			args: args{request: domainSend.TemplateRequest{
+				Name:    "Order Shipped",
+				Content: "Order {{order_id}} is shipped",
+			}},
+			err: pkgError.ValidationError("name: must be in a valid format."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTemplate(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendTemplate(t *testing.T) {
+	type args struct {
+		request domainSend.SendTemplateRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.SendTemplateRequest{
+				Phone:        "1728937129312@s.whatsapp.net",
+				TemplateName: "order_shipped",
+				Variables:    map[string]string{"order_id": "INV-001"},
+			}},
+			err: nil,
+		},
+		{
+			name: "should error without template name",
+			args: args{request: domainSend.SendTemplateRequest{
+				Phone: "1728937129312@s.whatsapp.net",
+			}},
+			err: pkgError.ValidationError("template_name: cannot be blank."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendTemplate(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateUploadMedia(t *testing.T) {
+	media := &multipart.FileHeader{
+		Filename: "sample-image.png",
+		Size:     100,
+		Header:   map[string][]string{"Content-Type": {"image/png"}},
+	}
+
+	type args struct {
+		request domainSend.UploadMediaRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with normal condition",
+			args: args{request: domainSend.UploadMediaRequest{
+				Type:  "image",
+				Media: media,
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with invalid type",
+			args: args{request: domainSend.UploadMediaRequest{
+				Type:  "sticker",
+				Media: media,
+			}},
+			err: pkgError.ValidationError("type: must be a valid value."),
+		},
+		{
+			name: "should error with empty media",
+			args: args{request: domainSend.UploadMediaRequest{
+				Type: "document",
+			}},
+			err: pkgError.ValidationError("media: cannot be blank."),
+		},
+		{
+			name: "should error with too large image",
+			args: args{request: domainSend.UploadMediaRequest{
+				Type:  "image",
+				Media: &multipart.FileHeader{Filename: "large.png", Size: 30000000},
+			}},
+			err: pkgError.ValidationError("max image upload is 20 MB"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUploadMedia(context.Background(), tt.args.request)
+			assert.Equal(t, tt.err, err)
+		})
+	}
+}
+
+func TestValidateSendBroadcast(t *testing.T) {
+	delay, tooLong := 10, 7200
+	type args struct {
+		request domainSend.BroadcastRequest
+	}
+	tests := []struct {
+		name string
+		args args
+		err  any
+	}{
+		{
+			name: "should success with recipients and message",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{
+					{Phone: "6289685028129", Variables: map[string]string{"name": "Budi"}},
+					{Phone: "6289685028130"},
+				},
+				Message: "Hi {{name}}",
+				Delay:   &delay,
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with media and no message",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{{Phone: "6289685028129"}},
+				MediaID:    "6b1f6e0e-2f4e-4c1e-9d53-2b1cf2a4d7a1",
+			}},
+			err: nil,
+		},
+		{
+			name: "should success with template and no message",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients:   []domainSend.BroadcastRecipient{{Phone: "6289685028129", Variables: map[string]string{"name": "Budi"}}},
+				TemplateName: "greeting",
+			}},
+			err: nil,
+		},
+		{
+			name: "should error with message and template",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients:   []domainSend.BroadcastRecipient{{Phone: "6289685028129"}},
+				Message:      "Hi",
+				TemplateName: "greeting",
+			}},
+			err: pkgError.ValidationError("either message or template_name can be provided, not both"),
+		},
+		{
+			name: "should error without recipients",
+			args: args{request: domainSend.BroadcastRequest{Message: "Hi"}},
+			err:  pkgError.ValidationError("recipients: cannot be blank."),
+		},
+		{
+			name: "should error without message",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{{Phone: "6289685028129"}},
+			}},
+			err: pkgError.ValidationError("message: cannot be blank."),
+		},
+		{
+			name: "should error with too long delay",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{{Phone: "6289685028129"}},
+				Message:    "Hi",
+				Delay:      &tooLong,
+			}},
+			err: pkgError.ValidationError("delay: must be no greater than 3600."),
+		},
+		{
+			name: "should error with blank phone",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{{Phone: "6289685028129"}, {}},
+				Message:    "Hi",
+			}},
+			err: pkgError.ValidationError("recipients: phone of recipient 1 cannot be blank."),
+		},
+		{
+			name: "should error with duplicated phone",
+			args: args{request: domainSend.BroadcastRequest{
+				Recipients: []domainSend.BroadcastRecipient{{Phone: "6289685028129"}, {Phone: "6289685028129"}},
+				Message:    "Hi",
+			}},
+			err: pkgError.ValidationError("recipients: 6289685028129 is given more than once."),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateSendBroadcast(context.Background(), tt.args.request)
 			assert.Equal(t, tt.err, err)
 		})
 	}
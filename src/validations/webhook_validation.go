@@ -0,0 +1,47 @@
+package validations
+
+import (
+	"context"
+
+	domainWebhook "github.com/aldinokemal/go-whatsapp-web-multidevice/domains/webhook"
+	pkgError "github.com/aldinokemal/go-whatsapp-web-multidevice/pkg/error"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+func ValidateReplayDeadLetter(ctx context.Context, request domainWebhook.ReplayDeadLetterRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.ID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateRegisterWebhookEndpoint(ctx context.Context, request domainWebhook.RegisterEndpointRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.URL, validation.Required, is.URL),
+		validation.Field(&request.Signature, validation.In("sha256", "sha512")),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}
+
+func ValidateDeleteWebhookEndpoint(ctx context.Context, request domainWebhook.DeleteEndpointRequest) error {
+	err := validation.ValidateStructWithContext(ctx, &request,
+		validation.Field(&request.ID, validation.Required, validation.Min(int64(1))),
+	)
+
+	if err != nil {
+		return pkgError.ValidationError(err.Error())
+	}
+
+	return nil
+}